@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// metricNamespace is the CloudWatch namespace cleanup metrics are published under.
+const metricNamespace = "Currenseen"
+
+var (
+	// Global dependencies - initialized once during Lambda cold start
+	log     *logger.Logger
+	cleaner *dynamodb.ExpiredCleaner
+)
+
+// initDependencies creates the logger and the expired rate cleaner.
+func initDependencies(ctx context.Context) error {
+	log = logger.NewFromEnv()
+
+	tableName := os.Getenv("TABLE_NAME")
+	if tableName == "" {
+		log.Error("TABLE_NAME environment variable is required")
+		return fmt.Errorf("TABLE_NAME environment variable is required")
+	}
+
+	dynamoClient, err := config.NewDynamoDBClient(ctx)
+	if err != nil {
+		log.Error("failed to create DynamoDB client", "error", err.Error())
+		return fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	cleanupConfig := config.LoadCleanupConfig()
+	cleaner = dynamodb.NewExpiredCleaner(dynamoClient, tableName, cleanupConfig)
+	log.Info("initialized expired rate cleaner",
+		"table", tableName,
+		"max_age", cleanupConfig.MaxAge.String(),
+		"max_pages", cleanupConfig.MaxPages,
+		"page_size", cleanupConfig.PageSize,
+	)
+	return nil
+}
+
+// handler is the Lambda handler for scheduled (EventBridge) cleanup runs.
+//
+// This function:
+// - Initializes dependencies on first invocation (cold start)
+// - Deletes a bounded number of scan pages of rates older than CLEANUP_MAX_AGE
+// - Emits the number of deleted rates as a CloudWatch metric
+//
+// Runs that do not reach the end of the table are resumed by the next invocation.
+func handler(ctx context.Context, event events.EventBridgeEvent) error {
+	if cleaner == nil {
+		if err := initDependencies(ctx); err != nil {
+			return err
+		}
+	}
+
+	result, err := cleaner.Run(ctx)
+	// Report deletions even on failure; they are not undone
+	emitDeletedMetric(result.Deleted)
+	if err != nil {
+		log.Error("expired rate cleanup failed", "error", err.Error(), "deleted", result.Deleted)
+		return fmt.Errorf("expired rate cleanup failed: %w", err)
+	}
+
+	log.Info("expired rate cleanup completed",
+		"scanned", result.Scanned,
+		"deleted", result.Deleted,
+		"complete", result.Complete,
+	)
+	return nil
+}
+
+// emitDeletedMetric writes the deleted count to stdout in CloudWatch Embedded
+// Metric Format, which Lambda publishes as a metric without an API call.
+func emitDeletedMetric(deleted int) {
+	record := map[string]any{
+		"_aws": map[string]any{
+			"Timestamp": time.Now().UnixMilli(),
+			"CloudWatchMetrics": []map[string]any{
+				{
+					"Namespace":  metricNamespace,
+					"Dimensions": [][]string{{}},
+					"Metrics": []map[string]string{
+						{"Name": "ExpiredItemsDeleted", "Unit": "Count"},
+					},
+				},
+			},
+		},
+		"ExpiredItemsDeleted": deleted,
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Error("failed to encode cleanup metric", "error", err.Error())
+		return
+	}
+	fmt.Println(string(line))
+}
+
+func main() {
+	// Start Lambda runtime
+	// The handler function will be called for each scheduled event
+	lambda.Start(handler)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/aws/aws-lambda-go/events"
+	lambdaadapter "github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/lambda"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/server"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/bootstrap"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// main runs the service as a standalone HTTP server (outside AWS Lambda).
+//
+// This function:
+// - Initializes the same dependencies and routes as the Lambda entrypoint
+// - Listens on PORT (default: 8080)
+// - Reloads the cache TTL and rate limits on SIGHUP or CONFIG_FILE changes
+// - Drains in-flight requests on SIGTERM/SIGINT, then stops the rate limiter
+func main() {
+	log := logger.NewFromEnv()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	deps, err := bootstrap.NewHandlerDependencies(ctx, log)
+	if err != nil {
+		log.Error("failed to initialize dependencies", "error", err.Error())
+		os.Exit(1)
+	}
+
+	config := server.DefaultConfig()
+	if port := os.Getenv("PORT"); port != "" {
+		config.Addr = ":" + port
+	}
+
+	var current atomic.Pointer[lambdaadapter.HandlerDependencies]
+	current.Store(deps)
+	go bootstrap.WatchConfig(ctx, &current, log)
+
+	srv := server.New(server.APIGatewayHandler(func(ctx context.Context, event events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return lambdaadapter.Handle(ctx, event, current.Load())
+	}), config, log)
+	if deps.RateLimiter != nil {
+		srv.OnShutdown(deps.RateLimiter.Stop)
+	}
+
+	if err := srv.Run(ctx); err != nil {
+		log.Error("HTTP server stopped with error", "error", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	lambdaadapter "github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/lambda"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+var (
+	// Global dependencies - initialized once during Lambda cold start
+	log       *logger.Logger
+	processor lambdaadapter.StreamProcessor
+)
+
+// handler is the Lambda handler for DynamoDB Streams events.
+//
+// This function:
+// - Initializes the logger and processor on first invocation (cold start)
+// - Dispatches exchange rate changes to the stream processor
+// - Returns batch item failures so only failed records are retried
+func handler(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	if processor == nil {
+		log = logger.NewFromEnv()
+		processor = lambdaadapter.LoggingStreamProcessor{Logger: log}
+		log.Info("initialized stream processor")
+	}
+
+	return lambdaadapter.StreamHandler(ctx, event, processor, log), nil
+}
+
+func main() {
+	// Start Lambda runtime
+	// The handler function will be called for each DynamoDB Streams batch
+	lambda.Start(handler)
+}
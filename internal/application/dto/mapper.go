@@ -13,16 +13,31 @@ func ToRateResponse(rate *entity.ExchangeRate) RateResponse {
 	}
 
 	return RateResponse{
-		Base:      rate.Base.String(),
-		Target:    rate.Target.String(),
-		Rate:      rate.Rate,
-		Timestamp: rate.Timestamp,
-		Stale:     rate.Stale,
+		Base:       rate.Base.String(),
+		Target:     rate.Target.String(),
+		Rate:       rate.Rate,
+		Bid:        rate.Bid,
+		Ask:        rate.Ask,
+		Timestamp:  rate.Timestamp,
+		AgeSeconds: ageSeconds(rate),
+		Stale:      rate.Stale,
+		Derived:    rate.Derived,
 	}
 }
 
+// ageSeconds returns the whole seconds since the rate's timestamp.
+// Timestamps slightly in the future (allowed for clock skew) report 0.
+func ageSeconds(rate *entity.ExchangeRate) int64 {
+	age := int64(rate.Age().Seconds())
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
 // ToRatesResponse converts a slice of domain ExchangeRate entities to a RatesResponse DTO.
 // The base currency is extracted from the first rate (all rates should have the same base).
+// Each entry carries its own Stale flag and age; the top-level Stale is set if any entry is stale.
 // If rates is empty, returns a RatesResponse with empty rates map.
 func ToRatesResponse(rates []*entity.ExchangeRate) RatesResponse {
 	if len(rates) == 0 {
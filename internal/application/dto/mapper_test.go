@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestToRatesResponse_PerEntryStaleness(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	gbp, _ := entity.NewCurrencyCode("GBP")
+
+	fresh, _ := entity.NewExchangeRate(base, eur, 0.85, time.Now().Add(-10*time.Second), false)
+	stale, _ := entity.NewExchangeRate(base, gbp, 0.75, time.Now().Add(-2*time.Hour), true)
+
+	resp := ToRatesResponse([]*entity.ExchangeRate{fresh, stale})
+
+	if !resp.Stale {
+		t.Error("top-level Stale = false, want true when any entry is stale")
+	}
+
+	tests := []struct {
+		target    string
+		wantStale bool
+		minAge    int64
+		maxAge    int64
+	}{
+		{target: "EUR", wantStale: false, minAge: 10, maxAge: 60},
+		{target: "GBP", wantStale: true, minAge: 7200, maxAge: 7260},
+	}
+	for _, tt := range tests {
+		entry, ok := resp.Rates[tt.target]
+		if !ok {
+			t.Fatalf("missing entry for %s", tt.target)
+		}
+		if entry.Stale != tt.wantStale {
+			t.Errorf("%s Stale = %v, want %v", tt.target, entry.Stale, tt.wantStale)
+		}
+		if entry.AgeSeconds < tt.minAge || entry.AgeSeconds > tt.maxAge {
+			t.Errorf("%s AgeSeconds = %d, want between %d and %d", tt.target, entry.AgeSeconds, tt.minAge, tt.maxAge)
+		}
+	}
+}
+
+func TestToRatesResponse_AllFresh(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	rate, _ := entity.NewExchangeRate(base, eur, 0.85, time.Now(), false)
+
+	resp := ToRatesResponse([]*entity.ExchangeRate{rate})
+	if resp.Stale {
+		t.Error("top-level Stale = true, want false when no entry is stale")
+	}
+	if resp.Rates["EUR"].Stale {
+		t.Error("EUR Stale = true, want false")
+	}
+}
+
+func TestToRateResponse_FutureTimestampAge(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	// Timestamps up to 5 minutes ahead are accepted for clock skew
+	rate, _ := entity.NewExchangeRate(base, eur, 0.85, time.Now().Add(time.Minute), false)
+
+	if age := ToRateResponse(rate).AgeSeconds; age != 0 {
+		t.Errorf("AgeSeconds = %d, want 0 for a future timestamp", age)
+	}
+}
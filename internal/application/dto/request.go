@@ -11,6 +11,12 @@ type GetRatesRequest struct {
 	Base string `json:"base"` // Base currency code (e.g., "USD")
 }
 
+// InvalidateRateRequest represents a request to remove a cached exchange rate.
+type InvalidateRateRequest struct {
+	Base   string `json:"base"`   // Base currency code (e.g., "USD")
+	Target string `json:"target"` // Target currency code (e.g., "EUR")
+}
+
 // HealthCheckRequest represents a request for a health check.
 // This is typically an empty request, but we define it for consistency.
 type HealthCheckRequest struct{}
@@ -2,33 +2,135 @@ package dto
 
 import "time"
 
+// Response sources describe where the data in a response came from.
+// They are not serialized in the plain response body, but are exposed in the
+// optional response envelope metadata.
+const (
+	// SourceCache indicates the data was served from a valid cache entry.
+	SourceCache = "cache"
+
+	// SourceProvider indicates the data was freshly fetched from the external API.
+	SourceProvider = "provider"
+
+	// SourceStaleCache indicates the data was served from an expired cache entry as a fallback.
+	SourceStaleCache = "stale_cache"
+
+	// SourceEmbedded indicates the data came from the bundled static snapshot,
+	// served as a last resort when the cache and every provider failed.
+	SourceEmbedded = "embedded"
+)
+
 // RateResponse represents a single exchange rate response.
 type RateResponse struct {
-	Base      string    `json:"base"`            // Base currency code
-	Target    string    `json:"target"`          // Target currency code
-	Rate      float64   `json:"rate"`            // Exchange rate
-	Timestamp time.Time `json:"timestamp"`       // When the rate was last updated
-	Stale     bool      `json:"stale,omitempty"` // Indicates if the rate is stale (from cache fallback)
+	Base        string    `json:"base"`                  // Base currency code
+	Target      string    `json:"target"`                // Target currency code
+	Rate        float64   `json:"rate"`                  // Exchange rate (mid)
+	Bid         *float64  `json:"bid,omitempty"`         // Bid price, omitted when the provider does not quote it
+	Ask         *float64  `json:"ask,omitempty"`         // Ask price, omitted when the provider does not quote it
+	Timestamp   time.Time `json:"timestamp"`             // When the rate was last updated
+	AgeSeconds  int64     `json:"age_seconds"`           // Seconds since Timestamp when the response was built
+	Stale       bool      `json:"stale,omitempty"`       // Indicates if the rate is stale (from cache fallback)
+	Derived     bool      `json:"derived,omitempty"`     // Computed from the inverse pair instead of quoted directly
+	Approximate bool      `json:"approximate,omitempty"` // From the bundled static snapshot; not a market rate
+	Source      string    `json:"-"`                     // Where the rate came from (see Source* constants)
 }
 
 // RatesResponse represents a response containing multiple exchange rates.
 type RatesResponse struct {
-	Base      string                  `json:"base"`            // Base currency code
-	Rates     map[string]RateResponse `json:"rates"`           // Map of target currency to rate
-	Timestamp time.Time               `json:"timestamp"`       // When the rates were last updated
-	Stale     bool                    `json:"stale,omitempty"` // Indicates if any rate is stale
+	Base         string                  `json:"base"`                  // Base currency code
+	Rates        map[string]RateResponse `json:"rates"`                 // Map of target currency to rate
+	Timestamp    time.Time               `json:"timestamp"`             // When the rates were last updated
+	Stale        bool                    `json:"stale,omitempty"`       // Indicates if any rate is stale (see each entry for which)
+	Approximate  bool                    `json:"approximate,omitempty"` // From the bundled static snapshot; not market rates
+	Source       string                  `json:"-"`                     // Where the rates came from (see Source* constants)
+	Skipped      int                     `json:"-"`                     // Number of provider entries dropped as invalid
+	CacheSkipped int                     `json:"-"`                     // Number of cached entries that could not be read
+}
+
+// MultiBaseRatesResponse represents all exchange rates for several base currencies.
+type MultiBaseRatesResponse struct {
+	Results   map[string]BaseRatesResult `json:"results"`   // Map of base currency to its rates or error
+	Timestamp time.Time                  `json:"timestamp"` // When the response was built
+}
+
+// BaseRatesResult holds the outcome for one base currency in a multi-base response.
+type BaseRatesResult struct {
+	Rates *RatesResponse `json:"rates,omitempty"` // Rates for the base (nil on error)
+	Error *ErrorResponse `json:"error,omitempty"` // Why the base failed (nil on success)
+}
+
+// InvalidateRateResponse represents the result of removing a cached exchange rate.
+type InvalidateRateResponse struct {
+	Base        string `json:"base"`        // Base currency code
+	Target      string `json:"target"`      // Target currency code
+	Invalidated bool   `json:"invalidated"` // Whether a cached rate was removed
 }
 
 // HealthCheckResponse represents the health status of the service.
 type HealthCheckResponse struct {
-	Status    string            `json:"status"`           // Overall status: "healthy" or "unhealthy"
+	Status    string            `json:"status"`           // Overall status: "healthy", "degraded", or "unhealthy"
 	Checks    map[string]string `json:"checks,omitempty"` // Individual component checks
 	Timestamp time.Time         `json:"timestamp"`        // When the health check was performed
 }
 
 // ErrorResponse represents an error response.
 type ErrorResponse struct {
-	Error     string    `json:"error"`          // Error message
-	Code      string    `json:"code,omitempty"` // Error code (e.g., "RATE_NOT_FOUND")
-	Timestamp time.Time `json:"timestamp"`      // When the error occurred
+	Error     string        `json:"error"`             // Error message
+	Code      string        `json:"code,omitempty"`    // Error code (e.g., "RATE_NOT_FOUND")
+	Debug     string        `json:"debug,omitempty"`   // Internal error details (DEBUG_ERRORS, never in production)
+	Details   *ErrorDetails `json:"details,omitempty"` // Client-facing hints for handling the error
+	Timestamp time.Time     `json:"timestamp"`         // When the error occurred
+}
+
+// ErrorDetails holds optional client-facing hints in an error response.
+type ErrorDetails struct {
+	AvailableTargets []string `json:"available_targets,omitempty"` // Targets offered for the base, when the requested one is not
+}
+
+// TruncateTimestamp returns r with Timestamp truncated to a multiple of precision,
+// so responses built moments apart compare equal. A precision of 0 or less
+// leaves the timestamp unchanged.
+func (r RateResponse) TruncateTimestamp(precision time.Duration) RateResponse {
+	if precision > 0 {
+		r.Timestamp = r.Timestamp.Truncate(precision)
+	}
+	return r
+}
+
+// TruncateTimestamps returns r with its Timestamp and the Timestamp of every rate
+// truncated to a multiple of precision (see RateResponse.TruncateTimestamp).
+// The rates map is copied, not modified.
+func (r RatesResponse) TruncateTimestamps(precision time.Duration) RatesResponse {
+	if precision <= 0 {
+		return r
+	}
+	r.Timestamp = r.Timestamp.Truncate(precision)
+	if r.Rates != nil {
+		rates := make(map[string]RateResponse, len(r.Rates))
+		for target, rate := range r.Rates {
+			rates[target] = rate.TruncateTimestamp(precision)
+		}
+		r.Rates = rates
+	}
+	return r
+}
+
+// CacheSource returns where the rate came from.
+func (r RateResponse) CacheSource() string {
+	return r.Source
+}
+
+// CacheSource returns where the rates came from.
+func (r RatesResponse) CacheSource() string {
+	return r.Source
+}
+
+// SkippedCount returns the number of provider entries dropped as invalid.
+func (r RatesResponse) SkippedCount() int {
+	return r.Skipped
+}
+
+// CacheSkippedCount returns the number of cached entries that could not be read.
+func (r RatesResponse) CacheSkippedCount() int {
+	return r.CacheSkipped
 }
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateResponse_TruncateTimestamp(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 15, 4, 5, 678_000_000, time.UTC)
+
+	tests := []struct {
+		name      string
+		precision time.Duration
+		want      time.Time
+	}{
+		{"unchanged", 0, timestamp},
+		{"second", time.Second, time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"minute", time.Minute, time.Date(2025, 1, 2, 15, 4, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RateResponse{Base: "USD", Target: "EUR", Timestamp: timestamp}.TruncateTimestamp(tt.precision)
+			if !got.Timestamp.Equal(tt.want) {
+				t.Errorf("Timestamp = %v, want %v", got.Timestamp, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatesResponse_TruncateTimestamps(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 15, 4, 5, 678_000_000, time.UTC)
+	want := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	original := RatesResponse{
+		Base:      "USD",
+		Rates:     map[string]RateResponse{"EUR": {Base: "USD", Target: "EUR", Timestamp: timestamp}},
+		Timestamp: timestamp,
+	}
+
+	got := original.TruncateTimestamps(time.Second)
+	if !got.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", got.Timestamp, want)
+	}
+	if !got.Rates["EUR"].Timestamp.Equal(want) {
+		t.Errorf("Rates[EUR].Timestamp = %v, want %v", got.Rates["EUR"].Timestamp, want)
+	}
+	if !original.Rates["EUR"].Timestamp.Equal(timestamp) {
+		t.Errorf("original Rates[EUR].Timestamp = %v, want it unchanged", original.Rates["EUR"].Timestamp)
+	}
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/metrics"
+)
+
+// cacheSummaryInterval is how often the running cache totals are logged at Info.
+const cacheSummaryInterval = time.Minute
+
+// recordCacheResult counts how a request was served and logs it with the
+// running hit ratio, so cache effectiveness can be queried from the logs.
+//
+// Each result is logged at Debug, so busy deployments are not flooded at Info;
+// a summary of the totals is logged at Info at most once per cacheSummaryInterval.
+// Requests that fail after the cache lookup count as misses.
+func recordCacheResult(log *logger.Logger, counter *metrics.CacheCounter, result metrics.CacheResult) {
+	stats := counter.Record(result)
+	log.Debug("cache result",
+		"cache_result", string(result),
+		"cache_hit_ratio", stats.HitRatio(),
+		"cache_requests", stats.Total(),
+	)
+
+	if counter.SummaryDue(time.Now(), cacheSummaryInterval) {
+		log.Info("cache summary",
+			"cache_hits", stats.Hits,
+			"cache_misses", stats.Misses,
+			"cache_stale", stats.Stale,
+			"cache_hit_ratio", stats.HitRatio(),
+			"cache_requests", stats.Total(),
+		)
+	}
+}
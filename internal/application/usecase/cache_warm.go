@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// cacheWarmTimeout bounds caching the sibling rates of a single-pair fetch within a request.
+const cacheWarmTimeout = 2 * time.Second
+
+// warmCache saves the rates a provider downloaded alongside a single-pair fetch,
+// so later requests for those pairs hit the cache.
+//
+// Saves run inline because Lambda freezes the sandbox once the handler returns,
+// so background work may never finish. The requested pair (fetched) is skipped,
+// as it is saved by the caller. Rates are saved concurrently, at most concurrency
+// at a time (see saveRates). Saves are best-effort and bounded by
+// cacheWarmTimeout: failures are only logged.
+func warmCache(ctx context.Context, repo repository.ExchangeRateRepository, log *logger.Logger, rates []*entity.ExchangeRate, fetched *entity.ExchangeRate, ttl time.Duration, concurrency int) {
+	ctx, cancel := context.WithTimeout(ctx, cacheWarmTimeout)
+	defer cancel()
+
+	siblings := make([]*entity.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		if rate != nil && !(rate.Base.Equal(fetched.Base) && rate.Target.Equal(fetched.Target)) {
+			siblings = append(siblings, rate)
+		}
+	}
+	if len(siblings) == 0 {
+		return
+	}
+
+	if saveErrs := saveRates(ctx, repo, siblings, ttl, concurrency); len(saveErrs) > 0 {
+		log.Warn("failed to cache fetched rates",
+			"rates_count", len(siblings),
+			"failed", len(saveErrs),
+			"error", errors.Join(saveErrs...).Error(),
+		)
+		return
+	}
+	log.Info("cached rates fetched alongside the requested rate", "warmed", len(siblings))
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+const (
+	// expiredCleanupTimeout bounds the deletion of expired cached rates within a request.
+	expiredCleanupTimeout = 2 * time.Second
+
+	// maxExpiredDeletesPerRequest bounds the deletes added to one request;
+	// the remaining rates are deleted by later requests (or the cleanup function).
+	maxExpiredDeletesPerRequest = 25
+)
+
+// deleteExpiredRates deletes expired cached rates before the response is returned.
+//
+// Deletes run inline because Lambda freezes the sandbox once the handler returns,
+// so background work may never finish. At most maxExpiredDeletesPerRequest rates
+// are deleted, bounded by expiredCleanupTimeout. Deletes are best-effort: failures
+// are only logged, and a rate that is already gone is not an error.
+//
+// Returns the number of rates deleted.
+func deleteExpiredRates(ctx context.Context, repo repository.ExchangeRateRepository, log *logger.Logger, expired []*entity.ExchangeRate) int {
+	if len(expired) > maxExpiredDeletesPerRequest {
+		expired = expired[:maxExpiredDeletesPerRequest]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, expiredCleanupTimeout)
+	defer cancel()
+
+	deleted := 0
+	for i, rate := range expired {
+		if ctx.Err() != nil {
+			log.Warn("expired rate cleanup timed out",
+				"deleted", deleted,
+				"remaining", len(expired)-i,
+			)
+			break
+		}
+		if err := repo.Delete(ctx, rate.Base, rate.Target); err != nil && !errors.Is(err, entity.ErrRateNotFound) {
+			log.Warn("failed to delete expired cached rate",
+				"error", err.Error(),
+				"base", rate.Base.String(),
+				"target", rate.Target.String(),
+			)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
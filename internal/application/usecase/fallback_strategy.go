@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ResolutionStep is one source the use cases can serve a request from.
+type ResolutionStep string
+
+const (
+	// StepCache serves cached rates that are still within the cache TTL.
+	StepCache ResolutionStep = "cache"
+
+	// StepProvider fetches fresh rates from the provider and saves them to the cache.
+	StepProvider ResolutionStep = "provider"
+
+	// StepStaleCache serves expired cached rates, marked stale.
+	StepStaleCache ResolutionStep = "stale_cache"
+
+	// StepRecentStaleCache serves expired cached rates, marked stale, only if they
+	// expired at most the use case's FallbackMaxStale ago (0 = no bound).
+	StepRecentStaleCache ResolutionStep = "recent_stale_cache"
+)
+
+// Fallback strategy names accepted by ParseFallbackStrategy (FALLBACK_STRATEGY).
+const (
+	FallbackCacheFirst    = "cache-first"
+	FallbackStaleOK       = "stale-ok"
+	FallbackProviderFirst = "provider-first"
+)
+
+// FallbackStrategy decides the order in which the use cases try each ResolutionStep.
+// The first step that produces rates serves the request.
+type FallbackStrategy interface {
+	// Name returns the strategy name, for logging.
+	Name() string
+
+	// Steps returns the resolution steps in the order they are tried.
+	Steps() []ResolutionStep
+}
+
+// orderedStrategy is a FallbackStrategy with a fixed step order.
+type orderedStrategy struct {
+	name  string
+	steps []ResolutionStep
+}
+
+// Name implements FallbackStrategy.
+func (s orderedStrategy) Name() string {
+	return s.name
+}
+
+// Steps implements FallbackStrategy.
+func (s orderedStrategy) Steps() []ResolutionStep {
+	return slices.Clone(s.steps)
+}
+
+// CacheFirstStrategy serves valid cache, then the provider, then stale cache.
+// This minimizes provider calls while never serving expired rates the provider could refresh.
+func CacheFirstStrategy() FallbackStrategy {
+	return orderedStrategy{FallbackCacheFirst, []ResolutionStep{StepCache, StepProvider, StepStaleCache}}
+}
+
+// StaleOKStrategy serves cached rates, expired ones marked stale, and only calls
+// the provider when nothing is cached or the cached rates expired more than
+// FallbackMaxStale ago. This minimizes provider cost. Rates too old to serve
+// first are still served as a last resort if the provider fails, unless its
+// circuit breaker is open.
+func StaleOKStrategy() FallbackStrategy {
+	return orderedStrategy{FallbackStaleOK, []ResolutionStep{StepCache, StepRecentStaleCache, StepProvider, StepStaleCache}}
+}
+
+// ProviderFirstStrategy always tries the provider, falling back to valid cache,
+// then stale cache, when it fails.
+func ProviderFirstStrategy() FallbackStrategy {
+	return orderedStrategy{FallbackProviderFirst, []ResolutionStep{StepProvider, StepCache, StepStaleCache}}
+}
+
+// ParseFallbackStrategy returns the strategy with the given name.
+// An empty name selects CacheFirstStrategy.
+//
+// Returns an error if the name is not one of the Fallback* constants.
+func ParseFallbackStrategy(name string) (FallbackStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", FallbackCacheFirst:
+		return CacheFirstStrategy(), nil
+	case FallbackStaleOK:
+		return StaleOKStrategy(), nil
+	case FallbackProviderFirst:
+		return ProviderFirstStrategy(), nil
+	default:
+		return nil, fmt.Errorf("unknown fallback strategy %q (want %s, %s, or %s)",
+			name, FallbackCacheFirst, FallbackStaleOK, FallbackProviderFirst)
+	}
+}
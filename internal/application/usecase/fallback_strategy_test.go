@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// callLog records repository and provider calls in order.
+type callLog []string
+
+// recordingMocks returns a repository and provider that log their calls.
+// The cached rate is valid when cacheValid is set, expired otherwise, and absent
+// when cached is false; the provider fails when providerErr is set.
+func recordingMocks(calls *callLog, cached, cacheValid bool, providerErr error) (*mockRepository, *mockProvider) {
+	timestamp := time.Now().Add(-2 * time.Hour)
+	if cacheValid {
+		timestamp = time.Now().Add(-30 * time.Minute)
+	}
+	cachedRate := func(base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+		if !cached {
+			return nil, entity.ErrRateNotFound
+		}
+		return entity.NewExchangeRate(base, target, 0.85, timestamp, false)
+	}
+
+	repo := &mockRepository{
+		getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			*calls = append(*calls, "Get")
+			return cachedRate(base, target)
+		},
+		getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+			*calls = append(*calls, "GetByBase")
+			rate, err := cachedRate(base, "EUR")
+			if err != nil {
+				return []*entity.ExchangeRate{}, nil
+			}
+			return []*entity.ExchangeRate{rate}, nil
+		},
+		getStaleFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			*calls = append(*calls, "GetStale")
+			return cachedRate(base, target)
+		},
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			*calls = append(*calls, "Save")
+			return nil
+		},
+	}
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			*calls = append(*calls, "FetchRate")
+			if providerErr != nil {
+				return nil, providerErr
+			}
+			return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+		},
+		fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+			*calls = append(*calls, "FetchAllRates")
+			if providerErr != nil {
+				return nil, providerErr
+			}
+			rate, _ := entity.NewExchangeRate(base, "EUR", 0.86, time.Now(), false)
+			return []*entity.ExchangeRate{rate}, nil
+		},
+	}
+	return repo, prov
+}
+
+func TestFallbackStrategy_RequestOrdering(t *testing.T) {
+	errAPI := errors.New("API error")
+
+	tests := []struct {
+		name         string
+		strategy     FallbackStrategy
+		maxStale     time.Duration
+		cached       bool
+		cacheValid   bool
+		providerErr  error
+		wantRate     []string
+		wantAllRates []string
+		wantSource   string
+	}{
+		{
+			name:         "cache-first serves valid cache",
+			strategy:     CacheFirstStrategy(),
+			cached:       true,
+			cacheValid:   true,
+			wantRate:     []string{"Get"},
+			wantAllRates: []string{"GetByBase"},
+			wantSource:   dto.SourceCache,
+		},
+		{
+			name:         "cache-first refreshes expired cache",
+			strategy:     CacheFirstStrategy(),
+			cached:       true,
+			wantRate:     []string{"Get", "FetchRate", "Save"},
+			wantAllRates: []string{"GetByBase", "FetchAllRates", "Save"},
+			wantSource:   dto.SourceProvider,
+		},
+		{
+			name:         "cache-first falls back to stale cache",
+			strategy:     CacheFirstStrategy(),
+			cached:       true,
+			providerErr:  errAPI,
+			wantRate:     []string{"Get", "FetchRate"},
+			wantAllRates: []string{"GetByBase", "FetchAllRates"},
+			wantSource:   dto.SourceStaleCache,
+		},
+		{
+			name:         "stale-ok serves expired cache without the provider",
+			strategy:     StaleOKStrategy(),
+			cached:       true,
+			wantRate:     []string{"Get"},
+			wantAllRates: []string{"GetByBase"},
+			wantSource:   dto.SourceStaleCache,
+		},
+		{
+			name:         "stale-ok refreshes cache expired beyond max staleness",
+			strategy:     StaleOKStrategy(),
+			maxStale:     30 * time.Minute,
+			cached:       true,
+			wantRate:     []string{"Get", "FetchRate", "Save"},
+			wantAllRates: []string{"GetByBase", "FetchAllRates", "Save"},
+			wantSource:   dto.SourceProvider,
+		},
+		{
+			name:         "stale-ok serves cache within max staleness",
+			strategy:     StaleOKStrategy(),
+			maxStale:     2 * time.Hour,
+			cached:       true,
+			wantRate:     []string{"Get"},
+			wantAllRates: []string{"GetByBase"},
+			wantSource:   dto.SourceStaleCache,
+		},
+		{
+			name:         "stale-ok serves cache beyond max staleness if the provider fails",
+			strategy:     StaleOKStrategy(),
+			maxStale:     30 * time.Minute,
+			cached:       true,
+			providerErr:  errAPI,
+			wantRate:     []string{"Get", "FetchRate"},
+			wantAllRates: []string{"GetByBase", "FetchAllRates"},
+			wantSource:   dto.SourceStaleCache,
+		},
+		{
+			name:         "stale-ok calls the provider on cache miss",
+			strategy:     StaleOKStrategy(),
+			wantRate:     []string{"Get", "FetchRate", "Save"},
+			wantAllRates: []string{"GetByBase", "FetchAllRates", "Save"},
+			wantSource:   dto.SourceProvider,
+		},
+		{
+			name:         "provider-first skips valid cache",
+			strategy:     ProviderFirstStrategy(),
+			cached:       true,
+			cacheValid:   true,
+			wantRate:     []string{"FetchRate", "Save"},
+			wantAllRates: []string{"FetchAllRates", "Save"},
+			wantSource:   dto.SourceProvider,
+		},
+		{
+			name:         "provider-first falls back to valid cache",
+			strategy:     ProviderFirstStrategy(),
+			cached:       true,
+			cacheValid:   true,
+			providerErr:  errAPI,
+			wantRate:     []string{"FetchRate", "Get"},
+			wantAllRates: []string{"FetchAllRates", "GetByBase"},
+			wantSource:   dto.SourceCache,
+		},
+		{
+			name:         "provider-first falls back to stale cache",
+			strategy:     ProviderFirstStrategy(),
+			cached:       true,
+			providerErr:  errAPI,
+			wantRate:     []string{"FetchRate", "Get"},
+			wantAllRates: []string{"FetchAllRates", "GetByBase"},
+			wantSource:   dto.SourceStaleCache,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls callLog
+			repo, prov := recordingMocks(&calls, tt.cached, tt.cacheValid, tt.providerErr)
+			rateConfig := GetExchangeRateConfig{FallbackStrategy: tt.strategy, FallbackMaxStale: tt.maxStale}
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, rateConfig, nil)
+
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if err != nil {
+				t.Fatalf("GetExchangeRate Execute() error = %v", err)
+			}
+			if !slices.Equal(calls, tt.wantRate) {
+				t.Errorf("GetExchangeRate calls = %v, want %v", calls, tt.wantRate)
+			}
+			if resp.Source != tt.wantSource {
+				t.Errorf("GetExchangeRate Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+
+			calls = nil
+			allRatesConfig := GetAllRatesConfig{FallbackStrategy: tt.strategy, FallbackMaxStale: tt.maxStale}
+			allUC := NewGetAllRatesUseCaseWithConfig(repo, prov, time.Hour, allRatesConfig, nil)
+
+			allResp, err := allUC.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+			if err != nil {
+				t.Fatalf("GetAllRates Execute() error = %v", err)
+			}
+			if !slices.Equal(calls, tt.wantAllRates) {
+				t.Errorf("GetAllRates calls = %v, want %v", calls, tt.wantAllRates)
+			}
+			if allResp.Source != tt.wantSource {
+				t.Errorf("GetAllRates Source = %q, want %q", allResp.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestFallbackStrategy_AllStepsFail(t *testing.T) {
+	errAPI := errors.New("API error")
+
+	for _, strategy := range []FallbackStrategy{CacheFirstStrategy(), StaleOKStrategy(), ProviderFirstStrategy()} {
+		t.Run(strategy.Name(), func(t *testing.T) {
+			var calls callLog
+			repo, prov := recordingMocks(&calls, false, false, errAPI)
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, GetExchangeRateConfig{FallbackStrategy: strategy}, nil)
+
+			_, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if !errors.Is(err, errAPI) {
+				t.Errorf("Execute() error = %v, want %v", err, errAPI)
+			}
+			// The cache is read once, however many steps consult it
+			if got := slices.Index(calls, "Get"); got < 0 || slices.Index(calls[got+1:], "Get") >= 0 {
+				t.Errorf("calls = %v, want exactly one Get", calls)
+			}
+		})
+	}
+}
+
+func TestParseFallbackStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", FallbackCacheFirst, false},
+		{"cache-first", FallbackCacheFirst, false},
+		{"Stale-OK", FallbackStaleOK, false},
+		{" provider-first ", FallbackProviderFirst, false},
+		{"random", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := ParseFallbackStrategy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFallbackStrategy(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && strategy.Name() != tt.want {
+				t.Errorf("ParseFallbackStrategy(%q) = %q, want %q", tt.name, strategy.Name(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/pkg/metrics"
+)
+
+const (
+	// fetchLockPollInterval is how often the cache is polled while another
+	// instance holds the fetch lock.
+	fetchLockPollInterval = 100 * time.Millisecond
+
+	// fetchLockReleaseTimeout bounds the release of a fetch lock, which runs
+	// even if the request context is already done.
+	fetchLockReleaseTimeout = 2 * time.Second
+)
+
+// acquireFetchLock takes the pair's FetchLock before a provider fetch.
+//
+// If another instance holds the lock, the cache is polled every
+// fetchLockPollInterval for up to FetchLockWait for the rate it is fetching.
+// A rate found within the cache TTL is returned with served=true, and no
+// provider call is made.
+//
+// Locking is best effort: if the lock cannot be acquired because of an error,
+// or the holder saves no rate in time (e.g. it crashed), the caller fetches
+// anyway. The returned release func must be called after the fetch; it is a
+// no-op unless the lock was taken.
+func (uc *GetExchangeRateUseCase) acquireFetchLock(ctx context.Context, res *rateResolution) (release func(), resp dto.RateResponse, served bool) {
+	noop := func() {}
+	lock := uc.config.FetchLock
+	log := uc.logger.WithContext(ctx)
+
+	acquired, err := lock.Acquire(ctx, res.base, res.target)
+	if err != nil {
+		log.Warn("failed to acquire fetch lock, fetching without it", "error", err.Error())
+		return noop, dto.RateResponse{}, false
+	}
+	if acquired {
+		return func() {
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchLockReleaseTimeout)
+			defer cancel()
+			if err := lock.Release(releaseCtx, res.base, res.target); err != nil {
+				log.Warn("failed to release fetch lock", "error", err.Error())
+			}
+		}, dto.RateResponse{}, false
+	}
+
+	log.Debug("fetch lock held by another instance, waiting for its rate",
+		"fetch_lock_wait", uc.config.FetchLockWait.String(),
+	)
+	deadline := time.Now().Add(uc.config.FetchLockWait)
+	ticker := time.NewTicker(fetchLockPollInterval)
+	defer ticker.Stop()
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return noop, dto.RateResponse{}, false
+		case <-ticker.C:
+		}
+		rate, err := uc.repository.Get(ctx, res.base, res.target)
+		if err != nil || rate == nil || !rate.IsValid(uc.ttl()) {
+			continue
+		}
+		log.Info("serving rate cached by the fetch lock holder", "rate", rate.Rate)
+		resp := dto.ToRateResponse(rate)
+		resp.Source = dto.SourceCache
+		recordCacheResult(log, uc.cacheCounter, metrics.CacheHit)
+		return noop, resp, true
+	}
+
+	log.Warn("fetch lock holder saved no rate in time, fetching anyway",
+		"fetch_lock_wait", uc.config.FetchLockWait.String(),
+	)
+	return noop, dto.RateResponse{}, false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
@@ -12,15 +13,48 @@ import (
 	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/metrics"
 )
 
 // GetAllRatesUseCase handles the use case for getting all exchange rates for a base currency.
 // This implements UC2 from the specification.
 type GetAllRatesUseCase struct {
-	repository repository.ExchangeRateRepository
-	provider   provider.ExchangeRateProvider
-	cacheTTL   time.Duration // TTL for cached rates
-	logger     *logger.Logger
+	repository   repository.ExchangeRateRepository
+	provider     provider.ExchangeRateProvider
+	cacheTTL     atomic.Int64 // TTL for cached rates in nanoseconds; see SetCacheTTL
+	config       GetAllRatesConfig
+	logger       *logger.Logger
+	cacheCounter *metrics.CacheCounter // Counts cache hits, misses, and stale fallbacks
+}
+
+// GetAllRatesConfig holds optional behavior settings for GetAllRatesUseCase.
+type GetAllRatesConfig struct {
+	ExpiredCleanupGrace time.Duration                 // Delete cached rates expired for longer than this (0 = disabled)
+	FallbackStrategy    FallbackStrategy              // Order of cache, provider, and stale cache lookups (nil = cache-first)
+	FallbackMaxStale    time.Duration                 // How long past the TTL StepRecentStaleCache, and the circuit-open stale fallback, still serve rates (0 = no bound)
+	MinRates            int                           // Fresh responses with fewer rates are suspect, and stale sets with fewer are not served (0 = disabled)
+	AbsoluteMaxAge      time.Duration                 // Never serve stale rates older than this; if none are left, the request fails with ErrProviderUnavailable (0 = no cap)
+	SaveConcurrency     int                           // Maximum concurrent cache saves of fetched rates (below 1 = sequential)
+	EmbeddedFallback    provider.ExchangeRateProvider // Bundled static rates served when every step fails (nil = disabled)
+}
+
+// DefaultGetAllRatesConfig returns the default use case configuration.
+//
+// Default values:
+// - ExpiredCleanupGrace: 0 (expired rates are left to DynamoDB TTL)
+// - FallbackStrategy: CacheFirstStrategy()
+// - FallbackMaxStale: 6h (stale-ok refreshes rates expired for longer than 6 hours)
+// - MinRates: 0 (any non-error provider response and any stale set is accepted)
+// - AbsoluteMaxAge: 0 (stale fallbacks are not capped by age)
+// - SaveConcurrency: 10
+// - EmbeddedFallback: nil (requests fail when every step fails)
+func DefaultGetAllRatesConfig() GetAllRatesConfig {
+	return GetAllRatesConfig{
+		ExpiredCleanupGrace: 0,
+		FallbackStrategy:    CacheFirstStrategy(),
+		FallbackMaxStale:    6 * time.Hour,
+		SaveConcurrency:     10,
+	}
 }
 
 // NewGetAllRatesUseCase creates a new GetAllRatesUseCase with dependency injection.
@@ -29,51 +63,480 @@ func NewGetAllRatesUseCase(
 	prov provider.ExchangeRateProvider,
 	cacheTTL time.Duration,
 	log *logger.Logger,
+) *GetAllRatesUseCase {
+	return NewGetAllRatesUseCaseWithConfig(repo, prov, cacheTTL, DefaultGetAllRatesConfig(), log)
+}
+
+// NewGetAllRatesUseCaseWithConfig creates a new GetAllRatesUseCase with custom configuration.
+func NewGetAllRatesUseCaseWithConfig(
+	repo repository.ExchangeRateRepository,
+	prov provider.ExchangeRateProvider,
+	cacheTTL time.Duration,
+	config GetAllRatesConfig,
+	log *logger.Logger,
 ) *GetAllRatesUseCase {
 	if log == nil {
 		log = logger.NewFromEnv()
 	}
-	return &GetAllRatesUseCase{
-		repository: repo,
-		provider:   prov,
-		cacheTTL:   cacheTTL,
-		logger:     log,
+	if config.FallbackStrategy == nil {
+		config.FallbackStrategy = CacheFirstStrategy()
+	}
+	uc := &GetAllRatesUseCase{
+		repository:   repo,
+		provider:     prov,
+		config:       config,
+		logger:       log,
+		cacheCounter: metrics.DefaultCacheCounter(),
+	}
+	uc.cacheTTL.Store(int64(cacheTTL))
+	return uc
+}
+
+// SetCacheTTL replaces the TTL of cached rates, e.g. on a config reload.
+// Requests already in flight may still use the previous TTL.
+// This method is thread-safe.
+func (uc *GetAllRatesUseCase) SetCacheTTL(ttl time.Duration) {
+	uc.cacheTTL.Store(int64(ttl))
+}
+
+// ttl returns the current TTL of cached rates.
+func (uc *GetAllRatesUseCase) ttl() time.Duration {
+	return time.Duration(uc.cacheTTL.Load())
+}
+
+// cleanupExpired deletes cached rates that expired more than ExpiredCleanupGrace ago
+// and were not replaced by freshRates.
+//
+// DynamoDB TTL deletion can lag by up to 48 hours. Until then, rates the provider
+// no longer returns keep showing up in GetByBase, slowing the query and forcing a
+// re-fetch on every request. Rates that were just re-fetched are skipped, since
+// Save has already overwritten them.
+//
+// Deletes run inline and bounded (see deleteExpiredRates); failures are only logged.
+func (uc *GetAllRatesUseCase) cleanupExpired(ctx context.Context, cachedRates, freshRates []*entity.ExchangeRate) {
+	if uc.config.ExpiredCleanupGrace <= 0 || uc.ttl() <= 0 {
+		return
+	}
+
+	refreshed := make(map[entity.CurrencyCode]bool, len(freshRates))
+	for _, rate := range freshRates {
+		if rate != nil {
+			refreshed[rate.Target] = true
+		}
+	}
+
+	var expired []*entity.ExchangeRate
+	for _, rate := range cachedRates {
+		if rate != nil && !refreshed[rate.Target] && rate.IsExpired(uc.ttl()+uc.config.ExpiredCleanupGrace) {
+			expired = append(expired, rate)
+		}
+	}
+	if len(expired) == 0 {
+		return
+	}
+
+	log := uc.logger.WithContext(ctx)
+	log.Info("deleting cached rates expired beyond grace period",
+		"count", len(expired),
+		"grace_period", uc.config.ExpiredCleanupGrace.String(),
+	)
+
+	deleteExpiredRates(ctx, uc.repository, log, expired)
+}
+
+// ratesResolution holds the state of resolving one all-rates request
+// across resolution steps.
+type ratesResolution struct {
+	base         entity.CurrencyCode
+	cached       []*entity.ExchangeRate // Cached rates (valid or expired)
+	cacheSkipped int                    // Cached items that could not be read
+	cacheLoaded  bool                   // Whether the cache has been read
+	providerErr  error                  // Error from the provider step, nil if not tried or successful
+	tooOld       bool                   // Whether every stale rate was refused for exceeding AbsoluteMaxAge
+	thinStale    int                    // Size of a stale set refused for having fewer than MinRates rates (0 = none)
+}
+
+// loadCached reads the cached rates once per request; later steps reuse them.
+// A partial result is used with a warning.
+func (uc *GetAllRatesUseCase) loadCached(ctx context.Context, res *ratesResolution) []*entity.ExchangeRate {
+	if res.cacheLoaded {
+		return res.cached
+	}
+	res.cacheLoaded = true
+
+	log := uc.logger.WithContext(ctx)
+	log.Debug("checking cache for exchange rates")
+	cachedRates, err := uc.repository.GetByBase(ctx, res.base)
+	var partial *repository.PartialResultError
+	if errors.As(err, &partial) {
+		// Some cached rates could not be read - serve the rest rather than failing
+		cachedRates, res.cacheSkipped, err = partial.Rates, partial.Skipped, nil
+		log.Warn("cache returned a partial result",
+			"rates_count", len(cachedRates),
+			"cache_skipped", res.cacheSkipped,
+		)
+	}
+	if err != nil {
+		log.Debug("cache check error", "error", err.Error())
+		return nil
+	}
+	res.cached = cachedRates
+	return cachedRates
+}
+
+// loadStale reads the cached rates with GetStaleByBase for the circuit-open
+// fallback, excluding rates that expired more than maxStale ago. A maxStale of 0
+// falls back to FallbackMaxStale. AbsoluteMaxAge, if set, caps the staleness
+// further. A partial result is used with a warning.
+func (uc *GetAllRatesUseCase) loadStale(ctx context.Context, res *ratesResolution, maxStale time.Duration) []*entity.ExchangeRate {
+	if maxStale <= 0 {
+		maxStale = uc.config.FallbackMaxStale
+	}
+	var maxStaleness time.Duration
+	if maxStale > 0 {
+		maxStaleness = uc.ttl() + maxStale
+	}
+	if maxAge := uc.config.AbsoluteMaxAge; maxAge > 0 && (maxStaleness <= 0 || maxAge < maxStaleness) {
+		maxStaleness = maxAge
+	}
+
+	log := uc.logger.WithContext(ctx)
+	staleRates, err := uc.repository.GetStaleByBase(ctx, res.base, maxStaleness)
+	var partial *repository.PartialResultError
+	if errors.As(err, &partial) {
+		staleRates, res.cacheSkipped, err = partial.Rates, partial.Skipped, nil
+		log.Warn("stale cache returned a partial result",
+			"rates_count", len(staleRates),
+			"cache_skipped", res.cacheSkipped,
+		)
+	}
+	if err != nil {
+		log.Debug("stale cache check error", "error", err.Error())
+		return nil
 	}
+	return staleRates
+}
+
+// resolveFromCache serves the cached rates if there are any and all are still valid.
+func (uc *GetAllRatesUseCase) resolveFromCache(ctx context.Context, res *ratesResolution, startTime time.Time) (dto.RatesResponse, bool) {
+	cachedRates := uc.loadCached(ctx, res)
+	if len(cachedRates) == 0 {
+		return dto.RatesResponse{}, false
+	}
+	log := uc.logger.WithContext(ctx)
+	for _, rate := range cachedRates {
+		if rate != nil && !rate.IsValid(uc.ttl()) {
+			log.Debug("some cached rates expired, trying next step")
+			return dto.RatesResponse{}, false
+		}
+	}
+
+	log.Info("cache hit, returning cached rates",
+		"rates_count", len(cachedRates),
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+	resp := dto.ToRatesResponse(cachedRates)
+	resp.Source = dto.SourceCache
+	resp.CacheSkipped = res.cacheSkipped
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheHit)
+	return resp, true
+}
+
+// resolveFromProvider fetches all rates, saves them to the cache, and deletes
+// cached rates expired beyond the grace period (if enabled).
+//
+// Provider errors are kept in res for the stale cache step and the final error.
+//
+// A response with fewer than MinRates rates (e.g. a truncated upstream file) is
+// suspect: if the cache holds more rates no older than AbsoluteMaxAge, those are
+// served instead and the suspect rates are not saved. Otherwise the thin response
+// is served as usual.
+//
+// Rates are saved concurrently, at most SaveConcurrency at a time (see saveRates).
+// Save failures are logged and do not fail the request.
+//
+// Note: This fetches all rates from the provider even if only some cached rates expired.
+// In a production system, you might want to check which rates are missing/expired
+// and only fetch those, but for simplicity, we fetch all rates.
+func (uc *GetAllRatesUseCase) resolveFromProvider(ctx context.Context, res *ratesResolution, startTime time.Time) (dto.RatesResponse, bool) {
+	log := uc.logger.WithContext(ctx)
+	log.Debug("fetching rates from external API")
+	fetchCtx, fetchStats := provider.WithFetchStats(ctx)
+	freshRates, err := uc.provider.FetchAllRates(fetchCtx, res.base)
+	if err != nil {
+		res.providerErr = err
+		return dto.RatesResponse{}, false
+	}
+
+	if uc.config.MinRates > 0 && len(freshRates) < uc.config.MinRates {
+		cachedRates, tooOld := uc.withinMaxAge(uc.loadCached(ctx, res))
+		log.Warn("provider returned fewer rates than expected",
+			"rates_count", len(freshRates),
+			"min_rates", uc.config.MinRates,
+			"cached_count", len(cachedRates),
+			"cached_too_old", tooOld,
+		)
+		if len(cachedRates) > len(freshRates) {
+			return uc.serveRicherCache(ctx, res, cachedRates), true
+		}
+	}
+
+	// Save all rates to cache
+	if saveErrs := saveRates(ctx, uc.repository, freshRates, uc.ttl(), uc.config.SaveConcurrency); len(saveErrs) > 0 {
+		log.Warn("failed to save rates to cache",
+			"failed", len(saveErrs),
+			"error", errors.Join(saveErrs...).Error(),
+		)
+	}
+
+	// Stop re-reading rates that DynamoDB TTL has not removed yet
+	// (only rates already read by an earlier cache step are considered)
+	uc.cleanupExpired(ctx, res.cached, freshRates)
+
+	// Surface entries the provider dropped while parsing
+	skipped := fetchStats.Skipped()
+	if skipped > 0 {
+		log.Warn("provider skipped invalid rates",
+			"skipped", skipped,
+			"skip_reasons", fetchStats.SkipReasons(),
+		)
+	}
+
+	log.Info("successfully fetched rates from API",
+		"rates_count", len(freshRates),
+		"skipped", skipped,
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+	resp := dto.ToRatesResponse(freshRates)
+	resp.Source = dto.SourceProvider
+	resp.Skipped = skipped
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheMiss)
+	return resp, true
+}
+
+// serveRicherCache serves the cached rates in place of a suspect provider response.
+// Rates still within the cache TTL are served as-is; expired rates are marked stale.
+func (uc *GetAllRatesUseCase) serveRicherCache(ctx context.Context, res *ratesResolution, cachedRates []*entity.ExchangeRate) dto.RatesResponse {
+	rates := make([]*entity.ExchangeRate, 0, len(cachedRates))
+	stale := false
+	for _, rate := range cachedRates {
+		if rate == nil {
+			continue
+		}
+		if rate.IsValid(uc.ttl()) {
+			rates = append(rates, rate)
+			continue
+		}
+		if staleRate, err := staleCopy(rate); err == nil {
+			rates = append(rates, staleRate)
+			stale = true
+		}
+	}
+
+	log := uc.logger.WithContext(ctx)
+	log.Warn("serving cached rates instead of a thin provider response",
+		"rates_count", len(rates),
+		"stale", stale,
+	)
+	resp := dto.ToRatesResponse(rates)
+	resp.CacheSkipped = res.cacheSkipped
+	if stale {
+		resp.Source = dto.SourceStaleCache
+		recordCacheResult(log, uc.cacheCounter, metrics.CacheStale)
+	} else {
+		resp.Source = dto.SourceCache
+		recordCacheResult(log, uc.cacheCounter, metrics.CacheHit)
+	}
+	return resp
+}
+
+// withinMaxAge returns the rates no older than AbsoluteMaxAge (all rates if it
+// is 0), and how many were dropped.
+func (uc *GetAllRatesUseCase) withinMaxAge(rates []*entity.ExchangeRate) ([]*entity.ExchangeRate, int) {
+	maxAge := uc.config.AbsoluteMaxAge
+	if maxAge <= 0 {
+		return rates, 0
+	}
+	kept := make([]*entity.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		if rate != nil && rate.Age() <= maxAge {
+			kept = append(kept, rate)
+		}
+	}
+	return kept, len(rates) - len(kept)
+}
+
+// staleCopy returns a copy of rate marked stale.
+func staleCopy(rate *entity.ExchangeRate) (*entity.ExchangeRate, error) {
+	staleRate, err := entity.NewExchangeRate(
+		rate.Base,
+		rate.Target,
+		rate.Rate,
+		rate.Timestamp,
+		true, // Mark as stale
+	)
+	if err != nil {
+		return nil, err
+	}
+	staleRate.Bid, staleRate.Ask = rate.Bid, rate.Ask
+	staleRate.Derived = rate.Derived
+	return staleRate, nil
+}
+
+// resolveFromStaleCache serves the cached rates, all marked stale.
+//
+// If the circuit breaker is open, the rates are read with GetStaleByBase(),
+// which excludes rates past the staleness cap (see loadStale), and the rest
+// are served. Otherwise the rates read by the cache step are reused,
+// and nothing is served if any of them expired more than maxStale ago.
+// Rates older than AbsoluteMaxAge are dropped (e.g. a currency the upstream
+// no longer returns); nothing is served if none are left, or if fewer than
+// MinRates rates are left: a thin stale set (e.g. 3 of the usual 150 rates)
+// would misleadingly look like the full set.
+func (uc *GetAllRatesUseCase) resolveFromStaleCache(ctx context.Context, res *ratesResolution, maxStale time.Duration) (dto.RatesResponse, bool) {
+	log := uc.logger.WithContext(ctx)
+	var cachedRates []*entity.ExchangeRate
+	if errors.Is(res.providerErr, circuitbreaker.ErrCircuitOpen) {
+		log.Warn("circuit breaker is open, attempting stale cache fallback")
+		// Circuit is open - explicitly use GetStaleByBase() for fallback
+		cachedRates = uc.loadStale(ctx, res, maxStale)
+	} else {
+		if res.providerErr != nil {
+			log.Warn("provider error, falling back to stale cache",
+				"error", res.providerErr.Error(),
+			)
+		}
+		cachedRates = uc.loadCached(ctx, res)
+	}
+	for _, rate := range cachedRates {
+		if rate != nil && maxStale > 0 && rate.IsExpired(uc.ttl()+maxStale) {
+			log.Debug("cached rates expired beyond max staleness, trying next step",
+				"max_stale", maxStale.String(),
+			)
+			return dto.RatesResponse{}, false
+		}
+	}
+	cachedRates, tooOld := uc.withinMaxAge(cachedRates)
+	if tooOld > 0 {
+		log.Warn("dropping cached rates older than absolute max age",
+			"too_old", tooOld,
+			"rates_count", len(cachedRates),
+			"absolute_max_age", uc.config.AbsoluteMaxAge.String(),
+		)
+	}
+	staleRates := make([]*entity.ExchangeRate, 0, len(cachedRates))
+	for _, rate := range cachedRates {
+		if rate == nil {
+			continue
+		}
+		if staleRate, staleErr := staleCopy(rate); staleErr == nil {
+			staleRates = append(staleRates, staleRate)
+		}
+	}
+	if len(staleRates) == 0 {
+		if tooOld > 0 {
+			res.tooOld = true
+		}
+		return dto.RatesResponse{}, false
+	}
+	if uc.config.MinRates > 0 && len(staleRates) < uc.config.MinRates {
+		log.Warn("stale cache has fewer rates than expected, refusing stale fallback",
+			"rates_count", len(staleRates),
+			"min_rates", uc.config.MinRates,
+		)
+		res.thinStale = len(staleRates)
+		return dto.RatesResponse{}, false
+	}
+
+	log.Info("returning stale cache as fallback",
+		"rates_count", len(staleRates),
+		"stale", true,
+	)
+	resp := dto.ToRatesResponse(staleRates)
+	resp.Source = dto.SourceStaleCache
+	resp.CacheSkipped = res.cacheSkipped
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheStale)
+	return resp, true
+}
+
+// resolveFromEmbedded serves the rates from EmbeddedFallback once every resolution
+// step failed, all marked stale and approximate. It is meant for development and
+// demo deployments only.
+func (uc *GetAllRatesUseCase) resolveFromEmbedded(ctx context.Context, res *ratesResolution) (dto.RatesResponse, bool) {
+	if uc.config.EmbeddedFallback == nil {
+		return dto.RatesResponse{}, false
+	}
+	log := uc.logger.WithContext(ctx)
+	rates, err := uc.config.EmbeddedFallback.FetchAllRates(ctx, res.base)
+	if err != nil {
+		log.Debug("embedded fallback has no rates for base", "error", err.Error())
+		return dto.RatesResponse{}, false
+	}
+	staleRates := make([]*entity.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		if rate == nil {
+			continue
+		}
+		if staleRate, staleErr := staleCopy(rate); staleErr == nil {
+			staleRates = append(staleRates, staleRate)
+		}
+	}
+	if len(staleRates) == 0 {
+		return dto.RatesResponse{}, false
+	}
+
+	log.Warn("serving approximate rates from embedded fallback",
+		"rates_count", len(staleRates),
+	)
+	resp := dto.ToRatesResponse(staleRates)
+	resp.Approximate = true
+	for target, rate := range resp.Rates {
+		rate.Approximate = true
+		resp.Rates[target] = rate
+	}
+	resp.Source = dto.SourceEmbedded
+	if source := provider.FetchSourceFromContext(ctx); source != nil {
+		source.Record(dto.SourceEmbedded, "")
+	}
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheStale)
+	return resp, true
 }
 
 // Execute executes the use case to get all exchange rates for a base currency.
 //
 // Flow:
 // 1. Validate base currency code
-// 2. Check cache (repository.GetByBase)
-// 3. If cache hit and all valid → return all cached rates
-// 4. If cache miss or some expired → fetch from external API
-// 5. Cache all rates
-// 6. Return rates to client
+// 2. Try each step of the configured FallbackStrategy in order
+// 3. Return the first rates found, or an error if every step failed
 //
-// Fallback Strategy:
-// - If circuit breaker is open (ErrCircuitOpen) → return stale cached rates
-// - If other provider error → fallback to stale cached rates (if available)
-// - If both unavailable → return error
+// Resolution steps:
+// - StepCache: cached rates if all are within the cache TTL (repository.GetByBase); a partial result is used with a warning
+// - StepProvider: all rates from the external API, saved to the cache
+// - StepStaleCache: cached rates marked stale; with the circuit breaker open, rates past FallbackMaxStale or AbsoluteMaxAge are dropped
+// - StepRecentStaleCache: like StepStaleCache, but only if every rate expired at most FallbackMaxStale ago
 //
-// Cache-First Strategy:
-// - Always check cache before external API
+// Stale steps drop rates older than AbsoluteMaxAge and never serve fewer than
+// MinRates rates; if none were left or too few, and no step succeeded,
+// provider.ErrProviderUnavailable is returned.
+//
+// If every step failed and EmbeddedFallback is set, its rates are served marked
+// stale and approximate (see resolveFromEmbedded).
+//
+// The default cache-first strategy tries cache → provider → stale cache:
 // - Reduces external API calls (>80% reduction)
 // - Faster response times (<200ms for cached)
 //
-// Note: This implementation fetches all rates from the provider if cache miss.
-// In a production system, you might want to check which rates are missing/expired
-// and only fetch those, but for simplicity, we fetch all rates.
+// Every request logs cache_result (hit, miss, stale) and the running cache_hit_ratio.
 func (uc *GetAllRatesUseCase) Execute(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
 	startTime := time.Now()
-	log := uc.logger.WithContext(ctx)
 
 	// Add base currency to context for logging
 	ctx = logger.WithCurrencyCodes(ctx, req.Base, "")
-	log = uc.logger.WithContext(ctx)
+	log := uc.logger.WithContext(ctx)
 
 	log.Debug("executing get all rates use case",
 		"base", req.Base,
+		"fallback_strategy", uc.config.FallbackStrategy.Name(),
 	)
 
 	// Validate base currency code
@@ -83,124 +546,57 @@ func (uc *GetAllRatesUseCase) Execute(ctx context.Context, req dto.GetRatesReque
 		return dto.RatesResponse{}, fmt.Errorf("invalid base currency: %w", err)
 	}
 
-	// Step 1: Check cache
-	log.Debug("checking cache for exchange rates")
-	cachedRates, err := uc.repository.GetByBase(ctx, base)
-	if err == nil && len(cachedRates) > 0 {
-		// Check if all cached rates are still valid
-		allValid := true
-		for _, rate := range cachedRates {
-			if rate != nil && !rate.IsValid(uc.cacheTTL) {
-				allValid = false
-				break
-			}
-		}
-
-		if allValid {
-			// All cached rates are valid, return them
-			duration := time.Since(startTime)
-			log.Info("cache hit, returning cached rates",
-				"rates_count", len(cachedRates),
-				"duration_ms", duration.Milliseconds(),
-			)
-			return dto.ToRatesResponse(cachedRates), nil
+	res := &ratesResolution{base: base}
+	for _, step := range uc.config.FallbackStrategy.Steps() {
+		var resp dto.RatesResponse
+		var ok bool
+		switch step {
+		case StepCache:
+			resp, ok = uc.resolveFromCache(ctx, res, startTime)
+		case StepProvider:
+			resp, ok = uc.resolveFromProvider(ctx, res, startTime)
+		case StepStaleCache:
+			resp, ok = uc.resolveFromStaleCache(ctx, res, 0)
+		case StepRecentStaleCache:
+			resp, ok = uc.resolveFromStaleCache(ctx, res, uc.config.FallbackMaxStale)
 		}
-		log.Debug("some cached rates expired, fetching fresh rates")
-		// Some rates expired - will fetch fresh rates below
-	}
-
-	// Step 2: Fetch from external API
-	log.Debug("fetching rates from external API")
-	freshRates, err := uc.provider.FetchAllRates(ctx, base)
-	if err != nil {
-		// Check if circuit breaker is open (specific handling)
-		if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
-			log.Warn("circuit breaker is open, attempting stale cache fallback")
-			// Circuit is open - return stale cached rates (GetByBase already returns stale data)
-			if len(cachedRates) > 0 {
-				// Mark all as stale since they're expired
-				staleRates := make([]*entity.ExchangeRate, 0, len(cachedRates))
-				for _, rate := range cachedRates {
-					if rate != nil {
-						staleRate, staleErr := entity.NewExchangeRate(
-							rate.Base,
-							rate.Target,
-							rate.Rate,
-							rate.Timestamp,
-							true, // Mark as stale
-						)
-						if staleErr == nil {
-							staleRates = append(staleRates, staleRate)
-						}
-					}
-				}
-				if len(staleRates) > 0 {
-					log.Info("returning stale cache due to circuit breaker open",
-						"rates_count", len(staleRates),
-						"stale", true,
-					)
-					return dto.ToRatesResponse(staleRates), nil
-				}
-			}
-			// No stale cache available - return circuit open error
-			log.Error("circuit breaker open and no stale cache available",
-				"error", err.Error(),
-			)
-			return dto.RatesResponse{}, fmt.Errorf("circuit breaker is open and no stale cache available: %w", err)
+		if ok {
+			return resp, nil
 		}
+	}
+	if resp, ok := uc.resolveFromEmbedded(ctx, res); ok {
+		return resp, nil
+	}
 
-		// Step 3: Fallback to stale cache for other provider errors
-		log.Warn("provider error, falling back to stale cache",
+	// Every step failed
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheMiss)
+	err = res.providerErr
+	if err == nil {
+		err = entity.ErrRateNotFound
+	}
+	if res.tooOld {
+		log.Error("no cached rates recent enough to serve as fallback",
 			"error", err.Error(),
+			"absolute_max_age", uc.config.AbsoluteMaxAge.String(),
 		)
-		if len(cachedRates) > 0 {
-			// Mark all as stale since they're expired
-			staleRates := make([]*entity.ExchangeRate, 0, len(cachedRates))
-			for _, rate := range cachedRates {
-				if rate != nil {
-					staleRate, staleErr := entity.NewExchangeRate(
-						rate.Base,
-						rate.Target,
-						rate.Rate,
-						rate.Timestamp,
-						true, // Mark as stale
-					)
-					if staleErr == nil {
-						staleRates = append(staleRates, staleRate)
-					}
-				}
-			}
-			if len(staleRates) > 0 {
-				log.Info("returning stale cache as fallback",
-					"rates_count", len(staleRates),
-					"stale", true,
-				)
-				return dto.ToRatesResponse(staleRates), nil
-			}
-		}
-		log.Error("failed to fetch exchange rates",
+		return dto.RatesResponse{}, fmt.Errorf("%w: cached rates older than %s (%v)", provider.ErrProviderUnavailable, uc.config.AbsoluteMaxAge, err)
+	}
+	if res.thinStale > 0 {
+		log.Error("cached rates too incomplete to serve as fallback",
 			"error", err.Error(),
+			"rates_count", res.thinStale,
+			"min_rates", uc.config.MinRates,
 		)
-		return dto.RatesResponse{}, fmt.Errorf("failed to fetch exchange rates: %w", err)
+		return dto.RatesResponse{}, fmt.Errorf("%w: only %d cached rates, want at least %d (%v)", provider.ErrProviderUnavailable, res.thinStale, uc.config.MinRates, err)
 	}
-
-	// Step 3: Save all rates to cache (or Step 2 if no error)
-	for _, rate := range freshRates {
-		if rate != nil {
-			if saveErr := uc.repository.Save(ctx, rate, uc.cacheTTL); saveErr != nil {
-				log.Warn("failed to save rate to cache",
-					"error", saveErr.Error(),
-					"base", rate.Base.String(),
-					"target", rate.Target.String(),
-				)
-			}
-		}
+	if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		log.Error("circuit breaker open and no stale cache available",
+			"error", err.Error(),
+		)
+		return dto.RatesResponse{}, fmt.Errorf("circuit breaker is open and no stale cache available: %w", err)
 	}
-
-	duration := time.Since(startTime)
-	log.Info("successfully fetched rates from API",
-		"rates_count", len(freshRates),
-		"duration_ms", duration.Milliseconds(),
+	log.Error("failed to fetch exchange rates",
+		"error", err.Error(),
 	)
-	return dto.ToRatesResponse(freshRates), nil
+	return dto.RatesResponse{}, fmt.Errorf("failed to fetch exchange rates: %w", err)
 }
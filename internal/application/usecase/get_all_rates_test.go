@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
@@ -166,3 +169,658 @@ func TestGetAllRatesUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllRatesUseCase_Execute_SurfacesSkippedCount(t *testing.T) {
+	ctx := context.Background()
+	eur, _ := entity.NewCurrencyCode("EUR")
+
+	repo := &mockRepository{}
+	prov := &mockProvider{
+		fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+			// Simulate a provider that dropped invalid entries while parsing
+			stats := provider.FetchStatsFromContext(ctx)
+			if stats == nil {
+				t.Fatal("expected fetch stats in context")
+			}
+			stats.Record(3, map[string]int{"non_positive_rate": 2, "invalid_currency_code": 1})
+
+			rate, _ := entity.NewExchangeRate(base, eur, 0.85, time.Now(), false)
+			return []*entity.ExchangeRate{rate}, nil
+		},
+	}
+
+	uc := NewGetAllRatesUseCase(repo, prov, 1*time.Hour, nil)
+	resp, err := uc.Execute(ctx, dto.GetRatesRequest{Base: "USD"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if resp.Skipped != 3 {
+		t.Errorf("Execute() Skipped = %d, want 3", resp.Skipped)
+	}
+	if len(resp.Rates) != 1 {
+		t.Errorf("expected 1 rate, got %d", len(resp.Rates))
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_SaveConcurrency(t *testing.T) {
+	const ratesCount = 40
+
+	tests := []struct {
+		name        string
+		concurrency int
+		wantBound   int
+	}{
+		{"sequential", 1, 1},
+		{"bounded pool", 4, 4},
+		{"below 1 is sequential", 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight, saved atomic.Int32
+			repo := &mockRepository{
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					n := inFlight.Add(1)
+					defer inFlight.Add(-1)
+					for {
+						prev := maxInFlight.Load()
+						if n <= prev || maxInFlight.CompareAndSwap(prev, n) {
+							break
+						}
+					}
+					time.Sleep(2 * time.Millisecond)
+					saved.Add(1)
+					// Failures must not stop the remaining saves
+					if rate.Target == "AAA" {
+						return errors.New("dynamodb unavailable")
+					}
+					return nil
+				},
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					rates := make([]*entity.ExchangeRate, 0, ratesCount)
+					for i := 0; i < ratesCount; i++ {
+						target := entity.CurrencyCode(fmt.Sprintf("A%c%c", 'A'+i/26, 'A'+i%26))
+						rate, _ := entity.NewExchangeRate(base, target, 1.5, time.Now(), false)
+						rates = append(rates, rate)
+					}
+					return rates, nil
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			config.SaveConcurrency = tt.concurrency
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if len(resp.Rates) != ratesCount {
+				t.Errorf("response has %d rates, want %d", len(resp.Rates), ratesCount)
+			}
+			if got := saved.Load(); got != ratesCount {
+				t.Errorf("saves = %d, want %d", got, ratesCount)
+			}
+			if got := maxInFlight.Load(); got > int32(tt.wantBound) {
+				t.Errorf("max concurrent saves = %d, want at most %d", got, tt.wantBound)
+			}
+			if tt.wantBound > 1 && maxInFlight.Load() < 2 {
+				t.Errorf("max concurrent saves = %d, want saves to run concurrently", maxInFlight.Load())
+			}
+		})
+	}
+}
+
+func TestSaveRates_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var saved atomic.Int32
+	repo := &mockRepository{
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			// Cancel once the first saves are running
+			if saved.Add(1) == 2 {
+				cancel()
+			}
+			return nil
+		},
+	}
+
+	rates := make([]*entity.ExchangeRate, 0, 20)
+	for i := 0; i < 20; i++ {
+		rate, _ := entity.NewExchangeRate("USD", entity.CurrencyCode(fmt.Sprintf("A%cA", 'A'+i)), 1.5, time.Now(), false)
+		rates = append(rates, rate)
+	}
+
+	errs := saveRates(ctx, repo, rates, time.Hour, 2)
+	if got := saved.Load(); got >= 20 {
+		t.Errorf("saves = %d, want saves to stop after cancellation", got)
+	}
+	if len(errs) == 0 || !errors.Is(errs[len(errs)-1], context.Canceled) {
+		t.Errorf("saveRates() errors = %v, want context.Canceled", errs)
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_EmbeddedFallback(t *testing.T) {
+	embedded := &mockProvider{
+		fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+			snapshotTime := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			eur, _ := entity.NewExchangeRate(base, "EUR", 0.9, snapshotTime, true)
+			gbp, _ := entity.NewExchangeRate(base, "GBP", 0.8, snapshotTime, true)
+			return []*entity.ExchangeRate{eur, gbp}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		embedded   bool
+		cached     bool // Stale cached rates are available
+		wantSource string
+		wantErr    bool
+	}{
+		{"serves embedded rates when everything fails", true, false, dto.SourceEmbedded, false},
+		{"prefers the stale cache", true, true, dto.SourceStaleCache, false},
+		{"disabled by default", false, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					if !tt.cached {
+						return []*entity.ExchangeRate{}, nil
+					}
+					rate, _ := entity.NewExchangeRate(base, "EUR", 0.85, time.Now().Add(-2*time.Hour), false)
+					return []*entity.ExchangeRate{rate}, nil
+				},
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return nil, errors.New("provider down")
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			if tt.embedded {
+				config.EmbeddedFallback = embedded
+			}
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Execute() = %+v, want error", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if resp.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+			isEmbedded := tt.wantSource == dto.SourceEmbedded
+			if resp.Approximate != isEmbedded || !resp.Stale {
+				t.Errorf("Approximate = %v, Stale = %v, want %v, true", resp.Approximate, resp.Stale, isEmbedded)
+			}
+			if isEmbedded {
+				if len(resp.Rates) != 2 {
+					t.Fatalf("got %d rates, want 2", len(resp.Rates))
+				}
+				for target, rate := range resp.Rates {
+					if !rate.Stale || !rate.Approximate {
+						t.Errorf("%s rate = %+v, want stale and approximate", target, rate)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_MinRates(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	targets := []entity.CurrencyCode{"EUR", "GBP", "JPY", "CHF", "CAD"}
+
+	cached := func(age time.Duration) []*entity.ExchangeRate {
+		rates := make([]*entity.ExchangeRate, 0, len(targets))
+		for _, target := range targets {
+			rate, _ := entity.NewExchangeRate("USD", target, 1.5, time.Now().Add(-age), false)
+			rates = append(rates, rate)
+		}
+		return rates
+	}
+
+	tests := []struct {
+		name       string
+		cached     []*entity.ExchangeRate
+		minRates   int
+		maxAge     time.Duration
+		wantRates  int
+		wantSource string
+		wantSaves  int
+	}{
+		{
+			name:       "thin response falls back to fuller expired cache",
+			cached:     cached(2 * cacheTTL),
+			minRates:   4,
+			wantRates:  5,
+			wantSource: dto.SourceStaleCache,
+		},
+		{
+			name:       "thin response served when cached rates exceed absolute max age",
+			cached:     cached(24 * time.Hour),
+			minRates:   4,
+			maxAge:     12 * time.Hour,
+			wantRates:  1,
+			wantSource: dto.SourceProvider,
+			wantSaves:  1,
+		},
+		{
+			name:       "thin response served when cache is not richer",
+			cached:     nil,
+			minRates:   4,
+			wantRates:  1,
+			wantSource: dto.SourceProvider,
+			wantSaves:  1,
+		},
+		{
+			name:       "disabled by default",
+			cached:     cached(2 * cacheTTL),
+			wantRates:  1,
+			wantSource: dto.SourceProvider,
+			wantSaves:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saves := 0
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return tt.cached, nil
+				},
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					saves++
+					return nil
+				},
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					// A truncated upstream file with a single rate
+					rate, _ := entity.NewExchangeRate(base, "EUR", 0.85, time.Now(), false)
+					return []*entity.ExchangeRate{rate}, nil
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			config.MinRates = tt.minRates
+			config.AbsoluteMaxAge = tt.maxAge
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if len(resp.Rates) != tt.wantRates {
+				t.Errorf("len(Rates) = %d, want %d", len(resp.Rates), tt.wantRates)
+			}
+			if resp.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+			if saves != tt.wantSaves {
+				t.Errorf("saves = %d, want %d", saves, tt.wantSaves)
+			}
+		})
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_ThinStaleCache(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	targets := []entity.CurrencyCode{"EUR", "GBP", "JPY", "CHF", "CAD"}
+
+	tests := []struct {
+		name        string
+		cachedCount int
+		minRates    int
+		providerErr error
+		wantErr     error
+		wantRates   int
+	}{
+		{"full stale set served", 5, 4, errors.New("provider down"), nil, 5},
+		{"thin stale set refused", 3, 4, errors.New("provider down"), provider.ErrProviderUnavailable, 0},
+		{"thin stale set refused with circuit open", 3, 4, circuitbreaker.ErrCircuitOpen, provider.ErrProviderUnavailable, 0},
+		{"thin stale set served when disabled", 3, 0, errors.New("provider down"), nil, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					rates := make([]*entity.ExchangeRate, 0, tt.cachedCount)
+					for _, target := range targets[:tt.cachedCount] {
+						rate, _ := entity.NewExchangeRate(base, target, 1.5, time.Now().Add(-2*cacheTTL), false)
+						rates = append(rates, rate)
+					}
+					return rates, nil
+				},
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return nil, tt.providerErr
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			config.MinRates = tt.minRates
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Source != dto.SourceStaleCache || len(resp.Rates) != tt.wantRates {
+				t.Errorf("Execute() = %d rates from %q, want %d stale cached rates", len(resp.Rates), resp.Source, tt.wantRates)
+			}
+		})
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_CleansUpExpiredRates(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	grace := 24 * time.Hour
+
+	eur, _ := entity.NewCurrencyCode("EUR")
+	gbp, _ := entity.NewCurrencyCode("GBP")
+	jpy, _ := entity.NewCurrencyCode("JPY")
+
+	tests := []struct {
+		name        string
+		grace       time.Duration
+		providerErr error
+		wantDeleted []entity.CurrencyCode
+	}{
+		{
+			name:        "deletes rates expired beyond grace and not refreshed",
+			grace:       grace,
+			wantDeleted: []entity.CurrencyCode{gbp},
+		},
+		{
+			name:  "disabled by default",
+			grace: 0,
+		},
+		{
+			name:        "keeps expired rates for stale fallback",
+			grace:       grace,
+			providerErr: errors.New("provider unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := make(chan entity.CurrencyCode, 3)
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					// EUR is refreshed below, GBP is no longer returned by the provider,
+					// and JPY is expired but still within the grace period
+					wellPast := time.Now().Add(-(cacheTTL + grace + time.Hour))
+					recent := time.Now().Add(-(cacheTTL + time.Hour))
+					eurRate, _ := entity.NewExchangeRate(base, eur, 0.85, wellPast, false)
+					gbpRate, _ := entity.NewExchangeRate(base, gbp, 0.75, wellPast, false)
+					jpyRate, _ := entity.NewExchangeRate(base, jpy, 150, recent, false)
+					return []*entity.ExchangeRate{eurRate, gbpRate, jpyRate}, nil
+				},
+				deleteFunc: func(ctx context.Context, base, target entity.CurrencyCode) error {
+					deleted <- target
+					return nil
+				},
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					if tt.providerErr != nil {
+						return nil, tt.providerErr
+					}
+					rate, _ := entity.NewExchangeRate(base, eur, 0.86, time.Now(), false)
+					return []*entity.ExchangeRate{rate}, nil
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			config.ExpiredCleanupGrace = tt.grace
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			if _, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"}); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			// Deletes complete before Execute returns
+			close(deleted)
+			var got []entity.CurrencyCode
+			for target := range deleted {
+				got = append(got, target)
+			}
+			if len(got) != len(tt.wantDeleted) {
+				t.Fatalf("deleted %v, want %v", got, tt.wantDeleted)
+			}
+			for i, want := range tt.wantDeleted {
+				if got[i] != want {
+					t.Errorf("deleted %s, want %s", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_AbsoluteMaxAge(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	maxAge := 12 * time.Hour
+
+	tests := []struct {
+		name      string
+		rateAges  map[entity.CurrencyCode]time.Duration
+		minRates  int
+		wantErr   error
+		wantRates int
+	}{
+		{"serves hour-old rates", map[entity.CurrencyCode]time.Duration{"EUR": time.Hour + time.Minute}, 0, nil, 1},
+		{"refuses day-old rates", map[entity.CurrencyCode]time.Duration{"EUR": 24 * time.Hour}, 0, provider.ErrProviderUnavailable, 0},
+		{
+			name:      "drops an orphaned day-old rate",
+			rateAges:  map[entity.CurrencyCode]time.Duration{"EUR": time.Hour + time.Minute, "GBP": time.Hour + time.Minute, "XAF": 24 * time.Hour},
+			wantRates: 2,
+		},
+		{
+			name:     "refuses when too few rates are left",
+			rateAges: map[entity.CurrencyCode]time.Duration{"EUR": time.Hour + time.Minute, "XAF": 24 * time.Hour},
+			minRates: 2,
+			wantErr:  provider.ErrProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					rates := make([]*entity.ExchangeRate, 0, len(tt.rateAges))
+					for target, age := range tt.rateAges {
+						rate, _ := entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-age), false)
+						rates = append(rates, rate)
+					}
+					return rates, nil
+				},
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return nil, errors.New("provider down")
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			config.AbsoluteMaxAge = maxAge
+			config.MinRates = tt.minRates
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || resp.Source != dto.SourceStaleCache || len(resp.Rates) != tt.wantRates {
+				t.Errorf("Execute() = %+v, %v, want %d stale cached rates", resp, err, tt.wantRates)
+			}
+		})
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_CircuitOpenStaleCap(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	ages := map[entity.CurrencyCode]time.Duration{"EUR": 90 * time.Minute, "GBP": 5 * time.Hour}
+
+	tests := []struct {
+		name        string
+		strategy    FallbackStrategy
+		maxStale    time.Duration
+		maxAge      time.Duration
+		wantCap     time.Duration
+		wantTargets []string
+	}{
+		{"cache-first drops rates beyond the staleness cap", CacheFirstStrategy(), time.Hour, 0, 2 * time.Hour, []string{"EUR"}},
+		{"stale-ok drops rates beyond the staleness cap", StaleOKStrategy(), time.Hour, 0, 2 * time.Hour, []string{"EUR"}},
+		{"absolute max age caps the staleness", CacheFirstStrategy(), 6 * time.Hour, 4 * time.Hour, 4 * time.Hour, []string{"EUR"}},
+		{"no cap when both are disabled", CacheFirstStrategy(), 0, 0, 0, []string{"EUR", "GBP"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCap time.Duration
+			staleCalls := 0
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					rates := make([]*entity.ExchangeRate, 0, len(ages))
+					for _, target := range []entity.CurrencyCode{"EUR", "GBP"} {
+						rate, _ := entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-ages[target]), false)
+						rates = append(rates, rate)
+					}
+					return rates, nil
+				},
+			}
+			repo.getStaleByBaseFunc = func(ctx context.Context, base entity.CurrencyCode, maxStaleness time.Duration) ([]*entity.ExchangeRate, error) {
+				gotCap = maxStaleness
+				staleCalls++
+				repo.getStaleByBaseFunc = nil
+				return repo.GetStaleByBase(ctx, base, maxStaleness)
+			}
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return nil, fmt.Errorf("%w: external API unavailable", circuitbreaker.ErrCircuitOpen)
+				},
+			}
+
+			config := DefaultGetAllRatesConfig()
+			config.FallbackStrategy = tt.strategy
+			config.FallbackMaxStale = tt.maxStale
+			config.AbsoluteMaxAge = tt.maxAge
+			uc := NewGetAllRatesUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if staleCalls != 1 {
+				t.Fatalf("GetStaleByBase() called %d times, want 1", staleCalls)
+			}
+			if gotCap != tt.wantCap {
+				t.Errorf("GetStaleByBase() maxStaleness = %v, want %v", gotCap, tt.wantCap)
+			}
+			if resp.Source != dto.SourceStaleCache {
+				t.Errorf("Execute() source = %q, want %q", resp.Source, dto.SourceStaleCache)
+			}
+			if len(resp.Rates) != len(tt.wantTargets) {
+				t.Fatalf("Execute() returned %d rates, want %v", len(resp.Rates), tt.wantTargets)
+			}
+			for _, target := range tt.wantTargets {
+				if _, ok := resp.Rates[target]; !ok {
+					t.Errorf("Execute() rates = %v, want %v", resp.Rates, tt.wantTargets)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllRatesUseCase_Execute_PartialCacheResult(t *testing.T) {
+	eur, _ := entity.NewCurrencyCode("EUR")
+	gbp, _ := entity.NewCurrencyCode("GBP")
+	cacheTTL := 1 * time.Hour
+
+	tests := []struct {
+		name         string
+		rateAge      time.Duration
+		providerErr  error
+		wantSource   string
+		wantStale    bool
+		wantProvider bool
+	}{
+		{
+			name:       "valid partial result served from cache",
+			rateAge:    10 * time.Minute,
+			wantSource: dto.SourceCache,
+		},
+		{
+			name:         "expired partial result used as stale fallback",
+			rateAge:      2 * time.Hour,
+			providerErr:  errors.New("API error"),
+			wantSource:   dto.SourceStaleCache,
+			wantStale:    true,
+			wantProvider: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getByBaseFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					rate1, _ := entity.NewExchangeRate(base, eur, 0.85, time.Now().Add(-tt.rateAge), false)
+					rate2, _ := entity.NewExchangeRate(base, gbp, 0.75, time.Now().Add(-tt.rateAge), false)
+					rates := []*entity.ExchangeRate{rate1, rate2}
+					return rates, &repository.PartialResultError{Rates: rates, Skipped: 1}
+				},
+			}
+			providerCalled := false
+			prov := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					providerCalled = true
+					return nil, tt.providerErr
+				},
+			}
+
+			uc := NewGetAllRatesUseCase(repo, prov, cacheTTL, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRatesRequest{Base: "USD"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v, want partial result treated as success", err)
+			}
+
+			if len(resp.Rates) != 2 {
+				t.Errorf("expected 2 rates, got %d", len(resp.Rates))
+			}
+			if resp.CacheSkipped != 1 {
+				t.Errorf("Execute() CacheSkipped = %d, want 1", resp.CacheSkipped)
+			}
+			if resp.Source != tt.wantSource {
+				t.Errorf("Execute() Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+			if resp.Stale != tt.wantStale {
+				t.Errorf("Execute() Stale = %v, want %v", resp.Stale, tt.wantStale)
+			}
+			if providerCalled != tt.wantProvider {
+				t.Errorf("provider called = %v, want %v", providerCalled, tt.wantProvider)
+			}
+		})
+	}
+}
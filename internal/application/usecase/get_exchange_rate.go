@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
@@ -12,15 +15,86 @@ import (
 	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/metrics"
+	"golang.org/x/sync/singleflight"
 )
 
 // GetExchangeRateUseCase handles the use case for getting an exchange rate for a currency pair.
 // This implements UC1 from the specification.
 type GetExchangeRateUseCase struct {
-	repository repository.ExchangeRateRepository
-	provider   provider.ExchangeRateProvider
-	cacheTTL   time.Duration // TTL for cached rates
-	logger     *logger.Logger
+	repository   repository.ExchangeRateRepository
+	provider     provider.ExchangeRateProvider
+	cacheTTL     atomic.Int64 // TTL for cached rates in nanoseconds; see SetCacheTTL
+	config       GetExchangeRateConfig
+	logger       *logger.Logger
+	cacheCounter *metrics.CacheCounter // Counts cache hits, misses, and stale fallbacks
+
+	anomalyMu sync.Mutex
+	anomalies map[string]*anomalyCandidate // Rejected anomalous rates awaiting confirmation, by pair
+
+	refreshGroup singleflight.Group // Shares one refresh-ahead per pair between concurrent requests
+}
+
+// anomalyCandidate tracks consecutive fetches of a rejected anomalous rate.
+type anomalyCandidate struct {
+	rate  float64 // Most recent fetched rate
+	count int     // Consecutive fetches agreeing with it
+}
+
+// GetExchangeRateConfig holds optional behavior settings for GetExchangeRateUseCase.
+type GetExchangeRateConfig struct {
+	MaxRateDelta         float64                       // Maximum relative change vs. the cached rate (0.5 = 50%, 0 = disabled)
+	RejectAnomalousRates bool                          // Serve the cached rate instead of an anomalous fresh rate
+	AnomalyConfirmations int                           // Accept a rejected rate once this many consecutive fetches agree on it (0 = never)
+	AnomalyMaxCacheAge   time.Duration                 // Accept a rejected rate once the cached rate is older than this (0 = never)
+	FallbackStrategy     FallbackStrategy              // Order of cache, provider, and stale cache lookups (nil = cache-first)
+	FallbackMaxStale     time.Duration                 // How long past the TTL StepRecentStaleCache still serves a rate (0 = no bound)
+	AbsoluteMaxAge       time.Duration                 // Never serve a stale rate older than this; the request fails with ErrProviderUnavailable (0 = no cap)
+	ExpiredCleanupGrace  time.Duration                 // Delete a cached rate of an unsupported pair expired for longer than this (0 = disabled)
+	WarmCacheOnFetch     bool                          // Also cache the other rates the provider downloaded for the base
+	SaveConcurrency      int                           // Maximum concurrent cache saves of the rates warmed by WarmCacheOnFetch (below 1 = sequential)
+	EmbeddedFallback     provider.ExchangeRateProvider // Bundled static rates served when every step fails (nil = disabled)
+	FetchLock            repository.FetchLock          // Distributed lock limiting provider fetches of a pair to one instance (nil = disabled)
+	FetchLockWait        time.Duration                 // How long to poll the cache while another instance holds the fetch lock
+	RefreshAheadFraction float64                       // Refresh a cached rate while serving it once less than this fraction of the TTL remains (0 = disabled)
+	RefreshAheadJitter   float64                       // Randomly shrink the refresh-ahead window by up to this fraction
+	NoCachePairs         map[string]bool               // Pairs ("BASE/TARGET") always fetched from the provider, served from the cache only as a stale fallback (see ParseNoCachePairs)
+}
+
+// DefaultGetExchangeRateConfig returns the default use case configuration.
+//
+// Default values:
+// - MaxRateDelta: 0.5 (warn when a fresh rate moves more than 50% from the cached rate)
+// - RejectAnomalousRates: false (anomalies are logged but the fresh rate is served)
+// - AnomalyConfirmations: 3 (a rejected rate is accepted on the third consecutive fetch)
+// - AnomalyMaxCacheAge: 24h (a rejected rate replaces a cached rate older than a day)
+// - FallbackStrategy: CacheFirstStrategy()
+// - FallbackMaxStale: 6h (stale-ok refreshes rates expired for longer than 6 hours)
+// - AbsoluteMaxAge: 0 (stale fallbacks are not capped by age)
+// - ExpiredCleanupGrace: 0 (expired rates are left to DynamoDB TTL)
+// - WarmCacheOnFetch: false (only the requested rate is cached)
+// - SaveConcurrency: 10
+// - EmbeddedFallback: nil (requests fail when every step fails)
+// - FetchLock: nil (every instance fetches on a cache miss)
+// - FetchLockWait: 2s
+// - RefreshAheadFraction: 0 (cached rates are only refreshed after they expire)
+// - RefreshAheadJitter: 0.25
+// - NoCachePairs: nil (every pair is served from the cache)
+func DefaultGetExchangeRateConfig() GetExchangeRateConfig {
+	return GetExchangeRateConfig{
+		MaxRateDelta:         0.5,
+		RejectAnomalousRates: false,
+		AnomalyConfirmations: 3,
+		AnomalyMaxCacheAge:   24 * time.Hour,
+		FallbackStrategy:     CacheFirstStrategy(),
+		FallbackMaxStale:     6 * time.Hour,
+		AbsoluteMaxAge:       0,
+		ExpiredCleanupGrace:  0,
+		WarmCacheOnFetch:     false,
+		SaveConcurrency:      10,
+		FetchLockWait:        2 * time.Second,
+		RefreshAheadJitter:   0.25,
+	}
 }
 
 // NewGetExchangeRateUseCase creates a new GetExchangeRateUseCase with dependency injection.
@@ -29,48 +103,451 @@ func NewGetExchangeRateUseCase(
 	prov provider.ExchangeRateProvider,
 	cacheTTL time.Duration,
 	log *logger.Logger,
+) *GetExchangeRateUseCase {
+	return NewGetExchangeRateUseCaseWithConfig(repo, prov, cacheTTL, DefaultGetExchangeRateConfig(), log)
+}
+
+// NewGetExchangeRateUseCaseWithConfig creates a new GetExchangeRateUseCase with custom configuration.
+func NewGetExchangeRateUseCaseWithConfig(
+	repo repository.ExchangeRateRepository,
+	prov provider.ExchangeRateProvider,
+	cacheTTL time.Duration,
+	config GetExchangeRateConfig,
+	log *logger.Logger,
 ) *GetExchangeRateUseCase {
 	if log == nil {
 		log = logger.NewFromEnv()
 	}
-	return &GetExchangeRateUseCase{
-		repository: repo,
-		provider:   prov,
-		cacheTTL:   cacheTTL,
-		logger:     log,
+	if config.FallbackStrategy == nil {
+		config.FallbackStrategy = CacheFirstStrategy()
+	}
+	uc := &GetExchangeRateUseCase{
+		repository:   repo,
+		provider:     prov,
+		config:       config,
+		logger:       log,
+		cacheCounter: metrics.DefaultCacheCounter(),
+		anomalies:    make(map[string]*anomalyCandidate),
+	}
+	uc.cacheTTL.Store(int64(cacheTTL))
+	return uc
+}
+
+// SetCacheTTL replaces the TTL of cached rates, e.g. on a config reload.
+// Requests already in flight may still use the previous TTL.
+// This method is thread-safe.
+func (uc *GetExchangeRateUseCase) SetCacheTTL(ttl time.Duration) {
+	uc.cacheTTL.Store(int64(ttl))
+}
+
+// ttl returns the current TTL of cached rates.
+func (uc *GetExchangeRateUseCase) ttl() time.Duration {
+	return time.Duration(uc.cacheTTL.Load())
+}
+
+// isAnomalous reports whether fresh deviates from cached by more than the configured
+// MaxRateDelta, and returns the relative change.
+func (uc *GetExchangeRateUseCase) isAnomalous(cached, fresh *entity.ExchangeRate) (bool, float64) {
+	if uc.config.MaxRateDelta <= 0 || cached == nil || fresh == nil || cached.Rate <= 0 {
+		return false, 0
+	}
+	delta := math.Abs(fresh.Rate-cached.Rate) / cached.Rate
+	return delta > uc.config.MaxRateDelta, delta
+}
+
+// confirmAnomaly records a rejected anomalous fetch for a pair and reports whether
+// it should be accepted anyway.
+//
+// A rejected rate is accepted when:
+// - The cached rate is older than AnomalyMaxCacheAge, so the cache cannot pin an outdated rate forever
+// - AnomalyConfirmations consecutive fetches agree on it (each within MaxRateDelta of the previous)
+//
+// Without this, a genuine large move (e.g. a devaluation) would never be saved.
+// Returns whether to accept the fresh rate and the number of consecutive confirmations.
+func (uc *GetExchangeRateUseCase) confirmAnomaly(cached, fresh *entity.ExchangeRate) (bool, int) {
+	key := fresh.Base.String() + "/" + fresh.Target.String()
+
+	uc.anomalyMu.Lock()
+	defer uc.anomalyMu.Unlock()
+
+	if uc.config.AnomalyMaxCacheAge > 0 && time.Since(cached.Timestamp) > uc.config.AnomalyMaxCacheAge {
+		delete(uc.anomalies, key)
+		return true, 0
+	}
+
+	candidate, ok := uc.anomalies[key]
+	if !ok || math.Abs(fresh.Rate-candidate.rate)/candidate.rate > uc.config.MaxRateDelta {
+		candidate = &anomalyCandidate{}
+		uc.anomalies[key] = candidate
+	}
+	candidate.rate = fresh.Rate
+	candidate.count++
+
+	if uc.config.AnomalyConfirmations > 0 && candidate.count >= uc.config.AnomalyConfirmations {
+		delete(uc.anomalies, key)
+		return true, candidate.count
+	}
+	return false, candidate.count
+}
+
+// clearAnomaly forgets a pending anomalous rate for a pair once a fetch agrees
+// with the cached rate again.
+func (uc *GetExchangeRateUseCase) clearAnomaly(base, target entity.CurrencyCode) {
+	uc.anomalyMu.Lock()
+	defer uc.anomalyMu.Unlock()
+	delete(uc.anomalies, base.String()+"/"+target.String())
+}
+
+// rateResolution holds the state of resolving one exchange rate request
+// across resolution steps.
+type rateResolution struct {
+	base, target entity.CurrencyCode
+	cached       *entity.ExchangeRate // Cached rate (valid or expired), nil if none
+	cacheLoaded  bool                 // Whether the cache has been read
+	providerErr  error                // Error from the provider step, nil if not tried or successful
+	tooOld       bool                 // Whether a stale rate was refused for exceeding AbsoluteMaxAge
+}
+
+// loadCached reads the cached rate once per request; later steps reuse it.
+func (uc *GetExchangeRateUseCase) loadCached(ctx context.Context, res *rateResolution) *entity.ExchangeRate {
+	if res.cacheLoaded {
+		return res.cached
+	}
+	res.cacheLoaded = true
+
+	log := uc.logger.WithContext(ctx)
+	log.Debug("checking cache for exchange rate")
+	cachedRate, err := uc.repository.Get(ctx, res.base, res.target)
+	if err != nil {
+		log.Debug("cache check error", "error", err.Error())
+		return nil
+	}
+	if cachedRate != nil {
+		log.Debug("cache check result",
+			"cache_hit", true,
+			"rate", cachedRate.Rate,
+			"valid", cachedRate.IsValid(uc.ttl()),
+			"timestamp", cachedRate.Timestamp,
+		)
+	}
+	res.cached = cachedRate
+	return cachedRate
+}
+
+// staleResponse returns rate as a response marked stale, or false if the entity is invalid.
+func staleResponse(rate *entity.ExchangeRate) (dto.RateResponse, bool) {
+	staleRate, err := entity.NewExchangeRate(
+		rate.Base,
+		rate.Target,
+		rate.Rate,
+		rate.Timestamp,
+		true, // Mark as stale
+	)
+	if err != nil {
+		return dto.RateResponse{}, false
+	}
+	staleRate.Bid, staleRate.Ask = rate.Bid, rate.Ask
+	staleRate.Derived = rate.Derived
+	resp := dto.ToRateResponse(staleRate)
+	resp.Source = dto.SourceStaleCache
+	return resp, true
+}
+
+// resolveFromEmbedded serves the rate from EmbeddedFallback once every resolution
+// step failed, marked stale and approximate. It is meant for development and
+// demo deployments only.
+func (uc *GetExchangeRateUseCase) resolveFromEmbedded(ctx context.Context, res *rateResolution) (dto.RateResponse, bool) {
+	if uc.config.EmbeddedFallback == nil {
+		return dto.RateResponse{}, false
+	}
+	log := uc.logger.WithContext(ctx)
+	rate, err := uc.config.EmbeddedFallback.FetchRate(ctx, res.base, res.target)
+	if err != nil {
+		log.Debug("embedded fallback has no rate for pair", "error", err.Error())
+		return dto.RateResponse{}, false
+	}
+	resp, ok := staleResponse(rate)
+	if !ok {
+		return dto.RateResponse{}, false
+	}
+	resp.Approximate = true
+	resp.Source = dto.SourceEmbedded
+
+	log.Warn("serving approximate rate from embedded fallback",
+		"timestamp", rate.Timestamp,
+	)
+	if source := provider.FetchSourceFromContext(ctx); source != nil {
+		source.Record(dto.SourceEmbedded, "")
+	}
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheStale)
+	return resp, true
+}
+
+// resolveFromCache serves the cached rate if it is still valid.
+//
+// With RefreshAheadFraction, a rate close to expiry is also refreshed before
+// the cached rate is returned (see refreshAhead).
+func (uc *GetExchangeRateUseCase) resolveFromCache(ctx context.Context, res *rateResolution, startTime time.Time) (dto.RateResponse, bool) {
+	cachedRate := uc.loadCached(ctx, res)
+	if cachedRate == nil {
+		return dto.RateResponse{}, false
+	}
+	log := uc.logger.WithContext(ctx)
+	if !cachedRate.IsValid(uc.ttl()) {
+		log.Debug("cache expired, trying next step")
+		return dto.RateResponse{}, false
+	}
+
+	log.Info("cache hit, returning cached rate",
+		"rate", cachedRate.Rate,
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+	resp := dto.ToRateResponse(cachedRate)
+	resp.Source = dto.SourceCache
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheHit)
+	uc.refreshAhead(ctx, cachedRate)
+	return resp, true
+}
+
+// cleanupExpired deletes the cached rate of a pair the provider no longer supports
+// once it expired more than ExpiredCleanupGrace ago.
+//
+// Only a rate already read by an earlier cache step is considered. The rate is
+// dropped from res so the stale cache step does not serve it.
+func (uc *GetExchangeRateUseCase) cleanupExpired(ctx context.Context, res *rateResolution) {
+	if uc.config.ExpiredCleanupGrace <= 0 || uc.ttl() <= 0 || res.cached == nil {
+		return
+	}
+	if !res.cached.IsExpired(uc.ttl() + uc.config.ExpiredCleanupGrace) {
+		return
+	}
+
+	log := uc.logger.WithContext(ctx)
+	log.Info("deleting cached rate of unsupported pair expired beyond grace period",
+		"grace_period", uc.config.ExpiredCleanupGrace.String(),
+	)
+	deleteExpiredRates(ctx, uc.repository, log, []*entity.ExchangeRate{res.cached})
+	res.cached = nil
+}
+
+// fetchRate fetches the requested rate from the provider.
+//
+// With WarmCacheOnFetch, it also returns the other rates the provider downloaded
+// for the base: in one call if the provider implements provider.RateAndAllFetcher,
+// otherwise through a provider.FetchedRates collector on the context.
+func (uc *GetExchangeRateUseCase) fetchRate(ctx context.Context, res *rateResolution) (*entity.ExchangeRate, []*entity.ExchangeRate, error) {
+	if !uc.config.WarmCacheOnFetch {
+		rate, err := uc.provider.FetchRate(ctx, res.base, res.target)
+		return rate, nil, err
+	}
+	if fetcher, ok := uc.provider.(provider.RateAndAllFetcher); ok {
+		return fetcher.FetchRateAndAll(ctx, res.base, res.target)
 	}
+	fetchCtx, fetched := provider.WithFetchedRates(ctx)
+	rate, err := uc.provider.FetchRate(fetchCtx, res.base, res.target)
+	return rate, fetched.Rates(), err
+}
+
+// resolveFromProvider fetches a fresh rate and saves it to the cache.
+//
+// Anomaly Detection:
+// - If the fresh rate changed more than MaxRateDelta from the cached rate → log warning
+// - If RejectAnomalousRates is set → return the cached rate (marked stale) instead,
+// until the rate is confirmed by AnomalyConfirmations consecutive fetches or the
+// cached rate is older than AnomalyMaxCacheAge (see confirmAnomaly)
+// - A rejected response reports the cache as its source, including in the FetchSource
+// - A cached rate older than AbsoluteMaxAge is not served in place of a rejected rate
+//
+// If the provider no longer supports the pair, a cached rate expired beyond
+// ExpiredCleanupGrace is deleted (see cleanupExpired).
+//
+// With WarmCacheOnFetch, the other rates the provider downloaded for the base
+// (see fetchRate) are cached too once the fresh rate is saved, at most
+// SaveConcurrency at a time (see warmCache).
+// They are not checked for anomalies.
+//
+// With a FetchLock, only the instance holding the pair's lock calls the
+// provider; others wait for the rate it caches (see acquireFetchLock).
+//
+// Provider errors are kept in res for the stale cache step and the final error.
+func (uc *GetExchangeRateUseCase) resolveFromProvider(ctx context.Context, res *rateResolution, startTime time.Time) (dto.RateResponse, bool) {
+	log := uc.logger.WithContext(ctx)
+	if uc.config.FetchLock != nil {
+		release, resp, served := uc.acquireFetchLock(ctx, res)
+		if served {
+			return resp, true
+		}
+		defer release()
+	}
+	log.Debug("fetching rate from external API")
+	freshRate, fetched, err := uc.fetchRate(ctx, res)
+	if err == nil && freshRate == nil {
+		err = entity.ErrRateNotFound
+	}
+	if err != nil {
+		res.providerErr = err
+		if errors.Is(err, provider.ErrCurrencyUnsupported) {
+			uc.cleanupExpired(ctx, res)
+		}
+		return dto.RateResponse{}, false
+	}
+
+	// Compare against the last cached value to catch upstream spikes
+	if uc.config.MaxRateDelta > 0 {
+		cachedRate := uc.loadCached(ctx, res)
+		if anomalous, delta := uc.isAnomalous(cachedRate, freshRate); anomalous {
+			rejected, confirmations := false, 0
+			if uc.config.RejectAnomalousRates {
+				var accepted bool
+				accepted, confirmations = uc.confirmAnomaly(cachedRate, freshRate)
+				rejected = !accepted
+			}
+			log.Warn("fetched rate deviates from cached rate beyond threshold",
+				"cached_rate", cachedRate.Rate,
+				"fresh_rate", freshRate.Rate,
+				"delta", delta,
+				"max_rate_delta", uc.config.MaxRateDelta,
+				"rejected", rejected,
+				"confirmations", confirmations,
+			)
+			if rejected {
+				if maxAge := uc.config.AbsoluteMaxAge; maxAge > 0 && cachedRate.Age() > maxAge {
+					log.Warn("cached rate older than absolute max age, refusing it in place of the anomalous rate",
+						"age", cachedRate.Age().String(),
+						"absolute_max_age", maxAge.String(),
+					)
+					res.tooOld = true
+					return dto.RateResponse{}, false
+				}
+				if resp, ok := staleResponse(cachedRate); ok {
+					// The served rate came from the cache, not the provider that was called
+					if source := provider.FetchSourceFromContext(ctx); source != nil {
+						source.Record(dto.SourceCache, "")
+					}
+					recordCacheResult(log, uc.cacheCounter, metrics.CacheStale)
+					return resp, true
+				}
+			}
+		} else if uc.config.RejectAnomalousRates {
+			uc.clearAnomaly(res.base, res.target)
+		}
+	}
+
+	// Successfully fetched - save to cache
+	if saveErr := uc.repository.Save(ctx, freshRate, uc.ttl()); saveErr != nil {
+		log.Warn("failed to save rate to cache",
+			"error", saveErr.Error(),
+		)
+	} else {
+		log.Debug("rate saved to cache successfully")
+		if len(fetched) > 0 {
+			warmCache(ctx, uc.repository, log, fetched, freshRate, uc.ttl(), uc.config.SaveConcurrency)
+		}
+	}
+	log.Info("successfully fetched rate from API",
+		"rate", freshRate.Rate,
+		"duration_ms", time.Since(startTime).Milliseconds(),
+	)
+	resp := dto.ToRateResponse(freshRate)
+	resp.Source = dto.SourceProvider
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheMiss)
+	return resp, true
+}
+
+// resolveFromStaleCache serves an expired cached rate, marked stale.
+//
+// If the circuit breaker is open, the rate is read with GetStale(); otherwise
+// the rate read by the cache step is reused. A rate that expired more than
+// maxStale ago is not served (0 = no bound), and neither is a rate older than
+// AbsoluteMaxAge.
+func (uc *GetExchangeRateUseCase) resolveFromStaleCache(ctx context.Context, res *rateResolution, maxStale time.Duration) (dto.RateResponse, bool) {
+	log := uc.logger.WithContext(ctx)
+
+	var staleRate *entity.ExchangeRate
+	if errors.Is(res.providerErr, circuitbreaker.ErrCircuitOpen) {
+		log.Warn("circuit breaker is open, attempting stale cache fallback")
+		// Circuit is open - explicitly use GetStale() for fallback
+		rate, err := uc.repository.GetStale(ctx, res.base, res.target)
+		if err != nil {
+			return dto.RateResponse{}, false
+		}
+		staleRate = rate
+	} else {
+		staleRate = uc.loadCached(ctx, res)
+		if staleRate != nil && res.providerErr != nil {
+			log.Warn("provider error, falling back to stale cache",
+				"error", res.providerErr.Error(),
+			)
+		}
+	}
+	if staleRate == nil {
+		return dto.RateResponse{}, false
+	}
+	if maxStale > 0 && staleRate.IsExpired(uc.ttl()+maxStale) {
+		log.Debug("cached rate expired beyond max staleness, trying next step",
+			"max_stale", maxStale.String(),
+		)
+		return dto.RateResponse{}, false
+	}
+	if maxAge := uc.config.AbsoluteMaxAge; maxAge > 0 && staleRate.Age() > maxAge {
+		log.Warn("cached rate older than absolute max age, refusing stale fallback",
+			"age", staleRate.Age().String(),
+			"absolute_max_age", maxAge.String(),
+		)
+		res.tooOld = true
+		return dto.RateResponse{}, false
+	}
+
+	resp, ok := staleResponse(staleRate)
+	if !ok {
+		return dto.RateResponse{}, false
+	}
+	log.Info("returning stale cache as fallback",
+		"rate", resp.Rate,
+		"stale", true,
+	)
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheStale)
+	return resp, true
 }
 
 // Execute executes the use case to get an exchange rate for a currency pair.
 //
 // Flow:
 // 1. Validate currency codes
-// 2. Check cache (repository.Get)
-// 3. If cache hit and valid (not expired) → return cached rate
-// 4. If cache miss or expired → fetch from external API
-// 5. Update cache with new rate
-// 6. Return rate to client
-//
-// Fallback Strategy:
-// - If circuit breaker is open (ErrCircuitOpen) → use GetStale() for fallback
-// - If other provider error → fallback to stale cache (if available)
-// - If both unavailable → return error
-//
-// Cache-First Strategy:
-// - Always check cache before external API
+// 2. Try each step of the configured FallbackStrategy in order
+// 3. Return the first rate found, or an error if every step failed
+//
+// Resolution steps:
+// - StepCache: cached rate within the cache TTL (repository.Get)
+// - StepProvider: fresh rate from the external API, compared against the cached rate (anomaly detection) and saved to the cache
+// - StepStaleCache: expired cached rate marked stale; GetStale() if the circuit breaker is open
+// - StepRecentStaleCache: like StepStaleCache, but only for rates expired at most FallbackMaxStale ago
+//
+// For pairs in NoCachePairs, StepCache and StepRecentStaleCache are skipped
+// (see skipsCacheStep).
+//
+// Stale steps never serve a rate older than AbsoluteMaxAge; if one was refused
+// and no step succeeded, provider.ErrProviderUnavailable is returned.
+//
+// If every step failed and EmbeddedFallback is set, its rate is served marked
+// stale and approximate (see resolveFromEmbedded).
+//
+// The default cache-first strategy tries cache → provider → stale cache:
 // - Reduces external API calls (>80% reduction)
 // - Faster response times (<200ms for cached)
+//
+// Every request logs cache_result (hit, miss, stale) and the running cache_hit_ratio.
 func (uc *GetExchangeRateUseCase) Execute(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
 	startTime := time.Now()
-	log := uc.logger.WithContext(ctx)
 
 	// Add currency codes to context for logging
 	ctx = logger.WithCurrencyCodes(ctx, req.Base, req.Target)
-	log = uc.logger.WithContext(ctx)
+	log := uc.logger.WithContext(ctx)
 
 	log.Debug("executing get exchange rate use case",
 		"base", req.Base,
 		"target", req.Target,
+		"fallback_strategy", uc.config.FallbackStrategy.Name(),
 	)
 
 	// Validate currency codes
@@ -95,108 +572,51 @@ func (uc *GetExchangeRateUseCase) Execute(ctx context.Context, req dto.GetRateRe
 		return dto.RateResponse{}, fmt.Errorf("currency code validation: %w", entity.ErrCurrencyCodeMismatch)
 	}
 
-	// Step 1: Check cache
-	log.Debug("checking cache for exchange rate")
-	cachedRate, err := uc.repository.Get(ctx, base, target)
-	if err != nil {
-		log.Debug("cache check error", "error", err.Error())
-	} else if cachedRate != nil {
-		isValid := cachedRate.IsValid(uc.cacheTTL)
-		log.Debug("cache check result",
-			"cache_hit", true,
-			"rate", cachedRate.Rate,
-			"valid", isValid,
-			"timestamp", cachedRate.Timestamp,
-		)
-	}
-	if err == nil && cachedRate != nil {
-		// Cache hit - check if still valid
-		if cachedRate.IsValid(uc.cacheTTL) {
-			// Cache is valid, return it
-			duration := time.Since(startTime)
-			log.Info("cache hit, returning cached rate",
-				"rate", cachedRate.Rate,
-				"duration_ms", duration.Milliseconds(),
-			)
-			return dto.ToRateResponse(cachedRate), nil
+	res := &rateResolution{base: base, target: target}
+	for _, step := range uc.config.FallbackStrategy.Steps() {
+		if uc.skipsCacheStep(res, step) {
+			log.Debug("skipping cache step for no-cache pair", "step", string(step))
+			continue
+		}
+		var resp dto.RateResponse
+		var ok bool
+		switch step {
+		case StepCache:
+			resp, ok = uc.resolveFromCache(ctx, res, startTime)
+		case StepProvider:
+			resp, ok = uc.resolveFromProvider(ctx, res, startTime)
+		case StepStaleCache:
+			resp, ok = uc.resolveFromStaleCache(ctx, res, 0)
+		case StepRecentStaleCache:
+			resp, ok = uc.resolveFromStaleCache(ctx, res, uc.config.FallbackMaxStale)
 		}
-		log.Debug("cache expired, fetching fresh rate")
-		// Cache exists but expired - will fetch fresh rate below
+		if ok {
+			return resp, nil
+		}
+	}
+	if resp, ok := uc.resolveFromEmbedded(ctx, res); ok {
+		return resp, nil
 	}
 
-	// Step 2: Fetch from external API
-	log.Debug("fetching rate from external API")
-	freshRate, err := uc.provider.FetchRate(ctx, base, target)
-	if err == nil && freshRate != nil {
-		// Successfully fetched - save to cache
-		if saveErr := uc.repository.Save(ctx, freshRate, uc.cacheTTL); saveErr != nil {
-			log.Warn("failed to save rate to cache",
-				"error", saveErr.Error(),
-			)
-		} else {
-			log.Debug("rate saved to cache successfully")
-		}
-		duration := time.Since(startTime)
-		log.Info("successfully fetched rate from API",
-			"rate", freshRate.Rate,
-			"duration_ms", duration.Milliseconds(),
+	// Every step failed
+	recordCacheResult(log, uc.cacheCounter, metrics.CacheMiss)
+	err = res.providerErr
+	if err == nil {
+		err = entity.ErrRateNotFound
+	}
+	if res.tooOld {
+		log.Error("no cached rate recent enough to serve as fallback",
+			"error", err.Error(),
+			"absolute_max_age", uc.config.AbsoluteMaxAge.String(),
 		)
-		return dto.ToRateResponse(freshRate), nil
+		return dto.RateResponse{}, fmt.Errorf("%w: cached rate older than %s (%v)", provider.ErrProviderUnavailable, uc.config.AbsoluteMaxAge, err)
 	}
-
-	// Step 3: Fallback to stale cache if external API failed
-	// Check if circuit breaker is open (specific handling)
 	if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
-		log.Warn("circuit breaker is open, attempting stale cache fallback")
-		// Circuit is open - explicitly use GetStale() for fallback
-		staleRate, staleErr := uc.repository.GetStale(ctx, base, target)
-		if staleErr == nil && staleRate != nil {
-			// Create stale rate entity (mark as stale)
-			staleEntity, entityErr := entity.NewExchangeRate(
-				staleRate.Base,
-				staleRate.Target,
-				staleRate.Rate,
-				staleRate.Timestamp,
-				true, // Mark as stale
-			)
-			if entityErr == nil {
-				log.Info("returning stale cache due to circuit breaker open",
-					"rate", staleEntity.Rate,
-					"stale", true,
-				)
-				return dto.ToRateResponse(staleEntity), nil
-			}
-		}
-		// No stale cache available - return circuit open error
 		log.Error("circuit breaker open and no stale cache available",
 			"error", err.Error(),
 		)
 		return dto.RateResponse{}, fmt.Errorf("circuit breaker is open and no stale cache available: %w", err)
 	}
-
-	// Step 4: Fallback to stale cache for other provider errors
-	if cachedRate != nil {
-		log.Warn("provider error, falling back to stale cache",
-			"error", err.Error(),
-		)
-		// Return stale cache as fallback
-		staleRate, err := entity.NewExchangeRate(
-			cachedRate.Base,
-			cachedRate.Target,
-			cachedRate.Rate,
-			cachedRate.Timestamp,
-			true, // Mark as stale
-		)
-		if err == nil {
-			log.Info("returning stale cache as fallback",
-				"rate", staleRate.Rate,
-				"stale", true,
-			)
-			return dto.ToRateResponse(staleRate), nil
-		}
-	}
-
-	// Both cache and external API failed
 	log.Error("both cache and API failed",
 		"error", err.Error(),
 	)
@@ -1,15 +1,27 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/metrics"
 )
 
 // mockRepository is a mock implementation of ExchangeRateRepository for testing.
@@ -19,6 +31,8 @@ type mockRepository struct {
 	getByBaseFunc func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error)
 	deleteFunc    func(ctx context.Context, base, target entity.CurrencyCode) error
 	getStaleFunc  func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error)
+
+	getStaleByBaseFunc func(ctx context.Context, base entity.CurrencyCode, maxStaleness time.Duration) ([]*entity.ExchangeRate, error)
 }
 
 func (m *mockRepository) Get(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
@@ -56,6 +70,24 @@ func (m *mockRepository) GetStale(ctx context.Context, base, target entity.Curre
 	return nil, entity.ErrRateNotFound
 }
 
+// GetStaleByBase defaults to the rates from GetByBase no older than maxStaleness.
+func (m *mockRepository) GetStaleByBase(ctx context.Context, base entity.CurrencyCode, maxStaleness time.Duration) ([]*entity.ExchangeRate, error) {
+	if m.getStaleByBaseFunc != nil {
+		return m.getStaleByBaseFunc(ctx, base, maxStaleness)
+	}
+	rates, err := m.GetByBase(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	kept := []*entity.ExchangeRate{}
+	for _, rate := range rates {
+		if !rate.IsExpired(maxStaleness) {
+			kept = append(kept, rate)
+		}
+	}
+	return kept, nil
+}
+
 // mockProvider is a mock implementation of ExchangeRateProvider for testing.
 type mockProvider struct {
 	fetchRateFunc     func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error)
@@ -76,6 +108,16 @@ func (m *mockProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCo
 	return nil, errors.New("not implemented")
 }
 
+// mockRateAndAllProvider is a mockProvider that also implements provider.RateAndAllFetcher.
+type mockRateAndAllProvider struct {
+	mockProvider
+	fetchRateAndAllFunc func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, []*entity.ExchangeRate, error)
+}
+
+func (m *mockRateAndAllProvider) FetchRateAndAll(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, []*entity.ExchangeRate, error) {
+	return m.fetchRateAndAllFunc(ctx, base, target)
+}
+
 func TestGetExchangeRateUseCase_Execute(t *testing.T) {
 	ctx := context.Background()
 	cacheTTL := 1 * time.Hour
@@ -279,3 +321,1130 @@ func TestGetExchangeRateUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExchangeRateUseCase_Execute_AnomalyDetection(t *testing.T) {
+	ctx := context.Background()
+	cacheTTL := 1 * time.Hour
+	expiredTimestamp := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name       string
+		freshRate  float64
+		config     GetExchangeRateConfig
+		wantRate   float64
+		wantStale  bool
+		wantSource string
+		wantSaved  bool
+	}{
+		{
+			name:       "anomalous rate accepted when rejection disabled",
+			freshRate:  8.5,
+			config:     GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: false},
+			wantRate:   8.5,
+			wantStale:  false,
+			wantSource: dto.SourceProvider,
+			wantSaved:  true,
+		},
+		{
+			name:       "anomalous rate rejected in favor of cached rate",
+			freshRate:  8.5,
+			config:     GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true},
+			wantRate:   0.85,
+			wantStale:  true,
+			wantSource: dto.SourceStaleCache,
+			wantSaved:  false,
+		},
+		{
+			name:       "rate within threshold accepted when rejection enabled",
+			freshRate:  0.9,
+			config:     GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true},
+			wantRate:   0.9,
+			wantStale:  false,
+			wantSource: dto.SourceProvider,
+			wantSaved:  true,
+		},
+		{
+			name:       "detection disabled",
+			freshRate:  8.5,
+			config:     GetExchangeRateConfig{MaxRateDelta: 0, RejectAnomalousRates: true},
+			wantRate:   8.5,
+			wantStale:  false,
+			wantSource: dto.SourceProvider,
+			wantSaved:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			repo := &mockRepository{
+				getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return entity.NewExchangeRate(base, target, 0.85, expiredTimestamp, false)
+				},
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					saved = true
+					return nil
+				},
+			}
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return entity.NewExchangeRate(base, target, tt.freshRate, time.Now(), false)
+				},
+			}
+
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, cacheTTL, tt.config, nil)
+			resp, err := uc.Execute(ctx, dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if resp.Rate != tt.wantRate {
+				t.Errorf("Execute() Rate = %f, want %f", resp.Rate, tt.wantRate)
+			}
+			if resp.Stale != tt.wantStale {
+				t.Errorf("Execute() Stale = %v, want %v", resp.Stale, tt.wantStale)
+			}
+			if resp.Source != tt.wantSource {
+				t.Errorf("Execute() Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+			if saved != tt.wantSaved {
+				t.Errorf("rate saved = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_AnomalyEscapeHatch(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+
+	tests := []struct {
+		name        string
+		cachedAge   time.Duration
+		freshRates  []float64
+		config      GetExchangeRateConfig
+		wantSources []string
+	}{
+		{
+			name:        "accepted after consecutive confirmations",
+			cachedAge:   2 * time.Hour,
+			freshRates:  []float64{8.5, 8.6, 8.5},
+			config:      GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true, AnomalyConfirmations: 3},
+			wantSources: []string{dto.SourceStaleCache, dto.SourceStaleCache, dto.SourceProvider},
+		},
+		{
+			name:        "disagreeing fetches restart confirmation",
+			cachedAge:   2 * time.Hour,
+			freshRates:  []float64{8.5, 30, 30},
+			config:      GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true, AnomalyConfirmations: 3},
+			wantSources: []string{dto.SourceStaleCache, dto.SourceStaleCache, dto.SourceStaleCache},
+		},
+		{
+			name:        "normal fetch resets confirmation",
+			cachedAge:   2 * time.Hour,
+			freshRates:  []float64{8.5, 0.86, 8.5},
+			config:      GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true, AnomalyConfirmations: 2},
+			wantSources: []string{dto.SourceStaleCache, dto.SourceProvider, dto.SourceStaleCache},
+		},
+		{
+			name:        "accepted when cached rate is too old",
+			cachedAge:   48 * time.Hour,
+			freshRates:  []float64{8.5},
+			config:      GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true, AnomalyMaxCacheAge: 24 * time.Hour},
+			wantSources: []string{dto.SourceProvider},
+		},
+		{
+			name:        "escape hatches disabled",
+			cachedAge:   48 * time.Hour,
+			freshRates:  []float64{8.5, 8.5, 8.5, 8.5},
+			config:      GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true},
+			wantSources: []string{dto.SourceStaleCache, dto.SourceStaleCache, dto.SourceStaleCache, dto.SourceStaleCache},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachedTimestamp := time.Now().Add(-tt.cachedAge)
+			var saved []float64
+			repo := &mockRepository{
+				getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					// Saves are recorded but not read back, so the cached rate stays at 0.85
+					return entity.NewExchangeRate(base, target, 0.85, cachedTimestamp, false)
+				},
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					saved = append(saved, rate.Rate)
+					return nil
+				},
+			}
+			var fetches int
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					rate := tt.freshRates[fetches]
+					fetches++
+					if source := provider.FetchSourceFromContext(ctx); source != nil {
+						source.Record("primary", "https://example.com")
+					}
+					return entity.NewExchangeRate(base, target, rate, time.Now(), false)
+				},
+			}
+
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, cacheTTL, tt.config, nil)
+			for i, wantSource := range tt.wantSources {
+				ctx, source := provider.WithFetchSource(context.Background())
+				resp, err := uc.Execute(ctx, dto.GetRateRequest{Base: "USD", Target: "EUR"})
+				if err != nil {
+					t.Fatalf("Execute() #%d error = %v", i+1, err)
+				}
+				if resp.Source != wantSource {
+					t.Errorf("Execute() #%d Source = %q, want %q", i+1, resp.Source, wantSource)
+				}
+
+				// A rejected rate is served from the cache, so the fetch source must say so
+				wantFetchSource := "primary"
+				if wantSource == dto.SourceStaleCache {
+					wantFetchSource = dto.SourceCache
+				}
+				if source.Provider() != wantFetchSource {
+					t.Errorf("Execute() #%d FetchSource = %q, want %q", i+1, source.Provider(), wantFetchSource)
+				}
+			}
+
+			var wantSaved int
+			for _, s := range tt.wantSources {
+				if s == dto.SourceProvider {
+					wantSaved++
+				}
+			}
+			if len(saved) != wantSaved {
+				t.Errorf("saved %d rates (%v), want %d", len(saved), saved, wantSaved)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_CleansUpExpiredRate(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	grace := 24 * time.Hour
+
+	tests := []struct {
+		name        string
+		grace       time.Duration
+		rateAge     time.Duration
+		providerErr error
+		wantDeleted bool
+		wantErr     error
+	}{
+		{
+			name:        "deletes unsupported pair expired beyond grace",
+			grace:       grace,
+			rateAge:     cacheTTL + grace + time.Hour,
+			providerErr: provider.ErrCurrencyUnsupported,
+			wantDeleted: true,
+			wantErr:     provider.ErrCurrencyUnsupported,
+		},
+		{
+			name:        "keeps unsupported pair within grace",
+			grace:       grace,
+			rateAge:     cacheTTL + time.Hour,
+			providerErr: provider.ErrCurrencyUnsupported,
+		},
+		{
+			name:        "keeps rate on other provider errors",
+			grace:       grace,
+			rateAge:     cacheTTL + grace + time.Hour,
+			providerErr: errors.New("provider unavailable"),
+		},
+		{
+			name:        "disabled by default",
+			rateAge:     cacheTTL + grace + time.Hour,
+			providerErr: provider.ErrCurrencyUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			repo := &mockRepository{
+				getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-tt.rateAge), false)
+				},
+				deleteFunc: func(ctx context.Context, base, target entity.CurrencyCode) error {
+					deleted = true
+					return nil
+				},
+			}
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return nil, tt.providerErr
+				},
+			}
+
+			config := DefaultGetExchangeRateConfig()
+			config.ExpiredCleanupGrace = tt.grace
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+
+			// Deletes complete before Execute returns
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v (deleted rate must not be served stale)", err, tt.wantErr)
+				}
+			} else if err != nil || !resp.Stale {
+				t.Errorf("Execute() = %+v, %v, want the stale cached rate", resp, err)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_WarmsCacheOnFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		warm      bool
+		wantSaved []string
+	}{
+		{
+			name:      "caches every fetched rate",
+			warm:      true,
+			wantSaved: []string{"EUR", "GBP", "JPY"},
+		},
+		{
+			name:      "disabled by default",
+			wantSaved: []string{"EUR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saveMu sync.Mutex
+			var saved []string
+			repo := &mockRepository{
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					saveMu.Lock()
+					defer saveMu.Unlock()
+					saved = append(saved, rate.Target.String())
+					return nil
+				},
+			}
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					var all []*entity.ExchangeRate
+					for _, code := range []string{"EUR", "GBP", "JPY"} {
+						rate, _ := entity.NewExchangeRate(base, entity.CurrencyCode(code), 0.85, time.Now(), false)
+						all = append(all, rate)
+					}
+					if fetched := provider.FetchedRatesFromContext(ctx); fetched != nil {
+						fetched.Record(all)
+					}
+					return all[0], nil
+				},
+			}
+
+			config := DefaultGetExchangeRateConfig()
+			config.WarmCacheOnFetch = tt.warm
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+			if _, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"}); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			// The requested rate is saved first, and only once; warmed rates follow in any order
+			slices.Sort(saved[1:])
+			if !reflect.DeepEqual(saved, tt.wantSaved) {
+				t.Errorf("saved targets = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_WarmsCacheWithRateAndAllFetcher(t *testing.T) {
+	var saveMu sync.Mutex
+	var saved []string
+	repo := &mockRepository{
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			saveMu.Lock()
+			defer saveMu.Unlock()
+			saved = append(saved, rate.Target.String())
+			return nil
+		},
+	}
+	prov := &mockRateAndAllProvider{
+		fetchRateAndAllFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, []*entity.ExchangeRate, error) {
+			var all []*entity.ExchangeRate
+			for _, code := range []string{"EUR", "GBP", "JPY"} {
+				rate, _ := entity.NewExchangeRate(base, entity.CurrencyCode(code), 0.85, time.Now(), false)
+				all = append(all, rate)
+			}
+			return all[1], all, nil
+		},
+	}
+
+	config := DefaultGetExchangeRateConfig()
+	config.WarmCacheOnFetch = true
+	uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+	resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "GBP"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v (FetchRate must not be called)", err)
+	}
+	if resp.Target != "GBP" {
+		t.Errorf("Target = %q, want GBP", resp.Target)
+	}
+	slices.Sort(saved[1:])
+	if want := []string{"GBP", "EUR", "JPY"}; !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved targets = %v, want %v", saved, want)
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_WarmsCacheConcurrently(t *testing.T) {
+	const ratesCount = 300 // About the size of a full base file
+
+	var inFlight, maxInFlight, saved atomic.Int32
+	repo := &mockRepository{
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				prev := maxInFlight.Load()
+				if n <= prev || maxInFlight.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			saved.Add(1)
+			return nil
+		},
+	}
+	prov := &mockRateAndAllProvider{
+		fetchRateAndAllFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, []*entity.ExchangeRate, error) {
+			all := make([]*entity.ExchangeRate, 0, ratesCount)
+			for i := 0; i < ratesCount; i++ {
+				code := entity.CurrencyCode(fmt.Sprintf("%c%c%c", 'A'+i/676, 'A'+i/26%26, 'A'+i%26))
+				rate, _ := entity.NewExchangeRate(base, code, 1.5, time.Now(), false)
+				all = append(all, rate)
+			}
+			return all[0], all, nil
+		},
+	}
+
+	config := DefaultGetExchangeRateConfig()
+	config.WarmCacheOnFetch = true
+	config.SaveConcurrency = 10
+	uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+	if _, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "AAA"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Sequential saves would take about 600ms; concurrent ones finish well within cacheWarmTimeout
+	if got := saved.Load(); got != ratesCount {
+		t.Errorf("saves = %d, want %d", got, ratesCount)
+	}
+	if got := maxInFlight.Load(); got < 2 || got > 10 {
+		t.Errorf("max concurrent saves = %d, want between 2 and 10", got)
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_EmbeddedFallback(t *testing.T) {
+	embedded := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.9, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), true)
+		},
+	}
+
+	tests := []struct {
+		name        string
+		embedded    bool
+		stale       bool // A stale cached rate is available
+		providerErr error
+		wantSource  string
+		wantErr     bool
+	}{
+		{"serves embedded rate when everything fails", true, false, errors.New("provider down"), dto.SourceEmbedded, false},
+		{"serves embedded rate when the circuit is open", true, false, circuitbreaker.ErrCircuitOpen, dto.SourceEmbedded, false},
+		{"prefers the provider", true, false, nil, dto.SourceProvider, false},
+		{"prefers the stale cache", true, true, errors.New("provider down"), dto.SourceStaleCache, false},
+		{"disabled by default", false, false, errors.New("provider down"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					if tt.stale {
+						return entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-2*time.Hour), false)
+					}
+					return nil, entity.ErrRateNotFound
+				},
+			}
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					if tt.providerErr != nil {
+						return nil, tt.providerErr
+					}
+					return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+				},
+			}
+
+			config := DefaultGetExchangeRateConfig()
+			if tt.embedded {
+				config.EmbeddedFallback = embedded
+			}
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Execute() = %+v, want error", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if resp.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+			isEmbedded := tt.wantSource == dto.SourceEmbedded
+			if resp.Approximate != isEmbedded {
+				t.Errorf("Approximate = %v, want %v", resp.Approximate, isEmbedded)
+			}
+			if isEmbedded && (!resp.Stale || resp.Rate != 0.9) {
+				t.Errorf("embedded response = %+v, want the stale snapshot rate", resp)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_AbsoluteMaxAge(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	maxAge := 12 * time.Hour
+
+	tests := []struct {
+		name        string
+		rateAge     time.Duration
+		providerErr error
+		wantErr     error
+	}{
+		{"serves an hour-old rate", time.Hour + time.Minute, errors.New("provider down"), nil},
+		{"refuses a day-old rate", 24 * time.Hour, errors.New("provider down"), provider.ErrProviderUnavailable},
+		{"refuses a day-old rate with the circuit open", 24 * time.Hour, circuitbreaker.ErrCircuitOpen, provider.ErrProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachedRate := func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+				return entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-tt.rateAge), false)
+			}
+			repo := &mockRepository{getFunc: cachedRate, getStaleFunc: cachedRate}
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return nil, tt.providerErr
+				},
+			}
+
+			config := DefaultGetExchangeRateConfig()
+			config.AbsoluteMaxAge = maxAge
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, cacheTTL, config, nil)
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !resp.Stale {
+				t.Errorf("Execute() = %+v, %v, want the stale cached rate", resp, err)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_AbsoluteMaxAgeRejectedAnomaly(t *testing.T) {
+	saved := false
+	repo := &mockRepository{
+		getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-24*time.Hour), false)
+		},
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			saved = true
+			return nil
+		},
+	}
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 8.5, time.Now(), false)
+		},
+	}
+
+	config := DefaultGetExchangeRateConfig()
+	config.MaxRateDelta = 0.5
+	config.RejectAnomalousRates = true
+	config.AnomalyMaxCacheAge = 0 // Never accept the anomalous rate
+	config.AbsoluteMaxAge = 12 * time.Hour
+	uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+	resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+
+	// Neither the day-old cached rate nor the rejected fresh rate is served
+	if !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("Execute() = %+v, %v, want ErrProviderUnavailable", resp, err)
+	}
+	if saved {
+		t.Error("rejected anomalous rate was saved")
+	}
+}
+
+// loggedCacheResults returns the cache_result attribute of every "cache result" log line.
+func loggedCacheResults(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	var results []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to parse log line %q: %v", line, err)
+		}
+		if entry["msg"] == "cache result" {
+			results = append(results, fmt.Sprint(entry["cache_result"]))
+		}
+	}
+	return results
+}
+
+func TestGetExchangeRateUseCase_Execute_LogsCacheResult(t *testing.T) {
+	cacheTTL := 1 * time.Hour
+	validTimestamp := time.Now().Add(-30 * time.Minute)
+	expiredTimestamp := time.Now().Add(-2 * time.Hour)
+
+	cached := func(timestamp time.Time) func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+		return func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.85, timestamp, false)
+		}
+	}
+	notFound := func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+		return nil, entity.ErrRateNotFound
+	}
+	fetchOK := func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+		return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+	}
+	fetchErr := func(err error) func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+		return func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return nil, err
+		}
+	}
+
+	tests := []struct {
+		name             string
+		repoGetFunc      func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error)
+		repoGetStaleFunc func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error)
+		providerFunc     func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error)
+		config           GetExchangeRateConfig
+		wantErr          bool
+		want             metrics.CacheResult
+	}{
+		{
+			name:         "valid cache entry",
+			repoGetFunc:  cached(validTimestamp),
+			providerFunc: fetchErr(errors.New("provider must not be called")),
+			want:         metrics.CacheHit,
+		},
+		{
+			name:         "cache miss fetched from provider",
+			repoGetFunc:  notFound,
+			providerFunc: fetchOK,
+			want:         metrics.CacheMiss,
+		},
+		{
+			name:         "expired cache entry refreshed from provider",
+			repoGetFunc:  cached(expiredTimestamp),
+			providerFunc: fetchOK,
+			want:         metrics.CacheMiss,
+		},
+		{
+			name:        "anomalous fresh rate rejected",
+			repoGetFunc: cached(expiredTimestamp),
+			providerFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+				return entity.NewExchangeRate(base, target, 8.5, time.Now(), false)
+			},
+			config: GetExchangeRateConfig{MaxRateDelta: 0.5, RejectAnomalousRates: true},
+			want:   metrics.CacheStale,
+		},
+		{
+			name:             "circuit open with stale cache",
+			repoGetFunc:      notFound,
+			repoGetStaleFunc: cached(expiredTimestamp),
+			providerFunc:     fetchErr(circuitbreaker.ErrCircuitOpen),
+			want:             metrics.CacheStale,
+		},
+		{
+			name:         "circuit open without stale cache",
+			repoGetFunc:  notFound,
+			providerFunc: fetchErr(circuitbreaker.ErrCircuitOpen),
+			wantErr:      true,
+			want:         metrics.CacheMiss,
+		},
+		{
+			name:         "provider error with expired cache",
+			repoGetFunc:  cached(expiredTimestamp),
+			providerFunc: fetchErr(errors.New("API error")),
+			want:         metrics.CacheStale,
+		},
+		{
+			name:         "provider error without cache",
+			repoGetFunc:  notFound,
+			providerFunc: fetchErr(errors.New("API error")),
+			wantErr:      true,
+			want:         metrics.CacheMiss,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+
+			repo := &mockRepository{getFunc: tt.repoGetFunc, getStaleFunc: tt.repoGetStaleFunc}
+			prov := &mockProvider{fetchRateFunc: tt.providerFunc}
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, cacheTTL, tt.config, log)
+			counter := &metrics.CacheCounter{}
+			uc.cacheCounter = counter
+
+			_, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			results := loggedCacheResults(t, &buf)
+			if len(results) != 1 || results[0] != string(tt.want) {
+				t.Errorf("logged cache_result = %v, want [%s]", results, tt.want)
+			}
+			if got := counter.Stats().Total(); got != 1 {
+				t.Errorf("counter total = %d, want 1", got)
+			}
+		})
+	}
+}
+
+// memoryFetchLock is an in-memory repository.FetchLock shared by simulated instances.
+type memoryFetchLock struct {
+	mu   sync.Mutex
+	held map[string]bool
+	err  error
+}
+
+func (l *memoryFetchLock) Acquire(ctx context.Context, base, target entity.CurrencyCode) (bool, error) {
+	if l.err != nil {
+		return false, l.err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := base.String() + "/" + target.String()
+	if l.held[key] {
+		return false, nil
+	}
+	l.held[key] = true
+	return true, nil
+}
+
+func (l *memoryFetchLock) Release(ctx context.Context, base, target entity.CurrencyCode) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.held, base.String()+"/"+target.String())
+	return nil
+}
+
+func TestGetExchangeRateUseCase_Execute_FetchLockStampede(t *testing.T) {
+	// Shared cache and lock across simulated instances
+	var cacheMu sync.Mutex
+	var cached *entity.ExchangeRate
+	repo := &mockRepository{
+		getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			cacheMu.Lock()
+			defer cacheMu.Unlock()
+			if cached == nil {
+				return nil, entity.ErrRateNotFound
+			}
+			return cached, nil
+		},
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			cacheMu.Lock()
+			defer cacheMu.Unlock()
+			cached = rate
+			return nil
+		},
+	}
+	lock := &memoryFetchLock{held: make(map[string]bool)}
+
+	var providerCalls atomic.Int32
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			providerCalls.Add(1)
+			time.Sleep(150 * time.Millisecond)
+			return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+		},
+	}
+
+	config := DefaultGetExchangeRateConfig()
+	config.FetchLock = lock
+	config.FetchLockWait = 2 * time.Second
+	instances := []*GetExchangeRateUseCase{
+		NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil),
+		NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(uc *GetExchangeRateUseCase) {
+			defer wg.Done()
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if err != nil {
+				t.Errorf("Execute() error = %v", err)
+				return
+			}
+			if resp.Rate != 0.85 {
+				t.Errorf("Rate = %v, want 0.85", resp.Rate)
+			}
+		}(instances[i%len(instances)])
+	}
+	wg.Wait()
+
+	if got := providerCalls.Load(); got != 1 {
+		t.Errorf("provider calls = %d, want 1", got)
+	}
+	if len(lock.held) != 0 {
+		t.Errorf("locks still held after requests: %v", lock.held)
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_FetchLockFallsBackToFetching(t *testing.T) {
+	tests := []struct {
+		name string
+		lock *memoryFetchLock
+	}{
+		{
+			name: "lock error",
+			lock: &memoryFetchLock{held: make(map[string]bool), err: errors.New("dynamodb throttled")},
+		},
+		{
+			name: "holder saves no rate in time",
+			lock: &memoryFetchLock{held: map[string]bool{"USD/EUR": true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providerCalls := 0
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					providerCalls++
+					return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+				},
+			}
+
+			config := DefaultGetExchangeRateConfig()
+			config.FetchLock = tt.lock
+			config.FetchLockWait = 250 * time.Millisecond
+			uc := NewGetExchangeRateUseCaseWithConfig(&mockRepository{}, prov, time.Hour, config, nil)
+
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Source != dto.SourceProvider {
+				t.Errorf("Source = %q, want %q", resp.Source, dto.SourceProvider)
+			}
+			if providerCalls != 1 {
+				t.Errorf("provider calls = %d, want 1", providerCalls)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_RefreshAhead(t *testing.T) {
+	tests := []struct {
+		name        string
+		age         time.Duration
+		fraction    float64
+		wantRefresh bool
+	}{
+		{"near expiry refreshes before returning", 55 * time.Minute, 0.2, true},
+		{"plenty of freshness left", 10 * time.Minute, 0.2, false},
+		{"disabled", 59 * time.Minute, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachedRate, _ := entity.NewExchangeRate("USD", "EUR", 0.85, time.Now().Add(-tt.age), false)
+
+			var saveMu sync.Mutex
+			var saved []*entity.ExchangeRate
+			repo := &mockRepository{
+				getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return cachedRate, nil
+				},
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					saveMu.Lock()
+					defer saveMu.Unlock()
+					saved = append(saved, rate)
+					return nil
+				},
+			}
+			var providerCalls atomic.Int32
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					providerCalls.Add(1)
+					return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+				},
+			}
+
+			config := DefaultGetExchangeRateConfig()
+			config.RefreshAheadFraction = tt.fraction
+			config.RefreshAheadJitter = 0
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			// The cached rate is served, still fresh
+			if resp.Rate != 0.85 || resp.Stale || resp.Source != dto.SourceCache {
+				t.Errorf("response = rate %v, stale %v, source %q; want cached 0.85, fresh", resp.Rate, resp.Stale, resp.Source)
+			}
+
+			// The refresh completes before Execute returns
+			wantCalls := int32(0)
+			if tt.wantRefresh {
+				wantCalls = 1
+			}
+			if got := providerCalls.Load(); got != wantCalls {
+				t.Errorf("provider calls = %d, want %d", got, wantCalls)
+			}
+			if tt.wantRefresh && (len(saved) != 1 || saved[0].Rate != 0.86) {
+				t.Errorf("saved = %v, want the refreshed rate 0.86", saved)
+			}
+			if !tt.wantRefresh && len(saved) != 0 {
+				t.Errorf("saved %d rates, want none", len(saved))
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_RefreshAheadOncePerPair(t *testing.T) {
+	cachedRate, _ := entity.NewExchangeRate("USD", "EUR", 0.85, time.Now().Add(-58*time.Minute), false)
+	repo := &mockRepository{
+		getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return cachedRate, nil
+		},
+	}
+
+	release := make(chan struct{})
+	var providerCalls atomic.Int32
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			providerCalls.Add(1)
+			<-release
+			return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+		},
+	}
+
+	config := DefaultGetExchangeRateConfig()
+	config.RefreshAheadFraction = 0.2
+	uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+
+	// Requests arriving while the refresh runs share it instead of starting another
+	var wg sync.WaitGroup
+	execute := func() {
+		defer wg.Done()
+		resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"})
+		if err != nil {
+			t.Errorf("Execute() error = %v", err)
+			return
+		}
+		if resp.Rate != 0.85 || resp.Source != dto.SourceCache {
+			t.Errorf("response = rate %v from %q, want cached 0.85", resp.Rate, resp.Source)
+		}
+	}
+	wg.Add(1)
+	go execute()
+	for providerCalls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go execute()
+	}
+	time.Sleep(50 * time.Millisecond) // Let the other requests join the refresh
+	close(release)
+	wg.Wait()
+
+	if got := providerCalls.Load(); got != 1 {
+		t.Errorf("provider calls = %d, want 1", got)
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_RefreshAheadPublishesEvent(t *testing.T) {
+	cachedRate, _ := entity.NewExchangeRate("USD", "EUR", 0.85, time.Now().Add(-58*time.Minute), false)
+
+	// Stands in for a repository that publishes a rate event on every save
+	var events []repository.RateEvent
+	repo := &mockRepository{
+		getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return cachedRate, nil
+		},
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			events = append(events, repository.NewRateEvent(rate, nil))
+			return nil
+		},
+	}
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+		},
+	}
+
+	config := DefaultGetExchangeRateConfig()
+	config.RefreshAheadFraction = 0.2
+	config.RefreshAheadJitter = 0
+	uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+
+	if _, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: "EUR"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// The event is published before Execute returns, so the router's flush sends it
+	if len(events) != 1 || events[0].Rate != 0.86 {
+		t.Errorf("published events = %+v, want one for the refreshed rate 0.86", events)
+	}
+}
+
+func TestGetExchangeRateUseCase_SetCacheTTL(t *testing.T) {
+	cachedRate, _ := entity.NewExchangeRate("USD", "EUR", 0.85, time.Now().Add(-30*time.Minute), false)
+	var savedTTL time.Duration
+	repo := &mockRepository{
+		getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return cachedRate, nil
+		},
+		saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+			savedTTL = ttl
+			return nil
+		},
+	}
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+		},
+	}
+	uc := NewGetExchangeRateUseCase(repo, prov, time.Hour, nil)
+	req := dto.GetRateRequest{Base: "USD", Target: "EUR"}
+
+	// The 30-minute-old rate is fresh under the 1-hour TTL
+	resp, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Source != dto.SourceCache {
+		t.Errorf("Source = %q before reload, want %q", resp.Source, dto.SourceCache)
+	}
+
+	// After a reload to 15 minutes it is expired, and the fresh rate is saved with the new TTL
+	uc.SetCacheTTL(15 * time.Minute)
+	resp, err = uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Rate != 0.86 {
+		t.Errorf("Rate = %v after reload, want the fetched 0.86", resp.Rate)
+	}
+	if savedTTL != 15*time.Minute {
+		t.Errorf("saved TTL = %v, want 15m", savedTTL)
+	}
+}
+
+func TestGetExchangeRateUseCase_Execute_NoCachePairs(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		strategy     FallbackStrategy
+		providerErr  error
+		wantSource   string
+		wantProvider bool
+	}{
+		{"normal pair served from cache", "EUR", CacheFirstStrategy(), nil, dto.SourceCache, false},
+		{"no-cache pair calls the provider", "BTC", CacheFirstStrategy(), nil, dto.SourceProvider, true},
+		{"no-cache pair skips stale-ok cache", "BTC", StaleOKStrategy(), nil, dto.SourceProvider, true},
+		{"no-cache pair falls back to stale cache", "BTC", CacheFirstStrategy(), errors.New("provider down"), dto.SourceStaleCache, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved bool
+			repo := &mockRepository{
+				getFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return entity.NewExchangeRate(base, target, 0.85, time.Now().Add(-time.Minute), false)
+				},
+				saveFunc: func(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
+					saved = true
+					return nil
+				},
+			}
+			var providerCalled bool
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					providerCalled = true
+					if tt.providerErr != nil {
+						return nil, tt.providerErr
+					}
+					return entity.NewExchangeRate(base, target, 0.86, time.Now(), false)
+				},
+			}
+
+			noCachePairs, err := ParseNoCachePairs([]string{" usd/btc ", ""})
+			if err != nil {
+				t.Fatalf("ParseNoCachePairs() error = %v", err)
+			}
+			config := DefaultGetExchangeRateConfig()
+			config.FallbackStrategy = tt.strategy
+			config.NoCachePairs = noCachePairs
+			uc := NewGetExchangeRateUseCaseWithConfig(repo, prov, time.Hour, config, nil)
+
+			resp, err := uc.Execute(context.Background(), dto.GetRateRequest{Base: "USD", Target: tt.target})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", resp.Source, tt.wantSource)
+			}
+			if providerCalled != tt.wantProvider {
+				t.Errorf("provider called = %v, want %v", providerCalled, tt.wantProvider)
+			}
+			if wantSaved := tt.wantProvider && tt.providerErr == nil; saved != wantSaved {
+				t.Errorf("rate saved = %v, want %v", saved, wantSaved)
+			}
+		})
+	}
+}
+
+func TestParseNoCachePairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{"normalizes entries", []string{" btc/usd", "ETH/USD", ""}, map[string]bool{"BTC/USD": true, "ETH/USD": true}, false},
+		{"missing separator", []string{"BTCUSD"}, nil, true},
+		{"invalid currency code", []string{"BTC/US"}, nil, true},
+		{"wildcards are not supported", []string{"BTC/*"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNoCachePairs(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNoCachePairs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNoCachePairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,35 +4,211 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
 	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
+// Health probe currency pair used to check provider connectivity.
+const (
+	healthProbeBase   entity.CurrencyCode = "USD"
+	healthProbeTarget entity.CurrencyCode = "EUR"
+)
+
+// CircuitStateReader gives read-only access to the provider circuit breaker state.
+// api.CircuitBreakerProvider implements it.
+type CircuitStateReader interface {
+	CircuitState(base entity.CurrencyCode) circuitbreaker.State
+}
+
+// ProviderProbe is a named provider checked individually by the health check.
+type ProviderProbe struct {
+	Name     string                        // Reported as the "provider_<name>" check
+	Provider provider.ExchangeRateProvider // Provider to probe
+	Breaker  CircuitStateReader            // Skips the probe while open (optional)
+}
+
+// HealthCheckConfig holds optional behavior settings for HealthCheckUseCase.
+type HealthCheckConfig struct {
+	ProbeTimeout time.Duration // Maximum time for each provider probe (0 = no limit beyond the request context)
+
+	// RequireCache reports the service degraded until CacheBase has cached rates,
+	// so a cold instance is not reported ready (empty CacheBase disables the check)
+	RequireCache bool
+	CacheBase    entity.CurrencyCode
+}
+
+// DefaultHealthCheckConfig returns the default use case configuration.
+//
+// Default values:
+// - ProbeTimeout: 2 seconds (health checks must answer well within load balancer timeouts)
+// - RequireCache: false (a cold cache does not affect the status)
+func DefaultHealthCheckConfig() HealthCheckConfig {
+	return HealthCheckConfig{
+		ProbeTimeout: 2 * time.Second,
+	}
+}
+
 // HealthCheckUseCase handles the use case for health checking the service.
 // This implements UC3 from the specification.
 type HealthCheckUseCase struct {
 	repository repository.ExchangeRateRepository
+	provider   provider.ExchangeRateProvider // Probed for connectivity (nil skips the provider check)
+	breaker    CircuitStateReader            // Skips the probe while open (nil always probes)
+	probes     []ProviderProbe               // Providers probed individually and concurrently
+	config     HealthCheckConfig
 }
 
 // NewHealthCheckUseCase creates a new HealthCheckUseCase with dependency injection.
 func NewHealthCheckUseCase(repo repository.ExchangeRateRepository) *HealthCheckUseCase {
+	return NewHealthCheckUseCaseWithProvider(repo, nil, nil)
+}
+
+// NewHealthCheckUseCaseWithProvider creates a HealthCheckUseCase that also checks
+// provider connectivity. If breaker is non-nil and its circuit is open, the provider
+// is reported degraded without a probe call.
+//
+// prov should be the provider behind the breaker, not the breaker-wrapped one:
+// the breaker state is only read, so health probes (e.g. load balancer polling
+// during an outage) never count as failures or use up half-open trial calls.
+func NewHealthCheckUseCaseWithProvider(repo repository.ExchangeRateRepository, prov provider.ExchangeRateProvider, breaker CircuitStateReader) *HealthCheckUseCase {
+	return NewHealthCheckUseCaseWithConfig(repo, prov, breaker, DefaultHealthCheckConfig())
+}
+
+// NewHealthCheckUseCaseWithConfig creates a HealthCheckUseCase like
+// NewHealthCheckUseCaseWithProvider, with custom configuration.
+func NewHealthCheckUseCaseWithConfig(repo repository.ExchangeRateRepository, prov provider.ExchangeRateProvider, breaker CircuitStateReader, config HealthCheckConfig) *HealthCheckUseCase {
 	return &HealthCheckUseCase{
 		repository: repo,
+		provider:   prov,
+		breaker:    breaker,
+		config:     config,
 	}
 }
 
+// NewHealthCheckUseCaseWithProviders creates a HealthCheckUseCase that probes each
+// provider concurrently and reports it as "provider_<name>": "healthy" or "unhealthy".
+func NewHealthCheckUseCaseWithProviders(repo repository.ExchangeRateRepository, probes []ProviderProbe, config HealthCheckConfig) *HealthCheckUseCase {
+	return &HealthCheckUseCase{
+		repository: repo,
+		probes:     probes,
+		config:     config,
+	}
+}
+
+// checkProvider reports the provider as "healthy" or "degraded".
+//
+// While the circuit breaker is open the provider is known to be failing, so no
+// probe is sent. An open breaker whose cooldown has expired is still reported
+// degraded until the next rate request moves it to half-open.
+// The probe is bounded by ProbeTimeout.
+func (uc *HealthCheckUseCase) checkProvider(ctx context.Context, checks map[string]string) bool {
+	if uc.breaker != nil && uc.breaker.CircuitState(healthProbeBase) == circuitbreaker.StateOpen {
+		checks["provider"] = "degraded"
+		checks["provider_error"] = circuitbreaker.ErrCircuitOpen.Error()
+		return false
+	}
+
+	if uc.config.ProbeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.config.ProbeTimeout)
+		defer cancel()
+	}
+	if _, err := uc.provider.FetchRate(ctx, healthProbeBase, healthProbeTarget); err != nil {
+		checks["provider"] = "degraded"
+		checks["provider_error"] = err.Error()
+		return false
+	}
+	checks["provider"] = "healthy"
+	return true
+}
+
+// checkCache reports the cache as "warm" if CacheBase has cached rates, or "cold".
+//
+// Rates are counted regardless of expiry: an expired rate can still be served
+// as a stale fallback, so only an empty cache makes the instance unready.
+func (uc *HealthCheckUseCase) checkCache(ctx context.Context, checks map[string]string) bool {
+	rates, err := uc.repository.GetByBase(ctx, uc.config.CacheBase)
+	var partial *repository.PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		checks["cache"] = "cold"
+		checks["cache_error"] = err.Error()
+		return false
+	}
+	if len(rates) == 0 {
+		checks["cache"] = "cold"
+		return false
+	}
+	checks["cache"] = "warm"
+	return true
+}
+
+// probeProviders probes every registered provider concurrently, each bounded by
+// ProbeTimeout, and reports whether at least one is healthy.
+//
+// Providers whose circuit breaker is open are reported unhealthy without a probe.
+func (uc *HealthCheckUseCase) probeProviders(ctx context.Context, checks map[string]string) bool {
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		anyHealthy bool
+	)
+	setResult := func(name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := "provider_" + name
+		if err != nil {
+			checks[key] = "unhealthy"
+			checks[key+"_error"] = err.Error()
+			return
+		}
+		checks[key] = "healthy"
+		anyHealthy = true
+	}
+
+	for _, probe := range uc.probes {
+		if probe.Breaker != nil && probe.Breaker.CircuitState(healthProbeBase) == circuitbreaker.StateOpen {
+			setResult(probe.Name, circuitbreaker.ErrCircuitOpen)
+			continue
+		}
+
+		wg.Add(1)
+		go func(probe ProviderProbe) {
+			defer wg.Done()
+			probeCtx := ctx
+			if uc.config.ProbeTimeout > 0 {
+				var cancel context.CancelFunc
+				probeCtx, cancel = context.WithTimeout(ctx, uc.config.ProbeTimeout)
+				defer cancel()
+			}
+			_, err := probe.Provider.FetchRate(probeCtx, healthProbeBase, healthProbeTarget)
+			setResult(probe.Name, err)
+		}(probe)
+	}
+	wg.Wait()
+
+	return anyHealthy
+}
+
 // Execute executes the health check use case.
 //
 // Checks:
 // 1. Lambda function status (always OK if we're running)
 // 2. DynamoDB connectivity (via repository)
-// 3. Optionally: External API connectivity (not implemented in Phase 2)
+// 3. Optionally: External API connectivity (if a provider is configured)
+// 4. Optionally: Each registered provider, probed concurrently with ProbeTimeout
+// 5. Optionally (RequireCache): Cached rates for CacheBase exist
 //
 // Returns:
 // - Status "healthy" if all checks pass
+// - Status "degraded" if only provider checks fail (stale cache can still be served)
+// - Status "degraded" if RequireCache is set and the cache is cold
+// - With registered providers, at least one must be healthy to avoid "degraded"
 // - Status "unhealthy" if any critical check fails
 func (uc *HealthCheckUseCase) Execute(ctx context.Context, req dto.HealthCheckRequest) (dto.HealthCheckResponse, error) {
 	checks := make(map[string]string)
@@ -71,9 +247,28 @@ func (uc *HealthCheckUseCase) Execute(ctx context.Context, req dto.HealthCheckRe
 		checks["dynamodb"] = "healthy"
 	}
 
+	// Check 3: Provider connectivity (not critical - stale cache is served while it fails)
+	providerHealthy := true
+	if uc.provider != nil {
+		providerHealthy = uc.checkProvider(ctx, checks)
+	}
+
+	// Check 4: Registered providers (healthy while at least one is up)
+	if len(uc.probes) > 0 && !uc.probeProviders(ctx, checks) {
+		providerHealthy = false
+	}
+
+	// Check 5: Cache warmth (not critical - the first request warms it)
+	cacheWarm := true
+	if uc.config.RequireCache && uc.config.CacheBase != "" {
+		cacheWarm = uc.checkCache(ctx, checks)
+	}
+
 	status := "healthy"
 	if !allHealthy {
 		status = "unhealthy"
+	} else if !providerHealthy || !cacheWarm {
+		status = "degraded"
 	}
 
 	return dto.HealthCheckResponse{
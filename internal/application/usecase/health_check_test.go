@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
 func TestHealthCheckUseCase_Execute(t *testing.T) {
@@ -87,3 +90,272 @@ func TestHealthCheckUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+// mockCircuitState is a fixed CircuitStateReader for testing.
+type mockCircuitState circuitbreaker.State
+
+func (m mockCircuitState) CircuitState(base entity.CurrencyCode) circuitbreaker.State {
+	return circuitbreaker.State(m)
+}
+
+func TestHealthCheckUseCase_Execute_Provider(t *testing.T) {
+	tests := []struct {
+		name         string
+		breaker      CircuitStateReader
+		providerErr  error
+		wantStatus   string
+		wantProvider string
+		wantProbes   int
+	}{
+		{
+			name:         "provider healthy",
+			breaker:      mockCircuitState(circuitbreaker.StateClosed),
+			wantStatus:   "healthy",
+			wantProvider: "healthy",
+			wantProbes:   1,
+		},
+		{
+			name:         "provider probe fails",
+			breaker:      mockCircuitState(circuitbreaker.StateClosed),
+			providerErr:  errors.New("API error"),
+			wantStatus:   "degraded",
+			wantProvider: "degraded",
+			wantProbes:   1,
+		},
+		{
+			name:         "open circuit skips the probe",
+			breaker:      mockCircuitState(circuitbreaker.StateOpen),
+			wantStatus:   "degraded",
+			wantProvider: "degraded",
+			wantProbes:   0,
+		},
+		{
+			name:         "half-open circuit is probed",
+			breaker:      mockCircuitState(circuitbreaker.StateHalfOpen),
+			wantStatus:   "healthy",
+			wantProvider: "healthy",
+			wantProbes:   1,
+		},
+		{
+			name:         "no breaker always probes",
+			wantStatus:   "healthy",
+			wantProvider: "healthy",
+			wantProbes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{}
+			probes := 0
+			prov := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					probes++
+					if tt.providerErr != nil {
+						return nil, tt.providerErr
+					}
+					return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+				},
+			}
+
+			uc := NewHealthCheckUseCaseWithProvider(repo, prov, tt.breaker)
+			resp, err := uc.Execute(context.Background(), dto.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", resp.Status, tt.wantStatus)
+			}
+			if resp.Checks["provider"] != tt.wantProvider {
+				t.Errorf("provider check = %q, want %q", resp.Checks["provider"], tt.wantProvider)
+			}
+			if probes != tt.wantProbes {
+				t.Errorf("provider called %d times, want %d", probes, tt.wantProbes)
+			}
+		})
+	}
+}
+
+func TestHealthCheckUseCase_Execute_ProviderTimeout(t *testing.T) {
+	prov := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	uc := NewHealthCheckUseCaseWithProvider(&mockRepository{}, prov, nil)
+	uc.config.ProbeTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	resp, err := uc.Execute(context.Background(), dto.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute() took %v, want the probe bounded by ProbeTimeout", elapsed)
+	}
+	if resp.Status != "degraded" || resp.Checks["provider"] != "degraded" {
+		t.Errorf("Status = %q, provider = %q, want degraded", resp.Status, resp.Checks["provider"])
+	}
+}
+
+func TestHealthCheckUseCase_Execute_RequireCache(t *testing.T) {
+	usd, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	cachedRate, _ := entity.NewExchangeRate(usd, eur, 0.85, time.Now(), false)
+
+	tests := []struct {
+		name         string
+		getByBase    func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error)
+		requireCache bool
+		wantStatus   string
+		wantCache    string
+	}{
+		{
+			name: "warm cache is ready",
+			getByBase: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+				if base != usd {
+					t.Errorf("GetByBase(%s), want the default base USD", base)
+				}
+				return []*entity.ExchangeRate{cachedRate}, nil
+			},
+			requireCache: true,
+			wantStatus:   "healthy",
+			wantCache:    "warm",
+		},
+		{
+			name: "cold cache is degraded",
+			getByBase: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+				return []*entity.ExchangeRate{}, nil
+			},
+			requireCache: true,
+			wantStatus:   "degraded",
+			wantCache:    "cold",
+		},
+		{
+			name: "repository error is degraded",
+			getByBase: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+				return nil, errors.New("throttled")
+			},
+			requireCache: true,
+			wantStatus:   "degraded",
+			wantCache:    "cold",
+		},
+		{
+			name: "cold cache ignored when not required",
+			getByBase: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+				return []*entity.ExchangeRate{}, nil
+			},
+			wantStatus: "healthy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultHealthCheckConfig()
+			config.RequireCache = tt.requireCache
+			config.CacheBase = usd
+			uc := NewHealthCheckUseCaseWithConfig(&mockRepository{getByBaseFunc: tt.getByBase}, nil, nil, config)
+
+			resp, err := uc.Execute(context.Background(), dto.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", resp.Status, tt.wantStatus)
+			}
+			if got := resp.Checks["cache"]; got != tt.wantCache {
+				t.Errorf("cache check = %q, want %q", got, tt.wantCache)
+			}
+		})
+	}
+}
+
+func TestHealthCheckUseCase_Execute_NoProvider(t *testing.T) {
+	uc := NewHealthCheckUseCase(&mockRepository{})
+
+	resp, err := uc.Execute(context.Background(), dto.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, ok := resp.Checks["provider"]; ok {
+		t.Errorf("provider check = %q, want none without a provider", resp.Checks["provider"])
+	}
+}
+
+func TestHealthCheckUseCase_Execute_ProviderProbes(t *testing.T) {
+	healthy := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+		},
+	}
+	failing := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return nil, errors.New("API error")
+		},
+	}
+	hanging := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	tests := []struct {
+		name       string
+		probes     []ProviderProbe
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			name: "all providers healthy",
+			probes: []ProviderProbe{
+				{Name: "primary", Provider: healthy},
+				{Name: "secondary", Provider: healthy},
+			},
+			wantStatus: "healthy",
+			wantChecks: map[string]string{"provider_primary": "healthy", "provider_secondary": "healthy"},
+		},
+		{
+			name: "one provider healthy",
+			probes: []ProviderProbe{
+				{Name: "primary", Provider: failing},
+				{Name: "secondary", Provider: healthy},
+				{Name: "tertiary", Provider: hanging},
+			},
+			wantStatus: "healthy",
+			wantChecks: map[string]string{"provider_primary": "unhealthy", "provider_secondary": "healthy", "provider_tertiary": "unhealthy"},
+		},
+		{
+			name: "no provider healthy",
+			probes: []ProviderProbe{
+				{Name: "primary", Provider: failing},
+				{Name: "secondary", Provider: healthy, Breaker: mockCircuitState(circuitbreaker.StateOpen)},
+			},
+			wantStatus: "degraded",
+			wantChecks: map[string]string{"provider_primary": "unhealthy", "provider_secondary": "unhealthy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := HealthCheckConfig{ProbeTimeout: 50 * time.Millisecond}
+			uc := NewHealthCheckUseCaseWithProviders(&mockRepository{}, tt.probes, config)
+
+			resp, err := uc.Execute(context.Background(), dto.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", resp.Status, tt.wantStatus)
+			}
+			for key, want := range tt.wantChecks {
+				if got := resp.Checks[key]; got != want {
+					t.Errorf("Checks[%q] = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// InvalidateRateUseCase handles the admin use case for removing a cached exchange rate,
+// so the next request for the pair fetches a fresh rate from the provider.
+type InvalidateRateUseCase struct {
+	repository repository.ExchangeRateRepository
+	logger     *logger.Logger
+}
+
+// NewInvalidateRateUseCase creates a new InvalidateRateUseCase with dependency injection.
+func NewInvalidateRateUseCase(repo repository.ExchangeRateRepository, log *logger.Logger) *InvalidateRateUseCase {
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	return &InvalidateRateUseCase{
+		repository: repo,
+		logger:     log,
+	}
+}
+
+// Execute removes the cached rate for a currency pair.
+//
+// Flow:
+// 1. Validate currency codes
+// 2. Delete the cached rate (repository.Delete)
+//
+// Returns entity.ErrRateNotFound if no rate is cached for the pair.
+func (uc *InvalidateRateUseCase) Execute(ctx context.Context, req dto.InvalidateRateRequest) (dto.InvalidateRateResponse, error) {
+	ctx = logger.WithCurrencyCodes(ctx, req.Base, req.Target)
+	log := uc.logger.WithContext(ctx)
+
+	base, err := entity.NewCurrencyCode(req.Base)
+	if err != nil {
+		return dto.InvalidateRateResponse{}, fmt.Errorf("invalid base currency: %w", err)
+	}
+	target, err := entity.NewCurrencyCode(req.Target)
+	if err != nil {
+		return dto.InvalidateRateResponse{}, fmt.Errorf("invalid target currency: %w", err)
+	}
+	if base.Equal(target) {
+		return dto.InvalidateRateResponse{}, fmt.Errorf("currency code validation: %w", entity.ErrCurrencyCodeMismatch)
+	}
+
+	if err := uc.repository.Delete(ctx, base, target); err != nil {
+		return dto.InvalidateRateResponse{}, fmt.Errorf("failed to invalidate cached rate: %w", err)
+	}
+
+	log.Info("invalidated cached rate")
+	return dto.InvalidateRateResponse{
+		Base:        base.String(),
+		Target:      target.String(),
+		Invalidated: true,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestInvalidateRateUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         dto.InvalidateRateRequest
+		deleteErr   error
+		wantDeleted bool
+		wantErr     error
+	}{
+		{
+			name:        "deletes cached rate",
+			req:         dto.InvalidateRateRequest{Base: "usd", Target: "EUR"},
+			wantDeleted: true,
+		},
+		{
+			name:        "rate not cached",
+			req:         dto.InvalidateRateRequest{Base: "USD", Target: "EUR"},
+			deleteErr:   entity.ErrRateNotFound,
+			wantDeleted: true,
+			wantErr:     entity.ErrRateNotFound,
+		},
+		{
+			name:    "invalid base",
+			req:     dto.InvalidateRateRequest{Base: "US", Target: "EUR"},
+			wantErr: entity.ErrInvalidCurrencyCode,
+		},
+		{
+			name:    "same currencies",
+			req:     dto.InvalidateRateRequest{Base: "USD", Target: "USD"},
+			wantErr: entity.ErrCurrencyCodeMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
+			repo := &mockRepository{
+				deleteFunc: func(ctx context.Context, base, target entity.CurrencyCode) error {
+					deleted = append(deleted, base.String()+"/"+target.String())
+					return tt.deleteErr
+				},
+			}
+
+			uc := NewInvalidateRateUseCase(repo, nil)
+			resp, err := uc.Execute(context.Background(), tt.req)
+			if (len(deleted) == 1) != tt.wantDeleted {
+				t.Errorf("Delete calls = %v, want deleted = %v", deleted, tt.wantDeleted)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if deleted[0] != "USD/EUR" {
+				t.Errorf("deleted %q, want USD/EUR", deleted[0])
+			}
+			if !resp.Invalidated || resp.Base != "USD" || resp.Target != "EUR" {
+				t.Errorf("Execute() = %+v, want USD/EUR invalidated", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// ParseNoCachePairs parses NoCachePairs entries of the form "BASE/TARGET",
+// e.g. "BTC/USD", into the set used by GetExchangeRateConfig.
+//
+// Entries are trimmed and uppercased; empty entries are ignored.
+// Returns an error if an entry is not "BASE/TARGET" with valid currency codes.
+func ParseNoCachePairs(entries []string) (map[string]bool, error) {
+	pairs := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToUpper(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		baseStr, targetStr, ok := strings.Cut(entry, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid no-cache pair %q: want BASE/TARGET", entry)
+		}
+		base, err := entity.NewCurrencyCode(baseStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid no-cache pair %q: %w", entry, err)
+		}
+		target, err := entity.NewCurrencyCode(targetStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid no-cache pair %q: %w", entry, err)
+		}
+		pairs[noCacheKey(base, target)] = true
+	}
+	return pairs, nil
+}
+
+// noCacheKey returns the NoCachePairs key of a pair.
+func noCacheKey(base, target entity.CurrencyCode) string {
+	return base.Normalize().String() + "/" + target.Normalize().String()
+}
+
+// skipsCacheStep reports whether step is skipped for the pair because it is in
+// NoCachePairs: StepCache and StepRecentStaleCache serve from the cache without
+// trying the provider, so a volatile pair always goes to the provider first.
+// The provider step still caches its rate, for StepStaleCache to fall back on.
+func (uc *GetExchangeRateUseCase) skipsCacheStep(res *rateResolution, step ResolutionStep) bool {
+	if step != StepCache && step != StepRecentStaleCache {
+		return false
+	}
+	return uc.config.NoCachePairs[noCacheKey(res.base, res.target)]
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// refreshAheadTimeout bounds a refresh-ahead fetch and save within a request.
+const refreshAheadTimeout = 2 * time.Second
+
+// refreshWindow returns how much freshness a cached rate may have left before it
+// is refreshed ahead of expiry: RefreshAheadFraction of the cache TTL, shrunk by
+// a random part of up to RefreshAheadJitter so rates cached together (e.g. by an
+// all-rates fetch) are refreshed on different requests.
+func (uc *GetExchangeRateUseCase) refreshWindow() time.Duration {
+	window := float64(uc.ttl()) * uc.config.RefreshAheadFraction
+	if jitter := uc.config.RefreshAheadJitter; jitter > 0 {
+		window *= 1 - jitter*rand.Float64()
+	}
+	return time.Duration(window)
+}
+
+// refreshAhead refreshes a valid cached rate once its remaining freshness drops
+// below the refresh window (see refreshWindow). The caller keeps serving the
+// cached rate.
+//
+// The refresh runs inline because Lambda freezes the sandbox once the handler
+// returns, so background work may never finish, and because the rate event of
+// the save must be buffered before the router flushes events. Concurrent
+// requests for the same pair in this instance share one refresh (singleflight).
+// The refresh is best effort and bounded by refreshAheadTimeout: failures are
+// only logged, and a fresh rate that fails anomaly detection is not saved, so it
+// is handled by the provider step once the cached rate expires.
+func (uc *GetExchangeRateUseCase) refreshAhead(ctx context.Context, cached *entity.ExchangeRate) {
+	if uc.config.RefreshAheadFraction <= 0 || uc.ttl() <= 0 {
+		return
+	}
+	remaining := uc.ttl() - cached.Age()
+	if remaining >= uc.refreshWindow() {
+		return
+	}
+
+	log := uc.logger.WithContext(ctx)
+	key := cached.Base.String() + "/" + cached.Target.String()
+	_, _, shared := uc.refreshGroup.Do(key, func() (any, error) {
+		log.Debug("refreshing cached rate ahead of expiry", "remaining", remaining.String())
+
+		refreshCtx, cancel := context.WithTimeout(ctx, refreshAheadTimeout)
+		defer cancel()
+
+		fresh, err := uc.provider.FetchRate(refreshCtx, cached.Base, cached.Target)
+		if err != nil || fresh == nil {
+			if err != nil {
+				log.Warn("refresh-ahead fetch failed", "error", err.Error())
+			}
+			return nil, nil
+		}
+		if anomalous, delta := uc.isAnomalous(cached, fresh); anomalous {
+			log.Warn("refresh-ahead rate deviates from cached rate beyond threshold, not saved",
+				"cached_rate", cached.Rate,
+				"fresh_rate", fresh.Rate,
+				"delta", delta,
+			)
+			return nil, nil
+		}
+		if err := uc.repository.Save(refreshCtx, fresh, uc.ttl()); err != nil {
+			log.Warn("failed to save refresh-ahead rate", "error", err.Error())
+			return nil, nil
+		}
+		log.Debug("cached rate refreshed ahead of expiry", "rate", fresh.Rate)
+		return nil, nil
+	})
+	if shared {
+		log.Debug("joined a refresh-ahead already in flight")
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+)
+
+// saveRates saves rates to the cache with at most concurrency saves in flight.
+//
+// Cache save failures are not fatal, so saveRates does not stop at the first
+// error: every failed save is collected and returned. Once ctx is done, no new
+// saves are started and the rates left unsaved are reported as one error.
+// Values of concurrency below 1 save sequentially.
+//
+// Returns the errors of the failed saves, or nil if all rates were saved.
+func saveRates(ctx context.Context, repo repository.ExchangeRateRepository, rates []*entity.ExchangeRate, ttl time.Duration, concurrency int) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(rates) {
+		concurrency = len(rates)
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	collect := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	jobs := make(chan *entity.ExchangeRate)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rate := range jobs {
+				if err := repo.Save(ctx, rate, ttl); err != nil {
+					collect(fmt.Errorf("failed to save %s/%s: %w", rate.Base, rate.Target, err))
+				}
+			}
+		}()
+	}
+
+feed:
+	for i, rate := range rates {
+		if rate == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			collect(fmt.Errorf("%d rates not saved: %w", len(rates)-i, ctx.Err()))
+			break
+		}
+		select {
+		case jobs <- rate:
+		case <-ctx.Done():
+			collect(fmt.Errorf("%d rates not saved: %w", len(rates)-i, ctx.Err()))
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}
@@ -48,6 +48,13 @@ func (c CurrencyCode) String() string {
 	return string(c)
 }
 
+// Normalize returns the canonical form of the currency code (trimmed, uppercase).
+// Codes created with NewCurrencyCode are already normalized; this guards codes
+// built by direct conversion, e.g. CurrencyCode("usd").
+func (c CurrencyCode) Normalize() CurrencyCode {
+	return CurrencyCode(strings.ToUpper(strings.TrimSpace(string(c))))
+}
+
 // IsValid checks if the currency code is valid.
 func (c CurrencyCode) IsValid() bool {
 	return currencyCodeRegex.MatchString(string(c))
@@ -57,4 +64,3 @@ func (c CurrencyCode) IsValid() bool {
 func (c CurrencyCode) Equal(other CurrencyCode) bool {
 	return strings.EqualFold(string(c), string(other))
 }
-
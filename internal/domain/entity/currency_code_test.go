@@ -94,6 +94,26 @@ func TestCurrencyCode_String(t *testing.T) {
 	}
 }
 
+func TestCurrencyCode_Normalize(t *testing.T) {
+	tests := []struct {
+		code CurrencyCode
+		want CurrencyCode
+	}{
+		{CurrencyCode("USD"), CurrencyCode("USD")},
+		{CurrencyCode("usd"), CurrencyCode("USD")},
+		{CurrencyCode("eUr"), CurrencyCode("EUR")},
+		{CurrencyCode(" gbp "), CurrencyCode("GBP")},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			if got := tt.code.Normalize(); got != tt.want {
+				t.Errorf("CurrencyCode.Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCurrencyCode_IsValid(t *testing.T) {
 	tests := []struct {
 		name string
@@ -18,5 +18,13 @@ var (
 
 	// ErrRateNotFound indicates that an exchange rate was not found
 	ErrRateNotFound = errors.New("exchange rate not found")
-)
 
+	// ErrRateOutOfRange indicates an exchange rate outside the configured sane bounds
+	ErrRateOutOfRange = errors.New("exchange rate out of range")
+
+	// ErrInvalidBidAsk indicates bid/ask prices that are not finite, positive, and ordered around the rate
+	ErrInvalidBidAsk = errors.New("invalid bid/ask spread")
+
+	// ErrPairDenied indicates a currency pair that operators have blocked from being served
+	ErrPairDenied = errors.New("currency pair denied")
+)
@@ -6,14 +6,67 @@ import (
 	"time"
 )
 
+const (
+	// DefaultMinRate is the default lower bound for exchange rate values.
+	DefaultMinRate = 1e-12
+
+	// DefaultMaxRate is the default upper bound for exchange rate values.
+	// It is generous enough for pairs like USD/IRR or BTC/VND.
+	DefaultMaxRate = 1e12
+)
+
+// RateBounds holds the inclusive range of accepted exchange rate values.
+// Rates outside this range are treated as upstream data corruption.
+// Providers check fresh rates against the bounds before they reach the cache;
+// cached rates are not re-checked, so tightening the bounds never hides stored data.
+type RateBounds struct {
+	Min float64
+	Max float64
+}
+
+// DefaultRateBounds returns the default rate bounds.
+func DefaultRateBounds() RateBounds {
+	return RateBounds{Min: DefaultMinRate, Max: DefaultMaxRate}
+}
+
+// Validate validates the bounds.
+// Returns an error if either bound is not positive and finite, or if Min > Max.
+func (b RateBounds) Validate() error {
+	if b.Min <= 0 || math.IsInf(b.Min, 0) || math.IsNaN(b.Min) {
+		return fmt.Errorf("minimum rate must be positive and finite, got %g", b.Min)
+	}
+	if b.Max <= 0 || math.IsInf(b.Max, 0) || math.IsNaN(b.Max) {
+		return fmt.Errorf("maximum rate must be positive and finite, got %g", b.Max)
+	}
+	if b.Min > b.Max {
+		return fmt.Errorf("minimum rate %g cannot exceed maximum rate %g", b.Min, b.Max)
+	}
+	return nil
+}
+
+// Check validates a rate against the bounds.
+// Returns an error wrapping ErrRateOutOfRange if the rate is outside [Min, Max].
+func (b RateBounds) Check(rate float64) error {
+	if rate < b.Min || rate > b.Max {
+		return fmt.Errorf("%w: rate must be between %g and %g, got %g", ErrRateOutOfRange, b.Min, b.Max, rate)
+	}
+	return nil
+}
+
 // ExchangeRate represents an exchange rate between two currencies.
 // It is the core domain entity for the currency exchange rate service.
+//
+// Rate is the mid rate. Bid and Ask are only set when the provider quotes them
+// (most providers do not); use SetBidAsk to set them with validation.
 type ExchangeRate struct {
 	Base      CurrencyCode
 	Target    CurrencyCode
 	Rate      float64
 	Timestamp time.Time
-	Stale     bool // Indicates if the rate is stale (from cache fallback)
+	Stale     bool     // Indicates if the rate is stale (from cache fallback)
+	Bid       *float64 // Price buyers pay for the target currency, nil when unavailable
+	Ask       *float64 // Price sellers ask for the target currency, nil when unavailable
+	Derived   bool     // Computed from the inverse pair instead of quoted directly by the provider
 }
 
 // This is a constructor function, using the Constructor/Factory pattern
@@ -73,6 +126,51 @@ func validateExchangeRate(base, target CurrencyCode, rate float64, timestamp tim
 	return nil
 }
 
+// SetBidAsk sets the optional bid and ask prices.
+// Either may be nil when unavailable. Present values must be positive and finite,
+// and satisfy Bid <= Rate <= Ask.
+// Returns an error (and leaves the current prices unchanged) if they are invalid.
+func (e *ExchangeRate) SetBidAsk(bid, ask *float64) error {
+	if err := validateBidAsk(e.Rate, bid, ask); err != nil {
+		return err
+	}
+	e.Bid = copyPrice(bid)
+	e.Ask = copyPrice(ask)
+	return nil
+}
+
+// validateBidAsk validates optional bid and ask prices against the mid rate.
+func validateBidAsk(rate float64, bid, ask *float64) error {
+	for _, price := range []struct {
+		name  string
+		value *float64
+	}{{"bid", bid}, {"ask", ask}} {
+		if price.value == nil {
+			continue
+		}
+		if v := *price.value; v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			return fmt.Errorf("%w: %s must be positive and finite, got %g", ErrInvalidBidAsk, price.name, v)
+		}
+	}
+
+	if bid != nil && *bid > rate {
+		return fmt.Errorf("%w: bid %g exceeds rate %g", ErrInvalidBidAsk, *bid, rate)
+	}
+	if ask != nil && *ask < rate {
+		return fmt.Errorf("%w: ask %g is below rate %g", ErrInvalidBidAsk, *ask, rate)
+	}
+	return nil
+}
+
+// copyPrice returns a copy of an optional price, so callers cannot mutate the entity.
+func copyPrice(price *float64) *float64 {
+	if price == nil {
+		return nil
+	}
+	v := *price
+	return &v
+}
+
 // IsExpired checks if the exchange rate is expired based on the given TTL duration.
 // Returns true if the current time is at or after the expiration time (timestamp + TTL).
 // Returns false if TTL is zero or negative (no expiration).
@@ -91,6 +189,29 @@ func (e *ExchangeRate) Age() time.Duration {
 	return time.Since(e.Timestamp)
 }
 
+// AgeSeconds returns the age of the exchange rate in whole seconds, for DTOs and metrics.
+// Returns 0 for future timestamps (e.g. clock skew) instead of a negative age.
+func (e *ExchangeRate) AgeSeconds() int64 {
+	age := e.Age()
+	if age < 0 {
+		return 0
+	}
+	return int64(age / time.Second)
+}
+
+// StaleDuration returns how long ago the exchange rate expired for the given TTL.
+// Returns 0 if the rate is still valid, or if TTL is zero or negative (no expiration).
+func (e *ExchangeRate) StaleDuration(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return 0
+	}
+	stale := e.Age() - ttl
+	if stale < 0 {
+		return 0
+	}
+	return stale
+}
+
 // IsValid checks if the exchange rate is still valid (not expired) for the given TTL.
 func (e *ExchangeRate) IsValid(ttl time.Duration) bool {
 	return !e.IsExpired(ttl)
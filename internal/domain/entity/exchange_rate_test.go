@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -285,6 +286,57 @@ func TestExchangeRate_Age(t *testing.T) {
 	})
 }
 
+func TestExchangeRate_AgeSeconds(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		want      int64
+		tolerance int64
+	}{
+		{"fresh rate", time.Now().Add(-90 * time.Second), 90, 5},
+		{"expired rate", time.Now().Add(-2 * time.Hour), 7200, 5},
+		{"future timestamp clamped", time.Now().Add(1 * time.Hour), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Built directly so future timestamps bypass NewExchangeRate validation
+			rate := &ExchangeRate{Base: "USD", Target: "EUR", Rate: 0.85, Timestamp: tt.timestamp}
+
+			got := rate.AgeSeconds()
+			if got < tt.want || got > tt.want+tt.tolerance {
+				t.Errorf("ExchangeRate.AgeSeconds() = %d, want %d (within %d)", got, tt.want, tt.tolerance)
+			}
+		})
+	}
+}
+
+func TestExchangeRate_StaleDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		ttl       time.Duration
+		want      time.Duration
+		tolerance time.Duration
+	}{
+		{"fresh rate", time.Now().Add(-30 * time.Minute), time.Hour, 0, 0},
+		{"expired rate", time.Now().Add(-3 * time.Hour), time.Hour, 2 * time.Hour, 5 * time.Second},
+		{"future timestamp", time.Now().Add(time.Hour), time.Hour, 0, 0},
+		{"no expiration", time.Now().Add(-3 * time.Hour), 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := &ExchangeRate{Base: "USD", Target: "EUR", Rate: 0.85, Timestamp: tt.timestamp}
+
+			got := rate.StaleDuration(tt.ttl)
+			if got < tt.want || got > tt.want+tt.tolerance {
+				t.Errorf("ExchangeRate.StaleDuration(%v) = %v, want %v (within %v)", tt.ttl, got, tt.want, tt.tolerance)
+			}
+		})
+	}
+}
+
 func TestExchangeRate_IsValid(t *testing.T) {
 	base, _ := NewCurrencyCode("USD")
 	target, _ := NewCurrencyCode("EUR")
@@ -322,3 +374,128 @@ func TestExchangeRate_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestRateBounds_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  RateBounds
+		rate    float64
+		wantErr bool
+	}{
+		{name: "exactly min", bounds: DefaultRateBounds(), rate: DefaultMinRate, wantErr: false},
+		{name: "exactly max", bounds: DefaultRateBounds(), rate: DefaultMaxRate, wantErr: false},
+		{name: "below min", bounds: DefaultRateBounds(), rate: DefaultMinRate / 10, wantErr: true},
+		{name: "above max", bounds: DefaultRateBounds(), rate: DefaultMaxRate * 10, wantErr: true},
+		{name: "absurd magnitude", bounds: DefaultRateBounds(), rate: 1e30, wantErr: true},
+		{name: "custom bounds accept", bounds: RateBounds{Min: 0.5, Max: 2}, rate: 1, wantErr: false},
+		{name: "custom bounds reject", bounds: RateBounds{Min: 0.5, Max: 2}, rate: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bounds.Check(tt.rate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrRateOutOfRange) {
+				t.Errorf("Check() error = %v, want ErrRateOutOfRange", err)
+			}
+		})
+	}
+}
+
+func TestRateBounds_Validate(t *testing.T) {
+	if err := DefaultRateBounds().Validate(); err != nil {
+		t.Errorf("DefaultRateBounds().Validate() error = %v, want nil", err)
+	}
+
+	invalid := []RateBounds{
+		{Min: 0, Max: 1},
+		{Min: 2, Max: 1},
+		{Min: 1, Max: math.Inf(1)},
+	}
+	for _, bounds := range invalid {
+		if err := bounds.Validate(); err == nil {
+			t.Errorf("Validate(%+v) expected error, got nil", bounds)
+		}
+	}
+}
+
+func TestNewExchangeRate_IgnoresRateBounds(t *testing.T) {
+	base, _ := NewCurrencyCode("USD")
+	target, _ := NewCurrencyCode("EUR")
+
+	// Bounds apply to provider input only; stored rates must still load
+	if _, err := NewExchangeRate(base, target, DefaultMaxRate*10, time.Now(), false); err != nil {
+		t.Errorf("NewExchangeRate() error = %v, want nil", err)
+	}
+}
+
+func TestExchangeRate_SetBidAsk(t *testing.T) {
+	base, _ := NewCurrencyCode("USD")
+	target, _ := NewCurrencyCode("EUR")
+	price := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name    string
+		bid     *float64
+		ask     *float64
+		wantErr bool
+	}{
+		{name: "both unavailable", bid: nil, ask: nil, wantErr: false},
+		{name: "valid spread", bid: price(0.84), ask: price(0.86), wantErr: false},
+		{name: "zero spread", bid: price(0.85), ask: price(0.85), wantErr: false},
+		{name: "only bid", bid: price(0.84), ask: nil, wantErr: false},
+		{name: "only ask", bid: nil, ask: price(0.86), wantErr: false},
+		{name: "bid above rate", bid: price(0.86), ask: price(0.87), wantErr: true},
+		{name: "ask below rate", bid: price(0.83), ask: price(0.84), wantErr: true},
+		{name: "inverted spread", bid: price(0.86), ask: price(0.84), wantErr: true},
+		{name: "non-positive bid", bid: price(0), ask: nil, wantErr: true},
+		{name: "NaN ask", bid: nil, ask: price(math.NaN()), wantErr: true},
+		{name: "infinite ask", bid: nil, ask: price(math.Inf(1)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := NewExchangeRate(base, target, 0.85, time.Now(), false)
+			if err != nil {
+				t.Fatalf("NewExchangeRate() error = %v", err)
+			}
+
+			err = rate.SetBidAsk(tt.bid, tt.ask)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetBidAsk() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidBidAsk) {
+					t.Errorf("SetBidAsk() error = %v, want ErrInvalidBidAsk", err)
+				}
+				if rate.Bid != nil || rate.Ask != nil {
+					t.Error("SetBidAsk() changed prices on error")
+				}
+				return
+			}
+			if (tt.bid == nil) != (rate.Bid == nil) || (tt.bid != nil && *rate.Bid != *tt.bid) {
+				t.Errorf("Bid = %v, want %v", rate.Bid, tt.bid)
+			}
+			if (tt.ask == nil) != (rate.Ask == nil) || (tt.ask != nil && *rate.Ask != *tt.ask) {
+				t.Errorf("Ask = %v, want %v", rate.Ask, tt.ask)
+			}
+		})
+	}
+}
+
+func TestExchangeRate_SetBidAsk_CopiesPrices(t *testing.T) {
+	base, _ := NewCurrencyCode("USD")
+	target, _ := NewCurrencyCode("EUR")
+	rate, _ := NewExchangeRate(base, target, 0.85, time.Now(), false)
+
+	bid, ask := 0.84, 0.86
+	if err := rate.SetBidAsk(&bid, &ask); err != nil {
+		t.Fatalf("SetBidAsk() error = %v", err)
+	}
+	bid, ask = 1, 0.5
+	if *rate.Bid != 0.84 || *rate.Ask != 0.86 {
+		t.Errorf("prices = %v/%v after caller mutation, want 0.84/0.86", *rate.Bid, *rate.Ask)
+	}
+}
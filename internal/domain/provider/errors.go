@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Provider errors shared by ExchangeRateProvider implementations.
+var (
+	// ErrUpstreamInvalidResponse indicates the upstream API returned a response
+	// that could not be used (e.g. exceeding the maximum allowed size)
+	ErrUpstreamInvalidResponse = errors.New("upstream returned an invalid response")
+
+	// ErrProviderRequestBuild indicates the request to the upstream API could
+	// not be built (e.g. a malformed endpoint URL)
+	ErrProviderRequestBuild = errors.New("failed to build provider request")
+
+	// ErrProviderTransport indicates the request to the upstream API failed
+	// before a response was received (e.g. DNS, connection or TLS errors, timeouts)
+	ErrProviderTransport = errors.New("provider request failed")
+
+	// ErrProviderRead indicates the upstream API responded but its body could
+	// not be read (e.g. the connection dropped mid-body)
+	ErrProviderRead = errors.New("failed to read provider response")
+
+	// ErrProviderBusy indicates the maximum number of concurrent provider calls
+	// was reached and the caller could not get a slot in time
+	ErrProviderBusy = errors.New("too many concurrent provider calls")
+
+	// ErrCurrencyUnsupported indicates the provider does not offer rates for a
+	// requested currency. This is a client-side error, not a provider fault
+	ErrCurrencyUnsupported = errors.New("currency not supported by provider")
+
+	// ErrProviderUnavailable indicates the provider could not be reached and no
+	// cached rate was recent enough to serve as a fallback
+	ErrProviderUnavailable = errors.New("provider unavailable")
+)
+
+// UnsupportedTargetError reports a target currency missing from the provider's
+// rates for a base it does serve. It matches ErrCurrencyUnsupported with errors.Is.
+//
+// Available lists the target codes the provider offers for the base, sorted,
+// so clients can be pointed at alternatives.
+type UnsupportedTargetError struct {
+	Base      string
+	Target    string
+	Available []string
+}
+
+func (e *UnsupportedTargetError) Error() string {
+	return fmt.Sprintf("target currency %s not found in response", e.Target)
+}
+
+// Unwrap returns ErrCurrencyUnsupported.
+func (e *UnsupportedTargetError) Unwrap() error { return ErrCurrencyUnsupported }
@@ -64,3 +64,20 @@ type ExchangeRateProvider interface {
 	// Context cancellation: Returns error if ctx is cancelled or times out.
 	FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error)
 }
+
+// RateAndAllFetcher is an optional extension of ExchangeRateProvider for providers
+// that download every rate for the base currency to answer a single-pair fetch.
+//
+// Callers can type-assert for it to get the full set without a second download,
+// e.g. to warm a cache. Providers that don't implement it can still report the
+// full set through a FetchedRates collector on the context.
+type RateAndAllFetcher interface {
+	ExchangeRateProvider
+
+	// FetchRateAndAll retrieves the rate for a currency pair together with every
+	// rate downloaded for the base currency, in a single request.
+	//
+	// The target rate is returned as FetchRate would return it; the full set
+	// includes it and shares its timestamp. Returns the same errors as FetchRate.
+	FetchRateAndAll(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, []*entity.ExchangeRate, error)
+}
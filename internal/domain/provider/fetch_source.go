@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// fetchSourceKey is the context key for FetchSource.
+type fetchSourceKey struct{}
+
+// FetchSource records which provider and URL served a fetch.
+//
+// Like FetchStats, callers that want to know where a response came from (e.g.
+// to log it or expose it in a response header) attach a FetchSource to the
+// context with WithFetchSource. Providers record into it on success; the most
+// recent record wins, so wrapping providers can overwrite their delegates.
+//
+// FetchSource is safe for concurrent use.
+type FetchSource struct {
+	mu       sync.Mutex
+	provider string
+	url      string
+}
+
+// WithFetchSource returns a context carrying a new FetchSource collector.
+func WithFetchSource(ctx context.Context) (context.Context, *FetchSource) {
+	source := &FetchSource{}
+	return context.WithValue(ctx, fetchSourceKey{}, source), source
+}
+
+// FetchSourceFromContext returns the FetchSource attached to ctx, or nil if none.
+func FetchSourceFromContext(ctx context.Context) *FetchSource {
+	source, _ := ctx.Value(fetchSourceKey{}).(*FetchSource)
+	return source
+}
+
+// Record sets the provider name and URL that served the fetch.
+func (s *FetchSource) Record(provider, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = provider
+	s.url = url
+}
+
+// Provider returns the recorded provider name, or "" if nothing was fetched.
+func (s *FetchSource) Provider() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provider
+}
+
+// URL returns the recorded URL, or "" if none was recorded.
+func (s *FetchSource) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.url
+}
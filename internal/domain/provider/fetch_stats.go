@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// fetchStatsKey is the context key for FetchStats.
+type fetchStatsKey struct{}
+
+// FetchStats collects observability data about a provider fetch.
+//
+// Provider methods return only domain entities, so callers that want to know
+// how many entries were dropped while parsing attach a FetchStats to the
+// context with WithFetchStats. Providers record into it if present.
+//
+// FetchStats is safe for concurrent use.
+type FetchStats struct {
+	mu          sync.Mutex
+	skipped     int
+	skipReasons map[string]int
+}
+
+// WithFetchStats returns a context carrying a new FetchStats collector.
+func WithFetchStats(ctx context.Context) (context.Context, *FetchStats) {
+	stats := &FetchStats{skipReasons: make(map[string]int)}
+	return context.WithValue(ctx, fetchStatsKey{}, stats), stats
+}
+
+// FetchStatsFromContext returns the FetchStats attached to ctx, or nil if none.
+func FetchStatsFromContext(ctx context.Context) *FetchStats {
+	stats, _ := ctx.Value(fetchStatsKey{}).(*FetchStats)
+	return stats
+}
+
+// Record adds skipped entries and their reasons to the stats.
+func (s *FetchStats) Record(skipped int, reasons map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipped += skipped
+	for reason, count := range reasons {
+		s.skipReasons[reason] += count
+	}
+}
+
+// Skipped returns the total number of skipped entries.
+func (s *FetchStats) Skipped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skipped
+}
+
+// SkipReasons returns a copy of the skipped entry counts per reason.
+func (s *FetchStats) SkipReasons() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reasons := make(map[string]int, len(s.skipReasons))
+	for reason, count := range s.skipReasons {
+		reasons[reason] = count
+	}
+	return reasons
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// fetchedRatesKey is the context key for FetchedRates.
+type fetchedRatesKey struct{}
+
+// FetchedRates collects every rate a provider parsed while serving a single-pair fetch.
+//
+// Providers that download all rates for the base to answer FetchRate (e.g. one
+// file per base currency) can hand the full set to callers that attach a
+// FetchedRates to the context with WithFetchedRates, so the rates can be cached
+// without another download. Like FetchSource, the most recent record wins, so
+// wrapping providers can overwrite (or clear) what their delegates recorded.
+//
+// FetchedRates is safe for concurrent use.
+type FetchedRates struct {
+	mu    sync.Mutex
+	rates []*entity.ExchangeRate
+}
+
+// WithFetchedRates returns a context carrying a new FetchedRates collector.
+func WithFetchedRates(ctx context.Context) (context.Context, *FetchedRates) {
+	fetched := &FetchedRates{}
+	return context.WithValue(ctx, fetchedRatesKey{}, fetched), fetched
+}
+
+// FetchedRatesFromContext returns the FetchedRates attached to ctx, or nil if none.
+func FetchedRatesFromContext(ctx context.Context) *FetchedRates {
+	fetched, _ := ctx.Value(fetchedRatesKey{}).(*FetchedRates)
+	return fetched
+}
+
+// Record replaces the recorded rates; nil clears them.
+func (f *FetchedRates) Record(rates []*entity.ExchangeRate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rates = rates
+}
+
+// Rates returns the recorded rates, or nil if none were recorded.
+func (f *FetchedRates) Rates() []*entity.ExchangeRate {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rates
+}
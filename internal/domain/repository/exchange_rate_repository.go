@@ -53,6 +53,9 @@ type ExchangeRateRepository interface {
 	//
 	// Returns an empty slice (not nil) if no rates are found. This is not an error.
 	//
+	// If some stored rates cannot be read, implementations may return the valid
+	// rates together with a *PartialResultError instead of failing entirely.
+	//
 	// Like Get(), this method returns rates regardless of TTL expiration.
 	// The caller should check expiration if needed.
 	//
@@ -79,4 +82,17 @@ type ExchangeRateRepository interface {
 	//
 	// Context cancellation: Returns error if ctx is cancelled.
 	GetStale(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error)
+
+	// GetStaleByBase retrieves the stale (expired) exchange rates for a base
+	// currency for fallback scenarios, mirroring GetStale().
+	//
+	// Rates older than maxStaleness are excluded, so the caller gets
+	// stale-but-not-too-old data; a maxStaleness <= 0 excludes nothing.
+	//
+	// Returns an empty slice (not nil) if no rates are left. Like GetByBase(),
+	// implementations may return the remaining rates together with a
+	// *PartialResultError if some stored rates cannot be read.
+	//
+	// Context cancellation: Returns error if ctx is cancelled.
+	GetStaleByBase(ctx context.Context, base entity.CurrencyCode, maxStaleness time.Duration) ([]*entity.ExchangeRate, error)
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// FetchLock coordinates provider fetches of a currency pair across instances,
+// so that a cache stampede triggers one provider call instead of one per instance.
+// This is a port in the Hexagonal Architecture pattern.
+//
+// Locks expire on their own (implementations choose the lease), so a holder that
+// crashes never blocks a pair for longer than the lease.
+type FetchLock interface {
+	// Acquire tries to take the lock for the pair.
+	// Returns false if another holder has an unexpired lock.
+	Acquire(ctx context.Context, base, target entity.CurrencyCode) (bool, error)
+
+	// Release releases a lock taken by Acquire.
+	// Releasing a lock that expired or was taken over by another holder is not an error.
+	Release(ctx context.Context, base, target entity.CurrencyCode) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// PartialResultError reports that a multi-item read succeeded for some items
+// but had to skip others (e.g. stored items that could not be decoded).
+//
+// It is non-fatal: Rates holds the valid subset, and implementations also
+// return that subset alongside the error. Callers that can serve partial data
+// detect it with errors.As and continue; callers that treat every error as
+// fatal keep working unchanged.
+type PartialResultError struct {
+	Rates   []*entity.ExchangeRate // Valid rates that were read
+	Skipped int                    // Number of items that were skipped
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("partial result: %d items skipped, %d returned", e.Skipped, len(e.Rates))
+}
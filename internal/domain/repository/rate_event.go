@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// RateEventTypeUpdated is the event type published when a rate is written to the cache.
+const RateEventTypeUpdated = "rate.updated"
+
+// RateEvent is the payload published to downstream consumers on cache writes.
+type RateEvent struct {
+	Type      string    `json:"type"`
+	Base      string    `json:"base"`
+	Target    string    `json:"target"`
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+	Stale     bool      `json:"stale"`
+	ExpiresAt *int64    `json:"expires_at,omitempty"` // Unix epoch in seconds, matches the cache TTL
+}
+
+// NewRateEvent builds a RateEvent for a saved exchange rate.
+func NewRateEvent(rate *entity.ExchangeRate, expiresAt *int64) RateEvent {
+	return RateEvent{
+		Type:      RateEventTypeUpdated,
+		Base:      rate.Base.String(),
+		Target:    rate.Target.String(),
+		Rate:      rate.Rate,
+		Timestamp: rate.Timestamp,
+		Stale:     rate.Stale,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// EventPublisher publishes rate events to downstream consumers (e.g. SNS, EventBridge).
+// This is a port in the Hexagonal Architecture pattern.
+//
+// Repositories publish after a successful write. Publishing is best effort:
+// implementations return errors, but callers must not fail the write because of them.
+type EventPublisher interface {
+	Publish(ctx context.Context, event RateEvent) error
+}
+
+// EventFlusher is implemented by EventPublishers that buffer events.
+//
+// Flush sends all buffered events and waits for sends in progress. Call it
+// before the request ends (e.g. before a Lambda invocation returns), since
+// work left running afterwards may never complete.
+type EventFlusher interface {
+	Flush(ctx context.Context) error
+}
+
+// NoopEventPublisher discards all events. It is the default publisher.
+type NoopEventPublisher struct{}
+
+// Publish implements EventPublisher.
+func (NoopEventPublisher) Publish(ctx context.Context, event RateEvent) error {
+	return nil
+}
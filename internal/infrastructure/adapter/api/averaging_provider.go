@@ -0,0 +1,388 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// ErrQuorumNotReached is returned when fewer providers than the configured quorum
+// returned a usable rate.
+var ErrQuorumNotReached = errors.New("provider quorum not reached")
+
+// skipReasonBelowQuorum is recorded when a currency was returned by too few providers.
+const skipReasonBelowQuorum = "below_quorum"
+
+// AveragingMethod selects how the remaining provider rates are combined.
+type AveragingMethod string
+
+const (
+	// AveragingMethodMedian combines rates using the (weighted) median.
+	AveragingMethodMedian AveragingMethod = "median"
+
+	// AveragingMethodMean combines rates using the (weighted) mean.
+	AveragingMethodMean AveragingMethod = "mean"
+)
+
+// AveragingProviderName is the provider name recorded in the context's
+// FetchSource for averaged results.
+const AveragingProviderName = "averaging"
+
+// WeightedProvider pairs an ExchangeRateProvider with its weight in the average.
+type WeightedProvider struct {
+	Provider provider.ExchangeRateProvider
+	Weight   float64 // Relative weight (values <= 0 are treated as 1)
+}
+
+// AveragingConfig holds configuration for AveragingProvider.
+type AveragingConfig struct {
+	Quorum       int             // Minimum number of providers that must return a rate (0 = majority)
+	OutlierSigma float64         // Discard rates further than N robust standard deviations (scaled MAD) from the median (0 = disabled)
+	Method       AveragingMethod // How remaining rates are combined
+}
+
+// DefaultAveragingConfig returns a default averaging configuration.
+//
+// Default values:
+// - Quorum: 0 (majority of configured providers)
+// - OutlierSigma: 2.0
+// - Method: AveragingMethodMedian
+func DefaultAveragingConfig() AveragingConfig {
+	return AveragingConfig{
+		Quorum:       0,
+		OutlierSigma: 2.0,
+		Method:       AveragingMethodMedian,
+	}
+}
+
+// AveragingProvider combines rates from several ExchangeRateProviders.
+//
+// This wrapper:
+// - Fetches from all providers concurrently
+// - Requires at least Quorum providers to succeed
+// - Discards outliers beyond OutlierSigma robust standard deviations from the median
+// - Returns the weighted median or mean of the remaining rates
+//
+// Unlike a failover chain, every provider is queried on every call.
+type AveragingProvider struct {
+	providers []WeightedProvider
+	config    AveragingConfig
+	logger    *logger.Logger
+}
+
+// NewAveragingProvider creates a new AveragingProvider.
+//
+// Parameters:
+//   - providers: The providers to combine (at least one)
+//   - config: Averaging configuration
+//   - log: Logger for structured logging (optional, creates default if nil)
+//
+// Returns an error if no providers are given or the quorum exceeds the provider count.
+func NewAveragingProvider(providers []WeightedProvider, config AveragingConfig, log *logger.Logger) (*AveragingProvider, error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("at least one provider is required")
+	}
+	if config.Quorum <= 0 {
+		config.Quorum = len(providers)/2 + 1 // majority
+	}
+	if config.Quorum > len(providers) {
+		return nil, fmt.Errorf("quorum %d exceeds provider count %d", config.Quorum, len(providers))
+	}
+	if config.OutlierSigma < 0 {
+		return nil, fmt.Errorf("outlier sigma cannot be negative, got %g", config.OutlierSigma)
+	}
+	if config.Method == "" {
+		config.Method = AveragingMethodMedian
+	}
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+
+	return &AveragingProvider{
+		providers: providers,
+		config:    config,
+		logger:    log,
+	}, nil
+}
+
+// sample is a single provider's value for one currency pair.
+type sample struct {
+	value     float64
+	weight    float64
+	timestamp time.Time
+}
+
+// FetchRate implements provider.ExchangeRateProvider.
+//
+// Context cancellation: Returns error if ctx is cancelled or times out.
+func (p *AveragingProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	results := make([]*entity.ExchangeRate, len(p.providers))
+	errs := make([]error, len(p.providers))
+
+	var wg sync.WaitGroup
+	for i, wp := range p.providers {
+		wg.Add(1)
+		go func(i int, wp WeightedProvider) {
+			defer wg.Done()
+			results[i], errs[i] = wp.Provider.FetchRate(ctx, base, target)
+		}(i, wp)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var samples []sample
+	for i, rate := range results {
+		if errs[i] == nil && rate != nil {
+			samples = append(samples, sample{value: rate.Rate, weight: p.weight(i), timestamp: rate.Timestamp})
+		}
+	}
+	if len(samples) < p.config.Quorum {
+		return nil, fmt.Errorf("%w: %d of %d providers returned %s/%s, need %d: %w",
+			ErrQuorumNotReached, len(samples), len(p.providers), base, target, p.config.Quorum, errors.Join(errs...))
+	}
+
+	value, timestamp := p.combine(samples)
+	rate, err := entity.NewExchangeRate(base, target, value, timestamp, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overwrite whichever delegate recorded last - the result is a combination
+	if source := provider.FetchSourceFromContext(ctx); source != nil {
+		source.Record(AveragingProviderName, "")
+	}
+	// A single delegate's rates were not averaged, so they must not be cached
+	if fetched := provider.FetchedRatesFromContext(ctx); fetched != nil {
+		fetched.Record(nil)
+	}
+	return rate, nil
+}
+
+// FetchAllRates implements provider.ExchangeRateProvider.
+//
+// Each target currency is combined independently. Currencies returned by fewer
+// than Quorum providers are skipped and recorded in the context's FetchStats.
+//
+// Context cancellation: Returns error if ctx is cancelled or times out.
+func (p *AveragingProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+	results := make([][]*entity.ExchangeRate, len(p.providers))
+	errs := make([]error, len(p.providers))
+
+	var wg sync.WaitGroup
+	for i, wp := range p.providers {
+		wg.Add(1)
+		go func(i int, wp WeightedProvider) {
+			defer wg.Done()
+			results[i], errs[i] = wp.Provider.FetchAllRates(ctx, base)
+		}(i, wp)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	succeeded := 0
+	samplesByTarget := make(map[entity.CurrencyCode][]sample)
+	for i, rates := range results {
+		if errs[i] != nil {
+			continue
+		}
+		succeeded++
+		for _, rate := range rates {
+			if rate == nil {
+				continue
+			}
+			samplesByTarget[rate.Target] = append(samplesByTarget[rate.Target],
+				sample{value: rate.Rate, weight: p.weight(i), timestamp: rate.Timestamp})
+		}
+	}
+	if succeeded < p.config.Quorum {
+		return nil, fmt.Errorf("%w: %d of %d providers returned rates for %s, need %d: %w",
+			ErrQuorumNotReached, succeeded, len(p.providers), base, p.config.Quorum, errors.Join(errs...))
+	}
+
+	targets := make([]entity.CurrencyCode, 0, len(samplesByTarget))
+	for target := range samplesByTarget {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	rates := make([]*entity.ExchangeRate, 0, len(targets))
+	belowQuorum := 0
+	for _, target := range targets {
+		samples := samplesByTarget[target]
+		if len(samples) < p.config.Quorum {
+			belowQuorum++
+			continue
+		}
+		value, timestamp := p.combine(samples)
+		rate, err := entity.NewExchangeRate(base, target, value, timestamp, false)
+		if err != nil {
+			continue
+		}
+		rates = append(rates, rate)
+	}
+
+	if belowQuorum > 0 {
+		p.logger.WithContext(ctx).Warn("skipped currencies returned by too few providers",
+			"base", base.String(),
+			"skipped", belowQuorum,
+			"quorum", p.config.Quorum,
+		)
+		if stats := provider.FetchStatsFromContext(ctx); stats != nil {
+			stats.Record(belowQuorum, map[string]int{skipReasonBelowQuorum: belowQuorum})
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no rates reached quorum for %s: %w", base, entity.ErrRateNotFound)
+	}
+
+	if source := provider.FetchSourceFromContext(ctx); source != nil {
+		source.Record(AveragingProviderName, "")
+	}
+	return rates, nil
+}
+
+// weight returns the effective weight of the provider at index i.
+func (p *AveragingProvider) weight(i int) float64 {
+	if w := p.providers[i].Weight; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// combine discards outliers and aggregates the remaining samples.
+// The returned timestamp is the most recent timestamp among the kept samples.
+func (p *AveragingProvider) combine(samples []sample) (float64, time.Time) {
+	kept := rejectOutliers(samples, p.config.OutlierSigma)
+
+	var timestamp time.Time
+	for _, s := range kept {
+		if s.timestamp.After(timestamp) {
+			timestamp = s.timestamp
+		}
+	}
+
+	if p.config.Method == AveragingMethodMean {
+		return weightedMean(kept), timestamp
+	}
+	return weightedMedian(kept), timestamp
+}
+
+// rejectOutliers removes samples further than sigma robust standard deviations
+// from the median.
+//
+// The spread is the median absolute deviation (MAD) scaled to be consistent with
+// the standard deviation, so a single divergent provider cannot inflate it the way
+// it inflates the population standard deviation. If more than half of the samples
+// agree exactly (MAD is 0), the mean absolute deviation from the median is used.
+// If sigma is 0 or all samples would be removed, the input is returned unchanged.
+func rejectOutliers(samples []sample, sigma float64) []sample {
+	if sigma <= 0 || len(samples) < 3 {
+		return samples
+	}
+
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		values[i] = s.value
+	}
+	center := median(values)
+
+	deviations := make([]float64, len(samples))
+	var sumDeviation float64
+	for i, s := range samples {
+		deviations[i] = math.Abs(s.value - center)
+		sumDeviation += deviations[i]
+	}
+
+	scale := madScale * median(append([]float64(nil), deviations...))
+	if scale == 0 {
+		scale = meanDeviationScale * sumDeviation / float64(len(samples))
+	}
+	if scale == 0 {
+		return samples
+	}
+
+	kept := make([]sample, 0, len(samples))
+	for i, s := range samples {
+		if deviations[i] <= sigma*scale {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == 0 {
+		return samples
+	}
+	return kept
+}
+
+const (
+	// madScale makes the median absolute deviation a consistent estimator of the
+	// standard deviation for normally distributed values.
+	madScale = 1.4826
+
+	// meanDeviationScale does the same for the mean absolute deviation.
+	meanDeviationScale = 1.2533
+)
+
+// median returns the median of values. values is reordered.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// weightedMean returns the weighted arithmetic mean of the samples.
+func weightedMean(samples []sample) float64 {
+	var sum, totalWeight float64
+	for _, s := range samples {
+		sum += s.value * s.weight
+		totalWeight += s.weight
+	}
+	return sum / totalWeight
+}
+
+// weightedMedian returns the value at which the cumulative weight reaches half
+// of the total weight. For equal weights this is the ordinary median.
+func weightedMedian(samples []sample) float64 {
+	sorted := make([]sample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].value < sorted[j].value })
+
+	var totalWeight float64
+	for _, s := range sorted {
+		totalWeight += s.weight
+	}
+
+	half := totalWeight / 2
+	var cumulative float64
+	for i, s := range sorted {
+		cumulative += s.weight
+		if cumulative > half {
+			return s.value
+		}
+		if cumulative == half && i+1 < len(sorted) {
+			// Exactly on the boundary - average the two middle values
+			return (s.value + sorted[i+1].value) / 2
+		}
+	}
+	return sorted[len(sorted)-1].value
+}
+
+// Ensure AveragingProvider implements ExchangeRateProvider interface.
+var _ provider.ExchangeRateProvider = (*AveragingProvider)(nil)
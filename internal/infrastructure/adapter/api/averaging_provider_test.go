@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+// staticRateProvider returns fixed rates and is safe for concurrent use.
+type staticRateProvider struct {
+	rate  float64
+	rates map[string]float64
+	err   error
+}
+
+func (s *staticRateProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return entity.NewExchangeRate(base, target, s.rate, time.Now(), false)
+}
+
+func (s *staticRateProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var rates []*entity.ExchangeRate
+	for code, value := range s.rates {
+		target, _ := entity.NewCurrencyCode(code)
+		rate, _ := entity.NewExchangeRate(base, target, value, time.Now(), false)
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func weighted(providers ...provider.ExchangeRateProvider) []WeightedProvider {
+	result := make([]WeightedProvider, len(providers))
+	for i, p := range providers {
+		result[i] = WeightedProvider{Provider: p, Weight: 1}
+	}
+	return result
+}
+
+func TestNewAveragingProvider(t *testing.T) {
+	p1 := &staticRateProvider{rate: 1}
+	p2 := &staticRateProvider{rate: 1}
+	p3 := &staticRateProvider{rate: 1}
+
+	ap, err := NewAveragingProvider(weighted(p1, p2, p3), DefaultAveragingConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewAveragingProvider() error = %v", err)
+	}
+	if ap.config.Quorum != 2 {
+		t.Errorf("default quorum = %d, want 2 (majority of 3)", ap.config.Quorum)
+	}
+
+	if _, err := NewAveragingProvider(nil, DefaultAveragingConfig(), nil); err == nil {
+		t.Error("NewAveragingProvider() with no providers: expected error, got nil")
+	}
+	if _, err := NewAveragingProvider(weighted(p1), AveragingConfig{Quorum: 2}, nil); err == nil {
+		t.Error("NewAveragingProvider() with quorum > providers: expected error, got nil")
+	}
+}
+
+func TestAveragingProvider_FetchRate(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	tests := []struct {
+		name      string
+		providers []WeightedProvider
+		config    AveragingConfig
+		want      float64
+		wantErr   error
+	}{
+		{
+			name: "median discards divergent outlier",
+			providers: weighted(
+				&staticRateProvider{rate: 0.85},
+				&staticRateProvider{rate: 0.86},
+				&staticRateProvider{rate: 0.84},
+				&staticRateProvider{rate: 0.85},
+				&staticRateProvider{rate: 8.5},
+			),
+			config: AveragingConfig{Quorum: 3, OutlierSigma: 2.0, Method: AveragingMethodMedian},
+			want:   0.85,
+		},
+		{
+			name: "mean discards divergent outlier",
+			providers: weighted(
+				&staticRateProvider{rate: 0.84},
+				&staticRateProvider{rate: 0.86},
+				&staticRateProvider{rate: 0.85},
+				&staticRateProvider{rate: 0.85},
+				&staticRateProvider{rate: 8.5},
+			),
+			config: AveragingConfig{Quorum: 3, OutlierSigma: 2.0, Method: AveragingMethodMean},
+			want:   0.85,
+		},
+		{
+			name: "weighted mean",
+			providers: []WeightedProvider{
+				{Provider: &staticRateProvider{rate: 1.0}, Weight: 3},
+				{Provider: &staticRateProvider{rate: 2.0}, Weight: 1},
+			},
+			config: AveragingConfig{Quorum: 2, Method: AveragingMethodMean},
+			want:   1.25,
+		},
+		{
+			name: "failed provider tolerated within quorum",
+			providers: weighted(
+				&staticRateProvider{rate: 0.85},
+				&staticRateProvider{rate: 0.87},
+				&staticRateProvider{err: errors.New("provider down")},
+			),
+			config: AveragingConfig{Quorum: 2, Method: AveragingMethodMedian},
+			want:   0.86,
+		},
+		{
+			name: "quorum not reached",
+			providers: weighted(
+				&staticRateProvider{rate: 0.85},
+				&staticRateProvider{err: errors.New("provider down")},
+				&staticRateProvider{err: errors.New("provider down")},
+			),
+			config:  AveragingConfig{Quorum: 2},
+			wantErr: ErrQuorumNotReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap, err := NewAveragingProvider(tt.providers, tt.config, nil)
+			if err != nil {
+				t.Fatalf("NewAveragingProvider() error = %v", err)
+			}
+
+			rate, err := ap.FetchRate(context.Background(), base, target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FetchRate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchRate() error = %v", err)
+			}
+			if math.Abs(rate.Rate-tt.want) > 1e-9 {
+				t.Errorf("FetchRate() rate = %v, want %v", rate.Rate, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejectOutliers(t *testing.T) {
+	sigma := DefaultAveragingConfig().OutlierSigma
+
+	tests := []struct {
+		name   string
+		values []float64
+		want   []float64
+	}{
+		{"three providers, one divergent", []float64{1.0, 1.01, 5.0}, []float64{1.0, 1.01}},
+		{"three providers, divergent first", []float64{0.5, 0.85, 0.86}, []float64{0.85, 0.86}},
+		{"three providers in agreement", []float64{1.00, 1.01, 1.02}, []float64{1.00, 1.01, 1.02}},
+		{"two agree exactly, one divergent", []float64{0.85, 0.85, 0.95}, []float64{0.85, 0.85}},
+		{"all identical", []float64{0.85, 0.85, 0.85}, []float64{0.85, 0.85, 0.85}},
+		{"too few samples", []float64{1.0, 5.0}, []float64{1.0, 5.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([]sample, len(tt.values))
+			for i, v := range tt.values {
+				samples[i] = sample{value: v, weight: 1}
+			}
+
+			kept := rejectOutliers(samples, sigma)
+			if len(kept) != len(tt.want) {
+				t.Fatalf("rejectOutliers() kept %d samples, want %d (%v)", len(kept), len(tt.want), tt.want)
+			}
+			for i, s := range kept {
+				if s.value != tt.want[i] {
+					t.Errorf("kept[%d] = %v, want %v", i, s.value, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAveragingProvider_FetchAllRates(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+
+	ap, err := NewAveragingProvider(weighted(
+		&staticRateProvider{rates: map[string]float64{"EUR": 0.85, "GBP": 0.73, "JPY": 150}},
+		&staticRateProvider{rates: map[string]float64{"EUR": 0.86, "GBP": 0.75}},
+		&staticRateProvider{rates: map[string]float64{"EUR": 0.84, "GBP": 0.74}},
+	), AveragingConfig{Quorum: 2, Method: AveragingMethodMedian}, nil)
+	if err != nil {
+		t.Fatalf("NewAveragingProvider() error = %v", err)
+	}
+
+	ctx, stats := provider.WithFetchStats(context.Background())
+	ctx, source := provider.WithFetchSource(ctx)
+	rates, err := ap.FetchAllRates(ctx, base)
+	if err != nil {
+		t.Fatalf("FetchAllRates() error = %v", err)
+	}
+
+	got := make(map[string]float64)
+	for _, rate := range rates {
+		got[rate.Target.String()] = rate.Rate
+	}
+	if len(got) != 2 {
+		t.Fatalf("FetchAllRates() returned %d rates, want 2: %v", len(got), got)
+	}
+	if got["EUR"] != 0.85 {
+		t.Errorf("EUR rate = %v, want 0.85", got["EUR"])
+	}
+	if got["GBP"] != 0.74 {
+		t.Errorf("GBP rate = %v, want 0.74", got["GBP"])
+	}
+
+	// JPY was returned by a single provider, below quorum
+	if stats.Skipped() != 1 {
+		t.Errorf("Skipped() = %d, want 1", stats.Skipped())
+	}
+	if stats.SkipReasons()[skipReasonBelowQuorum] != 1 {
+		t.Errorf("SkipReasons()[%q] = %d, want 1", skipReasonBelowQuorum, stats.SkipReasons()[skipReasonBelowQuorum])
+	}
+	if source.Provider() != AveragingProviderName {
+		t.Errorf("source provider = %q, want %q", source.Provider(), AveragingProviderName)
+	}
+}
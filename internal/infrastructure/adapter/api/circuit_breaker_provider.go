@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
@@ -9,6 +10,82 @@ import (
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
+// FailureClassifier decides whether a provider error counts as a circuit breaker failure.
+type FailureClassifier func(err error) bool
+
+// DefaultFailureClassifier counts errors that indicate the upstream is unhealthy.
+//
+// Not counted (the upstream is not at fault):
+// - Context cancellation by the caller
+// - provider.ErrCurrencyUnsupported (404 responses for a base never served before)
+// - 4xx responses other than 429
+//
+// Counted:
+// - Transport errors and timeouts (including context.DeadlineExceeded)
+// - 5xx and 429 responses
+// - 404 responses for a known-good base (the probe base, or a base served before),
+// so a misconfigured API URL that returns 404 for everything trips the breaker
+// - An AllEndpointsFailedError where any endpoint's failure is counted, even if
+// the last endpoint returned 404
+// - Any other unclassified error
+func DefaultFailureClassifier(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Classify every endpoint's failure, not just the last one it unwraps to
+	var allFailed *AllEndpointsFailedError
+	if errors.As(err, &allFailed) && len(allFailed.Failures) > 1 {
+		for _, failure := range allFailed.Failures {
+			if DefaultFailureClassifier(failure.Err) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, provider.ErrCurrencyUnsupported) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.KnownBase || isRetryableStatusCode(statusErr.StatusCode)
+	}
+
+	return true
+}
+
+// AllRatesSuccessCriterion decides whether a FetchAllRates result returned
+// without an error counts as a success for the circuit breaker. A result it
+// rejects is still returned to the caller, but recorded as a failure.
+type AllRatesSuccessCriterion func(rates []*entity.ExchangeRate) bool
+
+// AnyRatesSuccess counts every result returned without an error as a success,
+// including an empty one. It is the default criterion.
+func AnyRatesSuccess(rates []*entity.ExchangeRate) bool {
+	return true
+}
+
+// NonEmptyRatesSuccess counts only results with at least one rate as a success,
+// treating an empty response from a degraded upstream as a soft failure.
+func NonEmptyRatesSuccess(rates []*entity.ExchangeRate) bool {
+	return len(rates) > 0
+}
+
+// DefaultPairFailureClassifier counts errors specific to one currency pair,
+// which pair breakers track instead of the base's breaker:
+// - provider.ErrCurrencyUnsupported (e.g. a target missing from a served base)
+// - entity.ErrInvalidExchangeRate and entity.ErrInvalidBidAsk (a bad rate for the pair)
+func DefaultPairFailureClassifier(err error) bool {
+	return errors.Is(err, provider.ErrCurrencyUnsupported) ||
+		errors.Is(err, entity.ErrInvalidExchangeRate) ||
+		errors.Is(err, entity.ErrInvalidBidAsk)
+}
+
 // CircuitBreakerProvider wraps an ExchangeRateProvider with circuit breaker protection.
 //
 // This wrapper:
@@ -16,11 +93,30 @@ import (
 // - Records success/failure based on provider call results
 // - Returns ErrCircuitOpen when circuit is open
 //
+// Errors the classifier does not count (e.g. unsupported currencies) are returned
+// without being recorded, so a flood of bad requests cannot trip the breaker.
+//
+// When built with NewPerBaseCircuitBreakerProvider, each base currency has its
+// own breaker, so failures fetching USD rates do not block EUR requests.
+//
+// With WithPairBreakers, FetchRate is also guarded by a breaker per base/target
+// pair. Pair-specific errors (see DefaultPairFailureClassifier) count against
+// the pair's breaker only, so one bad pair neither blocks other pairs nor trips
+// the base's breaker.
+//
+// With WithAllRatesSuccess, FetchAllRates results returned without an error
+// can be recorded as failures, e.g. empty responses (see NonEmptyRatesSuccess).
+//
 // This enables graceful degradation: when the circuit is open, use cases can
 // fall back to cached (stale) data instead of failing completely.
 type CircuitBreakerProvider struct {
 	provider       provider.ExchangeRateProvider
 	circuitBreaker *circuitbreaker.CircuitBreaker
+	breakers       *circuitbreaker.MultiCircuitBreaker
+	isFailure      FailureClassifier
+	pairBreakers   *circuitbreaker.MultiCircuitBreaker
+	isPairFailure  FailureClassifier
+	allRatesOK     AllRatesSuccessCriterion
 }
 
 // NewCircuitBreakerProvider creates a new CircuitBreakerProvider.
@@ -30,25 +126,118 @@ type CircuitBreakerProvider struct {
 //   - circuitBreaker: The circuit breaker instance
 //
 // Returns a new CircuitBreakerProvider that wraps the given provider.
+// Errors are classified with DefaultFailureClassifier.
 func NewCircuitBreakerProvider(provider provider.ExchangeRateProvider, circuitBreaker *circuitbreaker.CircuitBreaker) *CircuitBreakerProvider {
+	return NewCircuitBreakerProviderWithClassifier(provider, circuitBreaker, nil)
+}
+
+// NewCircuitBreakerProviderWithClassifier creates a new CircuitBreakerProvider
+// with a custom failure classifier (nil uses DefaultFailureClassifier).
+func NewCircuitBreakerProviderWithClassifier(provider provider.ExchangeRateProvider, circuitBreaker *circuitbreaker.CircuitBreaker, classifier FailureClassifier) *CircuitBreakerProvider {
+	if classifier == nil {
+		classifier = DefaultFailureClassifier
+	}
 	return &CircuitBreakerProvider{
 		provider:       provider,
 		circuitBreaker: circuitBreaker,
+		isFailure:      classifier,
+	}
+}
+
+// NewPerBaseCircuitBreakerProvider creates a CircuitBreakerProvider that keeps
+// an independent circuit breaker per base currency (nil classifier uses
+// DefaultFailureClassifier).
+func NewPerBaseCircuitBreakerProvider(provider provider.ExchangeRateProvider, breakers *circuitbreaker.MultiCircuitBreaker, classifier FailureClassifier) *CircuitBreakerProvider {
+	if classifier == nil {
+		classifier = DefaultFailureClassifier
+	}
+	return &CircuitBreakerProvider{
+		provider:  provider,
+		breakers:  breakers,
+		isFailure: classifier,
+	}
+}
+
+// WithPairBreakers enables a circuit breaker per base/target pair for FetchRate,
+// keyed "BASE/TARGET" (nil classifier uses DefaultPairFailureClassifier).
+// It returns p and must be called before p is used.
+func (p *CircuitBreakerProvider) WithPairBreakers(breakers *circuitbreaker.MultiCircuitBreaker, classifier FailureClassifier) *CircuitBreakerProvider {
+	if classifier == nil {
+		classifier = DefaultPairFailureClassifier
+	}
+	p.pairBreakers = breakers
+	p.isPairFailure = classifier
+	return p
+}
+
+// WithAllRatesSuccess sets the criterion deciding whether a FetchAllRates result
+// returned without an error counts as a success (nil uses AnyRatesSuccess).
+// It returns p and must be called before p is used.
+func (p *CircuitBreakerProvider) WithAllRatesSuccess(criterion AllRatesSuccessCriterion) *CircuitBreakerProvider {
+	p.allRatesOK = criterion
+	return p
+}
+
+// pairKey returns the pair breaker key for base and target.
+func pairKey(base, target entity.CurrencyCode) string {
+	return base.Normalize().String() + "/" + target.Normalize().String()
+}
+
+// PairCircuitState returns the state of the breaker guarding the base/target pair,
+// without affecting it. Without pair breakers, or for a pair with no breaker yet,
+// it reports StateClosed.
+func (p *CircuitBreakerProvider) PairCircuitState(base, target entity.CurrencyCode) circuitbreaker.State {
+	if p.pairBreakers == nil {
+		return circuitbreaker.StateClosed
+	}
+	return p.pairBreakers.State(pairKey(base, target))
+}
+
+// breaker returns the circuit breaker guarding requests for base.
+func (p *CircuitBreakerProvider) breaker(base entity.CurrencyCode) *circuitbreaker.CircuitBreaker {
+	if p.breakers != nil {
+		return p.breakers.Get(base.Normalize().String())
+	}
+	return p.circuitBreaker
+}
+
+// CircuitState returns the state of the breaker guarding base, without affecting it.
+// With per-base breakers, a base with no breaker yet reports StateClosed.
+func (p *CircuitBreakerProvider) CircuitState(base entity.CurrencyCode) circuitbreaker.State {
+	if p.breakers != nil {
+		return p.breakers.State(base.Normalize().String())
 	}
+	return p.circuitBreaker.State()
+}
+
+// recordError records err as a failure on cb if the classifier counts it.
+// Otherwise the call is released without an outcome, freeing its half-open test slot.
+func (p *CircuitBreakerProvider) recordError(cb *circuitbreaker.CircuitBreaker, err error) {
+	if p.isFailure(err) {
+		cb.RecordFailure()
+		return
+	}
+	cb.Release()
 }
 
 // FetchRate implements provider.ExchangeRateProvider.
 //
 // This method:
-// - Checks if the circuit breaker allows the request
+// - Checks if the pair's breaker (if enabled) and the circuit breaker allow the request
 // - Calls the underlying provider if allowed
-// - Records success/failure based on the result
-// - Returns ErrCircuitOpen if circuit is open
+// - Records pair-specific failures on the pair's breaker only
+// - Records success, or failure if the classifier counts the error
+// - Returns ErrCircuitOpen if either circuit is open
 //
 // Context cancellation: Returns error if ctx is cancelled or times out.
 func (p *CircuitBreakerProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	if p.pairBreakers != nil {
+		return p.fetchRateWithPairBreaker(ctx, base, target)
+	}
+
 	// Check if circuit breaker allows the request
-	if !p.circuitBreaker.Allow() {
+	cb := p.breaker(base)
+	if !cb.Allow() {
 		return nil, fmt.Errorf("%w: external API unavailable", circuitbreaker.ErrCircuitOpen)
 	}
 
@@ -57,11 +246,42 @@ func (p *CircuitBreakerProvider) FetchRate(ctx context.Context, base, target ent
 
 	// Record result in circuit breaker
 	if err != nil {
-		p.circuitBreaker.RecordFailure()
+		p.recordError(cb, err)
 		return nil, err
 	}
 
-	p.circuitBreaker.RecordSuccess()
+	cb.RecordSuccess()
+	return rate, nil
+}
+
+// fetchRateWithPairBreaker runs FetchRate guarded by both the pair's breaker and
+// the base's breaker. A call rejected or not counted by one breaker is released
+// on it, freeing its half-open test slot.
+func (p *CircuitBreakerProvider) fetchRateWithPairBreaker(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	pairCB := p.pairBreakers.Get(pairKey(base, target))
+	if !pairCB.Allow() {
+		return nil, fmt.Errorf("%w: pair %s unavailable", circuitbreaker.ErrCircuitOpen, pairKey(base, target))
+	}
+	cb := p.breaker(base)
+	if !cb.Allow() {
+		pairCB.Release()
+		return nil, fmt.Errorf("%w: external API unavailable", circuitbreaker.ErrCircuitOpen)
+	}
+
+	rate, err := p.provider.FetchRate(ctx, base, target)
+	if err != nil {
+		if p.isPairFailure(err) {
+			pairCB.RecordFailure()
+			cb.Release()
+		} else {
+			pairCB.Release()
+			p.recordError(cb, err)
+		}
+		return nil, err
+	}
+
+	pairCB.RecordSuccess()
+	cb.RecordSuccess()
 	return rate, nil
 }
 
@@ -70,13 +290,15 @@ func (p *CircuitBreakerProvider) FetchRate(ctx context.Context, base, target ent
 // This method:
 // - Checks if the circuit breaker allows the request
 // - Calls the underlying provider if allowed
-// - Records success/failure based on the result
+// - Records success, or failure if the classifier counts the error
+// - Records a result the success criterion rejects as a failure, but still returns it
 // - Returns ErrCircuitOpen if circuit is open
 //
 // Context cancellation: Returns error if ctx is cancelled or times out.
 func (p *CircuitBreakerProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
 	// Check if circuit breaker allows the request
-	if !p.circuitBreaker.Allow() {
+	cb := p.breaker(base)
+	if !cb.Allow() {
 		return nil, fmt.Errorf("%w: external API unavailable", circuitbreaker.ErrCircuitOpen)
 	}
 
@@ -85,11 +307,16 @@ func (p *CircuitBreakerProvider) FetchAllRates(ctx context.Context, base entity.
 
 	// Record result in circuit breaker
 	if err != nil {
-		p.circuitBreaker.RecordFailure()
+		p.recordError(cb, err)
 		return nil, err
 	}
 
-	p.circuitBreaker.RecordSuccess()
+	if p.allRatesOK != nil && !p.allRatesOK(rates) {
+		cb.RecordFailure()
+		return rates, nil
+	}
+
+	cb.RecordSuccess()
 	return rates, nil
 }
 
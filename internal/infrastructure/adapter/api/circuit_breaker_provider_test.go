@@ -3,10 +3,15 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	domainprovider "github.com/misterfancybg/go-currenseen/internal/domain/provider"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
@@ -88,6 +93,9 @@ func TestCircuitBreakerProvider_FetchRate_CircuitOpen(t *testing.T) {
 	if cb.State() != circuitbreaker.StateOpen {
 		t.Fatalf("Circuit breaker state = %v, want Open", cb.State())
 	}
+	if got := wrapper.CircuitState(base); got != circuitbreaker.StateOpen {
+		t.Errorf("CircuitState() = %v, want Open", got)
+	}
 
 	// Next call should fail immediately with ErrCircuitOpen
 	_, err := wrapper.FetchRate(ctx, base, target)
@@ -255,3 +263,459 @@ func TestCircuitBreakerProvider_HalfOpen_Recovery(t *testing.T) {
 		t.Errorf("Circuit breaker state = %v, want Closed (recovered)", cb.State())
 	}
 }
+
+func TestDefaultFailureClassifier(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"currency unsupported", domainprovider.ErrCurrencyUnsupported, false},
+		{"wrapped currency unsupported", fmt.Errorf("all API endpoints failed, last error: %w", domainprovider.ErrCurrencyUnsupported), false},
+		{"404 response", &StatusError{StatusCode: http.StatusNotFound}, false},
+		{"404 response for known base", &StatusError{StatusCode: http.StatusNotFound, KnownBase: true}, true},
+		{"404 on every endpoint", &AllEndpointsFailedError{Failures: []EndpointError{
+			{URL: "https://primary", Err: &StatusError{StatusCode: http.StatusNotFound}},
+			{URL: "https://fallback", Err: &StatusError{StatusCode: http.StatusNotFound}},
+		}}, false},
+		{"503 then 404", &AllEndpointsFailedError{Failures: []EndpointError{
+			{URL: "https://primary", Err: &StatusError{StatusCode: http.StatusServiceUnavailable}},
+			{URL: "https://fallback", Err: &StatusError{StatusCode: http.StatusNotFound}},
+		}}, true},
+		{"400 response", &StatusError{StatusCode: http.StatusBadRequest}, false},
+		{"context canceled", context.Canceled, false},
+		{"timeout", context.DeadlineExceeded, true},
+		{"429 response", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"503 response", fmt.Errorf("last error: %w", &StatusError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"upstream invalid response", domainprovider.ErrUpstreamInvalidResponse, true},
+		{"unclassified error", errors.New("connection reset by peer"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultFailureClassifier(tt.err); got != tt.want {
+				t.Errorf("DefaultFailureClassifier(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerProvider_FailureClassification(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("XAU")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantOpen bool
+	}{
+		{"unsupported currency does not open", domainprovider.ErrCurrencyUnsupported, false},
+		{"timeout opens", fmt.Errorf("http request failed: %w", context.DeadlineExceeded), true},
+		{"5xx opens", &StatusError{StatusCode: http.StatusBadGateway}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProv := &mockProvider{
+				fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+					return nil, tt.err
+				},
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return nil, tt.err
+				},
+			}
+			cb, _ := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+				FailureThreshold: 3,
+				CooldownDuration: time.Minute,
+				SuccessThreshold: 1,
+			})
+			wrapper := NewCircuitBreakerProvider(mockProv, cb)
+
+			// Well past the threshold, alternating both methods
+			for i := 0; i < 5; i++ {
+				_, err := wrapper.FetchRate(context.Background(), base, target)
+				if cb.State() != circuitbreaker.StateOpen && !errors.Is(err, tt.err) {
+					t.Fatalf("FetchRate() error = %v, want %v returned to caller", err, tt.err)
+				}
+				_, _ = wrapper.FetchAllRates(context.Background(), base)
+			}
+
+			if got := cb.State() == circuitbreaker.StateOpen; got != tt.wantOpen {
+				t.Errorf("circuit open = %v, want %v", got, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerProvider_UnsupportedCurrencyFromUpstream(t *testing.T) {
+	// The upstream answers 404 for unknown base currencies
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cb, _ := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		FailureThreshold: 2,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	})
+	wrapper := NewCircuitBreakerProvider(NewCurrencyAPIProviderWithFallback(NewHTTPClient(), server.URL, server.URL, nil), cb)
+
+	base, _ := entity.NewCurrencyCode("XYZ")
+	for i := 0; i < 5; i++ {
+		_, err := wrapper.FetchAllRates(context.Background(), base)
+		if !errors.Is(err, domainprovider.ErrCurrencyUnsupported) {
+			t.Fatalf("FetchAllRates() error = %v, want ErrCurrencyUnsupported", err)
+		}
+	}
+
+	if cb.State() != circuitbreaker.StateClosed {
+		t.Errorf("circuit state = %v, want Closed", cb.State())
+	}
+}
+
+func TestCircuitBreakerProvider_NotFoundForKnownBase(t *testing.T) {
+	// A misconfigured API URL answers 404 for every base, including the probe base
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cb, _ := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		FailureThreshold: 2,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	})
+	wrapper := NewCircuitBreakerProvider(NewCurrencyAPIProviderWithFallback(NewHTTPClient(), server.URL, server.URL, nil), cb)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	_, err := wrapper.FetchAllRates(context.Background(), base)
+	if errors.Is(err, domainprovider.ErrCurrencyUnsupported) || !errors.Is(err, domainprovider.ErrUpstreamInvalidResponse) {
+		t.Fatalf("FetchAllRates() error = %v, want ErrUpstreamInvalidResponse", err)
+	}
+	_, _ = wrapper.FetchAllRates(context.Background(), base)
+
+	if cb.State() != circuitbreaker.StateOpen {
+		t.Errorf("circuit state = %v, want Open", cb.State())
+	}
+}
+
+func TestCurrencyAPIProvider_NotFoundAfterSuccess(t *testing.T) {
+	var broken atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date":"2024-01-15","gbp":{"eur":1.17}}`))
+	}))
+	defer server.Close()
+
+	p := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), server.URL, server.URL, nil)
+	base, _ := entity.NewCurrencyCode("GBP")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	if _, err := p.FetchRate(context.Background(), base, target); err != nil {
+		t.Fatalf("FetchRate() error = %v", err)
+	}
+
+	// The base was served before, so a 404 now is the upstream's fault
+	broken.Store(true)
+	_, err := p.FetchRate(context.Background(), base, target)
+	if errors.Is(err, domainprovider.ErrCurrencyUnsupported) || !DefaultFailureClassifier(err) {
+		t.Errorf("FetchRate() error = %v, want a counted upstream failure", err)
+	}
+}
+
+func TestNewCircuitBreakerProviderWithClassifier(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	ignored := errors.New("ignored by custom classifier")
+	mockProv := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return nil, ignored
+		},
+	}
+	cb, _ := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		FailureThreshold: 1,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	})
+	wrapper := NewCircuitBreakerProviderWithClassifier(mockProv, cb, func(err error) bool {
+		return !errors.Is(err, ignored)
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := wrapper.FetchRate(context.Background(), base, target); !errors.Is(err, ignored) {
+			t.Fatalf("FetchRate() error = %v, want %v", err, ignored)
+		}
+	}
+	if cb.State() != circuitbreaker.StateClosed {
+		t.Errorf("circuit state = %v, want Closed", cb.State())
+	}
+}
+
+func TestCircuitBreakerProvider_HalfOpen_IgnoredErrorReleasesProbe(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+	rate, _ := entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+
+	var next error
+	mockProv := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			if next != nil {
+				return nil, next
+			}
+			return rate, nil
+		},
+	}
+	cb, _ := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+		FailureThreshold: 1,
+		CooldownDuration: 20 * time.Millisecond,
+		SuccessThreshold: 1,
+	})
+	wrapper := NewCircuitBreakerProvider(mockProv, cb)
+
+	// Open the circuit and wait for HalfOpen
+	next = errors.New("provider error")
+	_, _ = wrapper.FetchRate(context.Background(), base, target)
+	time.Sleep(30 * time.Millisecond)
+
+	// The probe hits an unsupported currency: not a failure, slot released
+	next = domainprovider.ErrCurrencyUnsupported
+	if _, err := wrapper.FetchRate(context.Background(), base, target); !errors.Is(err, domainprovider.ErrCurrencyUnsupported) {
+		t.Fatalf("FetchRate() error = %v, want ErrCurrencyUnsupported", err)
+	}
+	if cb.State() != circuitbreaker.StateHalfOpen {
+		t.Fatalf("circuit state = %v, want HalfOpen", cb.State())
+	}
+
+	// The next probe is allowed and closes the circuit
+	next = nil
+	if _, err := wrapper.FetchRate(context.Background(), base, target); err != nil {
+		t.Fatalf("FetchRate() error = %v, want nil", err)
+	}
+	if cb.State() != circuitbreaker.StateClosed {
+		t.Errorf("circuit state = %v, want Closed", cb.State())
+	}
+}
+
+func TestCircuitBreakerProvider_PerBase_IndependentCircuits(t *testing.T) {
+	usd, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+
+	mockProv := &mockProvider{
+		fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+			if base == usd {
+				return nil, errors.New("provider error")
+			}
+			return []*entity.ExchangeRate{}, nil
+		},
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+		},
+	}
+
+	config := circuitbreaker.Config{
+		FailureThreshold: 2,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	}
+	breakers, err := circuitbreaker.NewMultiCircuitBreaker(config, time.Hour)
+	if err != nil {
+		t.Fatalf("NewMultiCircuitBreaker() error = %v", err)
+	}
+	wrapper := NewPerBaseCircuitBreakerProvider(mockProv, breakers, nil)
+
+	ctx := context.Background()
+
+	// Open the USD circuit
+	_, _ = wrapper.FetchAllRates(ctx, usd)
+	_, _ = wrapper.FetchAllRates(ctx, usd)
+
+	if breakers.State("USD") != circuitbreaker.StateOpen {
+		t.Fatalf("USD circuit state = %v, want Open", breakers.State("USD"))
+	}
+
+	if _, err := wrapper.FetchAllRates(ctx, usd); !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("FetchAllRates(USD) error = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := wrapper.FetchRate(ctx, usd, eur); !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("FetchRate(USD, EUR) error = %v, want ErrCircuitOpen", err)
+	}
+
+	// EUR has its own circuit and must not be rejected
+	if _, err := wrapper.FetchAllRates(ctx, eur); err != nil {
+		t.Errorf("FetchAllRates(EUR) error = %v, want nil", err)
+	}
+	if _, err := wrapper.FetchRate(ctx, eur, usd); err != nil {
+		t.Errorf("FetchRate(EUR, USD) error = %v, want nil", err)
+	}
+	if breakers.State("EUR") != circuitbreaker.StateClosed {
+		t.Errorf("EUR circuit state = %v, want Closed", breakers.State("EUR"))
+	}
+
+	// CircuitState reads per-base state without creating breakers
+	if got := wrapper.CircuitState(usd); got != circuitbreaker.StateOpen {
+		t.Errorf("CircuitState(USD) = %v, want Open", got)
+	}
+	breakersBefore := breakers.Len()
+	if got := wrapper.CircuitState("GBP"); got != circuitbreaker.StateClosed {
+		t.Errorf("CircuitState(GBP) = %v, want Closed", got)
+	}
+	if breakers.Len() != breakersBefore {
+		t.Errorf("CircuitState created a breaker: Len() = %d, want %d", breakers.Len(), breakersBefore)
+	}
+}
+
+func TestCircuitBreakerProvider_PairBreakers(t *testing.T) {
+	usd, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	xau, _ := entity.NewCurrencyCode("XAU")
+
+	calls := 0
+	mockProv := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			calls++
+			if target == xau {
+				// Consistently failing pair on a base that otherwise works
+				return nil, fmt.Errorf("parse: %w", entity.ErrInvalidExchangeRate)
+			}
+			return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+		},
+	}
+
+	config := circuitbreaker.Config{
+		FailureThreshold: 2,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	}
+	baseBreakers, err := circuitbreaker.NewMultiCircuitBreaker(config, time.Hour)
+	if err != nil {
+		t.Fatalf("NewMultiCircuitBreaker() error = %v", err)
+	}
+	pairBreakers, err := circuitbreaker.NewMultiCircuitBreaker(config, time.Hour)
+	if err != nil {
+		t.Fatalf("NewMultiCircuitBreaker() error = %v", err)
+	}
+	wrapper := NewPerBaseCircuitBreakerProvider(mockProv, baseBreakers, nil).WithPairBreakers(pairBreakers, nil)
+
+	ctx := context.Background()
+
+	// Open the USD/XAU circuit
+	for i := 0; i < 2; i++ {
+		if _, err := wrapper.FetchRate(ctx, usd, xau); !errors.Is(err, entity.ErrInvalidExchangeRate) {
+			t.Fatalf("FetchRate(USD, XAU) error = %v, want ErrInvalidExchangeRate", err)
+		}
+	}
+	if got := wrapper.PairCircuitState(usd, xau); got != circuitbreaker.StateOpen {
+		t.Fatalf("PairCircuitState(USD, XAU) = %v, want Open", got)
+	}
+
+	// The open pair is short-circuited without calling the provider
+	callsBefore := calls
+	if _, err := wrapper.FetchRate(ctx, usd, xau); !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("FetchRate(USD, XAU) error = %v, want ErrCircuitOpen", err)
+	}
+	if calls != callsBefore {
+		t.Errorf("provider called %d times for an open pair, want 0", calls-callsBefore)
+	}
+
+	// Pair failures do not count against the base's breaker or other pairs
+	if got := wrapper.CircuitState(usd); got != circuitbreaker.StateClosed {
+		t.Errorf("CircuitState(USD) = %v, want Closed", got)
+	}
+	if _, err := wrapper.FetchRate(ctx, usd, eur); err != nil {
+		t.Errorf("FetchRate(USD, EUR) error = %v, want nil", err)
+	}
+	if got := wrapper.PairCircuitState(usd, eur); got != circuitbreaker.StateClosed {
+		t.Errorf("PairCircuitState(USD, EUR) = %v, want Closed", got)
+	}
+}
+
+func TestCircuitBreakerProvider_PairBreakers_UpstreamFailuresCountAgainstBase(t *testing.T) {
+	usd, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+
+	mockProv := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+
+	config := circuitbreaker.Config{
+		FailureThreshold: 2,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	}
+	cb, err := circuitbreaker.NewCircuitBreaker(config)
+	if err != nil {
+		t.Fatalf("NewCircuitBreaker() error = %v", err)
+	}
+	pairBreakers, err := circuitbreaker.NewMultiCircuitBreaker(config, time.Hour)
+	if err != nil {
+		t.Fatalf("NewMultiCircuitBreaker() error = %v", err)
+	}
+	wrapper := NewCircuitBreakerProvider(mockProv, cb).WithPairBreakers(pairBreakers, nil)
+
+	ctx := context.Background()
+	_, _ = wrapper.FetchRate(ctx, usd, eur)
+	_, _ = wrapper.FetchRate(ctx, usd, eur)
+
+	if cb.State() != circuitbreaker.StateOpen {
+		t.Errorf("base circuit state = %v, want Open", cb.State())
+	}
+	if got := wrapper.PairCircuitState(usd, eur); got != circuitbreaker.StateClosed {
+		t.Errorf("PairCircuitState(USD, EUR) = %v, want Closed", got)
+	}
+}
+
+func TestCircuitBreakerProvider_AllRatesSuccessCriterion(t *testing.T) {
+	usd, _ := entity.NewCurrencyCode("USD")
+
+	tests := []struct {
+		name      string
+		criterion AllRatesSuccessCriterion
+		wantState circuitbreaker.State
+	}{
+		{"default counts empty as success", nil, circuitbreaker.StateClosed},
+		{"any rates counts empty as success", AnyRatesSuccess, circuitbreaker.StateClosed},
+		{"non-empty counts empty as failure", NonEmptyRatesSuccess, circuitbreaker.StateOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProv := &mockProvider{
+				fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+					return []*entity.ExchangeRate{}, nil
+				},
+			}
+			cb, err := circuitbreaker.NewCircuitBreaker(circuitbreaker.Config{
+				FailureThreshold: 2,
+				CooldownDuration: time.Minute,
+				SuccessThreshold: 1,
+			})
+			if err != nil {
+				t.Fatalf("NewCircuitBreaker() error = %v", err)
+			}
+			wrapper := NewCircuitBreakerProvider(mockProv, cb).WithAllRatesSuccess(tt.criterion)
+
+			for i := 0; i < 2; i++ {
+				rates, err := wrapper.FetchAllRates(context.Background(), usd)
+				if err != nil {
+					t.Fatalf("FetchAllRates() error = %v, want nil (soft failures still return the result)", err)
+				}
+				if rates == nil || len(rates) != 0 {
+					t.Errorf("FetchAllRates() = %v, want the empty result", rates)
+				}
+			}
+
+			if cb.State() != tt.wantState {
+				t.Errorf("circuit state = %v, want %v", cb.State(), tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointSelectorConfig holds configuration for health-aware URL selection.
+type EndpointSelectorConfig struct {
+	Window           time.Duration // How long a result counts toward an endpoint's health
+	MaxSamples       int           // Maximum results kept per endpoint
+	FailureThreshold int           // Consecutive failures before an endpoint is demoted
+	Cooldown         time.Duration // How long a demoted endpoint is tried last
+}
+
+// DefaultEndpointSelectorConfig returns the default endpoint selector configuration.
+//
+// Default values:
+// - Window: 5 minutes
+// - MaxSamples: 20
+// - FailureThreshold: 3
+// - Cooldown: 1 minute
+func DefaultEndpointSelectorConfig() EndpointSelectorConfig {
+	return EndpointSelectorConfig{
+		Window:           5 * time.Minute,
+		MaxSamples:       20,
+		FailureThreshold: 3,
+		Cooldown:         1 * time.Minute,
+	}
+}
+
+// endpointSample is the outcome of a single request to an endpoint.
+type endpointSample struct {
+	at      time.Time
+	success bool
+	latency time.Duration
+}
+
+// endpointStats holds recent results for a single endpoint.
+type endpointStats struct {
+	samples             []endpointSample
+	consecutiveFailures int
+	demotedUntil        time.Time
+}
+
+// endpointSelector orders provider URLs by recent health.
+//
+// URLs are ordered by:
+// - Demoted URLs last (after FailureThreshold consecutive failures, for Cooldown)
+// - Higher success ratio within the sliding window first
+// - Lower average latency of successful requests first
+// - Configured priority order otherwise
+//
+// URLs without recent results count as fully healthy, so a URL that recovers
+// from a demotion or whose failures age out of the window is tried again.
+//
+// A nil *endpointSelector keeps the configured order and records nothing.
+//
+// endpointSelector is safe for concurrent use.
+type endpointSelector struct {
+	mu     sync.Mutex
+	config EndpointSelectorConfig
+	stats  map[string]*endpointStats
+	now    func() time.Time
+}
+
+// newEndpointSelector creates an endpoint selector.
+// Zero or non-positive config values fall back to their defaults.
+func newEndpointSelector(config EndpointSelectorConfig, now func() time.Time) *endpointSelector {
+	defaults := DefaultEndpointSelectorConfig()
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.MaxSamples <= 0 {
+		config.MaxSamples = defaults.MaxSamples
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &endpointSelector{
+		config: config,
+		stats:  make(map[string]*endpointStats),
+		now:    now,
+	}
+}
+
+// start returns the start time for an endpoint request.
+func (s *endpointSelector) start() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return s.now()
+}
+
+// order returns urls sorted by health, most preferred first.
+func (s *endpointSelector) order(urls []string) []string {
+	if s == nil || len(urls) < 2 {
+		return urls
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	type ranked struct {
+		url     string
+		demoted bool
+		ratio   float64
+		latency time.Duration
+	}
+	candidates := make([]ranked, len(urls))
+	for i, url := range urls {
+		candidate := ranked{url: url, ratio: 1}
+		if stats, ok := s.stats[url]; ok {
+			s.expire(stats, now)
+			candidate.demoted = now.Before(stats.demotedUntil)
+			candidate.ratio, candidate.latency = stats.health()
+		}
+		candidates[i] = candidate
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.demoted != b.demoted {
+			return !a.demoted
+		}
+		if a.ratio != b.ratio {
+			return a.ratio > b.ratio
+		}
+		return a.latency < b.latency
+	})
+
+	ordered := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ordered[i] = candidate.url
+	}
+	return ordered
+}
+
+// record stores the outcome of a request to url that began at started.
+func (s *endpointSelector) record(url string, started time.Time, success bool) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stats, ok := s.stats[url]
+	if !ok {
+		stats = &endpointStats{}
+		s.stats[url] = stats
+	}
+	s.expire(stats, now)
+
+	stats.samples = append(stats.samples, endpointSample{at: now, success: success, latency: now.Sub(started)})
+	if len(stats.samples) > s.config.MaxSamples {
+		stats.samples = stats.samples[len(stats.samples)-s.config.MaxSamples:]
+	}
+
+	if success {
+		stats.consecutiveFailures = 0
+		return
+	}
+	stats.consecutiveFailures++
+	if stats.consecutiveFailures >= s.config.FailureThreshold {
+		stats.demotedUntil = now.Add(s.config.Cooldown)
+		stats.consecutiveFailures = 0
+	}
+}
+
+// expire drops samples older than the window, and all samples once a
+// demotion has ended so the endpoint gets a fresh start.
+// Must be called with lock held.
+func (s *endpointSelector) expire(stats *endpointStats, now time.Time) {
+	if !stats.demotedUntil.IsZero() && !now.Before(stats.demotedUntil) {
+		stats.samples = nil
+		stats.demotedUntil = time.Time{}
+		return
+	}
+
+	cutoff := now.Add(-s.config.Window)
+	kept := stats.samples[:0]
+	for _, sample := range stats.samples {
+		if sample.at.After(cutoff) {
+			kept = append(kept, sample)
+		}
+	}
+	stats.samples = kept
+}
+
+// health returns the success ratio and average successful latency of the samples.
+// Endpoints without samples count as fully healthy.
+func (e *endpointStats) health() (float64, time.Duration) {
+	if len(e.samples) == 0 {
+		return 1, 0
+	}
+
+	successes := 0
+	var total time.Duration
+	for _, sample := range e.samples {
+		if sample.success {
+			successes++
+			total += sample.latency
+		}
+	}
+	if successes == 0 {
+		return 0, 0
+	}
+	return float64(successes) / float64(len(e.samples)), total / time.Duration(successes)
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// fakeClock is a manually advanced clock for endpoint selector tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestEndpointSelector_NilKeepsOrder(t *testing.T) {
+	var selector *endpointSelector
+	urls := []string{"a", "b"}
+
+	selector.record("a", selector.start(), false)
+	if got := selector.order(urls); !reflect.DeepEqual(got, urls) {
+		t.Errorf("order() = %v, want %v", got, urls)
+	}
+}
+
+func TestEndpointSelector_Order(t *testing.T) {
+	config := EndpointSelectorConfig{
+		Window:           time.Minute,
+		MaxSamples:       10,
+		FailureThreshold: 3,
+		Cooldown:         30 * time.Second,
+	}
+	urls := []string{"primary", "fallback"}
+
+	tests := []struct {
+		name   string
+		record func(s *endpointSelector, clock *fakeClock)
+		want   []string
+	}{
+		{
+			name:   "no history keeps priority order",
+			record: func(s *endpointSelector, clock *fakeClock) {},
+			want:   []string{"primary", "fallback"},
+		},
+		{
+			name: "lower success ratio is tried later",
+			record: func(s *endpointSelector, clock *fakeClock) {
+				s.record("primary", clock.Now(), true)
+				s.record("primary", clock.Now(), false)
+				s.record("fallback", clock.Now(), true)
+			},
+			want: []string{"fallback", "primary"},
+		},
+		{
+			name: "lower latency is preferred",
+			record: func(s *endpointSelector, clock *fakeClock) {
+				started := clock.Now()
+				clock.Advance(200 * time.Millisecond)
+				s.record("primary", started, true)
+				started = clock.Now()
+				clock.Advance(50 * time.Millisecond)
+				s.record("fallback", started, true)
+			},
+			want: []string{"fallback", "primary"},
+		},
+		{
+			name: "failures age out of the window",
+			record: func(s *endpointSelector, clock *fakeClock) {
+				s.record("primary", clock.Now(), false)
+				s.record("fallback", clock.Now(), true)
+				clock.Advance(2 * time.Minute)
+			},
+			want: []string{"primary", "fallback"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(0, 0)}
+			selector := newEndpointSelector(config, clock.Now)
+			tt.record(selector, clock)
+
+			if got := selector.order(urls); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointSelector_DemotionCooldown(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	selector := newEndpointSelector(EndpointSelectorConfig{
+		Window:           time.Hour,
+		FailureThreshold: 2,
+		Cooldown:         30 * time.Second,
+	}, clock.Now)
+	urls := []string{"primary", "fallback"}
+
+	// The demoted primary is tried last, even behind a fallback that also failed
+	selector.record("primary", clock.Now(), false)
+	selector.record("primary", clock.Now(), false)
+	selector.record("fallback", clock.Now(), false)
+
+	if got := selector.order(urls); !reflect.DeepEqual(got, []string{"fallback", "primary"}) {
+		t.Fatalf("order() after demotion = %v, want [fallback primary]", got)
+	}
+
+	clock.Advance(31 * time.Second)
+	if got := selector.order(urls); !reflect.DeepEqual(got, []string{"primary", "fallback"}) {
+		t.Errorf("order() after cooldown = %v, want [primary fallback]", got)
+	}
+}
+
+func TestCurrencyAPIProvider_SmartURLSelection(t *testing.T) {
+	body := []byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`)
+
+	var mu sync.Mutex
+	hits := map[string]int{}
+	newServer := func(name string, status *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[name]++
+			code := *status
+			mu.Unlock()
+			if code != http.StatusOK {
+				w.WriteHeader(code)
+				return
+			}
+			_, _ = w.Write(body)
+		}))
+	}
+	primaryStatus, fallbackStatus := http.StatusServiceUnavailable, http.StatusOK
+	primary := newServer("primary", &primaryStatus)
+	defer primary.Close()
+	fallback := newServer("fallback", &fallbackStatus)
+	defer fallback.Close()
+
+	config := DefaultCurrencyAPIProviderConfig()
+	config.SmartURLSelection = true
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), primary.URL, fallback.URL, config, nil)
+	if provider.selector == nil {
+		t.Fatal("selector = nil, want enabled")
+	}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	provider.selector = newEndpointSelector(EndpointSelectorConfig{
+		Window:           time.Hour,
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+	}, clock.Now)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+	hitCounts := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits["primary"], hits["fallback"]
+	}
+	fetch := func() {
+		t.Helper()
+		if _, err := provider.FetchRate(context.Background(), base, target); err != nil {
+			t.Fatalf("FetchRate() error = %v", err)
+		}
+	}
+
+	// First request tries the failing primary, then the fallback
+	fetch()
+	// Subsequent requests go straight to the fallback
+	for i := 0; i < 3; i++ {
+		fetch()
+	}
+	if p, f := hitCounts(); p != 1 || f != 4 {
+		t.Errorf("hits = primary=%d fallback=%d, want primary=1 fallback=4", p, f)
+	}
+
+	// After the cooldown the recovered primary is preferred again
+	mu.Lock()
+	primaryStatus = http.StatusOK
+	mu.Unlock()
+	clock.Advance(2 * time.Minute)
+	fetch()
+	if p, f := hitCounts(); p != 2 || f != 4 {
+		t.Errorf("hits after cooldown = primary=%d fallback=%d, want primary=2 fallback=4", p, f)
+	}
+}
+
+func TestCurrencyAPIProvider_SmartURLSelectionDisabled(t *testing.T) {
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), "https://a.example.com", "https://b.example.com", DefaultCurrencyAPIProviderConfig(), nil)
+	if provider.selector != nil {
+		t.Error("selector != nil, want disabled by default")
+	}
+}
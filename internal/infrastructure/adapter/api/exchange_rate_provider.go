@@ -1,17 +1,25 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
 	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
 	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/version"
 )
 
 // currencyAPIResponse represents the new Exchange-api response structure.
@@ -30,15 +38,25 @@ import (
 type currencyAPIResponse struct {
 	Date  string                        `json:"date"`
 	Rates map[string]map[string]float64 `json:"-"` // Dynamic: key is base currency (lowercase)
-	// We'll use a custom unmarshaler or access rates dynamically
+
+	// Unparseable lists rate values that could not be converted to a number,
+	// as "base.target" keys. They are skipped during unmarshaling, logged by the provider,
+	// and counted in ParseResult.Skipped by parseAllRatesResponse.
+	Unparseable []string `json:"-"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for the new API format.
 // The new API nests rates under the base currency code (lowercase).
+//
+// Rates are decoded with UseNumber so every JSON numeric form (integer, decimal,
+// scientific notation) is converted explicitly rather than relying on the
+// decoder's default float64 representation.
 func (r *currencyAPIResponse) UnmarshalJSON(data []byte) error {
 	// First, unmarshal into a map to handle dynamic base currency key
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
 	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
+	if err := decoder.Decode(&raw); err != nil {
 		return err
 	}
 
@@ -49,27 +67,71 @@ func (r *currencyAPIResponse) UnmarshalJSON(data []byte) error {
 
 	// Initialize rates map
 	r.Rates = make(map[string]map[string]float64)
+	r.Unparseable = nil
 
 	// Find the currency code key (everything except "date")
 	for key, value := range raw {
 		if key == "date" {
 			continue
 		}
-		// This should be the base currency code (lowercase)
+		// This should be the base currency code (lowercase). Keys are lowercased
+		// so mirrors returning uppercase codes resolve the same way.
 		if ratesMap, ok := value.(map[string]interface{}); ok {
+			baseKey := strings.ToLower(key)
 			convertedRates := make(map[string]float64)
 			for targetKey, rateVal := range ratesMap {
-				if rate, ok := rateVal.(float64); ok {
-					convertedRates[targetKey] = rate
+				targetKey = strings.ToLower(targetKey)
+				rate, ok := parseRateValue(rateVal)
+				if !ok {
+					r.Unparseable = append(r.Unparseable, baseKey+"."+targetKey)
+					continue
 				}
+				convertedRates[targetKey] = rate
 			}
-			r.Rates[key] = convertedRates
+			r.Rates[baseKey] = convertedRates
 		}
 	}
+	sort.Strings(r.Unparseable)
 
 	return nil
 }
 
+// parseRateValue converts a decoded JSON rate value to float64.
+//
+// Accepted forms:
+// - json.Number: integer, decimal, or scientific notation (e.g. 110, 0.85, 1e-4)
+// - float64: values decoded without UseNumber
+// - string: string-encoded numbers ("0.85") returned by some provider mirrors
+//
+// Returns false for values that are not numeric or not finite, which are
+// skipped like other invalid rates.
+func parseRateValue(value interface{}) (float64, bool) {
+	var rate float64
+	switch v := value.(type) {
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		rate = parsed
+	case float64:
+		rate = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		rate = parsed
+	default:
+		return 0, false
+	}
+
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return 0, false
+	}
+	return rate, true
+}
+
 // parseRateResponse parses a single rate response from the new Exchange-api.
 //
 // This function:
@@ -81,18 +143,19 @@ func (r *currencyAPIResponse) UnmarshalJSON(data []byte) error {
 //
 // Returns an error if:
 // - The API returned an error
-// - Base currency not found in response
+// - Base currency not found in response (wraps provider.ErrUpstreamInvalidResponse)
 // - Target currency not found in response
-// - Rate is invalid (non-positive)
+// - Rate is invalid (non-positive, wraps provider.ErrUpstreamInvalidResponse)
+// - Rate is outside bounds (wraps entity.ErrRateOutOfRange)
 // - Entity creation fails
-func parseRateResponse(resp *currencyAPIResponse, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+func parseRateResponse(resp *currencyAPIResponse, base, target entity.CurrencyCode, bounds entity.RateBounds) (*entity.ExchangeRate, error) {
 	// Get base currency code in lowercase (API uses lowercase)
 	baseLower := strings.ToLower(base.String())
 
 	// Find rates for the base currency
 	baseRates, ok := resp.Rates[baseLower]
 	if !ok {
-		return nil, fmt.Errorf("base currency %s not found in response", base)
+		return nil, fmt.Errorf("%w: base currency %s not found in response", provider.ErrUpstreamInvalidResponse, base)
 	}
 
 	// Get target currency code in lowercase (API uses lowercase)
@@ -101,12 +164,17 @@ func parseRateResponse(resp *currencyAPIResponse, base, target entity.CurrencyCo
 	// Get rate for target currency
 	rate, ok := baseRates[targetLower]
 	if !ok {
-		return nil, fmt.Errorf("target currency %s not found in response", target)
+		return nil, &provider.UnsupportedTargetError{Base: base.String(), Target: target.String(), Available: availableTargets(baseRates)}
 	}
 
 	// Validate rate is positive (entity validation will also check this, but fail fast here)
 	if rate <= 0 {
-		return nil, fmt.Errorf("invalid rate: %f (must be positive)", rate)
+		return nil, fmt.Errorf("%w: invalid rate: %f (must be positive)", provider.ErrUpstreamInvalidResponse, rate)
+	}
+
+	// Reject absurd magnitudes before they reach the cache
+	if err := bounds.Check(rate); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", base, target, err)
 	}
 
 	// Create domain entity
@@ -115,38 +183,172 @@ func parseRateResponse(resp *currencyAPIResponse, base, target entity.CurrencyCo
 	return entity.NewExchangeRate(base, target, rate, time.Now(), false)
 }
 
+// availableTargets returns the valid currency codes of a base's rates, uppercase and sorted.
+func availableTargets(baseRates map[string]float64) []string {
+	targets := make([]string, 0, len(baseRates))
+	for code := range baseRates {
+		if currency := entity.CurrencyCode(code).Normalize(); currency.IsValid() {
+			targets = append(targets, currency.String())
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// StatusError reports an unexpected HTTP status code from the upstream API.
+//
+// A 404 usually means the requested base currency does not exist upstream, so it
+// matches provider.ErrCurrencyUnsupported with errors.Is. A 404 for a known-good
+// base (KnownBase) means the endpoint itself is broken instead, so it matches
+// provider.ErrUpstreamInvalidResponse.
+type StatusError struct {
+	StatusCode int
+	KnownBase  bool // The base was served before (or is the probe base), so a 404 is the upstream's fault
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// Unwrap returns provider.ErrCurrencyUnsupported for 404 responses, or
+// provider.ErrUpstreamInvalidResponse for 404 responses for a known-good base.
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		if e.KnownBase {
+			return provider.ErrUpstreamInvalidResponse
+		}
+		return provider.ErrCurrencyUnsupported
+	}
+	return nil
+}
+
+// probeBase is a base currency every endpoint serves (the health check probes it).
+// A 404 for it is never an unsupported currency.
+const probeBase entity.CurrencyCode = "USD"
+
+// EndpointError is the failure of a single API endpoint.
+type EndpointError struct {
+	URL string
+	Err error
+}
+
+func (e EndpointError) Error() string {
+	return fmt.Sprintf("%s: %v", e.URL, e.Err)
+}
+
+func (e EndpointError) Unwrap() error { return e.Err }
+
+// AllEndpointsFailedError reports that every API endpoint (primary and
+// fallback) failed, with the error of each endpoint in the order tried.
+//
+// It unwraps to the last endpoint's error, so errors.Is and errors.As checks
+// (e.g. for provider.ErrCurrencyUnsupported or *StatusError) behave as if the
+// last error had been returned directly.
+type AllEndpointsFailedError struct {
+	Failures []EndpointError
+}
+
+func (e *AllEndpointsFailedError) Error() string {
+	return fmt.Sprintf("all API endpoints failed, last error: %v", e.Last())
+}
+
+// Unwrap returns the last endpoint's error.
+func (e *AllEndpointsFailedError) Unwrap() error { return e.Last() }
+
+// Last returns the error of the last endpoint tried, or nil if none failed.
+func (e *AllEndpointsFailedError) Last() error {
+	if len(e.Failures) == 0 {
+		return nil
+	}
+	return e.Failures[len(e.Failures)-1].Err
+}
+
+// add records the failure of the endpoint at url.
+func (e *AllEndpointsFailedError) add(url string, err error) {
+	e.Failures = append(e.Failures, EndpointError{URL: url, Err: err})
+}
+
+// Skip reasons reported in ParseResult.SkipReasons.
+const (
+	skipReasonNonPositiveRate     = "non_positive_rate"
+	skipReasonUnparseable         = "unparseable_rate"
+	skipReasonOutOfRange          = "rate_out_of_range"
+	skipReasonInvalidCurrencyCode = "invalid_currency_code"
+	skipReasonSameAsBase          = "same_as_base"
+	skipReasonInvalidEntity       = "invalid_entity"
+)
+
+// ParseResult holds the outcome of parsing an all-rates response.
+//
+// Invalid entries are skipped rather than failing the whole response;
+// Skipped and SkipReasons make that graceful degradation observable.
+type ParseResult struct {
+	Rates       []*entity.ExchangeRate // Valid rates (empty, not nil, if none)
+	Skipped     int                    // Number of entries that were dropped
+	SkipReasons map[string]int         // Count of dropped entries per reason
+}
+
+// skip records a dropped entry with the given reason.
+func (r *ParseResult) skip(reason string) {
+	r.Skipped++
+	r.SkipReasons[reason]++
+}
+
 // parseAllRatesResponse parses an all-rates response from the new Exchange-api.
 //
 // This function:
 // - Validates the response structure
 // - Validates the base currency matches (case-insensitive)
 // - Converts the rates map to a slice of domain entities
-// - Skips invalid rates or currency codes (graceful degradation)
+// - Skips invalid rates, rates outside bounds, or invalid currency codes (graceful degradation)
+// - Counts skipped entries per reason in the result, including values for the
+// base that could not be parsed as numbers (resp.Unparseable)
 // - Returns an empty slice if no valid rates are found (not an error)
+// - Stamps every rate with the same timestamp, captured once per response
 //
 // Returns an error if:
 // - The API returned an error
-// - Base currency not found in response
+// - Base currency not found in response (wraps provider.ErrUpstreamInvalidResponse)
 //
 // Note: Invalid rates or currency codes are skipped (not returned as errors)
 // to allow partial success when some rates are valid.
-func parseAllRatesResponse(resp *currencyAPIResponse, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+func parseAllRatesResponse(resp *currencyAPIResponse, base entity.CurrencyCode, bounds entity.RateBounds) (*ParseResult, error) {
 	// Get base currency code in lowercase (API uses lowercase)
 	baseLower := strings.ToLower(base.String())
 
 	// Find rates for the base currency
 	baseRates, ok := resp.Rates[baseLower]
 	if !ok {
-		return nil, fmt.Errorf("base currency %s not found in response", base)
+		return nil, fmt.Errorf("%w: base currency %s not found in response", provider.ErrUpstreamInvalidResponse, base)
 	}
 
+	// Capture the timestamp once so every rate in the batch is consistent
+	now := time.Now()
+
 	// Convert rates map to entity slice
 	// Pre-allocate with capacity for better performance
-	rates := make([]*entity.ExchangeRate, 0, len(baseRates))
+	result := &ParseResult{
+		Rates:       make([]*entity.ExchangeRate, 0, len(baseRates)),
+		SkipReasons: make(map[string]int),
+	}
+
+	// Values dropped while unmarshaling are skipped entries too
+	for _, key := range resp.Unparseable {
+		if strings.HasPrefix(key, baseLower+".") {
+			result.skip(skipReasonUnparseable)
+		}
+	}
 
 	for targetStr, rate := range baseRates {
 		// Skip invalid rates (non-positive)
 		if rate <= 0 {
+			result.skip(skipReasonNonPositiveRate)
+			continue
+		}
+
+		// Skip absurd magnitudes (upstream data corruption)
+		if bounds.Check(rate) != nil {
+			result.skip(skipReasonOutOfRange)
 			continue
 		}
 
@@ -154,27 +356,30 @@ func parseAllRatesResponse(resp *currencyAPIResponse, base entity.CurrencyCode)
 		target, err := entity.NewCurrencyCode(targetStr)
 		if err != nil {
 			// Skip invalid currency codes (graceful degradation)
+			result.skip(skipReasonInvalidCurrencyCode)
 			continue
 		}
 
 		// Skip if target equals base (entity validation would reject this)
 		if target.Equal(base) {
+			result.skip(skipReasonSameAsBase)
 			continue
 		}
 
 		// Create entity (includes full validation)
-		rateEntity, err := entity.NewExchangeRate(base, target, rate, time.Now(), false)
+		rateEntity, err := entity.NewExchangeRate(base, target, rate, now, false)
 		if err != nil {
 			// Skip if entity creation fails (graceful degradation)
+			result.skip(skipReasonInvalidEntity)
 			continue
 		}
 
-		rates = append(rates, rateEntity)
+		result.Rates = append(result.Rates, rateEntity)
 	}
 
-	// Return empty slice (not nil) if no rates found
+	// Rates is an empty slice (not nil) if no rates found
 	// This is consistent with repository.GetByBase() behavior
-	return rates, nil
+	return result, nil
 }
 
 // CurrencyAPIProvider implements ExchangeRateProvider using Currency-api.
@@ -183,8 +388,73 @@ func parseAllRatesResponse(resp *currencyAPIResponse, base entity.CurrencyCode)
 type CurrencyAPIProvider struct {
 	client      *http.Client
 	baseURL     string
-	fallbackURL string // Fallback URL for high availability
+	fallbackURL string            // Fallback URL for high availability
+	urls        []string          // Base URLs tried in priority order
+	selector    *endpointSelector // Health-aware URL ordering (nil keeps priority order)
+	cache       *responseCache    // Short-lived response body cache (nil when disabled)
+	inflight    *inflightGroup    // Shares concurrent downloads of the same URL (nil when disabled)
+	config      CurrencyAPIProviderConfig
 	logger      *logger.Logger
+	knownBases  sync.Map // Bases served successfully; a later 404 for them is an upstream failure
+}
+
+// DefaultUserAgent returns the User-Agent sent on provider requests unless
+// overridden: "go-currenseen/" followed by the build version (see pkg/version).
+// It is built at runtime because -ldflags -X can only set variables.
+func DefaultUserAgent() string {
+	return "go-currenseen/" + version.Version
+}
+
+// DefaultMaxResponseBytes is the default limit for provider response bodies.
+const DefaultMaxResponseBytes int64 = 5 << 20 // 5 MiB
+
+// CurrencyAPIProviderConfig holds optional settings for CurrencyAPIProvider.
+type CurrencyAPIProviderConfig struct {
+	MaxResponseBytes  int64  // Maximum response body size in bytes
+	UserAgent         string // User-Agent header sent on every request
+	SmartURLSelection bool   // Prefer the healthiest URL based on recent results instead of priority order
+
+	HTTPCacheTTL        time.Duration // How long response bodies are reused in memory (0 disables)
+	HTTPCacheMaxEntries int           // Maximum cached response bodies
+	InflightDedup       bool          // Share one download between concurrent fetches of the same URL
+
+	RateBounds entity.RateBounds // Accepted rate range; rates outside it are rejected as upstream corruption
+	MinRates   int               // Reject all-rates responses with fewer valid rates as invalid (0 = accept empty responses)
+
+	FetchBudget         time.Duration // Maximum total time of one fetch across all endpoints (0 = unbounded)
+	MaxFallbackAttempts int           // Maximum endpoints tried by one fetch, primary included (0 = all)
+
+	MaxDateAge        time.Duration // Warn when a response's date is older than this, e.g. from a stale CDN edge (0 = disabled)
+	StaleDateFallback bool          // Try the remaining endpoints for a more recent date when a response is older than MaxDateAge
+
+	Interceptors         []RequestInterceptor  // Applied in order to every outbound request, after User-Agent is set
+	ResponseInterceptors []ResponseInterceptor // Applied in order to every 200 OK response, before the body is read
+}
+
+// DefaultCurrencyAPIProviderConfig returns the default provider configuration.
+//
+// Default values:
+// - MaxResponseBytes: 5 MiB (all-rates responses are typically well under 100 KB)
+// - UserAgent: DefaultUserAgent() ("go-currenseen/<version>")
+// - SmartURLSelection: false
+// - HTTPCacheTTL: 0 (disabled)
+// - HTTPCacheMaxEntries: DefaultHTTPCacheMaxEntries (64)
+// - InflightDedup: false
+// - RateBounds: entity.DefaultRateBounds() (1e-12 to 1e12)
+// - MinRates: 0 (a response with the base key but no valid rates succeeds with no rates)
+// - FetchBudget: 0 (each attempt is bounded only by the HTTP client timeout)
+// - MaxFallbackAttempts: 0 (every endpoint is tried)
+// - MaxDateAge: 0 (response dates are not checked)
+// - StaleDateFallback: false
+// - Interceptors: none
+// - ResponseInterceptors: none
+func DefaultCurrencyAPIProviderConfig() CurrencyAPIProviderConfig {
+	return CurrencyAPIProviderConfig{
+		MaxResponseBytes:    DefaultMaxResponseBytes,
+		UserAgent:           DefaultUserAgent(),
+		HTTPCacheMaxEntries: DefaultHTTPCacheMaxEntries,
+		RateBounds:          entity.DefaultRateBounds(),
+	}
 }
 
 // NewCurrencyAPIProvider creates a new CurrencyAPIProvider.
@@ -204,6 +474,12 @@ func NewCurrencyAPIProvider(client *http.Client, baseURL string, log *logger.Log
 // NewCurrencyAPIProviderWithFallback creates a new CurrencyAPIProvider with a custom fallback URL.
 // This is useful for testing. If fallbackURL is empty, uses the default fallback URL.
 func NewCurrencyAPIProviderWithFallback(client *http.Client, baseURL, fallbackURL string, log *logger.Logger) *CurrencyAPIProvider {
+	return NewCurrencyAPIProviderWithConfig(client, baseURL, fallbackURL, DefaultCurrencyAPIProviderConfig(), log)
+}
+
+// NewCurrencyAPIProviderWithConfig creates a new CurrencyAPIProvider with custom configuration.
+// Empty URLs use the defaults; zero or non-positive config values fall back to their defaults.
+func NewCurrencyAPIProviderWithConfig(client *http.Client, baseURL, fallbackURL string, config CurrencyAPIProviderConfig, log *logger.Logger) *CurrencyAPIProvider {
 	if baseURL == "" {
 		// New API URL: uses jsDelivr CDN (primary)
 		baseURL = "https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@latest/v1"
@@ -212,17 +488,220 @@ func NewCurrencyAPIProviderWithFallback(client *http.Client, baseURL, fallbackUR
 	if fallbackURL == "" {
 		fallbackURL = "https://latest.currency-api.pages.dev/v1"
 	}
+	if config.MaxResponseBytes <= 0 {
+		config.MaxResponseBytes = DefaultMaxResponseBytes
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent()
+	}
+	if config.RateBounds.Validate() != nil {
+		config.RateBounds = entity.DefaultRateBounds()
+	}
 	if log == nil {
 		log = logger.NewFromEnv()
 	}
+	var selector *endpointSelector
+	if config.SmartURLSelection {
+		selector = newEndpointSelector(DefaultEndpointSelectorConfig(), nil)
+	}
 	return &CurrencyAPIProvider{
 		client:      client,
 		baseURL:     baseURL,
 		fallbackURL: fallbackURL,
+		urls:        []string{baseURL, fallbackURL},
+		selector:    selector,
+		cache:       newResponseCache(config.HTTPCacheTTL, config.HTTPCacheMaxEntries, nil),
+		inflight:    newInflightGroup(config.InflightDedup),
+		config:      config,
 		logger:      log,
 	}
 }
 
+// NewCurrencyAPIProviderWithURLs creates a new CurrencyAPIProvider that tries
+// the given base URLs in priority order (e.g. a Cloudflare Pages mirror before jsDelivr).
+//
+// Returns an error if urls is empty or any URL is not an absolute http(s) URL.
+func NewCurrencyAPIProviderWithURLs(client *http.Client, urls []string, config CurrencyAPIProviderConfig, log *logger.Logger) (*CurrencyAPIProvider, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("provider URL list cannot be empty")
+	}
+	normalized := make([]string, 0, len(urls))
+	for _, raw := range urls {
+		if err := validateProviderURL(raw); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, strings.TrimRight(raw, "/"))
+	}
+
+	fallbackURL := ""
+	if len(normalized) > 1 {
+		fallbackURL = normalized[1]
+	}
+	p := NewCurrencyAPIProviderWithConfig(client, normalized[0], fallbackURL, config, log)
+	p.urls = normalized
+	return p, nil
+}
+
+// endpoints returns the base URLs one fetch tries, in order: by priority, or
+// healthiest first with SmartURLSelection, limited to the first
+// MaxFallbackAttempts. This bounds the latency of a fetch with a long URL list;
+// with SmartURLSelection, the URLs left out are still tried by later fetches
+// once the ones before them fail and are demoted.
+func (p *CurrencyAPIProvider) endpoints() []string {
+	roots := p.selector.order(p.urls)
+	if limit := p.config.MaxFallbackAttempts; limit > 0 && len(roots) > limit {
+		roots = roots[:limit]
+	}
+	return roots
+}
+
+// validateProviderURL checks that raw is an absolute http or https URL.
+func validateProviderURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid provider URL %q: %w", raw, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid provider URL %q: must be an absolute http(s) URL", raw)
+	}
+	return nil
+}
+
+// markKnownBase records that base was served successfully, so later 404s for it
+// are classified as upstream failures.
+func (p *CurrencyAPIProvider) markKnownBase(base entity.CurrencyCode) {
+	p.knownBases.Store(base.Normalize(), struct{}{})
+}
+
+// classifyNotFound flags a 404 for a known-good base (the probe base, or a base
+// served before) on err, so it counts as an upstream failure rather than an
+// unsupported currency.
+func (p *CurrencyAPIProvider) classifyNotFound(base entity.CurrencyCode, err error) {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		return
+	}
+	base = base.Normalize()
+	_, served := p.knownBases.Load(base)
+	statusErr.KnownBase = served || base == probeBase
+}
+
+// endpointFailed records a failed request to an endpoint.
+//
+// Only errors that indicate an unhealthy endpoint (see DefaultFailureClassifier)
+// count against it for URL selection; e.g. a 404 for an unknown currency does not.
+func (p *CurrencyAPIProvider) endpointFailed(failures *AllEndpointsFailedError, root, url string, started time.Time, err error) {
+	failures.add(url, err)
+	if DefaultFailureClassifier(err) {
+		p.selector.record(root, started, false)
+	}
+}
+
+// readResponseBody reads a response body up to the configured size limit.
+//
+// Returns provider.ErrUpstreamInvalidResponse if the body exceeds the limit,
+// so a misbehaving endpoint cannot exhaust the function's memory, and
+// provider.ErrProviderRead if the body cannot be read.
+func (p *CurrencyAPIProvider) readResponseBody(body io.Reader) ([]byte, error) {
+	limit := p.config.MaxResponseBytes
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", provider.ErrProviderRead, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: response body exceeds %d bytes", provider.ErrUpstreamInvalidResponse, limit)
+	}
+	return data, nil
+}
+
+// fetchBody downloads the response body for url, or returns a cached copy
+// (cached=true) if the HTTP response cache holds one. For a cached copy, the
+// age it had when downloaded plus the time since is recorded in the context's
+// ResponseAge, so rates parsed from it keep their original timestamp.
+//
+// With InflightDedup, a download of url already in progress is waited for
+// instead of starting another; its body is returned as a cached copy, with
+// its upstream age recorded in the context's ResponseAge.
+//
+// Returns an error if the request fails, the status is not 200 OK, a response
+// interceptor rejects the response, or the body cannot be read.
+func (p *CurrencyAPIProvider) fetchBody(ctx context.Context, url string) (body []byte, cached bool, err error) {
+	log := p.logger.WithContext(ctx)
+
+	if body, age, ok := p.cache.get(url); ok {
+		log.Debug("using cached API response", "url", url, "age", age.String())
+		if responseAge := ResponseAgeFromContext(ctx); responseAge != nil {
+			responseAge.Record(age)
+		}
+		return body, true, nil
+	}
+
+	body, age, shared, err := p.inflight.do(ctx, url, func() ([]byte, time.Duration, error) {
+		return p.download(ctx, url)
+	})
+	if shared {
+		log.Debug("shared concurrent API request", "url", url)
+		if responseAge := ResponseAgeFromContext(ctx); responseAge != nil && err == nil {
+			responseAge.Record(age)
+		}
+	}
+	return body, shared, err
+}
+
+// download performs the HTTP request for url and reads the response body.
+// It also returns the upstream age response interceptors recorded in the
+// context's ResponseAge (0 if none).
+//
+// Failures to build the request or to get a response are wrapped in
+// provider.ErrProviderRequestBuild and provider.ErrProviderTransport.
+func (p *CurrencyAPIProvider) download(ctx context.Context, url string) ([]byte, time.Duration, error) {
+	log := p.logger.WithContext(ctx)
+
+	// Create request with context (enables cancellation and timeout)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		log.Debug("failed to create request", "error", err.Error())
+		return nil, 0, fmt.Errorf("%w: %w", provider.ErrProviderRequestBuild, err)
+	}
+	req.Header.Set("User-Agent", p.config.UserAgent)
+	if err := applyInterceptors(req, p.config.Interceptors); err != nil {
+		log.Debug("request rejected by interceptor", "error", err.Error(), "url", url)
+		return nil, 0, err
+	}
+
+	// Execute request
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.Debug("HTTP request failed",
+			"error", err.Error(),
+			"url", url,
+		)
+		return nil, 0, fmt.Errorf("%w: %w", provider.ErrProviderTransport, err)
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK {
+		log.Debug("unexpected status code",
+			"status_code", resp.StatusCode,
+			"url", url,
+		)
+		return nil, 0, &StatusError{StatusCode: resp.StatusCode}
+	}
+	if err := applyResponseInterceptors(resp, p.config.ResponseInterceptors); err != nil {
+		log.Debug("response rejected by interceptor", "error", err.Error(), "url", url)
+		return nil, 0, err
+	}
+
+	// Read response body (bounded)
+	body, err := p.readResponseBody(resp.Body)
+	if err != nil {
+		log.Debug("failed to read response", "error", err.Error())
+		return nil, 0, err
+	}
+	return body, ResponseAgeFromContext(ctx).Age(), nil
+}
+
 // FetchRate implements provider.ExchangeRateProvider.
 //
 // This method:
@@ -230,10 +709,15 @@ func NewCurrencyAPIProviderWithFallback(client *http.Client, baseURL, fallbackUR
 // - Makes an HTTP GET request with context support
 // - Validates the HTTP response status code
 // - Parses the JSON response
+// - Warns when the response date is older than MaxDateAge and, with
+// StaleDateFallback, prefers a remaining endpoint serving a more recent date
 // - Extracts and returns the rate for the target currency
+// - Records every rate of the downloaded file in the context's FetchedRates, if present
 //
 // Context cancellation: Returns error if ctx is cancelled or times out.
 // The HTTP client respects the context deadline for request timeout.
+// With a FetchBudget, the call returns within the budget; each endpoint gets a
+// share of the remaining time (see attemptContext).
 func (p *CurrencyAPIProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
 	log := p.logger.WithContext(ctx)
 	log.Debug("fetching exchange rate from external API",
@@ -245,89 +729,115 @@ func (p *CurrencyAPIProvider) FetchRate(ctx context.Context, base, target entity
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
+	ctx, cancel := p.withFetchBudget(ctx)
+	defer cancel()
 
 	// Build URL - New API format: /currencies/{baseCurrency}.json
 	// Currency codes must be lowercase in the URL
 	baseLower := strings.ToLower(base.String())
 	path := fmt.Sprintf("/currencies/%s.json", baseLower)
 
-	// Try primary URL first, then fallback
-	urls := []string{
-		fmt.Sprintf("%s%s", p.baseURL, path),
-		fmt.Sprintf("%s%s", p.fallbackURL, path),
-	}
+	// Try URLs in priority order (primary first, then fallbacks),
+	// or healthiest first when smart URL selection is enabled
+	roots := p.endpoints()
 
-	var lastErr error
-	for i, url := range urls {
+	var failures AllEndpointsFailedError
+	for i, root := range roots {
+		if i > 0 && ctx.Err() != nil {
+			// No time left for the remaining endpoints
+			break
+		}
+		url := root + path
+		started := p.selector.start()
 		log.Debug("attempting API request",
 			"attempt", i+1,
-			"total_attempts", len(urls),
+			"total_attempts", len(roots),
 			"url", url,
 		)
 
-		// Create request with context (enables cancellation and timeout)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			log.Debug("failed to create request", "error", err.Error())
-			continue
-		}
-
-		// Execute request
-		resp, err := p.client.Do(req)
-		if err != nil {
-			// Log error but try fallback
-			log.Debug("HTTP request failed",
-				"error", err.Error(),
-				"url", url,
-			)
-			lastErr = fmt.Errorf("http request failed: %w", err)
-			continue
+		// Download the response body (or reuse a recently cached one)
+		attemptCtx, cancelAttempt := p.attemptContext(ctx, len(roots)-i)
+		reqCtx, age := WithResponseAge(attemptCtx)
+		body, cached, err := p.fetchBody(reqCtx, url)
+		cancelAttempt()
+		var interceptErr *InterceptorError
+		if errors.As(err, &interceptErr) {
+			// Interceptors would reject the request for every endpoint
+			return nil, err
 		}
-		defer resp.Body.Close()
-
-		// Check status code
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-			log.Debug("unexpected status code",
-				"status_code", resp.StatusCode,
-				"url", url,
-			)
-			continue
-		}
-
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read response: %w", err)
-			log.Debug("failed to read response", "error", err.Error())
+			p.classifyNotFound(base, err)
+			p.endpointFailed(&failures, root, url, started, err)
 			continue
 		}
 
 		// Parse JSON
 		var apiResp currencyAPIResponse
 		if err := json.Unmarshal(body, &apiResp); err != nil {
-			lastErr = fmt.Errorf("failed to parse response: %w", err)
+			p.endpointFailed(&failures, root, url, started, fmt.Errorf("%w: failed to parse response: %w", provider.ErrUpstreamInvalidResponse, err))
 			log.Debug("failed to parse response", "error", err.Error())
 			continue
 		}
+		if p.isStaleDate(ctx, url, &apiResp) && p.config.StaleDateFallback {
+			if fresher := p.fresherResponse(ctx, roots[i+1:], path, &failures); fresher != nil {
+				// The stale endpoint still answered
+				if !cached {
+					p.selector.record(root, started, true)
+				}
+				root, url, started, body, cached, age, apiResp = fresher.root, fresher.url, fresher.started, fresher.body, fresher.cached, fresher.age, fresher.resp
+			}
+		}
+		if !cached {
+			p.selector.record(root, started, true)
+			p.cache.put(url, body, age.Age())
+		}
+		p.markKnownBase(base)
+		if len(apiResp.Unparseable) > 0 {
+			log.Warn("skipped unparseable rate values in provider response",
+				"url", url,
+				"count", len(apiResp.Unparseable),
+				"values", apiResp.Unparseable,
+			)
+		}
 
 		// Success! Convert to domain entity
+		rate, err := parseRateResponse(&apiResp, base, target, p.config.RateBounds)
+		if err != nil {
+			return nil, err
+		}
+		rate.Timestamp = rate.Timestamp.Add(-age.Age())
+		p.recordFetchedRates(ctx, &apiResp, base, rate.Timestamp)
+
 		log.Info("successfully fetched rate from API",
 			"url", url,
 			"base", base.String(),
 			"target", target.String(),
 		)
-		return parseRateResponse(&apiResp, base, target)
+		recordFetchSource(ctx, url)
+		return rate, nil
 	}
 
 	// All URLs failed
 	log.Error("all API endpoints failed",
-		"error", lastErr.Error(),
+		"error", failures.Last().Error(),
+		"endpoints_failed", len(failures.Failures),
 		"base", base.String(),
 		"target", target.String(),
 	)
-	return nil, fmt.Errorf("all API endpoints failed, last error: %w", lastErr)
+	return nil, &failures
+}
+
+// FetchRateAndAll implements provider.RateAndAllFetcher.
+//
+// The base file is downloaded once (see FetchRate); every valid rate it contains
+// is returned alongside the target rate.
+func (p *CurrencyAPIProvider) FetchRateAndAll(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, []*entity.ExchangeRate, error) {
+	ctx, fetched := provider.WithFetchedRates(ctx)
+	rate, err := p.FetchRate(ctx, base, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rate, fetched.Rates(), nil
 }
 
 // FetchAllRates implements provider.ExchangeRateProvider.
@@ -337,11 +847,14 @@ func (p *CurrencyAPIProvider) FetchRate(ctx context.Context, base, target entity
 // - Makes an HTTP GET request with context support
 // - Validates the HTTP response status code
 // - Parses the JSON response
+// - Warns when the response date is older than MaxDateAge and, with
+// StaleDateFallback, prefers a remaining endpoint serving a more recent date
 // - Converts all rates to domain entities
 // - Returns empty slice (not nil) if no rates are found
 //
 // Context cancellation: Returns error if ctx is cancelled or times out.
 // The HTTP client respects the context deadline for request timeout.
+// With a FetchBudget, the call returns within the budget (see FetchRate).
 func (p *CurrencyAPIProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
 	log := p.logger.WithContext(ctx)
 	log.Debug("fetching all exchange rates from external API",
@@ -352,83 +865,105 @@ func (p *CurrencyAPIProvider) FetchAllRates(ctx context.Context, base entity.Cur
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
+	ctx, cancel := p.withFetchBudget(ctx)
+	defer cancel()
 
 	// Build URL - New API format: /currencies/{baseCurrency}.json
 	// Currency codes must be lowercase in the URL
 	baseLower := strings.ToLower(base.String())
 	path := fmt.Sprintf("/currencies/%s.json", baseLower)
 
-	// Try primary URL first, then fallback
-	urls := []string{
-		fmt.Sprintf("%s%s", p.baseURL, path),
-		fmt.Sprintf("%s%s", p.fallbackURL, path),
-	}
+	// Try URLs in priority order (primary first, then fallbacks),
+	// or healthiest first when smart URL selection is enabled
+	roots := p.endpoints()
 
-	var lastErr error
-	for i, url := range urls {
+	var failures AllEndpointsFailedError
+	for i, root := range roots {
+		if i > 0 && ctx.Err() != nil {
+			// No time left for the remaining endpoints
+			break
+		}
+		url := root + path
+		started := p.selector.start()
 		log.Debug("attempting API request",
 			"attempt", i+1,
-			"total_attempts", len(urls),
+			"total_attempts", len(roots),
 			"url", url,
 		)
 
-		// Create request with context (enables cancellation and timeout)
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			log.Debug("failed to create request", "error", err.Error())
-			continue
-		}
-
-		// Execute request
-		resp, err := p.client.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("http request failed: %w", err)
-			log.Debug("HTTP request failed",
-				"error", err.Error(),
-				"url", url,
-			)
-			continue
+		// Download the response body (or reuse a recently cached one)
+		attemptCtx, cancelAttempt := p.attemptContext(ctx, len(roots)-i)
+		reqCtx, age := WithResponseAge(attemptCtx)
+		body, cached, err := p.fetchBody(reqCtx, url)
+		cancelAttempt()
+		var interceptErr *InterceptorError
+		if errors.As(err, &interceptErr) {
+			// Interceptors would reject the request for every endpoint
+			return nil, err
 		}
-		defer resp.Body.Close()
-
-		// Check status code
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-			log.Debug("unexpected status code",
-				"status_code", resp.StatusCode,
-				"url", url,
-			)
-			continue
-		}
-
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read response: %w", err)
-			log.Debug("failed to read response", "error", err.Error())
+			p.classifyNotFound(base, err)
+			p.endpointFailed(&failures, root, url, started, err)
 			continue
 		}
 
 		// Parse JSON
 		var apiResp currencyAPIResponse
 		if err := json.Unmarshal(body, &apiResp); err != nil {
-			lastErr = fmt.Errorf("failed to parse response: %w", err)
+			p.endpointFailed(&failures, root, url, started, fmt.Errorf("%w: failed to parse response: %w", provider.ErrUpstreamInvalidResponse, err))
 			log.Debug("failed to parse response", "error", err.Error())
 			continue
 		}
+		if p.isStaleDate(ctx, url, &apiResp) && p.config.StaleDateFallback {
+			if fresher := p.fresherResponse(ctx, roots[i+1:], path, &failures); fresher != nil {
+				// The stale endpoint still answered
+				if !cached {
+					p.selector.record(root, started, true)
+				}
+				root, url, started, body, cached, age, apiResp = fresher.root, fresher.url, fresher.started, fresher.body, fresher.cached, fresher.age, fresher.resp
+			}
+		}
+		if len(apiResp.Unparseable) > 0 {
+			log.Warn("skipped unparseable rate values in provider response",
+				"url", url,
+				"count", len(apiResp.Unparseable),
+				"values", apiResp.Unparseable,
+			)
+		}
 
 		// Success! Convert to domain entities
-		rates, err := parseAllRatesResponse(&apiResp, base)
+		result, err := p.parseAllRates(&apiResp, base)
 		if err != nil {
-			lastErr = err
+			p.endpointFailed(&failures, root, url, started, err)
 			log.Debug("failed to parse rates response", "error", err.Error())
 			continue
 		}
+		if !cached {
+			p.selector.record(root, started, true)
+			p.cache.put(url, body, age.Age())
+		}
+		p.markKnownBase(base)
+		for _, rate := range result.Rates {
+			rate.Timestamp = rate.Timestamp.Add(-age.Age())
+		}
+
+		// Make skipped entries observable (logs and, if requested, fetch stats)
+		if result.Skipped > 0 {
+			log.Warn("skipped invalid rates in API response",
+				"base", base.String(),
+				"skipped", result.Skipped,
+				"skip_reasons", result.SkipReasons,
+			)
+		}
+		if stats := provider.FetchStatsFromContext(ctx); stats != nil {
+			stats.Record(result.Skipped, result.SkipReasons)
+		}
 
 		// Return empty slice (not nil) if no rates
 		// This is consistent with repository.GetByBase() behavior
+		rates := result.Rates
 		if rates == nil {
+			recordFetchSource(ctx, url)
 			return []*entity.ExchangeRate{}, nil
 		}
 
@@ -437,17 +972,66 @@ func (p *CurrencyAPIProvider) FetchAllRates(ctx context.Context, base entity.Cur
 			"base", base.String(),
 			"rates_count", len(rates),
 		)
+		recordFetchSource(ctx, url)
 		return rates, nil
 	}
 
 	// All URLs failed
 	log.Error("all API endpoints failed",
-		"error", lastErr.Error(),
+		"error", failures.Last().Error(),
+		"endpoints_failed", len(failures.Failures),
 		"base", base.String(),
 	)
-	return nil, fmt.Errorf("all API endpoints failed, last error: %w", lastErr)
+	return nil, &failures
+}
+
+// parseAllRates parses an all-rates response and, if MinRates is set, validates it:
+// a response with fewer than MinRates valid rates is rejected with
+// provider.ErrUpstreamInvalidResponse, so garbage is never cached.
+func (p *CurrencyAPIProvider) parseAllRates(resp *currencyAPIResponse, base entity.CurrencyCode) (*ParseResult, error) {
+	result, err := parseAllRatesResponse(resp, base, p.config.RateBounds)
+	if err != nil {
+		return nil, err
+	}
+	if p.config.MinRates <= 0 {
+		return result, nil
+	}
+	if len(result.Rates) < p.config.MinRates {
+		return nil, fmt.Errorf("%w: %d valid rates for base %s, want at least %d",
+			provider.ErrUpstreamInvalidResponse, len(result.Rates), base, p.config.MinRates)
+	}
+	return result, nil
+}
+
+// recordFetchedRates records every valid rate of a downloaded base file in the
+// context's FetchedRates, if present, so a single-pair fetch can warm the cache.
+// Every rate gets timestamp, the (backdated) timestamp of the rate returned by FetchRate.
+func (p *CurrencyAPIProvider) recordFetchedRates(ctx context.Context, resp *currencyAPIResponse, base entity.CurrencyCode, timestamp time.Time) {
+	fetched := provider.FetchedRatesFromContext(ctx)
+	if fetched == nil {
+		return
+	}
+	result, err := p.parseAllRates(resp, base)
+	if err != nil {
+		return
+	}
+	for _, rate := range result.Rates {
+		rate.Timestamp = timestamp
+	}
+	fetched.Record(result.Rates)
+}
+
+// recordFetchSource records the URL that served a successful fetch
+// in the context's FetchSource, if present.
+func recordFetchSource(ctx context.Context, url string) {
+	if source := provider.FetchSourceFromContext(ctx); source != nil {
+		source.Record(string(ProviderTypeCurrencyAPI), url)
+	}
 }
 
 // Ensure CurrencyAPIProvider implements ExchangeRateProvider interface.
 // This compile-time check ensures we've implemented all required methods.
 var _ provider.ExchangeRateProvider = (*CurrencyAPIProvider)(nil)
+
+// Ensure CurrencyAPIProvider can return the full rate set of a single-pair fetch.
+var _ provider.RateAndAllFetcher = (*CurrencyAPIProvider)(nil)
@@ -6,10 +6,15 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	domainprovider "github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/pkg/version"
 )
 
 func TestNewCurrencyAPIProvider(t *testing.T) {
@@ -294,6 +299,79 @@ func TestCurrencyAPIProvider_FetchAllRates_EmptyRates(t *testing.T) {
 	}
 }
 
+func TestCurrencyAPIProvider_FetchAllRates_MinRates(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		minRates int
+		wantErr  bool
+		wantLen  int
+	}{
+		{"empty accepted when disabled", `{"date": "2024-01-15", "usd": {}}`, 0, false, 0},
+		{"empty rejected when strict", `{"date": "2024-01-15", "usd": {}}`, 1, true, 0},
+		{"only invalid rates rejected when strict", `{"date": "2024-01-15", "usd": {"eur": -1, "usd": 1}}`, 1, true, 0},
+		{"missing base rejected when strict", `{"date": "2024-01-15", "eur": {"usd": 1.1}}`, 1, true, 0},
+		{"enough rates accepted when strict", `{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`, 2, false, 2},
+		{"too few rates rejected", `{"date": "2024-01-15", "usd": {"eur": 0.85}}`, 2, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			config := DefaultCurrencyAPIProviderConfig()
+			config.MinRates = tt.minRates
+			provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+
+			base, _ := entity.NewCurrencyCode("USD")
+			rates, err := provider.FetchAllRates(context.Background(), base)
+
+			if tt.wantErr {
+				if !errors.Is(err, domainprovider.ErrUpstreamInvalidResponse) {
+					t.Errorf("FetchAllRates() error = %v, want ErrUpstreamInvalidResponse", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchAllRates() error = %v, want nil", err)
+			}
+			if len(rates) != tt.wantLen {
+				t.Errorf("len(rates) = %d, want %d", len(rates), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestCurrencyAPIProvider_FetchAllRates_RecordsUnparseable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": "abc", "jpy": null}}`))
+	}))
+	defer server.Close()
+
+	provider := NewCurrencyAPIProvider(NewHTTPClient(), server.URL, nil)
+	base, _ := entity.NewCurrencyCode("USD")
+
+	ctx, stats := domainprovider.WithFetchStats(context.Background())
+	rates, err := provider.FetchAllRates(ctx, base)
+	if err != nil {
+		t.Fatalf("FetchAllRates() error = %v, want nil", err)
+	}
+	if len(rates) != 1 {
+		t.Errorf("len(rates) = %d, want 1", len(rates))
+	}
+	if stats.Skipped() != 2 {
+		t.Errorf("Skipped() = %d, want 2", stats.Skipped())
+	}
+	if got := stats.SkipReasons()[skipReasonUnparseable]; got != 2 {
+		t.Errorf("SkipReasons()[%q] = %d, want 2", skipReasonUnparseable, got)
+	}
+}
+
 func TestCurrencyAPIProvider_FetchAllRates_APIError(t *testing.T) {
 	// Create mock server that returns API error
 	// Note: New API doesn't have an "error" field in the same way
@@ -372,3 +450,490 @@ func TestCurrencyAPIProvider_FetchAllRates_ContextCancellation(t *testing.T) {
 		t.Errorf("Error = %v, want context.Canceled", err)
 	}
 }
+
+func TestCurrencyAPIProvider_ResponseBodyLimit(t *testing.T) {
+	const limit = 1024
+
+	// Server streams a valid JSON prefix followed by far more data than the limit
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "padding": "`))
+		chunk := strings.Repeat("x", 512)
+		for i := 0; i < 64; i++ {
+			if _, err := w.Write([]byte(chunk)); err != nil {
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`"}}`))
+	}))
+	defer server.Close()
+
+	config := DefaultCurrencyAPIProviderConfig()
+	config.MaxResponseBytes = limit
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	if _, err := provider.FetchRate(context.Background(), base, target); !errors.Is(err, domainprovider.ErrUpstreamInvalidResponse) {
+		t.Errorf("FetchRate() error = %v, want ErrUpstreamInvalidResponse", err)
+	}
+	if _, err := provider.FetchAllRates(context.Background(), base); !errors.Is(err, domainprovider.ErrUpstreamInvalidResponse) {
+		t.Errorf("FetchAllRates() error = %v, want ErrUpstreamInvalidResponse", err)
+	}
+}
+
+func TestCurrencyAPIProvider_ErrorCategories(t *testing.T) {
+	invalidJSON := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("invalid json"))
+	}))
+	defer invalidJSON.Close()
+
+	// Promises more body than it sends, so reading the body fails
+	truncated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15"`))
+	}))
+	defer truncated.Close()
+
+	missingBase := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "eur": {"usd": 1.17}}`))
+	}))
+	defer missingBase.Close()
+
+	nonPositiveRate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0}}`))
+	}))
+	defer nonPositiveRate.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name          string
+		baseURL       string
+		wantErr       error
+		fetchRateOnly bool // FetchAllRates skips invalid rates instead of failing
+	}{
+		{"request build", "http://example.com/\x7f", domainprovider.ErrProviderRequestBuild, false},
+		{"transport", closed.URL, domainprovider.ErrProviderTransport, false},
+		{"read", truncated.URL, domainprovider.ErrProviderRead, false},
+		{"parse", invalidJSON.URL, domainprovider.ErrUpstreamInvalidResponse, false},
+		{"missing base", missingBase.URL, domainprovider.ErrUpstreamInvalidResponse, false},
+		{"non-positive rate", nonPositiveRate.URL, domainprovider.ErrUpstreamInvalidResponse, true},
+	}
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), tt.baseURL, tt.baseURL, nil)
+
+			if _, err := provider.FetchRate(context.Background(), base, target); !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchRate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.fetchRateOnly {
+				return
+			}
+			if _, err := provider.FetchAllRates(context.Background(), base); !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchAllRates() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewCurrencyAPIProviderWithConfig_DefaultMaxResponseBytes(t *testing.T) {
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), "", "", CurrencyAPIProviderConfig{}, nil)
+
+	if provider.config.MaxResponseBytes != DefaultMaxResponseBytes {
+		t.Errorf("MaxResponseBytes = %d, want %d", provider.config.MaxResponseBytes, DefaultMaxResponseBytes)
+	}
+}
+
+func TestCurrencyAPIProvider_UserAgent(t *testing.T) {
+	tests := []struct {
+		name         string
+		buildVersion string // Simulates -ldflags -X for pkg/version.Version
+		userAgent    string
+		want         string
+	}{
+		{"default", "dev", "", "go-currenseen/dev"},
+		{"default with injected version", "v1.2.0", "", "go-currenseen/v1.2.0"},
+		{"custom", "v1.2.0", "currenseen-test/1.2.3 (+https://example.com)", "currenseen-test/1.2.3 (+https://example.com)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := version.Version
+			version.Version = tt.buildVersion
+			defer func() { version.Version = original }()
+
+			var gotUserAgents []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserAgents = append(gotUserAgents, r.Header.Get("User-Agent"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`))
+			}))
+			defer server.Close()
+
+			config := DefaultCurrencyAPIProviderConfig()
+			config.UserAgent = tt.userAgent
+			provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+
+			base, _ := entity.NewCurrencyCode("USD")
+			target, _ := entity.NewCurrencyCode("EUR")
+
+			if _, err := provider.FetchRate(context.Background(), base, target); err != nil {
+				t.Fatalf("FetchRate() error = %v", err)
+			}
+			if _, err := provider.FetchAllRates(context.Background(), base); err != nil {
+				t.Fatalf("FetchAllRates() error = %v", err)
+			}
+
+			if len(gotUserAgents) != 2 {
+				t.Fatalf("received %d requests, want 2", len(gotUserAgents))
+			}
+			for _, got := range gotUserAgents {
+				if got != tt.want {
+					t.Errorf("User-Agent = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCurrencyAPIProvider_RecordsFetchSource(t *testing.T) {
+	body := []byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name        string
+		primaryURL  string
+		fallbackURL string
+		wantURL     string
+	}{
+		{"primary", healthy.URL, failing.URL, healthy.URL + "/currencies/usd.json"},
+		{"fallback", failing.URL, healthy.URL, healthy.URL + "/currencies/usd.json"},
+	}
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), tt.primaryURL, tt.fallbackURL, nil)
+
+			fetches := map[string]func(context.Context) error{
+				"FetchRate": func(ctx context.Context) error {
+					_, err := provider.FetchRate(ctx, base, target)
+					return err
+				},
+				"FetchAllRates": func(ctx context.Context) error {
+					_, err := provider.FetchAllRates(ctx, base)
+					return err
+				},
+			}
+			for method, fetch := range fetches {
+				ctx, source := domainprovider.WithFetchSource(context.Background())
+				if err := fetch(ctx); err != nil {
+					t.Fatalf("%s() error = %v", method, err)
+				}
+				if source.Provider() != string(ProviderTypeCurrencyAPI) {
+					t.Errorf("%s() source provider = %q, want %q", method, source.Provider(), ProviderTypeCurrencyAPI)
+				}
+				if source.URL() != tt.wantURL {
+					t.Errorf("%s() source URL = %q, want %q", method, source.URL(), tt.wantURL)
+				}
+			}
+		})
+	}
+
+	// Nothing is recorded when every endpoint fails
+	provider := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), failing.URL, failing.URL, nil)
+	ctx, source := domainprovider.WithFetchSource(context.Background())
+	if _, err := provider.FetchRate(ctx, base, target); err == nil {
+		t.Fatal("FetchRate() error = nil, want error")
+	}
+	if source.Provider() != "" || source.URL() != "" {
+		t.Errorf("source = %q %q, want empty after failed fetch", source.Provider(), source.URL())
+	}
+}
+
+func TestCurrencyAPIProvider_FetchRate_RecordsFetchedRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75, "jpy": 150}}`))
+	}))
+	defer server.Close()
+
+	provider := NewCurrencyAPIProvider(NewHTTPClient(), server.URL, nil)
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	ctx, fetched := domainprovider.WithFetchedRates(context.Background())
+	rate, err := provider.FetchRate(ctx, base, target)
+	if err != nil {
+		t.Fatalf("FetchRate() error = %v", err)
+	}
+
+	rates := fetched.Rates()
+	if len(rates) != 3 {
+		t.Fatalf("recorded %d rates, want 3", len(rates))
+	}
+	for _, r := range rates {
+		if !r.Base.Equal(base) {
+			t.Errorf("recorded rate base = %v, want %v", r.Base, base)
+		}
+		if !r.Timestamp.Equal(rate.Timestamp) {
+			t.Errorf("recorded %v timestamp = %v, want %v", r.Target, r.Timestamp, rate.Timestamp)
+		}
+	}
+
+	// Without a collector, FetchRate records nothing
+	if _, err := provider.FetchRate(context.Background(), base, target); err != nil {
+		t.Fatalf("FetchRate() error = %v", err)
+	}
+}
+
+func TestCurrencyAPIProvider_FetchRateAndAll(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75, "jpy": 150}}`))
+	}))
+	defer server.Close()
+
+	provider := NewCurrencyAPIProvider(NewHTTPClient(), server.URL, nil)
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("GBP")
+
+	rate, all, err := provider.FetchRateAndAll(context.Background(), base, target)
+	if err != nil {
+		t.Fatalf("FetchRateAndAll() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("HTTP requests = %d, want 1", requests)
+	}
+	if !rate.Target.Equal(target) || rate.Rate != 0.75 {
+		t.Errorf("rate = %v %f, want GBP 0.75", rate.Target, rate.Rate)
+	}
+	if len(all) != 3 {
+		t.Fatalf("full set has %d rates, want 3", len(all))
+	}
+	byTarget := make(map[string]float64, len(all))
+	for _, r := range all {
+		byTarget[r.Target.String()] = r.Rate
+	}
+	want := map[string]float64{"EUR": 0.85, "GBP": 0.75, "JPY": 150}
+	for code, value := range want {
+		if byTarget[code] != value {
+			t.Errorf("full set %s = %f, want %f", code, byTarget[code], value)
+		}
+	}
+
+	// Unsupported targets fail like FetchRate
+	missing, _ := entity.NewCurrencyCode("CHF")
+	if _, all, err := provider.FetchRateAndAll(context.Background(), base, missing); err == nil || all != nil {
+		t.Errorf("FetchRateAndAll(CHF) = %v, %v, want error and no rates", all, err)
+	}
+}
+
+func TestCurrencyAPIProvider_AllEndpointsFailedError(t *testing.T) {
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+
+	provider := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), unavailable.URL, notFound.URL, nil)
+
+	base, _ := entity.NewCurrencyCode("GBP")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	fetches := map[string]func() error{
+		"FetchRate": func() error {
+			_, err := provider.FetchRate(context.Background(), base, target)
+			return err
+		},
+		"FetchAllRates": func() error {
+			_, err := provider.FetchAllRates(context.Background(), base)
+			return err
+		},
+	}
+	for method, fetch := range fetches {
+		err := fetch()
+
+		var failed *AllEndpointsFailedError
+		if !errors.As(err, &failed) {
+			t.Fatalf("%s() error = %v, want *AllEndpointsFailedError", method, err)
+		}
+		if len(failed.Failures) != 2 {
+			t.Fatalf("%s() failures = %d, want 2", method, len(failed.Failures))
+		}
+
+		wants := []struct {
+			url    string
+			status int
+		}{
+			{unavailable.URL + "/currencies/gbp.json", http.StatusServiceUnavailable},
+			{notFound.URL + "/currencies/gbp.json", http.StatusNotFound},
+		}
+		for i, want := range wants {
+			got := failed.Failures[i]
+			if got.URL != want.url {
+				t.Errorf("%s() failure[%d] URL = %q, want %q", method, i, got.URL, want.url)
+			}
+			var statusErr *StatusError
+			if !errors.As(got.Err, &statusErr) || statusErr.StatusCode != want.status {
+				t.Errorf("%s() failure[%d] error = %v, want status %d", method, i, got.Err, want.status)
+			}
+		}
+
+		// Sentinels of the last error still match
+		if !errors.Is(err, domainprovider.ErrCurrencyUnsupported) {
+			t.Errorf("%s() errors.Is(err, ErrCurrencyUnsupported) = false, want true", method)
+		}
+		if !strings.HasPrefix(err.Error(), "all API endpoints failed, last error: ") {
+			t.Errorf("%s() error message = %q", method, err.Error())
+		}
+	}
+}
+
+func TestNewCurrencyAPIProviderWithURLs_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    []string
+		wantErr bool
+	}{
+		{"single URL", []string{"https://a.example.com/v1"}, false},
+		{"trailing slash", []string{"https://a.example.com/v1/", "http://b.example.com"}, false},
+		{"nil list", nil, true},
+		{"empty list", []string{}, true},
+		{"relative URL", []string{"a.example.com/v1"}, true},
+		{"unsupported scheme", []string{"https://a.example.com/v1", "ftp://b.example.com/v1"}, true},
+		{"empty URL", []string{""}, true},
+		{"malformed URL", []string{"https://a.example.com/%zz"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewCurrencyAPIProviderWithURLs(NewHTTPClient(), tt.urls, DefaultCurrencyAPIProviderConfig(), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCurrencyAPIProviderWithURLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(provider.urls) != len(tt.urls) {
+				t.Errorf("urls = %v, want %d entries", provider.urls, len(tt.urls))
+			}
+		})
+	}
+}
+
+func TestCurrencyAPIProvider_CustomURLOrder(t *testing.T) {
+	body := []byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`)
+
+	var mu sync.Mutex
+	var hits []string
+	newServer := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits = append(hits, name)
+			mu.Unlock()
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			_, _ = w.Write(body)
+		}))
+	}
+	first := newServer("first", http.StatusServiceUnavailable)
+	defer first.Close()
+	second := newServer("second", http.StatusOK)
+	defer second.Close()
+	third := newServer("third", http.StatusOK)
+	defer third.Close()
+
+	provider, err := NewCurrencyAPIProviderWithURLs(NewHTTPClient(), []string{first.URL, second.URL, third.URL}, DefaultCurrencyAPIProviderConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewCurrencyAPIProviderWithURLs() error = %v", err)
+	}
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	ctx, source := domainprovider.WithFetchSource(context.Background())
+	if _, err := provider.FetchRate(ctx, base, target); err != nil {
+		t.Fatalf("FetchRate() error = %v", err)
+	}
+
+	if len(hits) != 2 || hits[0] != "first" || hits[1] != "second" {
+		t.Errorf("endpoints tried = %v, want [first second]", hits)
+	}
+	if want := second.URL + "/currencies/usd.json"; source.URL() != want {
+		t.Errorf("source URL = %q, want %q", source.URL(), want)
+	}
+}
+
+func TestCurrencyAPIProvider_MaxFallbackAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		allRates    bool
+		wantHits    int
+	}{
+		{"unlimited tries every URL", 0, false, 5},
+		{"limit bounds single-pair fetches", 2, false, 2},
+		{"limit bounds all-rates fetches", 3, true, 3},
+		{"limit above URL count tries every URL", 10, false, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			urls := make([]string, 5)
+			for i := range urls {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					hits.Add(1)
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}))
+				defer server.Close()
+				urls[i] = server.URL
+			}
+
+			config := DefaultCurrencyAPIProviderConfig()
+			config.MaxFallbackAttempts = tt.maxAttempts
+			provider, err := NewCurrencyAPIProviderWithURLs(NewHTTPClient(), urls, config, nil)
+			if err != nil {
+				t.Fatalf("NewCurrencyAPIProviderWithURLs() error = %v", err)
+			}
+
+			if tt.allRates {
+				_, err = provider.FetchAllRates(context.Background(), "USD")
+			} else {
+				_, err = provider.FetchRate(context.Background(), "USD", "EUR")
+			}
+			var failed *AllEndpointsFailedError
+			if !errors.As(err, &failed) {
+				t.Fatalf("fetch error = %v, want *AllEndpointsFailedError", err)
+			}
+			if len(failed.Failures) != tt.wantHits {
+				t.Errorf("endpoint failures = %d, want %d", len(failed.Failures), tt.wantHits)
+			}
+			if got := int(hits.Load()); got != tt.wantHits {
+				t.Errorf("endpoints tried = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
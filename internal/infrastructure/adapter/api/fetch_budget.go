@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// withFetchBudget bounds a whole fetch, across every endpoint attempt, by FetchBudget.
+// Without a FetchBudget, ctx is returned unchanged.
+func (p *CurrencyAPIProvider) withFetchBudget(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.config.FetchBudget <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.config.FetchBudget)
+}
+
+// attemptContext bounds the attempt on one endpoint to its share of the fetch budget.
+//
+// The time left until the deadline of ctx is split evenly across the endpoints
+// not yet tried (remaining, including this one), so a slow primary cannot use up
+// the time of its fallbacks. Time an attempt leaves unused carries over to later
+// endpoints. Without a FetchBudget, ctx is returned unchanged.
+func (p *CurrencyAPIProvider) attemptContext(ctx context.Context, remaining int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if p.config.FetchBudget <= 0 || !ok || remaining <= 1 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remaining))
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// newSlowServer returns a server that answers after delay, or when the request is cancelled.
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCurrencyAPIProvider_FetchBudget(t *testing.T) {
+	const budget = 300 * time.Millisecond
+	// Scheduling slack on top of the budget
+	const slack = 200 * time.Millisecond
+
+	slow := newSlowServer(t, 5*time.Second)
+	fast := newSlowServer(t, 0)
+
+	tests := []struct {
+		name        string
+		primaryURL  string
+		fallbackURL string
+		wantErr     bool
+	}{
+		{"all endpoints slow", slow.URL, slow.URL, true},
+		{"slow primary leaves time for the fallback", slow.URL, fast.URL, false},
+	}
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultCurrencyAPIProviderConfig()
+			config.FetchBudget = budget
+			provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), tt.primaryURL, tt.fallbackURL, config, nil)
+
+			fetches := map[string]func(context.Context) error{
+				"FetchRate": func(ctx context.Context) error {
+					_, err := provider.FetchRate(ctx, base, target)
+					return err
+				},
+				"FetchAllRates": func(ctx context.Context) error {
+					_, err := provider.FetchAllRates(ctx, base)
+					return err
+				},
+			}
+			for method, fetch := range fetches {
+				started := time.Now()
+				err := fetch(context.Background())
+				elapsed := time.Since(started)
+
+				if (err != nil) != tt.wantErr {
+					t.Errorf("%s() error = %v, wantErr %v", method, err, tt.wantErr)
+				}
+				if elapsed > budget+slack {
+					t.Errorf("%s() took %v, want within the %v budget", method, elapsed, budget)
+				}
+			}
+		})
+	}
+}
+
+func TestCurrencyAPIProvider_AttemptContext(t *testing.T) {
+	config := DefaultCurrencyAPIProviderConfig()
+	config.FetchBudget = time.Second
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), "https://primary.example", "https://fallback.example", config, nil)
+
+	ctx, cancel := provider.withFetchBudget(context.Background())
+	defer cancel()
+
+	attemptCtx, cancelAttempt := provider.attemptContext(ctx, 2)
+	defer cancelAttempt()
+	deadline, ok := attemptCtx.Deadline()
+	if !ok {
+		t.Fatal("attempt context has no deadline")
+	}
+	if share := time.Until(deadline); share > 500*time.Millisecond || share < 400*time.Millisecond {
+		t.Errorf("attempt share = %v, want about half of the budget", share)
+	}
+
+	// The last endpoint gets all the remaining time
+	lastCtx, cancelLast := provider.attemptContext(ctx, 1)
+	defer cancelLast()
+	if lastCtx != ctx {
+		t.Error("last attempt context differs from the fetch context")
+	}
+
+	// Without a budget, attempts are not bounded
+	unbounded := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), "https://primary.example", "", DefaultCurrencyAPIProviderConfig(), nil)
+	if ctx, _ := unbounded.withFetchBudget(context.Background()); ctx != context.Background() {
+		t.Error("withFetchBudget() changed the context without a budget")
+	}
+}
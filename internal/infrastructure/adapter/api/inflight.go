@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// inflightCall is a download in progress. done is closed once body, age, and err are set.
+type inflightCall struct {
+	done chan struct{}
+	body []byte
+	age  time.Duration // Upstream age of the response (see ResponseAge)
+	err  error
+}
+
+// inflightGroup shares concurrent downloads of the same URL.
+//
+// It complements responseCache: the cache reuses a finished download, while
+// the group lets callers that ask for a URL while it is still downloading wait
+// for that download instead of starting their own. Single-rate and all-rates
+// fetches of a base share one URL, so both paths benefit.
+//
+// Waiting callers receive the downloading caller's result, including its error
+// (e.g. its attempt deadline). A waiting caller whose own context ends stops
+// waiting and returns the context's error.
+//
+// A nil *inflightGroup shares nothing.
+//
+// inflightGroup is safe for concurrent use.
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// newInflightGroup creates an in-flight download group, or returns nil if disabled.
+func newInflightGroup(enabled bool) *inflightGroup {
+	if !enabled {
+		return nil
+	}
+	return &inflightGroup{calls: make(map[string]*inflightCall)}
+}
+
+// do runs download for url unless a download of url is already in progress,
+// in which case it waits for that download's result (shared=true).
+func (g *inflightGroup) do(ctx context.Context, url string, download func() ([]byte, time.Duration, error)) (body []byte, age time.Duration, shared bool, err error) {
+	if g == nil {
+		body, age, err = download()
+		return body, age, false, err
+	}
+
+	g.mu.Lock()
+	if call, ok := g.calls[url]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.body, call.age, true, call.err
+		case <-ctx.Done():
+			return nil, 0, true, ctx.Err()
+		}
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	g.calls[url] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, url)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.body, call.age, call.err = download()
+	return call.body, call.age, false, call.err
+}
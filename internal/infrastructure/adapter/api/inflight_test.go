@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestCurrencyAPIProvider_InflightDedup(t *testing.T) {
+	const callers = 20
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		wantRequests int32
+	}{
+		{"shares one download", true, 1},
+		{"disabled downloads per call", false, callers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				<-release
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`))
+			}))
+			defer server.Close()
+
+			config := DefaultCurrencyAPIProviderConfig()
+			config.InflightDedup = tt.enabled
+			provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, "", config, nil)
+			base, _ := entity.NewCurrencyCode("USD")
+			target, _ := entity.NewCurrencyCode("EUR")
+
+			// Single-rate and all-rates fetches of one base hit the same URL
+			errs := make(chan error, callers)
+			var wg sync.WaitGroup
+			for i := 0; i < callers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					if i%2 == 0 {
+						_, err := provider.FetchRate(context.Background(), base, target)
+						errs <- err
+						return
+					}
+					rates, err := provider.FetchAllRates(context.Background(), base)
+					if err == nil && len(rates) != 2 {
+						err = errors.New("want 2 rates")
+					}
+					errs <- err
+				}(i)
+			}
+
+			// Let every caller start (and, if enabled, join the download) before it completes
+			time.Sleep(100 * time.Millisecond)
+			close(release)
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil {
+					t.Errorf("fetch error = %v", err)
+				}
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("HTTP requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestInflightGroup_WaiterContextCancellation(t *testing.T) {
+	g := newInflightGroup(true)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _, _, _ = g.do(context.Background(), "url", func() ([]byte, time.Duration, error) {
+			close(started)
+			<-release
+			return []byte("body"), 0, nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, shared, err := g.do(ctx, "url", func() ([]byte, time.Duration, error) {
+		t.Error("download ran while another was in progress")
+		return nil, 0, nil
+	})
+	if !shared || !errors.Is(err, context.Canceled) {
+		t.Errorf("do() shared = %v, error = %v, want shared with context.Canceled", shared, err)
+	}
+}
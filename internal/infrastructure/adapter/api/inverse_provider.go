@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/internal/domain/service"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// InverseProvider derives a pair from its inverse when the upstream feed only
+// publishes one direction (e.g. EUR/USD but never USD/EUR).
+//
+// This wrapper:
+// - Fetches the requested pair from the underlying provider
+// - If the provider has no direct rate (provider.ErrCurrencyUnsupported),
+// fetches the inverse pair and returns 1/rate, marked Derived
+// - Returns the original error if the inverse fetch fails too
+// - Passes FetchAllRates through unchanged
+//
+// This is provider-level inversion: the derived rate is cached like any
+// fetched rate. Derived rates carry no bid/ask spread.
+type InverseProvider struct {
+	provider   provider.ExchangeRateProvider
+	calculator *service.RateCalculator
+	logger     *logger.Logger
+}
+
+// NewInverseProvider creates a new InverseProvider wrapping provider.
+// If log is nil, a logger is created from the environment.
+func NewInverseProvider(provider provider.ExchangeRateProvider, log *logger.Logger) *InverseProvider {
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	return &InverseProvider{
+		provider:   provider,
+		calculator: service.NewRateCalculator(),
+		logger:     log,
+	}
+}
+
+// FetchRate implements provider.ExchangeRateProvider.
+//
+// Context cancellation: Returns error if ctx is cancelled during either fetch.
+func (p *InverseProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	rate, err := p.provider.FetchRate(ctx, base, target)
+	if err == nil || !errors.Is(err, provider.ErrCurrencyUnsupported) {
+		return rate, err
+	}
+
+	log := p.logger.WithContext(ctx)
+	log.Debug("no direct rate from provider, trying inverse pair",
+		"base", base.String(),
+		"target", target.String(),
+		"error", err.Error(),
+	)
+	inverse, inverseErr := p.provider.FetchRate(ctx, target, base)
+	if inverseErr != nil {
+		log.Debug("inverse pair unavailable", "error", inverseErr.Error())
+		return nil, err
+	}
+	derived, inverseErr := p.calculator.InverseRate(inverse)
+	if inverseErr != nil {
+		log.Warn("failed to invert rate", "error", inverseErr.Error())
+		return nil, err
+	}
+	derived.Derived = true
+
+	log.Info("derived rate from inverse pair",
+		"base", base.String(),
+		"target", target.String(),
+		"inverse_rate", inverse.Rate,
+		"rate", derived.Rate,
+	)
+	return derived, nil
+}
+
+// FetchAllRates implements provider.ExchangeRateProvider.
+func (p *InverseProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+	return p.provider.FetchAllRates(ctx, base)
+}
+
+// Ensure InverseProvider implements ExchangeRateProvider interface.
+var _ provider.ExchangeRateProvider = (*InverseProvider)(nil)
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	domainprovider "github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+// newInverseOnlyServer serves only EUR-based rates, so GBP/EUR is only
+// available as the inverse of EUR/GBP.
+func newInverseOnlyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/currencies/eur.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"date": "2024-01-15",
+			"eur":  map[string]float64{"usd": 1.25, "gbp": 0.8},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInverseProvider_FetchRate(t *testing.T) {
+	server := newInverseOnlyServer(t)
+	inner := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), server.URL, server.URL, nil)
+	p := NewInverseProvider(inner, nil)
+
+	tests := []struct {
+		name        string
+		base        entity.CurrencyCode
+		target      entity.CurrencyCode
+		wantRate    float64
+		wantDerived bool
+		wantErr     error
+	}{
+		{"direct rate", "EUR", "GBP", 0.8, false, nil},
+		{"inverse of a published pair", "GBP", "EUR", 1.25, true, nil},
+		{"target missing in both directions", "EUR", "JPY", 0, false, domainprovider.ErrCurrencyUnsupported},
+		{"base missing in both directions", "GBP", "JPY", 0, false, domainprovider.ErrCurrencyUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := p.FetchRate(context.Background(), tt.base, tt.target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FetchRate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchRate() error = %v", err)
+			}
+			if rate.Base != tt.base || rate.Target != tt.target {
+				t.Errorf("pair = %s/%s, want %s/%s", rate.Base, rate.Target, tt.base, tt.target)
+			}
+			if math.Abs(rate.Rate-tt.wantRate) > 1e-12 {
+				t.Errorf("Rate = %v, want %v", rate.Rate, tt.wantRate)
+			}
+			if rate.Derived != tt.wantDerived {
+				t.Errorf("Derived = %v, want %v", rate.Derived, tt.wantDerived)
+			}
+		})
+	}
+}
+
+func TestInverseProvider_OnlyInvertsUnsupportedPairs(t *testing.T) {
+	upstreamErr := errors.New("upstream timeout")
+	inner := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return nil, upstreamErr
+		},
+	}
+	p := NewInverseProvider(inner, nil)
+
+	if _, err := p.FetchRate(context.Background(), "USD", "EUR"); !errors.Is(err, upstreamErr) {
+		t.Errorf("FetchRate() error = %v, want %v", err, upstreamErr)
+	}
+	if inner.callCount != 1 {
+		t.Errorf("provider calls = %d, want 1 (no inverse fetch for other errors)", inner.callCount)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+// providerDateLayout is the layout of the date field in provider responses.
+const providerDateLayout = "2006-01-02"
+
+// endpointResponse is a provider response downloaded and decoded from one endpoint.
+type endpointResponse struct {
+	root    string
+	url     string
+	started time.Time
+	body    []byte
+	cached  bool
+	age     *ResponseAge
+	resp    currencyAPIResponse
+}
+
+// isStaleDate reports whether the response date is older than MaxDateAge,
+// logging a warning if so.
+//
+// A CDN edge can keep serving an old file with 200 OK; the date in the body is
+// the only sign. Responses without a parseable date are not considered stale.
+func (p *CurrencyAPIProvider) isStaleDate(ctx context.Context, url string, resp *currencyAPIResponse) bool {
+	if p.config.MaxDateAge <= 0 || resp.Date == "" {
+		return false
+	}
+	date, err := time.Parse(providerDateLayout, resp.Date)
+	if err != nil {
+		return false
+	}
+	age := time.Since(date)
+	if age <= p.config.MaxDateAge {
+		return false
+	}
+	p.logger.WithContext(ctx).Warn("provider response date is older than expected",
+		"url", url,
+		"date", resp.Date,
+		"date_age", age.Round(time.Minute).String(),
+		"max_date_age", p.config.MaxDateAge.String(),
+	)
+	return true
+}
+
+// fresherResponse tries the remaining endpoints (roots) in order after one
+// served a stale date, and returns the first response with a recent date.
+//
+// Failed endpoints are added to failures. Returns nil if no remaining endpoint
+// served a recent date; the caller then uses the stale response.
+func (p *CurrencyAPIProvider) fresherResponse(ctx context.Context, roots []string, path string, failures *AllEndpointsFailedError) *endpointResponse {
+	for i, root := range roots {
+		if ctx.Err() != nil {
+			return nil
+		}
+		url := root + path
+		started := p.selector.start()
+
+		attemptCtx, cancelAttempt := p.attemptContext(ctx, len(roots)-i)
+		reqCtx, age := WithResponseAge(attemptCtx)
+		body, cached, err := p.fetchBody(reqCtx, url)
+		cancelAttempt()
+		if err != nil {
+			p.endpointFailed(failures, root, url, started, err)
+			continue
+		}
+
+		var resp currencyAPIResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			p.endpointFailed(failures, root, url, started, fmt.Errorf("%w: failed to parse response: %w", provider.ErrUpstreamInvalidResponse, err))
+			continue
+		}
+		if p.isStaleDate(ctx, url, &resp) {
+			if !cached {
+				p.selector.record(root, started, true)
+			}
+			continue
+		}
+
+		p.logger.WithContext(ctx).Info("fallback endpoint served a more recent date",
+			"url", url,
+			"date", resp.Date,
+		)
+		return &endpointResponse{root: root, url: url, started: started, body: body, cached: cached, age: age, resp: resp}
+	}
+	return nil
+}
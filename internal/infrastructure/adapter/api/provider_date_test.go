@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// newDatedServer returns a server serving a USD file with the given date and EUR rate,
+// counting its requests.
+func newDatedServer(t *testing.T, date string, eur float64, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"date": %q, "usd": {"eur": %v}}`, date, eur)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCurrencyAPIProvider_StaleDate(t *testing.T) {
+	staleDate := time.Now().AddDate(0, 0, -5).Format(providerDateLayout)
+	freshDate := time.Now().Format(providerDateLayout)
+
+	tests := []struct {
+		name              string
+		primaryDate       string
+		fallbackDate      string
+		staleDateFallback bool
+		wantWarning       bool
+		wantFallbackCalls int32
+		wantRate          float64
+	}{
+		{"recent date", freshDate, freshDate, true, false, 0, 0.85},
+		{"old date warns", staleDate, freshDate, false, true, 0, 0.85},
+		{"old date tries fallback", staleDate, freshDate, true, true, 1, 0.9},
+		{"stale fallback keeps primary response", staleDate, staleDate, true, true, 1, 0.85},
+	}
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var primaryCalls, fallbackCalls atomic.Int32
+			primary := newDatedServer(t, tt.primaryDate, 0.85, &primaryCalls)
+			fallback := newDatedServer(t, tt.fallbackDate, 0.9, &fallbackCalls)
+
+			var buf bytes.Buffer
+			log := &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+			config := DefaultCurrencyAPIProviderConfig()
+			config.MaxDateAge = 48 * time.Hour
+			config.StaleDateFallback = tt.staleDateFallback
+			provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), primary.URL, fallback.URL, config, log)
+
+			rate, err := provider.FetchRate(context.Background(), base, target)
+			if err != nil {
+				t.Fatalf("FetchRate() error = %v", err)
+			}
+			if rate.Rate != tt.wantRate {
+				t.Errorf("Rate = %v, want %v", rate.Rate, tt.wantRate)
+			}
+			if got := strings.Contains(buf.String(), "provider response date is older than expected"); got != tt.wantWarning {
+				t.Errorf("stale date warning logged = %v, want %v", got, tt.wantWarning)
+			}
+			if got := fallbackCalls.Load(); got != tt.wantFallbackCalls {
+				t.Errorf("fallback requests = %d, want %d", got, tt.wantFallbackCalls)
+			}
+
+			rates, err := provider.FetchAllRates(context.Background(), base)
+			if err != nil {
+				t.Fatalf("FetchAllRates() error = %v", err)
+			}
+			if len(rates) != 1 || rates[0].Rate != tt.wantRate {
+				t.Errorf("FetchAllRates() = %v, want EUR %v", rates, tt.wantRate)
+			}
+		})
+	}
+}
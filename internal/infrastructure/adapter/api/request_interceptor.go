@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// RequestIDHeader is the header used to propagate the request ID to providers.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestInterceptor modifies an outbound provider request before it is sent,
+// e.g. to add authentication, signing, or tracing headers.
+//
+// Returning an error aborts the request: the provider returns an *InterceptorError
+// without contacting any endpoint.
+type RequestInterceptor func(*http.Request) error
+
+// InterceptorError is returned when a RequestInterceptor rejects a request.
+type InterceptorError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *InterceptorError) Error() string {
+	return fmt.Sprintf("request interceptor failed: %v", e.Err)
+}
+
+// Unwrap returns the interceptor's error.
+func (e *InterceptorError) Unwrap() error {
+	return e.Err
+}
+
+// APIKeyInterceptor sets header to key on every request.
+// Requests are left unchanged if key is empty.
+func APIKeyInterceptor(header, key string) RequestInterceptor {
+	return func(req *http.Request) error {
+		if key != "" {
+			req.Header.Set(header, key)
+		}
+		return nil
+	}
+}
+
+// UserAgentInterceptor sets the User-Agent header, overriding CurrencyAPIProviderConfig.UserAgent.
+func UserAgentInterceptor(userAgent string) RequestInterceptor {
+	return func(req *http.Request) error {
+		req.Header.Set("User-Agent", userAgent)
+		return nil
+	}
+}
+
+// TracePropagationInterceptor forwards the request ID from the request context
+// (see logger.WithRequestID) in the X-Request-ID header, so provider calls can be
+// correlated with the API request that triggered them.
+func TracePropagationInterceptor() RequestInterceptor {
+	return func(req *http.Request) error {
+		if requestID := logger.GetRequestID(req.Context()); requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		return nil
+	}
+}
+
+// applyInterceptors runs interceptors in order, stopping at the first error.
+func applyInterceptors(req *http.Request, interceptors []RequestInterceptor) error {
+	for _, intercept := range interceptors {
+		if err := intercept(req); err != nil {
+			return &InterceptorError{Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+func TestCurrencyAPIProvider_InterceptorAddsHeader(t *testing.T) {
+	var gotKey, gotUserAgent, gotRequestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-Api-Key")
+		gotUserAgent = r.Header.Get("User-Agent")
+		gotRequestID = r.Header.Get(RequestIDHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`))
+	}))
+	defer server.Close()
+
+	config := DefaultCurrencyAPIProviderConfig()
+	config.Interceptors = []RequestInterceptor{
+		APIKeyInterceptor("X-Api-Key", "secret"),
+		UserAgentInterceptor("custom-agent/1.0"),
+		TracePropagationInterceptor(),
+	}
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+
+	ctx := logger.WithRequestID(context.Background(), "req-123")
+	if _, err := provider.FetchRate(ctx, entity.CurrencyCode("USD"), entity.CurrencyCode("EUR")); err != nil {
+		t.Fatalf("FetchRate() error = %v", err)
+	}
+
+	if gotKey != "secret" {
+		t.Errorf("X-Api-Key = %q, want %q", gotKey, "secret")
+	}
+	if gotUserAgent != "custom-agent/1.0" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "custom-agent/1.0")
+	}
+	if gotRequestID != "req-123" {
+		t.Errorf("%s = %q, want %q", RequestIDHeader, gotRequestID, "req-123")
+	}
+}
+
+func TestCurrencyAPIProvider_InterceptorErrorAbortsRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`))
+	}))
+	defer server.Close()
+
+	errSigning := errors.New("signing key unavailable")
+	var laterCalled bool
+	config := DefaultCurrencyAPIProviderConfig()
+	config.Interceptors = []RequestInterceptor{
+		func(*http.Request) error { return errSigning },
+		func(*http.Request) error { laterCalled = true; return nil },
+	}
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+	base := entity.CurrencyCode("USD")
+
+	_, err := provider.FetchRate(context.Background(), base, entity.CurrencyCode("EUR"))
+	if !errors.Is(err, errSigning) {
+		t.Errorf("FetchRate() error = %v, want %v", err, errSigning)
+	}
+	_, err = provider.FetchAllRates(context.Background(), base)
+	var interceptErr *InterceptorError
+	if !errors.As(err, &interceptErr) {
+		t.Errorf("FetchAllRates() error = %v, want *InterceptorError", err)
+	}
+
+	if got := requests.Load(); got != 0 {
+		t.Errorf("server received %d requests, want 0", got)
+	}
+	if laterCalled {
+		t.Error("interceptor after the failing one was called")
+	}
+}
+
+func TestAPIKeyInterceptor_EmptyKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+
+	if err := APIKeyInterceptor("X-Api-Key", "")(req); err != nil {
+		t.Fatalf("interceptor error = %v", err)
+	}
+	if _, ok := req.Header["X-Api-Key"]; ok {
+		t.Error("X-Api-Key set for empty key")
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHTTPCacheMaxEntries is the default size limit of the provider HTTP response cache.
+const DefaultHTTPCacheMaxEntries = 64
+
+// cachedResponse is a response body with its expiry time.
+//
+// fetchedAt and age record when the body was downloaded and how old it already
+// was then (e.g. the CDN Age header), so a reused body reports its real age.
+type cachedResponse struct {
+	body      []byte
+	fetchedAt time.Time
+	age       time.Duration
+	expiresAt time.Time
+}
+
+// responseCache is a short-lived in-memory cache of provider response bodies keyed by URL.
+//
+// It complements the DynamoDB cache: within a warm Lambda burst, lookups that
+// share a base currency file reuse one download instead of fetching it again.
+//
+// The cache holds at most maxEntries bodies. When full, expired entries are
+// removed first, then the entry closest to expiry.
+//
+// A nil *responseCache caches nothing.
+//
+// responseCache is safe for concurrent use.
+type responseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]cachedResponse
+	now        func() time.Time
+}
+
+// newResponseCache creates a response cache, or returns nil if ttl is not positive.
+// A non-positive maxEntries uses DefaultHTTPCacheMaxEntries.
+func newResponseCache(ttl time.Duration, maxEntries int, now func() time.Time) *responseCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultHTTPCacheMaxEntries
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &responseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cachedResponse),
+		now:        now,
+	}
+}
+
+// get returns the cached body for url, if present and not expired, and its
+// current age: the age recorded by put plus the time since it was cached.
+func (c *responseCache) get(url string) ([]byte, time.Duration, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	if !ok {
+		return nil, 0, false
+	}
+	now := c.now()
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, url)
+		return nil, 0, false
+	}
+	return entry.body, entry.age + now.Sub(entry.fetchedAt), true
+}
+
+// put caches body for url for the configured TTL.
+// age is how old the body already was when downloaded (e.g. the CDN Age header).
+func (c *responseCache) put(url string, body []byte, age time.Duration) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[url]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[url] = cachedResponse{body: body, fetchedAt: now, age: age, expiresAt: now.Add(c.ttl)}
+}
+
+// evict removes expired entries, or the entry closest to expiry if none have expired.
+// Must be called with lock held.
+func (c *responseCache) evict(now time.Time) {
+	oldestURL := ""
+	var oldest time.Time
+	for url, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, url)
+			continue
+		}
+		if oldestURL == "" || entry.expiresAt.Before(oldest) {
+			oldestURL, oldest = url, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestURL != "" {
+		delete(c.entries, oldestURL)
+	}
+}
+
+// len returns the number of cached entries, including expired ones not yet removed.
+func (c *responseCache) len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestNewResponseCache_Disabled(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if cache := newResponseCache(ttl, 10, nil); cache != nil {
+			t.Errorf("newResponseCache(%v) = %v, want nil", ttl, cache)
+		}
+	}
+
+	var cache *responseCache
+	cache.put("url", []byte("body"), 0)
+	if _, _, ok := cache.get("url"); ok {
+		t.Error("nil cache get() ok = true, want false")
+	}
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache := newResponseCache(10*time.Second, 10, clock.Now)
+
+	cache.put("a", []byte("body"), 0)
+
+	clock.Advance(9 * time.Second)
+	if body, _, ok := cache.get("a"); !ok || string(body) != "body" {
+		t.Fatalf("get() = %q, %v, want cached body", body, ok)
+	}
+
+	clock.Advance(time.Second)
+	if _, _, ok := cache.get("a"); ok {
+		t.Error("get() ok = true after TTL, want false")
+	}
+	if cache.len() != 0 {
+		t.Errorf("len() = %d, want 0 after expired get", cache.len())
+	}
+}
+
+func TestResponseCache_Age(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache := newResponseCache(time.Minute, 10, clock.Now)
+
+	cache.put("a", []byte("body"), 30*time.Second)
+	clock.Advance(5 * time.Second)
+
+	if _, age, ok := cache.get("a"); !ok || age != 35*time.Second {
+		t.Errorf("get() age = %v, %v, want 35s (recorded age plus time cached)", age, ok)
+	}
+}
+
+func TestResponseCache_BoundedSize(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache := newResponseCache(time.Minute, 2, clock.Now)
+
+	cache.put("a", []byte("a"), 0)
+	clock.Advance(time.Second)
+	cache.put("b", []byte("b"), 0)
+	clock.Advance(time.Second)
+	cache.put("c", []byte("c"), 0) // Evicts "a", closest to expiry
+
+	if cache.len() != 2 {
+		t.Errorf("len() = %d, want 2", cache.len())
+	}
+	if _, _, ok := cache.get("a"); ok {
+		t.Error("oldest entry a was not evicted")
+	}
+	for _, url := range []string{"b", "c"} {
+		if _, _, ok := cache.get(url); !ok {
+			t.Errorf("entry %s missing", url)
+		}
+	}
+
+	// Replacing an existing entry does not evict others
+	cache.put("c", []byte("c2"), 0)
+	if cache.len() != 2 {
+		t.Errorf("len() after replace = %d, want 2", cache.len())
+	}
+}
+
+func TestCurrencyAPIProvider_HTTPCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`))
+	}))
+	defer server.Close()
+
+	config := DefaultCurrencyAPIProviderConfig()
+	config.HTTPCacheTTL = 10 * time.Second
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	provider.cache = newResponseCache(config.HTTPCacheTTL, config.HTTPCacheMaxEntries, clock.Now)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	gbp, _ := entity.NewCurrencyCode("GBP")
+	ctx := context.Background()
+
+	if _, err := provider.FetchRate(ctx, base, eur); err != nil {
+		t.Fatalf("FetchRate(EUR) error = %v", err)
+	}
+	// Same base file within the TTL: served from memory
+	if _, err := provider.FetchRate(ctx, base, gbp); err != nil {
+		t.Fatalf("FetchRate(GBP) error = %v", err)
+	}
+	if _, err := provider.FetchAllRates(ctx, base); err != nil {
+		t.Fatalf("FetchAllRates() error = %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server requests within TTL = %d, want 1", got)
+	}
+
+	// After the TTL the file is downloaded again
+	clock.Advance(11 * time.Second)
+	if _, err := provider.FetchRate(ctx, base, eur); err != nil {
+		t.Fatalf("FetchRate() after TTL error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server requests after TTL = %d, want 2", got)
+	}
+}
+
+func TestCurrencyAPIProvider_HTTPCacheKeepsResponseAge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Age", "300")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`))
+	}))
+	defer server.Close()
+
+	config := DefaultCurrencyAPIProviderConfig()
+	config.HTTPCacheTTL = time.Minute
+	config.ResponseInterceptors = []ResponseInterceptor{CDNAgeInterceptor()}
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	provider.cache = newResponseCache(config.HTTPCacheTTL, config.HTTPCacheMaxEntries, clock.Now)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	eur, _ := entity.NewCurrencyCode("EUR")
+	gbp, _ := entity.NewCurrencyCode("GBP")
+
+	if _, err := provider.FetchRate(context.Background(), base, eur); err != nil {
+		t.Fatalf("FetchRate(EUR) error = %v", err)
+	}
+	clock.Advance(20 * time.Second)
+
+	// Served from memory: 300s CDN age plus 20s in the response cache
+	rate, err := provider.FetchRate(context.Background(), base, gbp)
+	if err != nil {
+		t.Fatalf("FetchRate(GBP) error = %v", err)
+	}
+	if age := rate.Age(); age < 320*time.Second || age > 325*time.Second {
+		t.Errorf("cached rate age = %v, want about 320s", age)
+	}
+}
+
+func TestCurrencyAPIProvider_HTTPCacheDisabledByDefault(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`))
+	}))
+	defer server.Close()
+
+	provider := NewCurrencyAPIProviderWithFallback(NewHTTPClient(), server.URL, server.URL, nil)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+	for i := 0; i < 2; i++ {
+		if _, err := provider.FetchRate(context.Background(), base, target); err != nil {
+			t.Fatalf("FetchRate() error = %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server requests = %d, want 2", got)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseInterceptor inspects a provider response before its body is read,
+// e.g. to capture CDN headers or enforce policies on the upstream.
+//
+// Returning an error rejects the response: it counts as a failure of that
+// endpoint, and the provider moves on to the next URL.
+type ResponseInterceptor func(*http.Response) error
+
+// responseAgeKey is the context key for ResponseAge.
+type responseAgeKey struct{}
+
+// ResponseAge records how long a response had been cached upstream (e.g. by a CDN)
+// before it was received.
+//
+// The provider attaches a ResponseAge to each outbound request's context;
+// response interceptors find it with ResponseAgeFromContext(resp.Request.Context()).
+// A recorded age is subtracted from the timestamps of the parsed rates
+// (bodies reused from the HTTP response cache are not adjusted).
+//
+// ResponseAge is safe for concurrent use.
+type ResponseAge struct {
+	mu  sync.Mutex
+	age time.Duration
+}
+
+// WithResponseAge returns a context carrying a new ResponseAge collector.
+func WithResponseAge(ctx context.Context) (context.Context, *ResponseAge) {
+	age := &ResponseAge{}
+	return context.WithValue(ctx, responseAgeKey{}, age), age
+}
+
+// ResponseAgeFromContext returns the ResponseAge attached to ctx, or nil if none.
+func ResponseAgeFromContext(ctx context.Context) *ResponseAge {
+	age, _ := ctx.Value(responseAgeKey{}).(*ResponseAge)
+	return age
+}
+
+// Record sets the response age. Negative ages are ignored.
+func (a *ResponseAge) Record(age time.Duration) {
+	if age < 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.age = age
+}
+
+// Age returns the recorded age, or 0 if none was recorded.
+// A nil *ResponseAge returns 0.
+func (a *ResponseAge) Age() time.Duration {
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.age
+}
+
+// CDNAgeInterceptor records the standard Age header (seconds the response spent
+// in a CDN cache) in the request context's ResponseAge, so rate timestamps reflect
+// when the upstream produced the data rather than when it was downloaded.
+//
+// Missing or malformed Age headers are ignored.
+func CDNAgeInterceptor() ResponseInterceptor {
+	return func(resp *http.Response) error {
+		if resp.Request == nil {
+			return nil
+		}
+		age := ResponseAgeFromContext(resp.Request.Context())
+		if age == nil {
+			return nil
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(resp.Header.Get("Age")), 10, 64)
+		if err != nil || seconds < 0 {
+			return nil
+		}
+		age.Record(time.Duration(seconds) * time.Second)
+		return nil
+	}
+}
+
+// applyResponseInterceptors runs interceptors in order, stopping at the first error.
+func applyResponseInterceptors(resp *http.Response, interceptors []ResponseInterceptor) error {
+	for _, intercept := range interceptors {
+		if err := intercept(resp); err != nil {
+			return fmt.Errorf("response rejected by interceptor: %w", err)
+		}
+	}
+	return nil
+}
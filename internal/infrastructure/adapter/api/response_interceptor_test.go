@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestCurrencyAPIProvider_ResponseInterceptorRejects(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Cache", "MISS")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85}}`))
+	}))
+	defer server.Close()
+
+	errPolicy := errors.New("uncached responses not allowed")
+	config := DefaultCurrencyAPIProviderConfig()
+	config.ResponseInterceptors = []ResponseInterceptor{
+		func(resp *http.Response) error {
+			if resp.Header.Get("X-Cache") == "MISS" {
+				return errPolicy
+			}
+			return nil
+		},
+	}
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+
+	_, err := provider.FetchRate(context.Background(), entity.CurrencyCode("USD"), entity.CurrencyCode("EUR"))
+	if !errors.Is(err, errPolicy) {
+		t.Errorf("FetchRate() error = %v, want %v", err, errPolicy)
+	}
+	// A rejected response counts as an endpoint failure, so the fallback is tried
+	if requests != 2 {
+		t.Errorf("server received %d requests, want 2", requests)
+	}
+}
+
+func TestCDNAgeInterceptor_AnnotatesContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"age in seconds", "120", 120 * time.Second},
+		{"missing header", "", 0},
+		{"malformed header", "soon", 0},
+		{"negative age", "-5", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, age := WithResponseAge(context.Background())
+			req := httptest.NewRequest(http.MethodGet, "https://example.com", nil).WithContext(ctx)
+			resp := &http.Response{Header: http.Header{}, Request: req}
+			if tt.header != "" {
+				resp.Header.Set("Age", tt.header)
+			}
+
+			if err := CDNAgeInterceptor()(resp); err != nil {
+				t.Fatalf("interceptor error = %v", err)
+			}
+			if got := age.Age(); got != tt.want {
+				t.Errorf("Age() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyAPIProvider_CDNAgeAdjustsTimestamp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Age", "300")
+		_, _ = w.Write([]byte(`{"date": "2024-01-15", "usd": {"eur": 0.85, "gbp": 0.75}}`))
+	}))
+	defer server.Close()
+
+	config := DefaultCurrencyAPIProviderConfig()
+	config.ResponseInterceptors = []ResponseInterceptor{CDNAgeInterceptor()}
+	provider := NewCurrencyAPIProviderWithConfig(NewHTTPClient(), server.URL, server.URL, config, nil)
+	base := entity.CurrencyCode("USD")
+
+	before := time.Now()
+	rate, err := provider.FetchRate(context.Background(), base, entity.CurrencyCode("EUR"))
+	if err != nil {
+		t.Fatalf("FetchRate() error = %v", err)
+	}
+	rates, err := provider.FetchAllRates(context.Background(), base)
+	if err != nil {
+		t.Fatalf("FetchAllRates() error = %v", err)
+	}
+
+	wantLatest := before.Add(-300 * time.Second)
+	for _, r := range append(rates, rate) {
+		if r.Timestamp.Before(wantLatest.Add(-time.Second)) || r.Timestamp.After(time.Now().Add(-300*time.Second)) {
+			t.Errorf("%s/%s Timestamp = %v, want about %v", r.Base, r.Target, r.Timestamp, wantLatest)
+		}
+	}
+}
+
+func TestResponseAge_Nil(t *testing.T) {
+	if got := ResponseAgeFromContext(context.Background()).Age(); got != 0 {
+		t.Errorf("Age() = %v, want 0", got)
+	}
+}
@@ -1,11 +1,14 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
 )
 
 func TestParseRateResponse_Success(t *testing.T) {
@@ -22,7 +25,7 @@ func TestParseRateResponse_Success(t *testing.T) {
 		},
 	}
 
-	rate, err := parseRateResponse(resp, base, target)
+	rate, err := parseRateResponse(resp, base, target, entity.DefaultRateBounds())
 	if err != nil {
 		t.Fatalf("parseRateResponse() error = %v, want nil", err)
 	}
@@ -63,7 +66,7 @@ func TestParseRateResponse_APIError(t *testing.T) {
 		Rates: map[string]map[string]float64{},
 	}
 
-	_, err := parseRateResponse(resp, base, target)
+	_, err := parseRateResponse(resp, base, target, entity.DefaultRateBounds())
 	if err == nil {
 		t.Fatal("parseRateResponse() error = nil, want error")
 	}
@@ -87,7 +90,7 @@ func TestParseRateResponse_BaseCurrencyMismatch(t *testing.T) {
 		},
 	}
 
-	_, err := parseRateResponse(resp, base, target)
+	_, err := parseRateResponse(resp, base, target, entity.DefaultRateBounds())
 	if err == nil {
 		t.Fatal("parseRateResponse() error = nil, want error")
 	}
@@ -110,7 +113,7 @@ func TestParseRateResponse_TargetNotFound(t *testing.T) {
 		},
 	}
 
-	_, err := parseRateResponse(resp, base, target)
+	_, err := parseRateResponse(resp, base, target, entity.DefaultRateBounds())
 	if err == nil {
 		t.Fatal("parseRateResponse() error = nil, want error")
 	}
@@ -119,6 +122,20 @@ func TestParseRateResponse_TargetNotFound(t *testing.T) {
 	if err.Error() != expectedErr {
 		t.Errorf("Error message = %q, want %q", err.Error(), expectedErr)
 	}
+	if !errors.Is(err, provider.ErrCurrencyUnsupported) {
+		t.Errorf("Error = %v, want ErrCurrencyUnsupported", err)
+	}
+
+	var unsupported *provider.UnsupportedTargetError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("Error = %T, want *provider.UnsupportedTargetError", err)
+	}
+	if unsupported.Base != "USD" || unsupported.Target != "EUR" {
+		t.Errorf("Base/Target = %s/%s, want USD/EUR", unsupported.Base, unsupported.Target)
+	}
+	if len(unsupported.Available) != 1 || unsupported.Available[0] != "GBP" {
+		t.Errorf("Available = %v, want [GBP]", unsupported.Available)
+	}
 }
 
 func TestParseRateResponse_InvalidRate(t *testing.T) {
@@ -144,7 +161,7 @@ func TestParseRateResponse_InvalidRate(t *testing.T) {
 				},
 			}
 
-			_, err := parseRateResponse(resp, base, target)
+			_, err := parseRateResponse(resp, base, target, entity.DefaultRateBounds())
 			if err == nil {
 				t.Fatal("parseRateResponse() error = nil, want error")
 			}
@@ -166,15 +183,20 @@ func TestParseAllRatesResponse_Success(t *testing.T) {
 		},
 	}
 
-	rates, err := parseAllRatesResponse(resp, base)
+	result, err := parseAllRatesResponse(resp, base, entity.DefaultRateBounds())
 	if err != nil {
 		t.Fatalf("parseAllRatesResponse() error = %v, want nil", err)
 	}
+	rates := result.Rates
 
 	if len(rates) != 3 {
 		t.Errorf("len(rates) = %d, want 3", len(rates))
 	}
 
+	if result.Skipped != 0 {
+		t.Errorf("Skipped = %d, want 0", result.Skipped)
+	}
+
 	// Verify all rates have correct base
 	for _, rate := range rates {
 		if !rate.Base.Equal(base) {
@@ -187,6 +209,39 @@ func TestParseAllRatesResponse_Success(t *testing.T) {
 	}
 }
 
+func TestParseAllRatesResponse_SharedTimestamp(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+
+	rates := make(map[string]float64)
+	for _, code := range []string{"eur", "gbp", "jpy", "chf", "cad", "aud", "nzd", "sek", "nok", "dkk"} {
+		rates[code] = 1.5
+	}
+	resp := &currencyAPIResponse{
+		Date:  "2024-01-15",
+		Rates: map[string]map[string]float64{"usd": rates},
+	}
+
+	before := time.Now()
+	result, err := parseAllRatesResponse(resp, base, entity.DefaultRateBounds())
+	if err != nil {
+		t.Fatalf("parseAllRatesResponse() error = %v, want nil", err)
+	}
+	after := time.Now()
+
+	if len(result.Rates) != len(rates) {
+		t.Fatalf("len(rates) = %d, want %d", len(result.Rates), len(rates))
+	}
+	want := result.Rates[0].Timestamp
+	if want.Before(before) || want.After(after) {
+		t.Errorf("Timestamp = %v, want between %v and %v", want, before, after)
+	}
+	for _, rate := range result.Rates {
+		if !rate.Timestamp.Equal(want) {
+			t.Errorf("Timestamp for %s = %v, want %v", rate.Target, rate.Timestamp, want)
+		}
+	}
+}
+
 func TestParseAllRatesResponse_APIError(t *testing.T) {
 	base, _ := entity.NewCurrencyCode("USD")
 
@@ -196,7 +251,7 @@ func TestParseAllRatesResponse_APIError(t *testing.T) {
 		Rates: map[string]map[string]float64{},
 	}
 
-	_, err := parseAllRatesResponse(resp, base)
+	_, err := parseAllRatesResponse(resp, base, entity.DefaultRateBounds())
 	if err == nil {
 		t.Fatal("parseAllRatesResponse() error = nil, want error")
 	}
@@ -214,7 +269,7 @@ func TestParseAllRatesResponse_BaseCurrencyMismatch(t *testing.T) {
 		},
 	}
 
-	_, err := parseAllRatesResponse(resp, base)
+	_, err := parseAllRatesResponse(resp, base, entity.DefaultRateBounds())
 	if err == nil {
 		t.Fatal("parseAllRatesResponse() error = nil, want error")
 	}
@@ -234,12 +289,14 @@ func TestParseAllRatesResponse_SkipsInvalidRates(t *testing.T) {
 				"usd": 1.0,  // Invalid (same as base)
 			},
 		},
+		Unparseable: []string{"usd.chf", "eur.gbp"}, // Only the base's values count
 	}
 
-	rates, err := parseAllRatesResponse(resp, base)
+	result, err := parseAllRatesResponse(resp, base, entity.DefaultRateBounds())
 	if err != nil {
 		t.Fatalf("parseAllRatesResponse() error = %v, want nil", err)
 	}
+	rates := result.Rates
 
 	// Should only return EUR (the valid one)
 	if len(rates) != 1 {
@@ -250,6 +307,37 @@ func TestParseAllRatesResponse_SkipsInvalidRates(t *testing.T) {
 	if !rates[0].Target.Equal(eur) {
 		t.Errorf("Rate target = %v, want EUR", rates[0].Target)
 	}
+
+	// Skipped entries should be counted per reason
+	if result.Skipped != 5 {
+		t.Errorf("Skipped = %d, want 5", result.Skipped)
+	}
+	wantReasons := map[string]int{
+		skipReasonNonPositiveRate:     2,
+		skipReasonUnparseable:         1,
+		skipReasonInvalidCurrencyCode: 1,
+		skipReasonSameAsBase:          1,
+	}
+	for reason, want := range wantReasons {
+		if got := result.SkipReasons[reason]; got != want {
+			t.Errorf("SkipReasons[%q] = %d, want %d", reason, got, want)
+		}
+	}
+}
+
+func TestParseRateResponse_OutOfRange(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	resp := &currencyAPIResponse{
+		Date:  "2024-01-15",
+		Rates: map[string]map[string]float64{"usd": {"eur": 3}},
+	}
+
+	_, err := parseRateResponse(resp, base, target, entity.RateBounds{Min: 0.5, Max: 2})
+	if !errors.Is(err, entity.ErrRateOutOfRange) {
+		t.Errorf("parseRateResponse() error = %v, want ErrRateOutOfRange", err)
+	}
 }
 
 func TestParseAllRatesResponse_EmptyRates(t *testing.T) {
@@ -262,10 +350,11 @@ func TestParseAllRatesResponse_EmptyRates(t *testing.T) {
 		},
 	}
 
-	rates, err := parseAllRatesResponse(resp, base)
+	result, err := parseAllRatesResponse(resp, base, entity.DefaultRateBounds())
 	if err != nil {
 		t.Fatalf("parseAllRatesResponse() error = %v, want nil", err)
 	}
+	rates := result.Rates
 
 	// Should return empty slice (not nil)
 	if rates == nil {
@@ -276,3 +365,121 @@ func TestParseAllRatesResponse_EmptyRates(t *testing.T) {
 		t.Errorf("len(rates) = %d, want 0", len(rates))
 	}
 }
+
+func TestCurrencyAPIResponse_UnmarshalJSON_StringEncodedRates(t *testing.T) {
+	data := []byte(`{
+		"date": "2024-01-15",
+		"usd": {
+			"eur": 0.85,
+			"gbp": "0.75",
+			"jpy": " 110.5 ",
+			"chf": "not-a-number",
+			"cad": true
+		}
+	}`)
+
+	var resp currencyAPIResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	rates := resp.Rates["usd"]
+	want := map[string]float64{"eur": 0.85, "gbp": 0.75, "jpy": 110.5}
+	if len(rates) != len(want) {
+		t.Errorf("len(rates) = %d, want %d (malformed values skipped): %v", len(rates), len(want), rates)
+	}
+	for code, rate := range want {
+		if got, ok := rates[code]; !ok || got != rate {
+			t.Errorf("rates[%q] = %v (present: %v), want %v", code, got, ok, rate)
+		}
+	}
+}
+
+func TestCurrencyAPIResponse_UnmarshalJSON_NumericForms(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want float64
+	}{
+		{name: "integer", raw: `110`, want: 110},
+		{name: "decimal", raw: `0.85`, want: 0.85},
+		{name: "scientific notation", raw: `1e-4`, want: 0.0001},
+		{name: "scientific notation with sign and capital E", raw: `2.5E+3`, want: 2500},
+		{name: "string-encoded scientific notation", raw: `"1e-4"`, want: 0.0001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(`{"date": "2024-01-15", "usd": {"eur": ` + tt.raw + `}}`)
+
+			var resp currencyAPIResponse
+			if err := json.Unmarshal(data, &resp); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			got, ok := resp.Rates["usd"]["eur"]
+			if !ok {
+				t.Fatalf("rate for %s was dropped", tt.raw)
+			}
+			if got != tt.want {
+				t.Errorf("rate = %v, want %v", got, tt.want)
+			}
+			if len(resp.Unparseable) != 0 {
+				t.Errorf("Unparseable = %v, want none", resp.Unparseable)
+			}
+		})
+	}
+}
+
+func TestCurrencyAPIResponse_UnmarshalJSON_TracksUnparseable(t *testing.T) {
+	data := []byte(`{
+		"date": "2024-01-15",
+		"usd": {
+			"eur": 0.85,
+			"gbp": "abc",
+			"jpy": null,
+			"chf": {"nested": 1},
+			"cad": "NaN"
+		}
+	}`)
+
+	var resp currencyAPIResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if len(resp.Rates["usd"]) != 1 {
+		t.Errorf("len(rates) = %d, want 1: %v", len(resp.Rates["usd"]), resp.Rates["usd"])
+	}
+	want := []string{"usd.cad", "usd.chf", "usd.gbp", "usd.jpy"}
+	if strings.Join(resp.Unparseable, ",") != strings.Join(want, ",") {
+		t.Errorf("Unparseable = %v, want %v", resp.Unparseable, want)
+	}
+}
+
+func TestCurrencyAPIResponse_UnmarshalJSON_MixedCaseKeys(t *testing.T) {
+	data := []byte(`{"date": "2024-01-15", "USD": {"EUR": 0.85, "Gbp": 0.75}}`)
+
+	var resp currencyAPIResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	base, _ := entity.NewCurrencyCode("usd")
+	target, _ := entity.NewCurrencyCode("eur")
+	rate, err := parseRateResponse(&resp, base, target, entity.DefaultRateBounds())
+	if err != nil {
+		t.Fatalf("parseRateResponse() error = %v", err)
+	}
+	if rate.Base != "USD" || rate.Target != "EUR" || rate.Rate != 0.85 {
+		t.Errorf("rate = %s/%s %v, want USD/EUR 0.85", rate.Base, rate.Target, rate.Rate)
+	}
+
+	result, err := parseAllRatesResponse(&resp, base, entity.DefaultRateBounds())
+	if err != nil {
+		t.Fatalf("parseAllRatesResponse() error = %v", err)
+	}
+	if len(result.Rates) != 2 {
+		t.Errorf("len(rates) = %d, want 2", len(result.Rates))
+	}
+}
@@ -11,14 +11,16 @@ import (
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
 	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
 )
 
 // RetryConfig holds retry configuration.
 type RetryConfig struct {
-	MaxAttempts       int           // Maximum number of retry attempts
-	InitialBackoff    time.Duration // Initial backoff duration
-	MaxBackoff        time.Duration // Maximum backoff duration
-	BackoffMultiplier float64       // Backoff multiplier (e.g., 2.0 for exponential)
+	MaxAttempts       int            // Maximum number of retry attempts
+	InitialBackoff    time.Duration  // Initial backoff duration
+	MaxBackoff        time.Duration  // Maximum backoff duration
+	BackoffMultiplier float64        // Backoff multiplier (e.g., 2.0 for exponential)
+	Logger            *logger.Logger // Logs each retry attempt (optional, nil disables)
 }
 
 // DefaultRetryConfig returns a default retry configuration.
@@ -28,6 +30,7 @@ type RetryConfig struct {
 // - InitialBackoff: 100ms
 // - MaxBackoff: 5s
 // - BackoffMultiplier: 2.0 (exponential backoff)
+// - Logger: nil (retries are not logged)
 //
 // This results in backoff durations: 100ms, 200ms, 400ms, ...
 func DefaultRetryConfig() RetryConfig {
@@ -106,12 +109,28 @@ func calculateBackoff(config RetryConfig, attempt int) time.Duration {
 	return time.Duration(backoff)
 }
 
+// logRetry logs a failed attempt that is about to be retried, if config.Logger is set.
+// attempt is zero-based; the log reports it one-based.
+func logRetry(ctx context.Context, config RetryConfig, operation string, attempt int, err error, backoff time.Duration) {
+	if config.Logger == nil {
+		return
+	}
+	config.Logger.WithContext(ctx).Warn("retrying provider call",
+		"operation", operation,
+		"attempt", attempt+1,
+		"max_attempts", config.MaxAttempts,
+		"error", err.Error(),
+		"backoff_ms", backoff.Milliseconds(),
+	)
+}
+
 // RetryableFetchRate executes FetchRate with retry logic.
 //
 // This function:
 // - Attempts to fetch the rate up to MaxAttempts times
 // - Uses exponential backoff between retries
 // - Only retries retryable errors (network timeouts, temporary errors)
+// - Logs each retry with config.Logger, if set
 // - Respects context cancellation
 // - Returns the first successful result
 //
@@ -149,6 +168,7 @@ func RetryableFetchRate(
 		// Don't sleep after last attempt
 		if attempt < config.MaxAttempts-1 {
 			backoff := calculateBackoff(config, attempt)
+			logRetry(ctx, config, "FetchRate", attempt, err, backoff)
 			time.Sleep(backoff)
 		}
 	}
@@ -162,6 +182,7 @@ func RetryableFetchRate(
 // - Attempts to fetch all rates up to MaxAttempts times
 // - Uses exponential backoff between retries
 // - Only retries retryable errors (network timeouts, temporary errors)
+// - Logs each retry with config.Logger, if set
 // - Respects context cancellation
 // - Returns the first successful result
 //
@@ -199,6 +220,7 @@ func RetryableFetchAllRates(
 		// Don't sleep after last attempt
 		if attempt < config.MaxAttempts-1 {
 			backoff := calculateBackoff(config, attempt)
+			logRetry(ctx, config, "FetchAllRates", attempt, err, backoff)
 			time.Sleep(backoff)
 		}
 	}
@@ -1,14 +1,18 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
 	"net"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
 )
 
 func TestDefaultRetryConfig(t *testing.T) {
@@ -207,6 +211,79 @@ func TestRetryableFetchRate_RetryableError_SucceedsOnRetry(t *testing.T) {
 	}
 }
 
+func TestRetryableFetchRate_LogsRetries(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+	rate, _ := entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+
+	attempt := 0
+	mock := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			attempt++
+			if attempt <= 2 {
+				// Flaky upstream: fails twice, then succeeds
+				return nil, &net.DNSError{Err: "timeout", IsTimeout: true}
+			}
+			return rate, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	config := DefaultRetryConfig()
+	config.InitialBackoff = time.Millisecond
+	config.Logger = &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	if _, err := RetryableFetchRate(context.Background(), mock, base, target, config); err != nil {
+		t.Fatalf("RetryableFetchRate() error = %v, want nil", err)
+	}
+
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("failed to decode log line %q: %v", line, err)
+		}
+		if entry["msg"] == "retrying provider call" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) != 2 {
+		t.Fatalf("retry logs = %d, want 2; output: %s", len(entries), buf.String())
+	}
+
+	for i, entry := range entries {
+		if got, want := entry["attempt"], float64(i+1); got != want {
+			t.Errorf("log %d attempt = %v, want %v", i, got, want)
+		}
+		if got, want := entry["backoff_ms"], float64(calculateBackoff(config, i).Milliseconds()); got != want {
+			t.Errorf("log %d backoff_ms = %v, want %v", i, got, want)
+		}
+		if entry["error"] == nil || entry["error"] == "" {
+			t.Errorf("log %d has no error", i)
+		}
+	}
+}
+
+func TestRetryableFetchRate_NilLogger(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	mock := &mockProvider{
+		fetchRateFunc: func(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+			return nil, &net.DNSError{Err: "timeout", IsTimeout: true}
+		},
+	}
+
+	config := DefaultRetryConfig()
+	config.InitialBackoff = time.Millisecond
+	if _, err := RetryableFetchRate(context.Background(), mock, base, target, config); err == nil {
+		t.Fatal("RetryableFetchRate() error = nil, want error")
+	}
+	if mock.callCount != config.MaxAttempts {
+		t.Errorf("callCount = %d, want %d", mock.callCount, config.MaxAttempts)
+	}
+}
+
 func TestRetryableFetchRate_NonRetryableError(t *testing.T) {
 	base, _ := entity.NewCurrencyCode("USD")
 	target, _ := entity.NewCurrencyCode("EUR")
@@ -360,6 +437,61 @@ func TestRetryableFetchAllRates_RetryableError_SucceedsOnRetry(t *testing.T) {
 	}
 }
 
+func TestRetryableFetchAllRates_LogsRetries(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	rate, _ := entity.NewExchangeRate(base, "EUR", 0.85, time.Now(), false)
+
+	attempt := 0
+	mock := &mockProvider{
+		fetchAllRatesFunc: func(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+			attempt++
+			if attempt <= 2 {
+				// Flaky upstream: fails twice, then succeeds
+				return nil, &net.DNSError{Err: "timeout", IsTimeout: true}
+			}
+			return []*entity.ExchangeRate{rate}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	config := DefaultRetryConfig()
+	config.InitialBackoff = time.Millisecond
+	config.Logger = &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	if _, err := RetryableFetchAllRates(context.Background(), mock, base, config); err != nil {
+		t.Fatalf("RetryableFetchAllRates() error = %v, want nil", err)
+	}
+
+	var entries []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("failed to decode log line %q: %v", line, err)
+		}
+		if entry["msg"] == "retrying provider call" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) != 2 {
+		t.Fatalf("retry logs = %d, want 2; output: %s", len(entries), buf.String())
+	}
+
+	for i, entry := range entries {
+		if got := entry["operation"]; got != "FetchAllRates" {
+			t.Errorf("log %d operation = %v, want FetchAllRates", i, got)
+		}
+		if got, want := entry["attempt"], float64(i+1); got != want {
+			t.Errorf("log %d attempt = %v, want %v", i, got, want)
+		}
+		if got, want := entry["backoff_ms"], float64(calculateBackoff(config, i).Milliseconds()); got != want {
+			t.Errorf("log %d backoff_ms = %v, want %v", i, got, want)
+		}
+		if entry["error"] == nil || entry["error"] == "" {
+			t.Errorf("log %d has no error", i)
+		}
+	}
+}
+
 func TestRetryableFetchAllRates_MaxAttemptsExceeded(t *testing.T) {
 	base, _ := entity.NewCurrencyCode("USD")
 
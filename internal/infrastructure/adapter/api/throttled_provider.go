@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+// ThrottleConfig holds configuration for ThrottledProvider.
+type ThrottleConfig struct {
+	MaxConcurrent int           // Maximum number of provider calls running at once
+	MaxWait       time.Duration // How long excess callers wait for a slot (0 = fail fast)
+}
+
+// DefaultThrottleConfig returns a default throttle configuration.
+//
+// Default values:
+// - MaxConcurrent: 10
+// - MaxWait: 5 seconds
+func DefaultThrottleConfig() ThrottleConfig {
+	return ThrottleConfig{
+		MaxConcurrent: 10,
+		MaxWait:       5 * time.Second,
+	}
+}
+
+// ThrottledProvider bounds the number of concurrent calls to an ExchangeRateProvider.
+//
+// This wrapper:
+// - Allows at most MaxConcurrent FetchRate/FetchAllRates calls at once
+// - Makes excess callers wait up to MaxWait for a slot
+// - Returns provider.ErrProviderBusy if no slot becomes available in time
+//
+// This keeps a burst of cache misses from opening many simultaneous upstream
+// connections. Wrap it around the circuit breaker provider so rejected callers
+// are not counted as upstream failures; when combined with request coalescing,
+// each coalesced group occupies a single slot.
+type ThrottledProvider struct {
+	provider provider.ExchangeRateProvider
+	slots    chan struct{}
+	config   ThrottleConfig
+}
+
+// NewThrottledProvider creates a new ThrottledProvider.
+//
+// Parameters:
+//   - provider: The underlying ExchangeRateProvider to wrap
+//   - config: Throttle configuration
+//
+// Returns an error if MaxConcurrent is not positive or MaxWait is negative.
+func NewThrottledProvider(provider provider.ExchangeRateProvider, config ThrottleConfig) (*ThrottledProvider, error) {
+	if config.MaxConcurrent <= 0 {
+		return nil, fmt.Errorf("max concurrent provider calls must be positive, got %d", config.MaxConcurrent)
+	}
+	if config.MaxWait < 0 {
+		return nil, fmt.Errorf("max wait cannot be negative, got %v", config.MaxWait)
+	}
+
+	return &ThrottledProvider{
+		provider: provider,
+		slots:    make(chan struct{}, config.MaxConcurrent),
+		config:   config,
+	}, nil
+}
+
+// acquire takes a slot, waiting up to MaxWait.
+// The returned function releases the slot.
+func (p *ThrottledProvider) acquire(ctx context.Context) (func(), error) {
+	release := func() { <-p.slots }
+
+	// Fast path: a slot is free
+	select {
+	case p.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if p.config.MaxWait == 0 {
+		return nil, fmt.Errorf("%w: limit of %d reached", provider.ErrProviderBusy, p.config.MaxConcurrent)
+	}
+
+	timer := time.NewTimer(p.config.MaxWait)
+	defer timer.Stop()
+
+	select {
+	case p.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("%w: no slot within %v (limit %d)", provider.ErrProviderBusy, p.config.MaxWait, p.config.MaxConcurrent)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// FetchRate implements provider.ExchangeRateProvider.
+//
+// Context cancellation: Returns error if ctx is cancelled while waiting or fetching.
+func (p *ThrottledProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	release, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return p.provider.FetchRate(ctx, base, target)
+}
+
+// FetchAllRates implements provider.ExchangeRateProvider.
+//
+// Context cancellation: Returns error if ctx is cancelled while waiting or fetching.
+func (p *ThrottledProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+	release, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return p.provider.FetchAllRates(ctx, base)
+}
+
+// Ensure ThrottledProvider implements ExchangeRateProvider interface.
+var _ provider.ExchangeRateProvider = (*ThrottledProvider)(nil)
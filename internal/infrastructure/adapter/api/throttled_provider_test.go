@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	domainprovider "github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+// blockingProvider blocks every call until released and tracks the peak number
+// of concurrent calls.
+type blockingProvider struct {
+	release  chan struct{}
+	started  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func newBlockingProvider() *blockingProvider {
+	return &blockingProvider{
+		release: make(chan struct{}),
+		started: make(chan struct{}, 100),
+	}
+}
+
+func (p *blockingProvider) enter() {
+	current := p.inFlight.Add(1)
+	for {
+		peak := p.peak.Load()
+		if current <= peak || p.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	p.started <- struct{}{}
+	<-p.release
+	p.inFlight.Add(-1)
+}
+
+func (p *blockingProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	p.enter()
+	return entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+}
+
+func (p *blockingProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+	p.enter()
+	return nil, nil
+}
+
+func TestNewThrottledProvider_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ThrottleConfig
+		wantErr bool
+	}{
+		{"default", DefaultThrottleConfig(), false},
+		{"fail fast", ThrottleConfig{MaxConcurrent: 1}, false},
+		{"zero concurrency", ThrottleConfig{MaxConcurrent: 0}, true},
+		{"negative wait", ThrottleConfig{MaxConcurrent: 1, MaxWait: -time.Second}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewThrottledProvider(&mockProvider{}, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewThrottledProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThrottledProvider_NeverExceedsLimit(t *testing.T) {
+	const limit = 3
+	const callers = 20
+
+	mock := newBlockingProvider()
+	throttled, err := NewThrottledProvider(mock, ThrottleConfig{MaxConcurrent: limit, MaxWait: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("NewThrottledProvider() error = %v", err)
+	}
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				_, err = throttled.FetchRate(context.Background(), base, target)
+			} else {
+				_, err = throttled.FetchAllRates(context.Background(), base)
+			}
+			errs <- err
+		}(i)
+	}
+
+	// Wait until the limit is saturated, then let calls complete one by one
+	for i := 0; i < limit; i++ {
+		<-mock.started
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := mock.inFlight.Load(); got != limit {
+		t.Errorf("in-flight calls = %d, want %d while saturated", got, limit)
+	}
+	for i := 0; i < callers; i++ {
+		mock.release <- struct{}{}
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("call error = %v, want nil", err)
+		}
+	}
+
+	if peak := mock.peak.Load(); peak > limit {
+		t.Errorf("peak concurrent calls = %d, exceeds limit %d", peak, limit)
+	}
+}
+
+func TestThrottledProvider_FailFast(t *testing.T) {
+	mock := newBlockingProvider()
+	throttled, _ := NewThrottledProvider(mock, ThrottleConfig{MaxConcurrent: 1})
+
+	base, _ := entity.NewCurrencyCode("USD")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = throttled.FetchAllRates(context.Background(), base)
+	}()
+	<-mock.started
+
+	_, err := throttled.FetchAllRates(context.Background(), base)
+	if !errors.Is(err, domainprovider.ErrProviderBusy) {
+		t.Errorf("FetchAllRates() error = %v, want ErrProviderBusy", err)
+	}
+
+	mock.release <- struct{}{}
+	<-done
+
+	// Slot is released after the call completes
+	go func() { mock.release <- struct{}{} }()
+	if _, err := throttled.FetchAllRates(context.Background(), base); err != nil {
+		t.Errorf("FetchAllRates() after release error = %v, want nil", err)
+	}
+}
+
+func TestThrottledProvider_WaitTimeoutAndCancellation(t *testing.T) {
+	mock := newBlockingProvider()
+	throttled, _ := NewThrottledProvider(mock, ThrottleConfig{MaxConcurrent: 1, MaxWait: 50 * time.Millisecond})
+
+	base, _ := entity.NewCurrencyCode("USD")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = throttled.FetchAllRates(context.Background(), base)
+	}()
+	<-mock.started
+	defer func() {
+		mock.release <- struct{}{}
+		<-done
+	}()
+
+	start := time.Now()
+	_, err := throttled.FetchAllRates(context.Background(), base)
+	if !errors.Is(err, domainprovider.ErrProviderBusy) {
+		t.Errorf("FetchAllRates() error = %v, want ErrProviderBusy after MaxWait", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("returned after %v, want to wait at least MaxWait", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := throttled.FetchAllRates(ctx, base); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchAllRates() error = %v, want context.Canceled", err)
+	}
+}
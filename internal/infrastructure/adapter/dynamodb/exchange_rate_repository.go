@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,14 +13,57 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
 )
 
+// repositoryClient is the subset of the DynamoDB client used by DynamoDBRepository.
+// *dynamodb.Client satisfies this interface; tests can provide a mock.
+type repositoryClient interface {
+	itemClient
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
 // DynamoDBRepository implements the ExchangeRateRepository interface using AWS DynamoDB.
 // This is an adapter in the Hexagonal Architecture pattern, connecting the domain layer
 // to the AWS DynamoDB infrastructure.
 type DynamoDBRepository struct {
-	client    *dynamodb.Client
+	client    repositoryClient
 	tableName string
+	config    RepositoryConfig
+	publisher repository.EventPublisher
+	logger    *logger.Logger
+
+	// corruptItems counts stored items GetByBase skipped because they could not be decoded
+	corruptItems atomic.Int64
+}
+
+// RepositoryConfig holds configuration for DynamoDBRepository.
+type RepositoryConfig struct {
+	// ConsistentRead makes Get and GetStale use strongly consistent reads, so a
+	// rate is readable immediately after Save. Strongly consistent reads consume
+	// twice the read capacity (RCU) of eventually consistent reads.
+	// GetByBase queries a GSI, which only supports eventually consistent reads.
+	ConsistentRead bool
+
+	// StaleRetention is how long a rate is kept after its cache TTL expires.
+	// It is added to the ttl attribute written by Save, so DynamoDB TTL does not
+	// delete a rate the moment it expires and the stale cache fallback can still
+	// serve it while the provider is unavailable. 0 deletes rates at the cache TTL.
+	StaleRetention time.Duration
+}
+
+// DefaultRepositoryConfig returns a default repository configuration.
+//
+// Default values:
+// - ConsistentRead: false (eventually consistent reads)
+// - StaleRetention: 24 hours
+func DefaultRepositoryConfig() RepositoryConfig {
+	return RepositoryConfig{
+		ConsistentRead: false,
+		StaleRetention: 24 * time.Hour,
+	}
 }
 
 // NewDynamoDBRepository creates a new DynamoDB repository.
@@ -32,23 +77,83 @@ type DynamoDBRepository struct {
 //
 // Returns a new DynamoDBRepository instance.
 func NewDynamoDBRepository(client *dynamodb.Client, tableName string) *DynamoDBRepository {
+	return NewDynamoDBRepositoryWithEventPublisher(client, tableName, nil, nil)
+}
+
+// NewDynamoDBRepositoryWithEventPublisher creates a new DynamoDB repository that
+// publishes a repository.RateEvent after every successful Save.
+//
+// Parameters:
+//   - client: The DynamoDB client
+//   - tableName: The name of the DynamoDB table to use
+//   - publisher: Receives rate update events (optional, no-op if nil)
+//   - log: Logger for publish failures (optional, creates default if nil)
+func NewDynamoDBRepositoryWithEventPublisher(client *dynamodb.Client, tableName string, publisher repository.EventPublisher, log *logger.Logger) *DynamoDBRepository {
+	return NewDynamoDBRepositoryWithConfig(client, tableName, DefaultRepositoryConfig(), publisher, log)
+}
+
+// NewDynamoDBRepositoryWithConfig creates a new DynamoDB repository with custom configuration.
+//
+// Parameters:
+//   - client: The DynamoDB client
+//   - tableName: The name of the DynamoDB table to use
+//   - config: Repository configuration (e.g. read consistency)
+//   - publisher: Receives rate update events (optional, no-op if nil)
+//   - log: Logger for publish failures (optional, creates default if nil)
+func NewDynamoDBRepositoryWithConfig(client *dynamodb.Client, tableName string, config RepositoryConfig, publisher repository.EventPublisher, log *logger.Logger) *DynamoDBRepository {
+	repo := newDynamoDBRepository(client, tableName, publisher, log)
+	repo.config = config
+	return repo
+}
+
+// newDynamoDBRepository creates a DynamoDBRepository over any repositoryClient.
+func newDynamoDBRepository(client repositoryClient, tableName string, publisher repository.EventPublisher, log *logger.Logger) *DynamoDBRepository {
+	if publisher == nil {
+		publisher = repository.NoopEventPublisher{}
+	}
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
 	return &DynamoDBRepository{
 		client:    client,
 		tableName: tableName,
+		config:    DefaultRepositoryConfig(),
+		publisher: publisher,
+		logger:    log,
 	}
 }
 
+// Cache entry schema versions.
+//
+// Bump currentSchemaVersion whenever the dynamoItem layout changes and teach
+// migrateDynamoItem how to upgrade items written by older versions.
+const (
+	// schemaVersionV1 is the original layout, written before items carried a
+	// SchemaVersion attribute. Items without the attribute are treated as v1.
+	schemaVersionV1 = 1
+
+	// schemaVersionV2 adds the SchemaVersion attribute.
+	schemaVersionV2 = 2
+
+	// currentSchemaVersion is the version written by Save.
+	currentSchemaVersion = schemaVersionV2
+)
+
 // dynamoItem represents a DynamoDB item structure.
 // This struct is used for marshaling/unmarshaling between Go and DynamoDB AttributeValue format.
 // The dynamodbav tags tell the AWS SDK how to map struct fields to DynamoDB attributes.
 type dynamoItem struct {
-	PK        string  `dynamodbav:"PK"`            // Partition key: RATE#USD#EUR
-	Base      string  `dynamodbav:"Base"`          // Base currency code (e.g., "USD")
-	Target    string  `dynamodbav:"Target"`        // Target currency code (e.g., "EUR")
-	Rate      float64 `dynamodbav:"Rate"`          // Exchange rate value
-	Timestamp int64   `dynamodbav:"Timestamp"`     // Unix timestamp in seconds
-	Stale     bool    `dynamodbav:"Stale"`         // Whether rate is marked as stale
-	TTL       *int64  `dynamodbav:"ttl,omitempty"` // TTL timestamp (Unix epoch in seconds), optional
+	PK            string      `dynamodbav:"PK"`                      // Partition key: RATE#USD#EUR
+	Base          string      `dynamodbav:"Base"`                    // Base currency code (e.g., "USD")
+	Target        string      `dynamodbav:"Target"`                  // Target currency code (e.g., "EUR")
+	Rate          rateNumber  `dynamodbav:"Rate"`                    // Exchange rate value, written with full precision
+	Timestamp     int64       `dynamodbav:"Timestamp"`               // Unix timestamp in seconds
+	Stale         bool        `dynamodbav:"Stale"`                   // Whether rate is marked as stale
+	TTL           *int64      `dynamodbav:"ttl,omitempty"`           // TTL timestamp (Unix epoch in seconds), optional
+	SchemaVersion int         `dynamodbav:"SchemaVersion,omitempty"` // Item layout version (0 = unset, read as v1)
+	Bid           *rateNumber `dynamodbav:"Bid,omitempty"`           // Bid price, optional
+	Ask           *rateNumber `dynamodbav:"Ask,omitempty"`           // Ask price, optional
+	Derived       bool        `dynamodbav:"Derived,omitempty"`       // Whether the rate was computed from the inverse pair
 }
 
 // entityToDynamoItem converts a domain entity to DynamoDB item format.
@@ -75,19 +180,77 @@ func entityToDynamoItem(rate *entity.ExchangeRate, ttl time.Duration) (*dynamoIt
 	}
 
 	return &dynamoItem{
-		PK:        buildPartitionKey(rate.Base, rate.Target),
-		Base:      rate.Base.String(),
-		Target:    rate.Target.String(),
-		Rate:      rate.Rate,
-		Timestamp: rate.Timestamp.Unix(),
-		Stale:     rate.Stale,
-		TTL:       ttlTimestamp,
+		PK:            buildPartitionKey(rate.Base, rate.Target),
+		Base:          rate.Base.String(),
+		Target:        rate.Target.String(),
+		Rate:          rateNumber(rate.Rate),
+		Timestamp:     rate.Timestamp.Unix(),
+		Stale:         rate.Stale,
+		TTL:           ttlTimestamp,
+		SchemaVersion: currentSchemaVersion,
+		Bid:           toRateNumber(rate.Bid),
+		Ask:           toRateNumber(rate.Ask),
+		Derived:       rate.Derived,
 	}, nil
 }
 
+// toRateNumber converts an optional price to its stored form.
+func toRateNumber(price *float64) *rateNumber {
+	if price == nil {
+		return nil
+	}
+	r := rateNumber(*price)
+	return &r
+}
+
+// fromRateNumber converts an optional stored price back to a float64.
+func fromRateNumber(r *rateNumber) *float64 {
+	if r == nil {
+		return nil
+	}
+	v := float64(*r)
+	return &v
+}
+
+// migrateDynamoItem upgrades an item written by an older schema version to
+// currentSchemaVersion in place.
+//
+// This function:
+// - Treats a missing SchemaVersion as v1
+// - Recovers Base and Target from the partition key if a v1 item lacks them
+// - Rejects items written by a newer, unknown schema version
+//
+// The migrated item is only used for the read; it is rewritten in the current
+// format the next time the rate is saved.
+func migrateDynamoItem(item *dynamoItem) error {
+	version := item.SchemaVersion
+	if version == 0 {
+		version = schemaVersionV1
+	}
+	if version > currentSchemaVersion {
+		return fmt.Errorf("unsupported cache entry schema version %d (max %d)", version, currentSchemaVersion)
+	}
+
+	if version == schemaVersionV1 && (item.Base == "" || item.Target == "") {
+		// PK format: RATE#{BASE}#{TARGET}
+		if parts := strings.Split(item.PK, "#"); len(parts) == 3 && parts[0] == "RATE" {
+			if item.Base == "" {
+				item.Base = parts[1]
+			}
+			if item.Target == "" {
+				item.Target = parts[2]
+			}
+		}
+	}
+
+	item.SchemaVersion = currentSchemaVersion
+	return nil
+}
+
 // dynamoItemToEntity converts a DynamoDB item to domain entity.
 //
 // This function:
+// - Migrates items written by older schema versions (see migrateDynamoItem)
 // - Validates currency codes using domain validation
 // - Converts Unix timestamp back to time.Time
 // - Creates a new ExchangeRate entity with validation
@@ -99,6 +262,10 @@ func dynamoItemToEntity(item *dynamoItem) (*entity.ExchangeRate, error) {
 		return nil, fmt.Errorf("dynamo item cannot be nil")
 	}
 
+	if err := migrateDynamoItem(item); err != nil {
+		return nil, err
+	}
+
 	// Validate and create currency codes (domain validation)
 	base, err := entity.NewCurrencyCode(item.Base)
 	if err != nil {
@@ -114,7 +281,15 @@ func dynamoItemToEntity(item *dynamoItem) (*entity.ExchangeRate, error) {
 	timestamp := time.Unix(item.Timestamp, 0)
 
 	// Create domain entity with validation
-	return entity.NewExchangeRate(base, target, item.Rate, timestamp, item.Stale)
+	rate, err := entity.NewExchangeRate(base, target, float64(item.Rate), timestamp, item.Stale)
+	if err != nil {
+		return nil, err
+	}
+	if err := rate.SetBidAsk(fromRateNumber(item.Bid), fromRateNumber(item.Ask)); err != nil {
+		return nil, fmt.Errorf("invalid bid/ask in stored data: %w", err)
+	}
+	rate.Derived = item.Derived
+	return rate, nil
 }
 
 // buildPartitionKey creates a partition key from currency codes.
@@ -126,8 +301,10 @@ func dynamoItemToEntity(item *dynamoItem) (*entity.ExchangeRate, error) {
 // - Makes the key type explicit (RATE# prefix)
 // - Enables direct lookup for Get() and Delete() operations
 // - Follows DynamoDB best practices for composite keys
+//
+// Codes are normalized so mixed-case lookups share a single cache entry.
 func buildPartitionKey(base, target entity.CurrencyCode) string {
-	return fmt.Sprintf("RATE#%s#%s", base.String(), target.String())
+	return fmt.Sprintf("RATE#%s#%s", base.Normalize(), target.Normalize())
 }
 
 // marshalDynamoItem converts a dynamoItem to DynamoDB AttributeValue map.
@@ -216,6 +393,7 @@ func (r *DynamoDBRepository) Get(ctx context.Context, base, target entity.Curren
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: pk},
 		},
+		ConsistentRead: aws.Bool(r.config.ConsistentRead),
 	}
 
 	// Execute GetItem
@@ -246,9 +424,13 @@ func (r *DynamoDBRepository) Get(ctx context.Context, base, target entity.Curren
 // - Converts domain entity to DynamoDB item format
 // - Calculates and stores TTL timestamp
 // - Marshals item to DynamoDB AttributeValue format
+// - Publishes a RateEvent once the item is written
 //
 // If the rate already exists, it will be updated with new values.
-// The TTL is calculated from the current time plus the provided ttl duration.
+// The cache expiry is the current time plus the provided ttl duration; the
+// stored ttl attribute, which DynamoDB TTL deletes by, adds StaleRetention on top.
+// Published events carry the cache expiry.
+// Publishing failures are logged and never fail the Save.
 //
 // Context cancellation: Returns error if ctx is cancelled.
 func (r *DynamoDBRepository) Save(ctx context.Context, rate *entity.ExchangeRate, ttl time.Duration) error {
@@ -263,6 +445,13 @@ func (r *DynamoDBRepository) Save(ctx context.Context, rate *entity.ExchangeRate
 		return fmt.Errorf("failed to convert entity to dynamo item: %w", err)
 	}
 
+	// Keep the item past the cache TTL so it can still be served as stale
+	expiresAt := item.TTL
+	if item.TTL != nil && r.config.StaleRetention > 0 {
+		retainUntil := *item.TTL + int64(r.config.StaleRetention/time.Second)
+		item.TTL = &retainUntil
+	}
+
 	// Marshal to DynamoDB AttributeValue map
 	av, err := marshalDynamoItem(item)
 	if err != nil {
@@ -281,6 +470,15 @@ func (r *DynamoDBRepository) Save(ctx context.Context, rate *entity.ExchangeRate
 		return mapDynamoDBError(err, "put item")
 	}
 
+	// Notify downstream consumers - best effort
+	if err := r.publisher.Publish(ctx, repository.NewRateEvent(rate, expiresAt)); err != nil {
+		r.logger.WithContext(ctx).Warn("failed to publish rate event",
+			"base", rate.Base.String(),
+			"target", rate.Target.String(),
+			"error", err.Error(),
+		)
+	}
+
 	return nil
 }
 
@@ -292,6 +490,11 @@ func (r *DynamoDBRepository) Save(ctx context.Context, rate *entity.ExchangeRate
 // - Returns all rates for the specified base currency
 // - Returns empty slice (not nil) if no rates are found
 // - Returns rates regardless of TTL expiration (use cases handle expiration)
+// - Skips and logs items that cannot be decoded (returns *repository.PartialResultError)
+//
+// A single corrupt item (e.g. an invalid currency code) must not deny service
+// for the whole base, mirroring how the provider skips invalid entries.
+// Skipped items are counted in CorruptItemCount.
 //
 // Context cancellation: Returns error if ctx is cancelled.
 func (r *DynamoDBRepository) GetByBase(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
@@ -310,7 +513,7 @@ func (r *DynamoDBRepository) GetByBase(ctx context.Context, base entity.Currency
 			"#base": "Base", // Map #base to the actual attribute name "Base"
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":base": &types.AttributeValueMemberS{Value: base.String()},
+			":base": &types.AttributeValueMemberS{Value: base.Normalize().String()},
 		},
 	}
 
@@ -323,26 +526,56 @@ func (r *DynamoDBRepository) GetByBase(ctx context.Context, base entity.Currency
 	// Convert items to entities
 	// Pre-allocate slice with capacity for better performance
 	rates := make([]*entity.ExchangeRate, 0, len(result.Items))
+	skipped := 0
 	for _, item := range result.Items {
 		// Unmarshal DynamoDB item to dynamoItem
 		dItem, err := unmarshalDynamoItem(item)
 		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal dynamodb item: %w", err)
+			r.skipCorruptItem(ctx, base, item, fmt.Errorf("failed to unmarshal dynamodb item: %w", err))
+			skipped++
+			continue
 		}
 
 		// Convert dynamoItem to domain entity
 		entity, err := dynamoItemToEntity(dItem)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert item to entity: %w", err)
+			r.skipCorruptItem(ctx, base, item, fmt.Errorf("failed to convert item to entity: %w", err))
+			skipped++
+			continue
 		}
 
 		rates = append(rates, entity)
 	}
 
+	if skipped > 0 {
+		return rates, &repository.PartialResultError{Rates: rates, Skipped: skipped}
+	}
+
 	// Return empty slice (not nil) per interface contract
 	return rates, nil
 }
 
+// CorruptItemCount returns the number of stored items GetByBase has skipped
+// because they could not be decoded, since the repository was created.
+func (r *DynamoDBRepository) CorruptItemCount() int64 {
+	return r.corruptItems.Load()
+}
+
+// skipCorruptItem records and logs an item GetByBase could not decode.
+func (r *DynamoDBRepository) skipCorruptItem(ctx context.Context, base entity.CurrencyCode, item map[string]types.AttributeValue, err error) {
+	r.corruptItems.Add(1)
+
+	pk := ""
+	if pkAttr, ok := item["PK"].(*types.AttributeValueMemberS); ok {
+		pk = pkAttr.Value
+	}
+	r.logger.WithContext(ctx).Warn("skipped corrupt exchange rate item",
+		"base", base.String(),
+		"pk", pk,
+		"error", err.Error(),
+	)
+}
+
 // Delete removes an exchange rate for a specific currency pair.
 //
 // This method:
@@ -402,3 +635,37 @@ func (r *DynamoDBRepository) GetStale(ctx context.Context, base, target entity.C
 	// Repository doesn't filter by TTL - use cases handle expiration
 	return r.Get(ctx, base, target)
 }
+
+// GetStaleByBase retrieves the stale (expired) exchange rates for a base
+// currency for fallback scenarios.
+//
+// This method:
+// - Reads the rates like GetByBase(), including its partial results
+// - Excludes rates older than maxStaleness (no cap if maxStaleness <= 0)
+// - Returns empty slice (not nil) if no rates are left
+//
+// Context cancellation: Returns error if ctx is cancelled.
+func (r *DynamoDBRepository) GetStaleByBase(ctx context.Context, base entity.CurrencyCode, maxStaleness time.Duration) ([]*entity.ExchangeRate, error) {
+	rates, err := r.GetByBase(ctx, base)
+	var partial *repository.PartialResultError
+	if errors.As(err, &partial) {
+		partial.Rates = withinStaleness(partial.Rates, maxStaleness)
+		return partial.Rates, partial
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withinStaleness(rates, maxStaleness), nil
+}
+
+// withinStaleness returns the rates no older than maxStaleness (all rates if
+// maxStaleness <= 0).
+func withinStaleness(rates []*entity.ExchangeRate, maxStaleness time.Duration) []*entity.ExchangeRate {
+	kept := make([]*entity.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		if !rate.IsExpired(maxStaleness) {
+			kept = append(kept, rate)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,356 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+)
+
+// mockRepositoryClient extends mockItemClient with the remaining repository operations.
+type mockRepositoryClient struct {
+	*mockItemClient
+	queryItems []map[string]types.AttributeValue // Returned by every Query
+}
+
+func (m *mockRepositoryClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{Items: m.queryItems}, nil
+}
+
+func (m *mockRepositoryClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// recordingRepositoryClient records the last GetItem input.
+type recordingRepositoryClient struct {
+	*mockRepositoryClient
+	lastGet *dynamodb.GetItemInput
+}
+
+func (m *recordingRepositoryClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	m.lastGet = params
+	return m.mockRepositoryClient.GetItem(ctx, params, optFns...)
+}
+
+// mockEventPublisher records published events for testing.
+type mockEventPublisher struct {
+	events []repository.RateEvent
+	err    error
+}
+
+func (m *mockEventPublisher) Publish(ctx context.Context, event repository.RateEvent) error {
+	m.events = append(m.events, event)
+	return m.err
+}
+
+func TestDynamoDBRepository_Save_PublishesEvent(t *testing.T) {
+	rate, err := createTestExchangeRate()
+	if err != nil {
+		t.Fatalf("createTestExchangeRate() error = %v", err)
+	}
+
+	publisher := &mockEventPublisher{}
+	client := &mockRepositoryClient{mockItemClient: newMockItemClient()}
+	repo := newDynamoDBRepository(client, "TestTable", publisher, nil)
+
+	if err := repo.Save(context.Background(), rate, 1*time.Hour); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("published %d events, want 1", len(publisher.events))
+	}
+	event := publisher.events[0]
+	if event.Type != repository.RateEventTypeUpdated {
+		t.Errorf("event.Type = %q, want %q", event.Type, repository.RateEventTypeUpdated)
+	}
+	if event.Base != "USD" || event.Target != "EUR" {
+		t.Errorf("event pair = %s/%s, want USD/EUR", event.Base, event.Target)
+	}
+	if event.Rate != rate.Rate {
+		t.Errorf("event.Rate = %v, want %v", event.Rate, rate.Rate)
+	}
+	if !event.Timestamp.Equal(rate.Timestamp) {
+		t.Errorf("event.Timestamp = %v, want %v", event.Timestamp, rate.Timestamp)
+	}
+	if event.ExpiresAt == nil {
+		t.Error("event.ExpiresAt is nil, want item TTL")
+	}
+}
+
+func TestDynamoDBRepository_Save_StaleRetention(t *testing.T) {
+	tests := []struct {
+		name          string
+		retention     time.Duration
+		wantItemTTLIn time.Duration
+	}{
+		{"ttl includes retention window", 24 * time.Hour, 25 * time.Hour},
+		{"no retention", 0, 1 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := createTestExchangeRate()
+			if err != nil {
+				t.Fatalf("createTestExchangeRate() error = %v", err)
+			}
+
+			publisher := &mockEventPublisher{}
+			client := &mockRepositoryClient{mockItemClient: newMockItemClient()}
+			repo := newDynamoDBRepository(client, "TestTable", publisher, nil)
+			repo.config.StaleRetention = tt.retention
+
+			now := time.Now()
+			if err := repo.Save(context.Background(), rate, 1*time.Hour); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			item := client.items[buildPartitionKey(rate.Base, rate.Target)]
+			ttlAttr, ok := item["ttl"].(*types.AttributeValueMemberN)
+			if !ok {
+				t.Fatalf("stored ttl = %#v, want a number", item["ttl"])
+			}
+			itemTTL, err := strconv.ParseInt(ttlAttr.Value, 10, 64)
+			if err != nil {
+				t.Fatalf("failed to parse stored ttl %q: %v", ttlAttr.Value, err)
+			}
+			if want := now.Add(tt.wantItemTTLIn).Unix(); itemTTL < want-1 || itemTTL > want+1 {
+				t.Errorf("stored ttl = %d, want about %d (now + %v)", itemTTL, want, tt.wantItemTTLIn)
+			}
+
+			// Events report when the cached rate expires, not when it is deleted
+			expiresAt := publisher.events[0].ExpiresAt
+			if want := now.Add(1 * time.Hour).Unix(); expiresAt == nil || *expiresAt < want-1 || *expiresAt > want+1 {
+				t.Errorf("event.ExpiresAt = %v, want about %d (now + cache TTL)", expiresAt, want)
+			}
+		})
+	}
+}
+
+func TestDynamoDBRepository_Save_PublishErrorSwallowed(t *testing.T) {
+	rate, err := createTestExchangeRate()
+	if err != nil {
+		t.Fatalf("createTestExchangeRate() error = %v", err)
+	}
+
+	publisher := &mockEventPublisher{err: errors.New("topic unavailable")}
+	client := &mockRepositoryClient{mockItemClient: newMockItemClient()}
+	repo := newDynamoDBRepository(client, "TestTable", publisher, nil)
+
+	if err := repo.Save(context.Background(), rate, 1*time.Hour); err != nil {
+		t.Errorf("Save() error = %v, want nil (publish errors must not fail Save)", err)
+	}
+	if len(client.items) != 1 {
+		t.Errorf("stored %d items, want 1", len(client.items))
+	}
+}
+
+func TestDynamoDBRepository_DefaultPublisherIsNoop(t *testing.T) {
+	repo := newDynamoDBRepository(&mockRepositoryClient{mockItemClient: newMockItemClient()}, "TestTable", nil, nil)
+
+	if _, ok := repo.publisher.(repository.NoopEventPublisher); !ok {
+		t.Errorf("default publisher = %T, want NoopEventPublisher", repo.publisher)
+	}
+}
+
+func TestDynamoDBRepository_MixedCaseSharesCacheEntry(t *testing.T) {
+	ctx := context.Background()
+	client := &mockRepositoryClient{mockItemClient: newMockItemClient()}
+	repo := newDynamoDBRepository(client, "TestTable", nil, nil)
+
+	requests := [][2]string{{"usd", "eur"}, {"USD", "EUR"}, {"Usd", "eUr"}}
+	for _, pair := range requests {
+		base, _ := entity.NewCurrencyCode(pair[0])
+		target, _ := entity.NewCurrencyCode(pair[1])
+		rate, err := entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+		if err != nil {
+			t.Fatalf("NewExchangeRate(%s/%s) error = %v", pair[0], pair[1], err)
+		}
+		if err := repo.Save(ctx, rate, time.Hour); err != nil {
+			t.Fatalf("Save(%s/%s) error = %v", pair[0], pair[1], err)
+		}
+	}
+
+	if len(client.items) != 1 {
+		t.Errorf("stored %d cache entries, want 1", len(client.items))
+	}
+	if _, ok := client.items["RATE#USD#EUR"]; !ok {
+		t.Errorf("expected cache entry RATE#USD#EUR, got %v", client.items)
+	}
+
+	// Lookups with unnormalized codes resolve to the same entry
+	got, err := repo.Get(ctx, entity.CurrencyCode("usd"), entity.CurrencyCode("Eur"))
+	if err != nil {
+		t.Fatalf("Get(usd/Eur) error = %v", err)
+	}
+	if got.Base != "USD" || got.Target != "EUR" {
+		t.Errorf("Get() = %s/%s, want USD/EUR", got.Base, got.Target)
+	}
+}
+
+func TestDynamoDBRepository_ConsistentRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RepositoryConfig
+		want   bool
+	}{
+		{"default is eventually consistent", DefaultRepositoryConfig(), false},
+		{"consistent read enabled", RepositoryConfig{ConsistentRead: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &recordingRepositoryClient{mockRepositoryClient: &mockRepositoryClient{mockItemClient: newMockItemClient()}}
+			repo := newDynamoDBRepository(client, "TestTable", nil, nil)
+			repo.config = tt.config
+
+			rate, _ := createTestExchangeRate()
+			if err := repo.Save(ctx, rate, time.Hour); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			// Get and GetStale both read through GetItem
+			for _, get := range []func(context.Context, entity.CurrencyCode, entity.CurrencyCode) (*entity.ExchangeRate, error){repo.Get, repo.GetStale} {
+				client.lastGet = nil
+				if _, err := get(ctx, rate.Base, rate.Target); err != nil {
+					t.Fatalf("read error = %v", err)
+				}
+				if client.lastGet == nil || client.lastGet.ConsistentRead == nil {
+					t.Fatal("expected GetItem with ConsistentRead set")
+				}
+				if got := *client.lastGet.ConsistentRead; got != tt.want {
+					t.Errorf("ConsistentRead = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDynamoDBRepository_GetByBase_SkipsCorruptItems(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	storedItem := func(target string) map[string]types.AttributeValue {
+		rate, err := entity.NewExchangeRate("USD", entity.CurrencyCode(target), 0.85, now, false)
+		if err != nil {
+			t.Fatalf("NewExchangeRate(USD/%s) error = %v", target, err)
+		}
+		item, err := entityToDynamoItem(rate, time.Hour)
+		if err != nil {
+			t.Fatalf("entityToDynamoItem() error = %v", err)
+		}
+		av, err := marshalDynamoItem(item)
+		if err != nil {
+			t.Fatalf("marshalDynamoItem() error = %v", err)
+		}
+		return av
+	}
+
+	corrupt := storedItem("GBP")
+	corrupt["Target"] = &types.AttributeValueMemberS{Value: "G1"}
+
+	client := &mockRepositoryClient{
+		mockItemClient: newMockItemClient(),
+		queryItems:     []map[string]types.AttributeValue{storedItem("EUR"), corrupt, storedItem("JPY")},
+	}
+	repo := newDynamoDBRepository(client, "TestTable", nil, nil)
+
+	rates, err := repo.GetByBase(ctx, "USD")
+	var partial *repository.PartialResultError
+	if !errors.As(err, &partial) {
+		t.Fatalf("GetByBase() error = %v, want *repository.PartialResultError", err)
+	}
+	if partial.Skipped != 1 || len(partial.Rates) != 2 {
+		t.Errorf("PartialResultError = {Skipped: %d, Rates: %d}, want {1, 2}", partial.Skipped, len(partial.Rates))
+	}
+	if len(rates) != 2 {
+		t.Fatalf("GetByBase() returned %d rates, want 2", len(rates))
+	}
+	if rates[0].Target != "EUR" || rates[1].Target != "JPY" {
+		t.Errorf("GetByBase() targets = %s, %s, want EUR, JPY", rates[0].Target, rates[1].Target)
+	}
+	if got := repo.CorruptItemCount(); got != 1 {
+		t.Errorf("CorruptItemCount() = %d, want 1", got)
+	}
+}
+
+func TestDynamoDBRepository_GetStaleByBase(t *testing.T) {
+	ctx := context.Background()
+
+	storedItem := func(target string, age time.Duration) map[string]types.AttributeValue {
+		rate, err := entity.NewExchangeRate("USD", entity.CurrencyCode(target), 0.85, time.Now().Add(-age), false)
+		if err != nil {
+			t.Fatalf("NewExchangeRate(USD/%s) error = %v", target, err)
+		}
+		item, err := entityToDynamoItem(rate, time.Hour)
+		if err != nil {
+			t.Fatalf("entityToDynamoItem() error = %v", err)
+		}
+		av, err := marshalDynamoItem(item)
+		if err != nil {
+			t.Fatalf("marshalDynamoItem() error = %v", err)
+		}
+		return av
+	}
+
+	corrupt := storedItem("CHF", time.Hour)
+	corrupt["Target"] = &types.AttributeValueMemberS{Value: "C1"}
+
+	tests := []struct {
+		name         string
+		items        []map[string]types.AttributeValue
+		maxStaleness time.Duration
+		wantTargets  []entity.CurrencyCode
+		wantPartial  bool
+	}{
+		{
+			name:         "excludes rates beyond the staleness cap",
+			items:        []map[string]types.AttributeValue{storedItem("EUR", 2*time.Hour), storedItem("GBP", 10*time.Hour)},
+			maxStaleness: 6 * time.Hour,
+			wantTargets:  []entity.CurrencyCode{"EUR"},
+		},
+		{
+			name:        "no cap",
+			items:       []map[string]types.AttributeValue{storedItem("EUR", 2*time.Hour), storedItem("GBP", 10*time.Hour)},
+			wantTargets: []entity.CurrencyCode{"EUR", "GBP"},
+		},
+		{
+			name:         "partial result keeps rates within the cap",
+			items:        []map[string]types.AttributeValue{storedItem("EUR", 10*time.Hour), corrupt, storedItem("JPY", time.Hour)},
+			maxStaleness: 6 * time.Hour,
+			wantTargets:  []entity.CurrencyCode{"JPY"},
+			wantPartial:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockRepositoryClient{mockItemClient: newMockItemClient(), queryItems: tt.items}
+			repo := newDynamoDBRepository(client, "TestTable", nil, nil)
+
+			rates, err := repo.GetStaleByBase(ctx, "USD", tt.maxStaleness)
+			var partial *repository.PartialResultError
+			if gotPartial := errors.As(err, &partial); gotPartial != tt.wantPartial || (err != nil && !gotPartial) {
+				t.Fatalf("GetStaleByBase() error = %v, want partial result %v", err, tt.wantPartial)
+			}
+			if partial != nil && len(partial.Rates) != len(rates) {
+				t.Errorf("PartialResultError.Rates = %d rates, want %d", len(partial.Rates), len(rates))
+			}
+			if len(rates) != len(tt.wantTargets) {
+				t.Fatalf("GetStaleByBase() returned %d rates, want %v", len(rates), tt.wantTargets)
+			}
+			for i, target := range tt.wantTargets {
+				if rates[i].Target != target {
+					t.Errorf("GetStaleByBase()[%d] target = %s, want %s", i, rates[i].Target, target)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package dynamodb
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -163,6 +164,91 @@ func TestDynamoItemToEntity(t *testing.T) {
 	}
 }
 
+func TestDynamoItemToEntity_SchemaVersions(t *testing.T) {
+	timestamp := time.Now().Add(-1 * time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		av      map[string]types.AttributeValue
+		wantErr bool
+	}{
+		{
+			name: "v1 item without version field",
+			av: map[string]types.AttributeValue{
+				"PK":        &types.AttributeValueMemberS{Value: "RATE#USD#EUR"},
+				"Base":      &types.AttributeValueMemberS{Value: "USD"},
+				"Target":    &types.AttributeValueMemberS{Value: "EUR"},
+				"Rate":      &types.AttributeValueMemberN{Value: "0.85"},
+				"Timestamp": &types.AttributeValueMemberN{Value: fmt.Sprint(timestamp)},
+				"Stale":     &types.AttributeValueMemberBOOL{Value: false},
+			},
+		},
+		{
+			name: "v1 item missing currency attributes",
+			av: map[string]types.AttributeValue{
+				"PK":        &types.AttributeValueMemberS{Value: "RATE#USD#EUR"},
+				"Rate":      &types.AttributeValueMemberN{Value: "0.85"},
+				"Timestamp": &types.AttributeValueMemberN{Value: fmt.Sprint(timestamp)},
+			},
+		},
+		{
+			name: "v2 item",
+			av: map[string]types.AttributeValue{
+				"PK":            &types.AttributeValueMemberS{Value: "RATE#USD#EUR"},
+				"Base":          &types.AttributeValueMemberS{Value: "USD"},
+				"Target":        &types.AttributeValueMemberS{Value: "EUR"},
+				"Rate":          &types.AttributeValueMemberN{Value: "0.85"},
+				"Timestamp":     &types.AttributeValueMemberN{Value: fmt.Sprint(timestamp)},
+				"Stale":         &types.AttributeValueMemberBOOL{Value: false},
+				"ttl":           &types.AttributeValueMemberN{Value: fmt.Sprint(timestamp + 3600)},
+				"SchemaVersion": &types.AttributeValueMemberN{Value: "2"},
+			},
+		},
+		{
+			name: "unknown future version",
+			av: map[string]types.AttributeValue{
+				"PK":            &types.AttributeValueMemberS{Value: "RATE#USD#EUR"},
+				"Base":          &types.AttributeValueMemberS{Value: "USD"},
+				"Target":        &types.AttributeValueMemberS{Value: "EUR"},
+				"Rate":          &types.AttributeValueMemberN{Value: "0.85"},
+				"Timestamp":     &types.AttributeValueMemberN{Value: fmt.Sprint(timestamp)},
+				"SchemaVersion": &types.AttributeValueMemberN{Value: "99"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := unmarshalDynamoItem(tt.av)
+			if err != nil {
+				t.Fatalf("unmarshalDynamoItem() error = %v", err)
+			}
+
+			rate, err := dynamoItemToEntity(item)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dynamoItemToEntity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if rate.Base != "USD" || rate.Target != "EUR" {
+				t.Errorf("rate = %s/%s, want USD/EUR", rate.Base, rate.Target)
+			}
+			if rate.Rate != 0.85 {
+				t.Errorf("Rate = %v, want 0.85", rate.Rate)
+			}
+			if rate.Timestamp.Unix() != timestamp {
+				t.Errorf("Timestamp = %v, want %v", rate.Timestamp.Unix(), timestamp)
+			}
+			if item.SchemaVersion != currentSchemaVersion {
+				t.Errorf("SchemaVersion after read = %d, want %d", item.SchemaVersion, currentSchemaVersion)
+			}
+		})
+	}
+}
+
 func TestBuildPartitionKey(t *testing.T) {
 	base, _ := entity.NewCurrencyCode("USD")
 	target, _ := entity.NewCurrencyCode("EUR")
@@ -185,6 +271,18 @@ func TestBuildPartitionKey(t *testing.T) {
 			target: entity.CurrencyCode("JPY"),
 			want:   "RATE#GBP#JPY",
 		},
+		{
+			name:   "lowercase codes are normalized",
+			base:   entity.CurrencyCode("usd"),
+			target: entity.CurrencyCode("eur"),
+			want:   "RATE#USD#EUR",
+		},
+		{
+			name:   "mixed-case codes are normalized",
+			base:   entity.CurrencyCode("Usd"),
+			target: entity.CurrencyCode("eUR"),
+			want:   "RATE#USD#EUR",
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,6 +312,10 @@ func TestMarshalUnmarshalDynamoItem(t *testing.T) {
 		t.Fatalf("marshalDynamoItem() error = %v", err)
 	}
 
+	if _, ok := av["SchemaVersion"]; !ok {
+		t.Error("marshaled item is missing SchemaVersion attribute")
+	}
+
 	// Unmarshal
 	unmarshaledItem, err := unmarshalDynamoItem(av)
 	if err != nil {
@@ -233,6 +335,9 @@ func TestMarshalUnmarshalDynamoItem(t *testing.T) {
 	if unmarshaledItem.Rate != item.Rate {
 		t.Errorf("Rate = %v, want %v", unmarshaledItem.Rate, item.Rate)
 	}
+	if unmarshaledItem.SchemaVersion != currentSchemaVersion {
+		t.Errorf("SchemaVersion = %v, want %v", unmarshaledItem.SchemaVersion, currentSchemaVersion)
+	}
 }
 
 func TestMapDynamoDBError(t *testing.T) {
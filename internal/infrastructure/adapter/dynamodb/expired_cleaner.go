@@ -0,0 +1,304 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// cleanupCursorKey is the partition key of the record storing where the
+	// previous cleanup run stopped. It shares the table (single-table design).
+	cleanupCursorKey = "CLEANUP#cursor"
+
+	// maxBatchWriteItems is the DynamoDB limit of items per BatchWriteItem request.
+	maxBatchWriteItems = 25
+
+	// maxBatchWriteAttempts bounds retries of unprocessed items in a batch delete.
+	maxBatchWriteAttempts = 3
+
+	// batchWriteBaseBackoff and batchWriteMaxBackoff bound the wait before
+	// retrying unprocessed items (exponential, with full jitter).
+	batchWriteBaseBackoff = 50 * time.Millisecond
+	batchWriteMaxBackoff  = time.Second
+)
+
+// cleanerClient is the subset of the DynamoDB client used by ExpiredCleaner.
+// *dynamodb.Client satisfies this interface; tests can provide a mock.
+type cleanerClient interface {
+	itemClient
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// ExpiredCleanerConfig holds configuration for ExpiredCleaner.
+type ExpiredCleanerConfig struct {
+	MaxAge   time.Duration // Rates with a Timestamp older than this are deleted
+	MaxPages int           // Maximum scan pages per run, bounding the work per invocation
+	PageSize int32         // Items evaluated per scan page
+}
+
+// DefaultExpiredCleanerConfig returns the default cleaner configuration.
+//
+// Default values:
+// - MaxAge: 48 hours (DynamoDB TTL's worst-case deletion lag)
+// - MaxPages: 10
+// - PageSize: 100
+func DefaultExpiredCleanerConfig() ExpiredCleanerConfig {
+	return ExpiredCleanerConfig{
+		MaxAge:   48 * time.Hour,
+		MaxPages: 10,
+		PageSize: 100,
+	}
+}
+
+// CleanupResult summarizes a cleanup run.
+type CleanupResult struct {
+	Scanned  int  // Items evaluated by the scan
+	Deleted  int  // Rates deleted
+	Complete bool // Whether the run reached the end of the table
+}
+
+// ExpiredCleaner deletes exchange rates older than a threshold.
+//
+// It complements DynamoDB TTL for tables where TTL is not enabled or lags.
+// Each run scans at most MaxPages pages and stores the scan position in the
+// table, so the next run resumes where the previous one stopped. Once the end
+// of the table is reached, the next run starts over.
+//
+// Only RATE# items are deleted; idempotency records and the cursor are ignored.
+type ExpiredCleaner struct {
+	client    cleanerClient
+	tableName string
+	config    ExpiredCleanerConfig
+	now       func() time.Time
+}
+
+// NewExpiredCleaner creates a new ExpiredCleaner.
+//
+// Parameters:
+//   - client: The DynamoDB client
+//   - tableName: The name of the DynamoDB table to clean
+//   - config: Cleaner configuration (zero or negative values use defaults)
+func NewExpiredCleaner(client *dynamodb.Client, tableName string, config ExpiredCleanerConfig) *ExpiredCleaner {
+	return newExpiredCleaner(client, tableName, config, time.Now)
+}
+
+// newExpiredCleaner creates an ExpiredCleaner over any cleanerClient.
+func newExpiredCleaner(client cleanerClient, tableName string, config ExpiredCleanerConfig, now func() time.Time) *ExpiredCleaner {
+	defaults := DefaultExpiredCleanerConfig()
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaults.MaxAge
+	}
+	if config.MaxPages <= 0 {
+		config.MaxPages = defaults.MaxPages
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = defaults.PageSize
+	}
+	return &ExpiredCleaner{
+		client:    client,
+		tableName: tableName,
+		config:    config,
+		now:       now,
+	}
+}
+
+// buildExpiredScanInput builds a scan page request for rates older than cutoff.
+//
+// The filter is applied after items are read, so Limit bounds the items
+// evaluated per page rather than the rates returned.
+// Note: "Timestamp" is a reserved keyword in DynamoDB, so ExpressionAttributeNames is used.
+func buildExpiredScanInput(tableName string, cutoff time.Time, pageSize int32, startKey map[string]types.AttributeValue) *dynamodb.ScanInput {
+	return &dynamodb.ScanInput{
+		TableName:            aws.String(tableName),
+		FilterExpression:     aws.String("begins_with(PK, :prefix) AND #ts < :cutoff"),
+		ProjectionExpression: aws.String("PK"),
+		ExpressionAttributeNames: map[string]string{
+			"#ts": "Timestamp",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prefix": &types.AttributeValueMemberS{Value: "RATE#"},
+			":cutoff": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", cutoff.Unix())},
+		},
+		Limit:             aws.Int32(pageSize),
+		ExclusiveStartKey: startKey,
+	}
+}
+
+// Run deletes rates older than MaxAge, scanning at most MaxPages pages.
+//
+// Context cancellation: Returns error if ctx is cancelled. Rates deleted before
+// an error are included in the result, and the cursor is only advanced past
+// pages that were fully processed.
+func (c *ExpiredCleaner) Run(ctx context.Context) (CleanupResult, error) {
+	var result CleanupResult
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+
+	startKey, err := c.loadCursor(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	cutoff := c.now().Add(-c.config.MaxAge)
+	for page := 0; page < c.config.MaxPages; page++ {
+		output, err := c.client.Scan(ctx, buildExpiredScanInput(c.tableName, cutoff, c.config.PageSize, startKey))
+		if err != nil {
+			return result, mapDynamoDBError(err, "scan")
+		}
+		result.Scanned += int(output.ScannedCount)
+
+		deleted, err := c.deleteItems(ctx, output.Items)
+		result.Deleted += deleted
+		if err != nil {
+			return result, err
+		}
+
+		startKey = output.LastEvaluatedKey
+		if len(startKey) == 0 {
+			result.Complete = true
+			break
+		}
+	}
+
+	if err := c.saveCursor(ctx, startKey); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// deleteItems batch-deletes items by partition key, in chunks of maxBatchWriteItems.
+// Returns the number of items deleted.
+func (c *ExpiredCleaner) deleteItems(ctx context.Context, items []map[string]types.AttributeValue) (int, error) {
+	deleted := 0
+	for start := 0; start < len(items); start += maxBatchWriteItems {
+		end := min(start+maxBatchWriteItems, len(items))
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, item := range items[start:end] {
+			requests = append(requests, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{
+					Key: map[string]types.AttributeValue{"PK": item["PK"]},
+				},
+			})
+		}
+
+		n, err := c.batchDelete(ctx, requests)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// batchDelete sends one BatchWriteItem request, retrying unprocessed items
+// with exponential backoff and jitter (see unprocessedBackoff).
+// Returns the number of items deleted.
+func (c *ExpiredCleaner) batchDelete(ctx context.Context, requests []types.WriteRequest) (int, error) {
+	total := len(requests)
+	for attempt := 0; attempt < maxBatchWriteAttempts && len(requests) > 0; attempt++ {
+		if attempt > 0 {
+			// Unprocessed items mean the table is throttling; back off before retrying
+			if err := sleepContext(ctx, unprocessedBackoff(attempt)); err != nil {
+				return total - len(requests), err
+			}
+		}
+		output, err := c.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{c.tableName: requests},
+		})
+		if err != nil {
+			return total - len(requests), mapDynamoDBError(err, "batch delete")
+		}
+		requests = output.UnprocessedItems[c.tableName]
+	}
+	if len(requests) > 0 {
+		return total - len(requests), fmt.Errorf("dynamodb batch delete left %d items unprocessed after %d attempts", len(requests), maxBatchWriteAttempts)
+	}
+	return total, nil
+}
+
+// unprocessedBackoff returns the wait before retry attempt n (n >= 1) of unprocessed items.
+//
+// The wait is drawn uniformly from [0, base*2^(n-1)], capped at batchWriteMaxBackoff
+// ("full jitter"), so concurrent cleaners do not retry in lockstep.
+func unprocessedBackoff(attempt int) time.Duration {
+	limit := batchWriteBaseBackoff << (attempt - 1)
+	if limit <= 0 || limit > batchWriteMaxBackoff {
+		limit = batchWriteMaxBackoff
+	}
+	return rand.N(limit + 1)
+}
+
+// sleepContext waits for d, or returns the context's error if ctx ends first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// loadCursor returns the scan position stored by the previous run, or nil to
+// start from the beginning of the table.
+func (c *ExpiredCleaner) loadCursor(ctx context.Context) (map[string]types.AttributeValue, error) {
+	output, err := c.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key:       cleanupCursorItemKey(),
+	})
+	if err != nil {
+		return nil, mapDynamoDBError(err, "get cleanup cursor")
+	}
+
+	lastKey, ok := output.Item["LastKey"].(*types.AttributeValueMemberS)
+	if !ok || lastKey.Value == "" {
+		return nil, nil
+	}
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: lastKey.Value},
+	}, nil
+}
+
+// saveCursor stores the scan position for the next run. A nil position clears
+// the cursor so the next run starts from the beginning of the table.
+func (c *ExpiredCleaner) saveCursor(ctx context.Context, startKey map[string]types.AttributeValue) error {
+	lastKey, ok := startKey["PK"].(*types.AttributeValueMemberS)
+	if !ok {
+		if _, err := c.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(c.tableName),
+			Key:       cleanupCursorItemKey(),
+		}); err != nil {
+			return mapDynamoDBError(err, "delete cleanup cursor")
+		}
+		return nil
+	}
+
+	if _, err := c.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(c.tableName),
+		Item: map[string]types.AttributeValue{
+			"PK":      &types.AttributeValueMemberS{Value: cleanupCursorKey},
+			"LastKey": lastKey,
+		},
+	}); err != nil {
+		return mapDynamoDBError(err, "put cleanup cursor")
+	}
+	return nil
+}
+
+// cleanupCursorItemKey returns the key of the cleanup cursor record.
+func cleanupCursorItemKey() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: cleanupCursorKey},
+	}
+}
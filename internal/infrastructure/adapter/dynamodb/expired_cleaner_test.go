@@ -0,0 +1,275 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// mockCleanerClient serves scan pages from an ordered list of partition keys
+// and records deletes. Every key is treated as expired; filtering is DynamoDB's job.
+type mockCleanerClient struct {
+	*mockItemClient
+	keys          []string
+	scans         []*dynamodb.ScanInput
+	deleted       []string
+	batchSizes    []int
+	unprocessOnce bool
+	batchErr      error
+}
+
+func newMockCleanerClient(n int) *mockCleanerClient {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("RATE#USD#%03d", i)
+	}
+	return &mockCleanerClient{mockItemClient: newMockItemClient(), keys: keys}
+}
+
+func (m *mockCleanerClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	m.scans = append(m.scans, params)
+
+	start := 0
+	if pk, ok := params.ExclusiveStartKey["PK"].(*types.AttributeValueMemberS); ok {
+		start = sort.SearchStrings(m.keys, pk.Value) + 1
+	}
+	end := min(start+int(aws.ToInt32(params.Limit)), len(m.keys))
+
+	output := &dynamodb.ScanOutput{ScannedCount: int32(end - start)}
+	for _, key := range m.keys[start:end] {
+		output.Items = append(output.Items, map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: key},
+		})
+	}
+	if end < len(m.keys) {
+		output.LastEvaluatedKey = map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: m.keys[end-1]},
+		}
+	}
+	return output, nil
+}
+
+func (m *mockCleanerClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if m.batchErr != nil {
+		return nil, m.batchErr
+	}
+	for table, requests := range params.RequestItems {
+		m.batchSizes = append(m.batchSizes, len(requests))
+		// Optionally leave the last item unprocessed once, like a throttled batch
+		if m.unprocessOnce && len(requests) > 1 {
+			m.unprocessOnce = false
+			m.record(requests[:len(requests)-1])
+			output := &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}
+			output.UnprocessedItems[table] = requests[len(requests)-1:]
+			return output, nil
+		}
+		m.record(requests)
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func (m *mockCleanerClient) record(requests []types.WriteRequest) {
+	for _, request := range requests {
+		m.deleted = append(m.deleted, request.DeleteRequest.Key["PK"].(*types.AttributeValueMemberS).Value)
+	}
+}
+
+func (m *mockCleanerClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	delete(m.items, params.Key["PK"].(*types.AttributeValueMemberS).Value)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (m *mockCleanerClient) cursor() string {
+	item, ok := m.items[cleanupCursorKey]
+	if !ok {
+		return ""
+	}
+	return item["LastKey"].(*types.AttributeValueMemberS).Value
+}
+
+func TestBuildExpiredScanInput(t *testing.T) {
+	cutoff := time.Unix(1700000000, 0)
+	startKey := map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "RATE#USD#EUR"}}
+
+	input := buildExpiredScanInput("TestTable", cutoff, 50, startKey)
+
+	if got := aws.ToString(input.TableName); got != "TestTable" {
+		t.Errorf("TableName = %s, want TestTable", got)
+	}
+	if got := aws.ToString(input.FilterExpression); got != "begins_with(PK, :prefix) AND #ts < :cutoff" {
+		t.Errorf("FilterExpression = %s", got)
+	}
+	if got := input.ExpressionAttributeNames["#ts"]; got != "Timestamp" {
+		t.Errorf("#ts = %s, want Timestamp", got)
+	}
+	if got := input.ExpressionAttributeValues[":prefix"].(*types.AttributeValueMemberS).Value; got != "RATE#" {
+		t.Errorf(":prefix = %s, want RATE#", got)
+	}
+	if got := input.ExpressionAttributeValues[":cutoff"].(*types.AttributeValueMemberN).Value; got != "1700000000" {
+		t.Errorf(":cutoff = %s, want 1700000000", got)
+	}
+	if got := aws.ToInt32(input.Limit); got != 50 {
+		t.Errorf("Limit = %d, want 50", got)
+	}
+	if input.ExclusiveStartKey["PK"].(*types.AttributeValueMemberS).Value != "RATE#USD#EUR" {
+		t.Error("ExclusiveStartKey not passed through")
+	}
+}
+
+func TestExpiredCleaner_Run_CutoffFromMaxAge(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	client := newMockCleanerClient(1)
+	cleaner := newExpiredCleaner(client, "TestTable", ExpiredCleanerConfig{MaxAge: time.Hour}, func() time.Time { return now })
+
+	if _, err := cleaner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := fmt.Sprintf("%d", now.Add(-time.Hour).Unix())
+	if got := client.scans[0].ExpressionAttributeValues[":cutoff"].(*types.AttributeValueMemberN).Value; got != want {
+		t.Errorf(":cutoff = %s, want %s", got, want)
+	}
+}
+
+func TestExpiredCleaner_Run_PaginatesAndResumes(t *testing.T) {
+	client := newMockCleanerClient(25)
+	cleaner := newExpiredCleaner(client, "TestTable", ExpiredCleanerConfig{MaxAge: time.Hour, MaxPages: 2, PageSize: 10}, time.Now)
+	ctx := context.Background()
+
+	// First run stops after two pages and stores the position
+	result, err := cleaner.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Deleted != 20 || result.Scanned != 20 || result.Complete {
+		t.Errorf("first run = %+v, want 20 deleted, 20 scanned, incomplete", result)
+	}
+	if got := client.cursor(); got != "RATE#USD#019" {
+		t.Errorf("cursor after first run = %q, want RATE#USD#019", got)
+	}
+
+	// Second run resumes from the cursor and reaches the end
+	result, err = cleaner.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Deleted != 5 || !result.Complete {
+		t.Errorf("second run = %+v, want 5 deleted, complete", result)
+	}
+	if len(client.scans) != 3 {
+		t.Errorf("scans = %d, want 3", len(client.scans))
+	}
+	if client.cursor() != "" {
+		t.Errorf("cursor after complete run = %q, want cleared", client.cursor())
+	}
+	if len(client.deleted) != 25 {
+		t.Errorf("deleted %d items, want 25", len(client.deleted))
+	}
+
+	// A run after completion starts over from the beginning
+	if _, err := cleaner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if client.scans[3].ExclusiveStartKey != nil {
+		t.Error("run after completion did not start from the beginning")
+	}
+}
+
+func TestExpiredCleaner_Run_BatchesOf25(t *testing.T) {
+	client := newMockCleanerClient(60)
+	cleaner := newExpiredCleaner(client, "TestTable", ExpiredCleanerConfig{MaxAge: time.Hour, MaxPages: 1, PageSize: 60}, time.Now)
+
+	result, err := cleaner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Deleted != 60 {
+		t.Errorf("Deleted = %d, want 60", result.Deleted)
+	}
+	want := []int{25, 25, 10}
+	if fmt.Sprint(client.batchSizes) != fmt.Sprint(want) {
+		t.Errorf("batch sizes = %v, want %v", client.batchSizes, want)
+	}
+}
+
+func TestExpiredCleaner_Run_RetriesUnprocessedItems(t *testing.T) {
+	client := newMockCleanerClient(5)
+	client.unprocessOnce = true
+	cleaner := newExpiredCleaner(client, "TestTable", ExpiredCleanerConfig{MaxAge: time.Hour}, time.Now)
+
+	result, err := cleaner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Deleted != 5 || len(client.deleted) != 5 {
+		t.Errorf("Deleted = %d (recorded %d), want 5", result.Deleted, len(client.deleted))
+	}
+	if fmt.Sprint(client.batchSizes) != "[5 1]" {
+		t.Errorf("batch sizes = %v, want [5 1]", client.batchSizes)
+	}
+}
+
+func TestUnprocessedBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		limit   time.Duration
+	}{
+		{1, batchWriteBaseBackoff},
+		{2, 2 * batchWriteBaseBackoff},
+		{3, 4 * batchWriteBaseBackoff},
+		{10, batchWriteMaxBackoff},
+		{100, batchWriteMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if got := unprocessedBackoff(tt.attempt); got < 0 || got > tt.limit {
+				t.Fatalf("unprocessedBackoff(%d) = %v, want within [0, %v]", tt.attempt, got, tt.limit)
+			}
+		}
+	}
+}
+
+func TestExpiredCleaner_BatchDelete_CanceledDuringBackoff(t *testing.T) {
+	client := newMockCleanerClient(5)
+	client.unprocessOnce = true
+	cleaner := newExpiredCleaner(client, "TestTable", ExpiredCleanerConfig{MaxAge: time.Hour}, time.Now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n, err := cleaner.batchDelete(ctx, []types.WriteRequest{
+		{DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "RATE#USD#000"}}}},
+		{DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "RATE#USD#001"}}}},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("batchDelete() error = %v, want context.Canceled", err)
+	}
+	if n != 1 || len(client.batchSizes) != 1 {
+		t.Errorf("deleted = %d after %d requests, want 1 deleted without a retry", n, len(client.batchSizes))
+	}
+}
+
+func TestExpiredCleaner_Run_BatchErrorKeepsCursor(t *testing.T) {
+	client := newMockCleanerClient(5)
+	client.items[cleanupCursorKey] = map[string]types.AttributeValue{
+		"PK":      &types.AttributeValueMemberS{Value: cleanupCursorKey},
+		"LastKey": &types.AttributeValueMemberS{Value: "RATE#USD#001"},
+	}
+	client.batchErr = errors.New("throttled")
+	cleaner := newExpiredCleaner(client, "TestTable", ExpiredCleanerConfig{MaxAge: time.Hour}, time.Now)
+
+	if _, err := cleaner.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "batch delete") {
+		t.Fatalf("Run() error = %v, want batch delete error", err)
+	}
+	// The failed page is retried on the next run
+	if got := client.cursor(); got != "RATE#USD#001" {
+		t.Errorf("cursor = %q, want unchanged RATE#USD#001", got)
+	}
+}
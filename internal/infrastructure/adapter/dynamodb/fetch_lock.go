@@ -0,0 +1,161 @@
+package dynamodb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+)
+
+// DefaultFetchLockTTL is the default lease of a fetch lock.
+const DefaultFetchLockTTL = 10 * time.Second
+
+// lockClient is the subset of the DynamoDB client used by FetchLock.
+// *dynamodb.Client satisfies this interface; tests can provide a mock.
+type lockClient interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// FetchLock is a distributed repository.FetchLock backed by DynamoDB.
+//
+// Locks share the exchange rate table (single-table design) under the
+// LOCK#{BASE}#{TARGET} partition key. A lock is taken with a conditional put
+// that succeeds only if no lock exists or the existing one has expired, so an
+// expired lock is taken over even before DynamoDB TTL deletes it.
+//
+// Each FetchLock has a random owner ID; Release only deletes locks it owns.
+type FetchLock struct {
+	client    lockClient
+	tableName string
+	ttl       time.Duration
+	owner     string
+	now       func() time.Time
+}
+
+// NewFetchLock creates a new FetchLock.
+//
+// Parameters:
+//   - client: The DynamoDB client
+//   - tableName: The name of the DynamoDB table to use
+//   - ttl: Lease of a lock, after which another instance may take it (default: DefaultFetchLockTTL)
+func NewFetchLock(client *dynamodb.Client, tableName string, ttl time.Duration) *FetchLock {
+	return newFetchLock(client, tableName, ttl)
+}
+
+// newFetchLock creates a FetchLock over any lockClient.
+func newFetchLock(client lockClient, tableName string, ttl time.Duration) *FetchLock {
+	if ttl <= 0 {
+		ttl = DefaultFetchLockTTL
+	}
+	return &FetchLock{
+		client:    client,
+		tableName: tableName,
+		ttl:       ttl,
+		owner:     newLockOwner(),
+		now:       time.Now,
+	}
+}
+
+// newLockOwner returns a random owner ID for lock items.
+func newLockOwner() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to the clock
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// buildLockKey creates the partition key of a pair's fetch lock.
+//
+// Format: LOCK#{BASE}#{TARGET}
+func buildLockKey(base, target entity.CurrencyCode) string {
+	return fmt.Sprintf("LOCK#%s#%s", base.Normalize(), target.Normalize())
+}
+
+// Acquire implements repository.FetchLock.
+//
+// This method:
+// - Puts a lock item with this FetchLock's owner and an expiry of now + ttl
+// - Succeeds only if no lock exists or the existing lock has expired
+// - Returns false (without an error) if another holder's lock is still valid
+//
+// The expiry is stored in the ttl attribute, so DynamoDB TTL removes stale locks.
+//
+// Context cancellation: Returns error if ctx is cancelled.
+func (l *FetchLock) Acquire(ctx context.Context, base, target entity.CurrencyCode) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	now := l.now()
+	_, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item: map[string]types.AttributeValue{
+			"PK":        &types.AttributeValueMemberS{Value: buildLockKey(base, target)},
+			"Owner":     &types.AttributeValueMemberS{Value: l.owner},
+			"CreatedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			"ttl":       &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(l.ttl).Unix(), 10)},
+		},
+		ConditionExpression:      aws.String("attribute_not_exists(PK) OR #ttl <= :now"),
+		ExpressionAttributeNames: map[string]string{"#ttl": "ttl"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false, nil
+		}
+		return false, mapDynamoDBError(err, "acquire fetch lock")
+	}
+	return true, nil
+}
+
+// Release implements repository.FetchLock.
+//
+// The lock item is deleted only if this FetchLock still owns it; a lock that
+// expired and was taken over by another instance is left alone.
+//
+// Context cancellation: Returns error if ctx is cancelled.
+func (l *FetchLock) Release(ctx context.Context, base, target entity.CurrencyCode) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	_, err := l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: buildLockKey(base, target)},
+		},
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner": "Owner",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner": &types.AttributeValueMemberS{Value: l.owner},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return nil
+		}
+		return mapDynamoDBError(err, "release fetch lock")
+	}
+	return nil
+}
+
+// Ensure FetchLock implements repository.FetchLock.
+var _ repository.FetchLock = (*FetchLock)(nil)
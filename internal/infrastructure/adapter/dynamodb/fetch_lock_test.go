@@ -0,0 +1,181 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// mockLockClient is an in-memory lockClient that evaluates the conditions
+// used by FetchLock atomically, like DynamoDB does.
+type mockLockClient struct {
+	mu    sync.Mutex
+	items map[string]map[string]types.AttributeValue
+	err   error
+}
+
+func newMockLockClient() *mockLockClient {
+	return &mockLockClient{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func attrNumber(av types.AttributeValue) int64 {
+	n, _ := strconv.ParseInt(av.(*types.AttributeValueMemberN).Value, 10, 64)
+	return n
+}
+
+func attrString(av types.AttributeValue) string {
+	return av.(*types.AttributeValueMemberS).Value
+}
+
+func (m *mockLockClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if aws.ToString(params.ConditionExpression) != "attribute_not_exists(PK) OR #ttl <= :now" {
+		return nil, errors.New("unexpected condition expression")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pk := attrString(params.Item["PK"])
+	if existing, ok := m.items[pk]; ok && attrNumber(existing["ttl"]) > attrNumber(params.ExpressionAttributeValues[":now"]) {
+		return nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	}
+	m.items[pk] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockLockClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pk := attrString(params.Key["PK"])
+	existing, ok := m.items[pk]
+	if !ok || attrString(existing["Owner"]) != attrString(params.ExpressionAttributeValues[":owner"]) {
+		return nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	}
+	delete(m.items, pk)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestBuildLockKey(t *testing.T) {
+	if got := buildLockKey("usd", "EUR"); got != "LOCK#USD#EUR" {
+		t.Errorf("buildLockKey() = %q, want LOCK#USD#EUR", got)
+	}
+}
+
+func TestFetchLock_ConcurrentAcquire(t *testing.T) {
+	client := newMockLockClient()
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	// One FetchLock per simulated instance
+	const instances = 20
+	var acquired atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < instances; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock := newFetchLock(client, "TestTable", time.Minute)
+			ok, err := lock.Acquire(context.Background(), base, target)
+			if err != nil {
+				t.Errorf("Acquire() error = %v", err)
+				return
+			}
+			if ok {
+				acquired.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := acquired.Load(); got != 1 {
+		t.Errorf("acquired by %d instances, want 1", got)
+	}
+}
+
+func TestFetchLock_ReleaseAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	client := newMockLockClient()
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	now := time.Now()
+	first := newFetchLock(client, "TestTable", 10*time.Second)
+	second := newFetchLock(client, "TestTable", 10*time.Second)
+	first.now = func() time.Time { return now }
+	second.now = func() time.Time { return now }
+
+	if ok, err := first.Acquire(ctx, base, target); err != nil || !ok {
+		t.Fatalf("first Acquire() = %v, %v, want true", ok, err)
+	}
+	if ok, _ := second.Acquire(ctx, base, target); ok {
+		t.Fatal("second Acquire() = true while the lock is held, want false")
+	}
+
+	// Another pair is independent
+	gbp, _ := entity.NewCurrencyCode("GBP")
+	if ok, _ := second.Acquire(ctx, base, gbp); !ok {
+		t.Error("Acquire(USD, GBP) = false, want true")
+	}
+
+	// Releasing someone else's lock is a no-op
+	if err := second.Release(ctx, base, target); err != nil {
+		t.Errorf("Release() by non-owner error = %v, want nil", err)
+	}
+	if ok, _ := second.Acquire(ctx, base, target); ok {
+		t.Fatal("Acquire() = true after a non-owner Release, want false")
+	}
+
+	// An expired lock is taken over before DynamoDB TTL deletes it
+	second.now = func() time.Time { return now.Add(11 * time.Second) }
+	if ok, err := second.Acquire(ctx, base, target); err != nil || !ok {
+		t.Fatalf("Acquire() after expiry = %v, %v, want true", ok, err)
+	}
+
+	// The previous holder's late Release does not drop the new holder's lock
+	if err := first.Release(ctx, base, target); err != nil {
+		t.Errorf("late Release() error = %v, want nil", err)
+	}
+	first.now = second.now
+	if ok, _ := first.Acquire(ctx, base, target); ok {
+		t.Error("Acquire() = true after a late Release, want false")
+	}
+
+	// The owner's Release frees the lock
+	if err := second.Release(ctx, base, target); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if ok, _ := first.Acquire(ctx, base, target); !ok {
+		t.Error("Acquire() = false after Release, want true")
+	}
+}
+
+func TestFetchLock_ClientError(t *testing.T) {
+	client := newMockLockClient()
+	client.err = errors.New("throttled")
+	lock := newFetchLock(client, "TestTable", 0)
+
+	if lock.ttl != DefaultFetchLockTTL {
+		t.Errorf("ttl = %v, want %v", lock.ttl, DefaultFetchLockTTL)
+	}
+	if ok, err := lock.Acquire(context.Background(), "USD", "EUR"); err == nil || ok {
+		t.Errorf("Acquire() = %v, %v, want false and an error", ok, err)
+	}
+	if err := lock.Release(context.Background(), "USD", "EUR"); err == nil {
+		t.Error("Release() error = nil, want error")
+	}
+}
@@ -0,0 +1,160 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxIdempotencyKeyLength limits the size of client-provided idempotency keys.
+const maxIdempotencyKeyLength = 255
+
+// itemClient is the subset of the DynamoDB client used for single-item access.
+// *dynamodb.Client satisfies this interface; tests can provide a mock.
+type itemClient interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+}
+
+// IdempotencyStore stores results of mutating operations keyed by a client-provided
+// idempotency key, so that retried requests can replay the stored result instead
+// of re-executing the operation.
+//
+// Records share the exchange rate table (single-table design) under the
+// IDEMPOTENCY# partition key prefix and expire via DynamoDB TTL.
+type IdempotencyStore struct {
+	client    itemClient
+	tableName string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// idempotencyItem represents a stored idempotency record.
+type idempotencyItem struct {
+	PK        string `dynamodbav:"PK"`        // Partition key: IDEMPOTENCY#{key}
+	Payload   []byte `dynamodbav:"Payload"`   // Stored operation result
+	CreatedAt int64  `dynamodbav:"CreatedAt"` // Unix timestamp in seconds
+	TTL       int64  `dynamodbav:"ttl"`       // Expiration (Unix epoch in seconds)
+}
+
+// NewIdempotencyStore creates a new IdempotencyStore.
+//
+// Parameters:
+//   - client: The DynamoDB client
+//   - tableName: The name of the DynamoDB table to use
+//   - ttl: How long stored results are replayed (default: 1 hour)
+func NewIdempotencyStore(client *dynamodb.Client, tableName string, ttl time.Duration) *IdempotencyStore {
+	return newIdempotencyStore(client, tableName, ttl)
+}
+
+// newIdempotencyStore creates an IdempotencyStore over any itemClient.
+func newIdempotencyStore(client itemClient, tableName string, ttl time.Duration) *IdempotencyStore {
+	if ttl <= 0 {
+		ttl = 1 * time.Hour // default
+	}
+	return &IdempotencyStore{
+		client:    client,
+		tableName: tableName,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+// buildIdempotencyKey creates a partition key for an idempotency key.
+//
+// Format: IDEMPOTENCY#{key}
+func buildIdempotencyKey(key string) string {
+	return fmt.Sprintf("IDEMPOTENCY#%s", key)
+}
+
+// validateIdempotencyKey checks that a client-provided key is usable.
+func validateIdempotencyKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("idempotency key cannot be empty")
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key must be at most %d characters, got %d", maxIdempotencyKeyLength, len(key))
+	}
+	return nil
+}
+
+// Get retrieves the stored result for an idempotency key.
+//
+// Returns found=false if no record exists or the record has expired.
+// Expiration is checked explicitly because DynamoDB TTL deletion is not immediate.
+//
+// Context cancellation: Returns error if ctx is cancelled.
+func (s *IdempotencyStore) Get(ctx context.Context, key string) (payload []byte, found bool, err error) {
+	if ctx.Err() != nil {
+		return nil, false, ctx.Err()
+	}
+
+	if err := validateIdempotencyKey(key); err != nil {
+		return nil, false, err
+	}
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: buildIdempotencyKey(key)},
+		},
+	})
+	if err != nil {
+		return nil, false, mapDynamoDBError(err, "get idempotency record")
+	}
+
+	if result.Item == nil {
+		return nil, false, nil
+	}
+
+	var item idempotencyItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+
+	// TTL deletion is eventually consistent - treat expired records as missing
+	if !s.now().Before(time.Unix(item.TTL, 0)) {
+		return nil, false, nil
+	}
+
+	return item.Payload, true, nil
+}
+
+// Save stores the result for an idempotency key with the configured TTL.
+//
+// Context cancellation: Returns error if ctx is cancelled.
+func (s *IdempotencyStore) Save(ctx context.Context, key string, payload []byte) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := validateIdempotencyKey(key); err != nil {
+		return err
+	}
+
+	now := s.now()
+	av, err := attributevalue.MarshalMap(idempotencyItem{
+		PK:        buildIdempotencyKey(key),
+		Payload:   payload,
+		CreatedAt: now.Unix(),
+		TTL:       now.Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      av,
+	})
+	if err != nil {
+		return mapDynamoDBError(err, "put idempotency record")
+	}
+
+	return nil
+}
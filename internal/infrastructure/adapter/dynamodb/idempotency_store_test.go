@@ -0,0 +1,101 @@
+package dynamodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// mockItemClient is an in-memory implementation of itemClient for testing.
+type mockItemClient struct {
+	items map[string]map[string]types.AttributeValue
+}
+
+func newMockItemClient() *mockItemClient {
+	return &mockItemClient{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func (m *mockItemClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	pk := params.Key["PK"].(*types.AttributeValueMemberS).Value
+	return &dynamodb.GetItemOutput{Item: m.items[pk]}, nil
+}
+
+func (m *mockItemClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	pk := params.Item["PK"].(*types.AttributeValueMemberS).Value
+	m.items[pk] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func TestIdempotencyStore_SaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	store := newIdempotencyStore(newMockItemClient(), "TestTable", 10*time.Minute)
+
+	// First call: nothing stored yet
+	_, found, err := store.Get(ctx, "key-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if found {
+		t.Fatal("Get() found = true before Save, want false")
+	}
+
+	if err := store.Save(ctx, "key-1", []byte(`{"status":"ok"}`)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Replay: stored payload is returned
+	payload, found, err := store.Get(ctx, "key-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !found {
+		t.Fatal("Get() found = false after Save, want true")
+	}
+	if string(payload) != `{"status":"ok"}` {
+		t.Errorf("Get() payload = %s, want {\"status\":\"ok\"}", payload)
+	}
+}
+
+func TestIdempotencyStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := newIdempotencyStore(newMockItemClient(), "TestTable", 10*time.Minute)
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	if err := store.Save(ctx, "key-1", []byte("result")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Still within TTL
+	store.now = func() time.Time { return now.Add(5 * time.Minute) }
+	if _, found, _ := store.Get(ctx, "key-1"); !found {
+		t.Error("Get() found = false within TTL, want true")
+	}
+
+	// Past TTL but not yet deleted by DynamoDB - must be treated as missing
+	store.now = func() time.Time { return now.Add(11 * time.Minute) }
+	if _, found, _ := store.Get(ctx, "key-1"); found {
+		t.Error("Get() found = true after TTL, want false")
+	}
+}
+
+func TestIdempotencyStore_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	store := newIdempotencyStore(newMockItemClient(), "TestTable", 0)
+
+	if store.ttl != 1*time.Hour {
+		t.Errorf("ttl = %v, want default 1h", store.ttl)
+	}
+
+	if _, _, err := store.Get(ctx, ""); err == nil {
+		t.Error("Get() with empty key: expected error, got nil")
+	}
+	if err := store.Save(ctx, strings.Repeat("k", maxIdempotencyKeyLength+1), nil); err == nil {
+		t.Error("Save() with oversized key: expected error, got nil")
+	}
+}
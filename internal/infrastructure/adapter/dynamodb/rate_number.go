@@ -0,0 +1,41 @@
+package dynamodb
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// rateSignificantDigits is the number of significant digits written for a rate.
+// 17 digits uniquely identify every float64, so ParseFloat restores the exact value.
+const rateSignificantDigits = 17
+
+// rateNumber is a float64 stored as a DynamoDB Number with fixed precision.
+//
+// The SDK's default float encoding depends on its formatting choices; writing
+// a fixed number of significant digits guarantees an exact round-trip of the
+// stored rate regardless of how the value was produced.
+type rateNumber float64
+
+// MarshalDynamoDBAttributeValue implements attributevalue.Marshaler.
+func (r rateNumber) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return &types.AttributeValueMemberN{
+		Value: strconv.FormatFloat(float64(r), 'g', rateSignificantDigits, 64),
+	}, nil
+}
+
+// UnmarshalDynamoDBAttributeValue implements attributevalue.Unmarshaler.
+// Numbers written with any precision, including by older versions, are accepted.
+func (r *rateNumber) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	number, ok := av.(*types.AttributeValueMemberN)
+	if !ok {
+		return fmt.Errorf("rate must be a DynamoDB number, got %T", av)
+	}
+	parsed, err := strconv.ParseFloat(number.Value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid rate %q: %w", number.Value, err)
+	}
+	*r = rateNumber(parsed)
+	return nil
+}
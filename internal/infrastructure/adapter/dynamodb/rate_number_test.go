@@ -0,0 +1,117 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestRateNumber_RoundTrip(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	for _, value := range []float64{0.1, 1.0 / 3, 0.000012345678901234567, 1e-12, 123456.789012345678, 1e12} {
+		rate, err := entity.NewExchangeRate(base, target, value, time.Now(), false)
+		if err != nil {
+			t.Fatalf("NewExchangeRate(%v) error = %v", value, err)
+		}
+		item, err := entityToDynamoItem(rate, time.Hour)
+		if err != nil {
+			t.Fatalf("entityToDynamoItem() error = %v", err)
+		}
+
+		av, err := marshalDynamoItem(item)
+		if err != nil {
+			t.Fatalf("marshalDynamoItem() error = %v", err)
+		}
+		unmarshaled, err := unmarshalDynamoItem(av)
+		if err != nil {
+			t.Fatalf("unmarshalDynamoItem() error = %v", err)
+		}
+
+		if got := float64(unmarshaled.Rate); got != value {
+			t.Errorf("round-trip of %v = %v, want exact equality", value, got)
+		}
+	}
+}
+
+func TestRateNumber_MarshalPrecision(t *testing.T) {
+	av, err := rateNumber(0.1).MarshalDynamoDBAttributeValue()
+	if err != nil {
+		t.Fatalf("MarshalDynamoDBAttributeValue() error = %v", err)
+	}
+	number, ok := av.(*types.AttributeValueMemberN)
+	if !ok {
+		t.Fatalf("MarshalDynamoDBAttributeValue() = %T, want number", av)
+	}
+	if number.Value != "0.10000000000000001" {
+		t.Errorf("marshaled 0.1 = %q, want 17 significant digits", number.Value)
+	}
+}
+
+func TestRateNumber_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		av      types.AttributeValue
+		want    float64
+		wantErr bool
+	}{
+		{name: "short form written by older versions", av: &types.AttributeValueMemberN{Value: "0.85"}, want: 0.85},
+		{name: "full precision", av: &types.AttributeValueMemberN{Value: "0.10000000000000001"}, want: 0.1},
+		{name: "exponent", av: &types.AttributeValueMemberN{Value: "1E-12"}, want: 1e-12},
+		{name: "string attribute", av: &types.AttributeValueMemberS{Value: "0.85"}, wantErr: true},
+		{name: "malformed number", av: &types.AttributeValueMemberN{Value: "abc"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r rateNumber
+			err := r.UnmarshalDynamoDBAttributeValue(tt.av)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalDynamoDBAttributeValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && float64(r) != tt.want {
+				t.Errorf("UnmarshalDynamoDBAttributeValue() = %v, want %v", float64(r), tt.want)
+			}
+		})
+	}
+}
+
+func TestDynamoItem_BidAskRoundTrip(t *testing.T) {
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+	bid, ask := 0.1, 0.30000000000000004
+	rate, _ := entity.NewExchangeRate(base, target, 0.2, time.Now(), false)
+	if err := rate.SetBidAsk(&bid, &ask); err != nil {
+		t.Fatalf("SetBidAsk() error = %v", err)
+	}
+
+	item, _ := entityToDynamoItem(rate, time.Hour)
+	av, err := marshalDynamoItem(item)
+	if err != nil {
+		t.Fatalf("marshalDynamoItem() error = %v", err)
+	}
+	unmarshaled, err := unmarshalDynamoItem(av)
+	if err != nil {
+		t.Fatalf("unmarshalDynamoItem() error = %v", err)
+	}
+	got, err := dynamoItemToEntity(unmarshaled)
+	if err != nil {
+		t.Fatalf("dynamoItemToEntity() error = %v", err)
+	}
+	if got.Bid == nil || *got.Bid != bid || got.Ask == nil || *got.Ask != ask {
+		t.Errorf("Bid/Ask = %v/%v, want %v/%v", got.Bid, got.Ask, bid, ask)
+	}
+
+	// Rates without a spread do not write the attributes
+	plain, _ := entity.NewExchangeRate(base, target, 0.2, time.Now(), false)
+	item, _ = entityToDynamoItem(plain, time.Hour)
+	av, _ = marshalDynamoItem(item)
+	for _, name := range []string{"Bid", "Ask"} {
+		if _, ok := av[name]; ok {
+			t.Errorf("marshaled item has %s attribute, want omitted", name)
+		}
+	}
+}
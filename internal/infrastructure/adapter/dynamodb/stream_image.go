@@ -0,0 +1,93 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// ErrNotRateItem indicates a stream image that is not an exchange rate item
+// (e.g. an idempotency record sharing the table).
+var ErrNotRateItem = errors.New("stream image is not an exchange rate item")
+
+// StreamImageToEntity converts a DynamoDB Streams image (NewImage or OldImage)
+// to a domain entity.
+//
+// This function:
+// - Converts Lambda stream attribute values to SDK attribute values
+// - Reuses unmarshalDynamoItem and dynamoItemToEntity for decoding and validation
+// - Returns ErrNotRateItem for items outside the RATE# key space
+func StreamImageToEntity(image map[string]events.DynamoDBAttributeValue) (*entity.ExchangeRate, error) {
+	if len(image) == 0 {
+		return nil, fmt.Errorf("stream image cannot be empty")
+	}
+
+	av := make(map[string]types.AttributeValue, len(image))
+	for name, value := range image {
+		converted, err := streamAttributeToAttributeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert attribute %q: %w", name, err)
+		}
+		av[name] = converted
+	}
+
+	item, err := unmarshalDynamoItem(av)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(item.PK, "RATE#") {
+		return nil, fmt.Errorf("%w: %s", ErrNotRateItem, item.PK)
+	}
+
+	return dynamoItemToEntity(item)
+}
+
+// streamAttributeToAttributeValue converts a Lambda stream attribute value to
+// the equivalent SDK attribute value.
+func streamAttributeToAttributeValue(value events.DynamoDBAttributeValue) (types.AttributeValue, error) {
+	switch value.DataType() {
+	case events.DataTypeString:
+		return &types.AttributeValueMemberS{Value: value.String()}, nil
+	case events.DataTypeNumber:
+		return &types.AttributeValueMemberN{Value: value.Number()}, nil
+	case events.DataTypeBoolean:
+		return &types.AttributeValueMemberBOOL{Value: value.Boolean()}, nil
+	case events.DataTypeNull:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case events.DataTypeBinary:
+		return &types.AttributeValueMemberB{Value: value.Binary()}, nil
+	case events.DataTypeStringSet:
+		return &types.AttributeValueMemberSS{Value: value.StringSet()}, nil
+	case events.DataTypeNumberSet:
+		return &types.AttributeValueMemberNS{Value: value.NumberSet()}, nil
+	case events.DataTypeBinarySet:
+		return &types.AttributeValueMemberBS{Value: value.BinarySet()}, nil
+	case events.DataTypeList:
+		list := make([]types.AttributeValue, 0, len(value.List()))
+		for _, element := range value.List() {
+			converted, err := streamAttributeToAttributeValue(element)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case events.DataTypeMap:
+		m := make(map[string]types.AttributeValue, len(value.Map()))
+		for name, element := range value.Map() {
+			converted, err := streamAttributeToAttributeValue(element)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = converted
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	default:
+		return nil, fmt.Errorf("unsupported stream attribute type %v", value.DataType())
+	}
+}
@@ -0,0 +1,170 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// baseCurrencyIndexName is the GSI used by GetByBase.
+	baseCurrencyIndexName = "BaseCurrencyIndex"
+
+	// ttlAttributeName is the attribute DynamoDB TTL expires items by.
+	ttlAttributeName = "ttl"
+
+	// tableActiveTimeout bounds how long EnsureTable waits for a new table to become active.
+	tableActiveTimeout = 2 * time.Minute
+)
+
+// tableClient is the subset of the DynamoDB client used to provision the table.
+// *dynamodb.Client satisfies this interface; tests can provide a mock.
+type tableClient interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+	UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
+}
+
+// EnsureTable creates the exchange rate table if it does not exist.
+//
+// This function:
+// - Creates the table with the PK hash key and the BaseCurrencyIndex GSI on Base
+// - Waits for a newly created table to become active
+// - Enables DynamoDB TTL on the ttl attribute if it is not already enabled
+// - Leaves the key schema and indexes of an existing table untouched
+//
+// It is safe to call repeatedly and from concurrent cold starts. It is intended
+// for local and development bootstrap; production tables are managed by
+// infrastructure/template.yaml.
+func EnsureTable(ctx context.Context, client *dynamodb.Client, tableName string) error {
+	return ensureTable(ctx, client, tableName)
+}
+
+// ensureTable implements EnsureTable over any tableClient.
+func ensureTable(ctx context.Context, client tableClient, tableName string) error {
+	_, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	var notFoundErr *types.ResourceNotFoundException
+	switch {
+	case err == nil:
+		// Table exists
+	case errors.As(err, &notFoundErr):
+		if err := createTable(ctx, client, tableName); err != nil {
+			return err
+		}
+	default:
+		return mapDynamoDBError(err, "describe table")
+	}
+
+	return ensureTTL(ctx, client, tableName)
+}
+
+// createTable creates the table and waits for it to become active.
+// A table created concurrently by another caller is not an error.
+func createTable(ctx context.Context, client tableClient, tableName string) error {
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("PK"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("Base"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("PK"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(baseCurrencyIndexName),
+				KeySchema: []types.KeySchemaElement{
+					{
+						AttributeName: aws.String("Base"),
+						KeyType:       types.KeyTypeHash,
+					},
+				},
+				Projection: &types.Projection{
+					ProjectionType: types.ProjectionTypeAll,
+				},
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	var inUseErr *types.ResourceInUseException
+	if err != nil && !errors.As(err, &inUseErr) {
+		return mapDynamoDBError(err, "create table")
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(client, func(o *dynamodb.TableExistsWaiterOptions) {
+		o.MinDelay = 1 * time.Second
+	})
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	}, tableActiveTimeout); err != nil {
+		return fmt.Errorf("waiting for table %s to become active: %w", tableName, err)
+	}
+	return nil
+}
+
+// EnsureTTL enables DynamoDB TTL on the ttl attribute of an existing table.
+//
+// The repository writes expiry timestamps to the ttl attribute, but DynamoDB
+// only deletes expired items once TTL is enabled for that attribute.
+//
+// This function:
+// - Does nothing if TTL is already enabled (or being enabled) on ttl
+// - Returns an error if TTL is enabled on a different attribute
+// - Otherwise enables TTL on ttl
+//
+// A table has a single TTL attribute, and switching it requires disabling TTL
+// first, so a mismatch is left for an operator to fix.
+//
+// It is safe to call repeatedly, e.g. on every cold start.
+func EnsureTTL(ctx context.Context, client *dynamodb.Client, tableName string) error {
+	return ensureTTL(ctx, client, tableName)
+}
+
+// ensureTTL implements EnsureTTL over any tableClient.
+// UpdateTimeToLive rejects a no-op update, so the current setting is checked first.
+func ensureTTL(ctx context.Context, client tableClient, tableName string) error {
+	described, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return mapDynamoDBError(err, "describe time to live")
+	}
+	if desc := described.TimeToLiveDescription; desc != nil {
+		switch desc.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			if attribute := aws.ToString(desc.AttributeName); attribute != ttlAttributeName {
+				return fmt.Errorf("table %s has TTL enabled on attribute %q, want %q", tableName, attribute, ttlAttributeName)
+			}
+			return nil
+		}
+	}
+
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(ttlAttributeName),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return mapDynamoDBError(err, "update time to live")
+	}
+	return nil
+}
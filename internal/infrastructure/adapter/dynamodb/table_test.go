@@ -0,0 +1,195 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// mockTableClient is an in-memory implementation of tableClient for testing.
+type mockTableClient struct {
+	exists       bool
+	ttlStatus    types.TimeToLiveStatus
+	ttlAttribute string
+	describeErr  error
+	created      int
+	ttlUpdates   int
+	createInput  *dynamodb.CreateTableInput
+}
+
+func (m *mockTableClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	if m.describeErr != nil {
+		return nil, m.describeErr
+	}
+	if !m.exists {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("table not found")}
+	}
+	return &dynamodb.DescribeTableOutput{
+		Table: &types.TableDescription{TableName: params.TableName, TableStatus: types.TableStatusActive},
+	}, nil
+}
+
+func (m *mockTableClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	if m.exists {
+		return nil, &types.ResourceInUseException{Message: aws.String("table already exists")}
+	}
+	m.exists = true
+	m.created++
+	m.createInput = params
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+func (m *mockTableClient) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	status := m.ttlStatus
+	if status == "" {
+		status = types.TimeToLiveStatusDisabled
+	}
+	return &dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &types.TimeToLiveDescription{
+			TimeToLiveStatus: status,
+			AttributeName:    aws.String(m.ttlAttribute),
+		},
+	}, nil
+}
+
+func (m *mockTableClient) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	if m.ttlStatus == types.TimeToLiveStatusEnabled {
+		return nil, errors.New("ValidationException: TimeToLive is already enabled")
+	}
+	if aws.ToString(params.TimeToLiveSpecification.AttributeName) != ttlAttributeName {
+		return nil, errors.New("unexpected TTL attribute")
+	}
+	m.ttlStatus = types.TimeToLiveStatusEnabled
+	m.ttlAttribute = ttlAttributeName
+	m.ttlUpdates++
+	return &dynamodb.UpdateTimeToLiveOutput{}, nil
+}
+
+func TestEnsureTable(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *mockTableClient
+		wantCreated    int
+		wantTTLUpdates int
+	}{
+		{
+			name:           "creates missing table and enables TTL",
+			client:         &mockTableClient{},
+			wantCreated:    1,
+			wantTTLUpdates: 1,
+		},
+		{
+			name:           "existing table without TTL",
+			client:         &mockTableClient{exists: true},
+			wantCreated:    0,
+			wantTTLUpdates: 1,
+		},
+		{
+			name:           "existing table with TTL enabled",
+			client:         &mockTableClient{exists: true, ttlStatus: types.TimeToLiveStatusEnabled, ttlAttribute: ttlAttributeName},
+			wantCreated:    0,
+			wantTTLUpdates: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ensureTable(context.Background(), tt.client, "TestTable"); err != nil {
+				t.Fatalf("ensureTable() error = %v", err)
+			}
+			if tt.client.created != tt.wantCreated {
+				t.Errorf("tables created = %d, want %d", tt.client.created, tt.wantCreated)
+			}
+			if tt.client.ttlUpdates != tt.wantTTLUpdates {
+				t.Errorf("TTL updates = %d, want %d", tt.client.ttlUpdates, tt.wantTTLUpdates)
+			}
+		})
+	}
+}
+
+func TestEnsureTable_Idempotent(t *testing.T) {
+	client := &mockTableClient{}
+	for i := 0; i < 2; i++ {
+		if err := ensureTable(context.Background(), client, "TestTable"); err != nil {
+			t.Fatalf("ensureTable() call %d error = %v", i+1, err)
+		}
+	}
+	if client.created != 1 || client.ttlUpdates != 1 {
+		t.Errorf("created = %d, TTL updates = %d, want 1 and 1", client.created, client.ttlUpdates)
+	}
+
+	// The schema matches what GetByBase queries
+	input := client.createInput
+	if len(input.GlobalSecondaryIndexes) != 1 || aws.ToString(input.GlobalSecondaryIndexes[0].IndexName) != baseCurrencyIndexName {
+		t.Fatalf("GlobalSecondaryIndexes = %+v, want %s", input.GlobalSecondaryIndexes, baseCurrencyIndexName)
+	}
+	if got := aws.ToString(input.GlobalSecondaryIndexes[0].KeySchema[0].AttributeName); got != "Base" {
+		t.Errorf("GSI hash key = %s, want Base", got)
+	}
+	if got := aws.ToString(input.KeySchema[0].AttributeName); got != "PK" {
+		t.Errorf("table hash key = %s, want PK", got)
+	}
+}
+
+func TestEnsureTable_DescribeError(t *testing.T) {
+	client := &mockTableClient{describeErr: errors.New("access denied")}
+	if err := ensureTable(context.Background(), client, "TestTable"); err == nil {
+		t.Fatal("ensureTable() error = nil, want error")
+	}
+	if client.created != 0 {
+		t.Errorf("tables created = %d, want 0", client.created)
+	}
+}
+
+func TestEnsureTTL(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *mockTableClient
+		wantErr        bool
+		wantTTLUpdates int
+	}{
+		{
+			name:           "enables disabled TTL",
+			client:         &mockTableClient{exists: true},
+			wantTTLUpdates: 1,
+		},
+		{
+			name:           "TTL being enabled",
+			client:         &mockTableClient{exists: true, ttlStatus: types.TimeToLiveStatusEnabling, ttlAttribute: ttlAttributeName},
+			wantTTLUpdates: 0,
+		},
+		{
+			name:    "TTL enabled on another attribute",
+			client:  &mockTableClient{exists: true, ttlStatus: types.TimeToLiveStatusEnabled, ttlAttribute: "TTL"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ensureTTL(context.Background(), tt.client, "TestTable")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ensureTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.client.ttlUpdates != tt.wantTTLUpdates {
+				t.Errorf("TTL updates = %d, want %d", tt.client.ttlUpdates, tt.wantTTLUpdates)
+			}
+		})
+	}
+}
+
+func TestEnsureTTL_Idempotent(t *testing.T) {
+	client := &mockTableClient{exists: true}
+	for i := 0; i < 2; i++ {
+		if err := ensureTTL(context.Background(), client, "TestTable"); err != nil {
+			t.Fatalf("ensureTTL() call %d error = %v", i+1, err)
+		}
+	}
+	if client.ttlUpdates != 1 {
+		t.Errorf("TTL updates = %d, want 1", client.ttlUpdates)
+	}
+}
@@ -2,12 +2,20 @@ package lambda
 
 import (
 	"context"
+	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
 	"github.com/misterfancybg/go-currenseen/internal/infrastructure/middleware"
 	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/version"
 )
 
 // GetRateUseCase defines the interface for getting a single exchange rate.
@@ -22,6 +30,12 @@ type GetAllRatesUseCase interface {
 	Execute(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error)
 }
 
+// InvalidateRateUseCase defines the interface for removing a cached exchange rate.
+// This interface enables dependency injection and makes handlers testable.
+type InvalidateRateUseCase interface {
+	Execute(ctx context.Context, req dto.InvalidateRateRequest) (dto.InvalidateRateResponse, error)
+}
+
 // HealthCheckUseCase defines the interface for health checking the service.
 // This interface enables dependency injection and makes handlers testable.
 type HealthCheckUseCase interface {
@@ -34,23 +48,59 @@ type HandlerDependencies struct {
 	GetRateUseCase     GetRateUseCase
 	GetAllRatesUseCase GetAllRatesUseCase
 	HealthCheckUseCase HealthCheckUseCase
-	Logger             *logger.Logger
+	// InvalidateRateUseCase serves the DELETE /rates/{base}/{target} admin route (optional - nil returns 404)
+	InvalidateRateUseCase InvalidateRateUseCase
+	Logger                *logger.Logger
 	// Security dependencies (optional - can be nil if disabled)
+	// APIKeyAuthenticator is applied to every route except AuthSkipPaths by middleware.WithAuthentication
 	APIKeyAuthenticator *middleware.APIKeyAuthenticator
-	RateLimiter         *middleware.RateLimiter
+	AuthSkipPaths       []string
+	// RateLimiter and ClientIdentifier are applied to every route by middleware.WithRateLimit
+	RateLimiter      *middleware.RateLimiter
+	ClientIdentifier *middleware.ClientIdentifierResolver // optional - nil trusts no proxies
+	// RequestDeduplicator replays responses for duplicate API Gateway deliveries (optional - can be nil if disabled)
+	RequestDeduplicator *middleware.RequestDeduplicator
+	// Response formatting (the envelope itself is set with middleware.SetResponseEnvelope)
+	Response config.ResponseConfig
+	// CacheTTL is the maximum age of a non-stale rate, reported in X-Rate-Freshness-SLA
+	CacheTTL time.Duration
+	// IdempotencyStore replays results of mutating requests (optional - can be nil if disabled)
+	IdempotencyStore IdempotencyStore
+	// EventFlusher sends buffered rate events before a request returns (optional - can be nil if disabled)
+	EventFlusher repository.EventFlusher
+	// DefaultBaseCurrency is the base currency for GET /rates (empty uses DefaultBaseCurrency)
+	DefaultBaseCurrency entity.CurrencyCode
+	// MaxBasesPerRequest caps GET /rates?bases= (0 uses DefaultMaxBasesPerRequest)
+	MaxBasesPerRequest int
+	// PairDenylist blocks currency pairs from being served (optional - nil allows all pairs)
+	PairDenylist *middleware.PairDenylist
 }
 
+// DefaultMaxBasesPerRequest is the maximum number of bases in GET /rates?bases= when none is configured.
+const DefaultMaxBasesPerRequest = 10
+
+// multiBaseConcurrency bounds the bases fetched at once for a multi-base request.
+const multiBaseConcurrency = 4
+
+// DefaultBaseCurrency is the base currency for GET /rates when none is configured.
+const DefaultBaseCurrency entity.CurrencyCode = "USD"
+
 // GetRateHandler handles GET /rates/{base}/{target} requests.
 //
 // This handler:
 // - Validates the request (path parameters, HTTP method)
-// - Extracts base and target currency codes
+// - Extracts base and target currency codes and adds them to the context for logging
+// - Rejects denied pairs before any cache or provider access
 // - Calls GetExchangeRateUseCase
-// - Formats and returns the response
+// - Formats and returns the response (only the ?fields= fields, if given)
+// - Sets Last-Modified to the rate timestamp
+// - Sets X-Rate-Freshness-SLA to the cache TTL, if enabled and the rate is not stale
 //
 // Returns:
 // - 200 OK with rate data on success
+// - 304 Not Modified if If-Modified-Since is at or after the rate timestamp
 // - 400 Bad Request for invalid input
+// - 403 Forbidden if the pair is denied
 // - 404 Not Found if rate not found
 // - 503 Service Unavailable if circuit breaker is open
 // - 500 Internal Server Error for other errors
@@ -72,50 +122,27 @@ func GetRateHandler(ctx context.Context, event events.APIGatewayProxyRequest, de
 		"handler", "GetRateHandler",
 	)
 
-	// Apply rate limiting (if enabled)
-	if deps.RateLimiter != nil {
-		apiKey, _ := middleware.ExtractAPIKey(event)
-		rateLimitKey := apiKey
-		if rateLimitKey == "" {
-			// Use IP address or request ID as fallback for rate limiting
-			if event.RequestContext.Identity.SourceIP != "" {
-				rateLimitKey = event.RequestContext.Identity.SourceIP
-			} else {
-				rateLimitKey = logger.GetRequestID(ctx)
-			}
-		}
-
-		allowed, err := deps.RateLimiter.Allow(ctx, rateLimitKey)
-		if err != nil || !allowed {
-			log.LogError(ctx, err, "rate limit exceeded",
-				"rate_limit_key", logger.MaskAPIKey(rateLimitKey),
-			)
-			return middleware.ErrorResponse(middleware.ErrRateLimitExceeded)
-		}
-	}
-
-	// Apply API key authentication (if enabled)
-	if deps.APIKeyAuthenticator != nil {
-		if err := deps.APIKeyAuthenticator.AuthenticateRequest(ctx, event); err != nil {
-			log.LogError(ctx, err, "authentication failed")
-			return middleware.ErrorResponse(err)
-		}
-	}
-
 	// Validate request
 	base, target, err := middleware.ValidateGetRateRequest(event)
+	if err == nil {
+		err = deps.PairDenylist.CheckPair(base, target)
+	}
 	if err != nil {
 		log.LogError(ctx, err, "request validation failed")
 		return middleware.ErrorResponse(err)
 	}
 
+	// Carry the pair in the context so downstream logs include it
+	ctx = logger.WithCurrencyCodes(ctx, base.String(), target.String())
+
 	// Create request DTO
 	req := dto.GetRateRequest{
 		Base:   base.String(),
 		Target: target.String(),
 	}
 
-	// Call use case
+	// Call use case (the provider records where it fetched from, if it is called)
+	ctx, source := provider.WithFetchSource(ctx)
 	resp, err := deps.GetRateUseCase.Execute(ctx, req)
 	if err != nil {
 		duration := time.Since(startTime)
@@ -124,30 +151,55 @@ func GetRateHandler(ctx context.Context, event events.APIGatewayProxyRequest, de
 		)
 		return middleware.ErrorResponse(err)
 	}
+	resp = resp.TruncateTimestamp(deps.Response.TimestampPrecision)
+
+	// Conditional request: the client's copy of the rate is current
+	if middleware.NotModified(event, resp.Timestamp) {
+		log.LogResponse(ctx, http.StatusNotModified, time.Since(startTime).Milliseconds(),
+			"handler", "GetRateHandler",
+			"base", base.String(),
+			"target", target.String(),
+		)
+		return middleware.NotModifiedResponse(resp.Timestamp)
+	}
 
 	// Log successful response
 	duration := time.Since(startTime)
-	log.LogResponse(ctx, 200, duration.Milliseconds(),
+	log.LogResponse(ctx, 200, duration.Milliseconds(), append([]any{
 		"handler", "GetRateHandler",
 		"base", base.String(),
 		"target", target.String(),
-	)
+	}, sourceLogArgs(source)...)...)
 
-	// Return success response
-	return middleware.SuccessResponse(200, resp)
+	// Return success response, reduced to a sparse fieldset (?fields=) if requested
+	body, err := middleware.FilterRateFields(resp, middleware.ParseRateFields(event))
+	if err != nil {
+		log.LogError(ctx, err, "response field filtering failed")
+		return middleware.ErrorResponse(err)
+	}
+	response := middleware.WithLastModified(middleware.SuccessResponse(ctx, 200, body), resp.Timestamp)
+	response = withFreshnessSLAHeader(response, resp.Stale, deps)
+	return withSourceHeader(response, source, deps.Response)
 }
 
 // GetAllRatesHandler handles GET /rates/{base} requests.
 //
 // This handler:
 // - Validates the request (path parameters, HTTP method)
-// - Extracts base currency code
+// - Extracts base currency code and adds it to the context for logging
+// - Rejects bases whose pairs are all denied, before any cache or provider access
 // - Calls GetAllRatesUseCase
-// - Formats and returns the response
+// - Removes denied pairs from the response
+// - Formats and returns the response (only the ?fields= fields of each rate, if given),
+// as one JSON object or, with ?format=ndjson, as one JSON rate per line
+// - Sets Last-Modified to the rates timestamp
+// - Sets X-Rate-Freshness-SLA to the cache TTL, if enabled and the rates are not stale
 //
 // Returns:
 // - 200 OK with rates data on success
-// - 400 Bad Request for invalid input
+// - 304 Not Modified if If-Modified-Since is at or after the rates timestamp
+// - 400 Bad Request for invalid input (including an unsupported ?format=)
+// - 403 Forbidden if every pair for the base is denied
 // - 503 Service Unavailable if circuit breaker is open
 // - 500 Internal Server Error for other errors
 func GetAllRatesHandler(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
@@ -168,49 +220,30 @@ func GetAllRatesHandler(ctx context.Context, event events.APIGatewayProxyRequest
 		"handler", "GetAllRatesHandler",
 	)
 
-	// Apply rate limiting (if enabled)
-	if deps.RateLimiter != nil {
-		apiKey, _ := middleware.ExtractAPIKey(event)
-		rateLimitKey := apiKey
-		if rateLimitKey == "" {
-			// Use IP address or request ID as fallback for rate limiting
-			if event.RequestContext.Identity.SourceIP != "" {
-				rateLimitKey = event.RequestContext.Identity.SourceIP
-			} else {
-				rateLimitKey = logger.GetRequestID(ctx)
-			}
-		}
-
-		allowed, err := deps.RateLimiter.Allow(ctx, rateLimitKey)
-		if err != nil || !allowed {
-			log.LogError(ctx, err, "rate limit exceeded",
-				"rate_limit_key", logger.MaskAPIKey(rateLimitKey),
-			)
-			return middleware.ErrorResponse(middleware.ErrRateLimitExceeded)
-		}
-	}
-
-	// Apply API key authentication (if enabled)
-	if deps.APIKeyAuthenticator != nil {
-		if err := deps.APIKeyAuthenticator.AuthenticateRequest(ctx, event); err != nil {
-			log.LogError(ctx, err, "authentication failed")
-			return middleware.ErrorResponse(err)
-		}
-	}
-
 	// Validate request
 	base, err := middleware.ValidateGetRatesRequest(event)
+	var format string
+	if err == nil {
+		format, err = middleware.ParseResponseFormat(event)
+	}
+	if err == nil {
+		err = deps.PairDenylist.CheckBase(base)
+	}
 	if err != nil {
 		log.LogError(ctx, err, "request validation failed")
 		return middleware.ErrorResponse(err)
 	}
 
+	// Carry the base in the context so downstream logs include it
+	ctx = logger.WithCurrencyCodes(ctx, base.String(), "")
+
 	// Create request DTO
 	req := dto.GetRatesRequest{
 		Base: base.String(),
 	}
 
-	// Call use case
+	// Call use case (the provider records where it fetched from, if it is called)
+	ctx, source := provider.WithFetchSource(ctx)
 	resp, err := deps.GetAllRatesUseCase.Execute(ctx, req)
 	if err != nil {
 		duration := time.Since(startTime)
@@ -219,17 +252,272 @@ func GetAllRatesHandler(ctx context.Context, event events.APIGatewayProxyRequest
 		)
 		return middleware.ErrorResponse(err)
 	}
+	resp = deps.PairDenylist.FilterRates(resp).TruncateTimestamps(deps.Response.TimestampPrecision)
+
+	// Conditional request: the client's copy of the rates is current
+	if middleware.NotModified(event, resp.Timestamp) {
+		log.LogResponse(ctx, http.StatusNotModified, time.Since(startTime).Milliseconds(),
+			"handler", "GetAllRatesHandler",
+			"base", base.String(),
+		)
+		return middleware.NotModifiedResponse(resp.Timestamp)
+	}
 
 	// Log successful response
 	duration := time.Since(startTime)
-	log.LogResponse(ctx, 200, duration.Milliseconds(),
+	log.LogResponse(ctx, 200, duration.Milliseconds(), append([]any{
 		"handler", "GetAllRatesHandler",
 		"base", base.String(),
 		"rates_count", len(resp.Rates),
+	}, sourceLogArgs(source)...)...)
+
+	// Return success response, reduced to a sparse fieldset (?fields=) if requested
+	fields := middleware.ParseRateFields(event)
+	var response events.APIGatewayProxyResponse
+	if format == middleware.FormatNDJSON {
+		response = middleware.NDJSONResponse(resp, fields)
+	} else {
+		body, err := middleware.FilterRateFields(resp, fields)
+		if err != nil {
+			log.LogError(ctx, err, "response field filtering failed")
+			return middleware.ErrorResponse(err)
+		}
+		response = middleware.SuccessResponse(ctx, 200, body)
+	}
+	response = middleware.WithLastModified(response, resp.Timestamp)
+	response = withFreshnessSLAHeader(response, resp.Stale, deps)
+	return withSourceHeader(response, source, deps.Response)
+}
+
+// GetDefaultRatesHandler handles GET /rates requests.
+//
+// This handler serves all rates for the configured default base currency
+// (deps.DefaultBaseCurrency, or DefaultBaseCurrency if unset) by delegating
+// to GetAllRatesHandler as if the request were GET /rates/{default}.
+func GetDefaultRatesHandler(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	base := deps.DefaultBaseCurrency
+	if base == "" {
+		base = DefaultBaseCurrency
+	}
+
+	// Copy path parameters so the caller's event is not modified
+	params := make(map[string]string, len(event.PathParameters)+1)
+	for key, value := range event.PathParameters {
+		params[key] = value
+	}
+	params["base"] = base.String()
+	event.PathParameters = params
+
+	return GetAllRatesHandler(ctx, event, deps)
+}
+
+// GetMultiBaseRatesHandler handles GET /rates?bases=USD,EUR,GBP requests.
+//
+// This handler:
+// - Validates the bases query parameter (at most deps.MaxBasesPerRequest bases)
+// - Calls GetAllRatesUseCase for each base concurrently, with a bounded pool
+// - Reports invalid or failed bases per base without failing the whole request
+//
+// Returns:
+// - 200 OK with a map of base (the canonical code for aliases) to rates or error
+// - 400 Bad Request if the bases parameter is missing, empty, or has too many bases
+func GetMultiBaseRatesHandler(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	startTime := time.Now()
+
+	// Extract or generate request ID and add to context
+	ctx = middleware.WithRequestID(ctx, event)
+
+	// Get logger (use default if not provided)
+	log := deps.Logger
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log = log.WithContext(ctx)
+
+	// Log incoming request
+	log.LogRequest(ctx, event.HTTPMethod, event.Path,
+		"handler", "GetMultiBaseRatesHandler",
+	)
+
+	// Validate request
+	maxBases := deps.MaxBasesPerRequest
+	if maxBases <= 0 {
+		maxBases = DefaultMaxBasesPerRequest
+	}
+	bases, err := middleware.ValidateMultiBaseRequest(event, maxBases)
+	if err != nil {
+		log.LogError(ctx, err, "request validation failed")
+		return middleware.ErrorResponse(err)
+	}
+
+	// Fetch each base concurrently, bounded by multiBaseConcurrency
+	results := make([]dto.BaseRatesResult, len(bases))
+	slots := make(chan struct{}, multiBaseConcurrency)
+	var wg sync.WaitGroup
+	for i, baseStr := range bases {
+		wg.Add(1)
+		go func(i int, baseStr string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = fetchBaseRates(ctx, baseStr, deps)
+		}(i, baseStr)
+	}
+	wg.Wait()
+
+	resp := dto.MultiBaseRatesResponse{
+		Results:   make(map[string]dto.BaseRatesResult, len(bases)),
+		Timestamp: time.Now(),
+	}
+	failed := 0
+	for i, baseStr := range bases {
+		if results[i].Error != nil {
+			failed++
+		}
+		resp.Results[baseStr] = results[i]
+	}
+
+	// Log successful response
+	duration := time.Since(startTime)
+	log.LogResponse(ctx, 200, duration.Milliseconds(),
+		"handler", "GetMultiBaseRatesHandler",
+		"bases", len(bases),
+		"failed_bases", failed,
 	)
 
 	// Return success response
-	return middleware.SuccessResponse(200, resp)
+	return middleware.SuccessResponse(ctx, 200, resp)
+}
+
+// fetchBaseRates returns all rates for a single base of a multi-base request,
+// or a client-safe error if the base is invalid or the use case fails.
+func fetchBaseRates(ctx context.Context, baseStr string, deps *HandlerDependencies) dto.BaseRatesResult {
+	log := deps.Logger
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log = log.WithContext(ctx)
+
+	base, err := middleware.ValidateCurrencyCode(baseStr)
+	if err == nil {
+		err = deps.PairDenylist.CheckBase(base)
+	}
+	if err == nil {
+		var rates dto.RatesResponse
+		rates, err = deps.GetAllRatesUseCase.Execute(ctx, dto.GetRatesRequest{Base: base.String()})
+		if err == nil {
+			rates = deps.PairDenylist.FilterRates(rates).TruncateTimestamps(deps.Response.TimestampPrecision)
+			return dto.BaseRatesResult{Rates: &rates}
+		}
+	}
+
+	log.LogError(ctx, err, "base rates failed", "base", baseStr)
+	errResp := middleware.ErrorBody(err)
+	return dto.BaseRatesResult{Error: &errResp}
+}
+
+// sourceLogArgs returns log attributes for the provider and URL that served
+// the request, or nil if it was served without calling a provider (e.g. from cache).
+func sourceLogArgs(source *provider.FetchSource) []any {
+	if source.Provider() == "" {
+		return nil
+	}
+	return []any{
+		"source_provider", source.Provider(),
+		"source_url", source.URL(),
+	}
+}
+
+// withSourceHeader adds an X-Rate-Source header naming the provider that
+// served the request, if enabled and a provider was called.
+// Only the provider name is exposed; the URL is logged but never returned.
+func withSourceHeader(resp events.APIGatewayProxyResponse, source *provider.FetchSource, cfg config.ResponseConfig) events.APIGatewayProxyResponse {
+	if !cfg.SourceHeader || source.Provider() == "" {
+		return resp
+	}
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]string)
+	}
+	resp.Headers["X-Rate-Source"] = source.Provider()
+	return resp
+}
+
+// withFreshnessSLAHeader adds an X-Rate-Freshness-SLA header with the maximum
+// age in seconds a served rate can have (the cache TTL), if enabled.
+// Stale responses get no header: their rates are already older than the TTL.
+func withFreshnessSLAHeader(resp events.APIGatewayProxyResponse, stale bool, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	if !deps.Response.FreshnessSLA || deps.CacheTTL <= 0 || stale {
+		return resp
+	}
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]string)
+	}
+	resp.Headers["X-Rate-Freshness-SLA"] = strconv.FormatInt(int64(deps.CacheTTL/time.Second), 10)
+	return resp
+}
+
+// InvalidateRateHandler handles DELETE /rates/{base}/{target} admin requests.
+//
+// This handler:
+// - Validates the request (path parameters, HTTP method)
+// - Calls InvalidateRateUseCase to remove the cached rate
+//
+// The router authenticates the request first and wraps the handler in
+// WithIdempotency, so a retried request carrying the same Idempotency-Key
+// replays the first result.
+//
+// Returns:
+// - 200 OK with the invalidated pair on success
+// - 400 Bad Request for invalid input
+// - 404 Not Found if no rate is cached for the pair
+// - 500 Internal Server Error for other errors
+func InvalidateRateHandler(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	startTime := time.Now()
+
+	// Extract or generate request ID and add to context
+	ctx = middleware.WithRequestID(ctx, event)
+
+	// Get logger (use default if not provided)
+	log := deps.Logger
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log = log.WithContext(ctx)
+
+	// Log incoming request
+	log.LogRequest(ctx, event.HTTPMethod, event.Path,
+		"handler", "InvalidateRateHandler",
+	)
+	log.LogRequestBody(ctx, event.Body)
+
+	// Validate request
+	base, target, err := middleware.ValidateInvalidateRateRequest(event)
+	if err != nil {
+		log.LogError(ctx, err, "request validation failed")
+		return middleware.ErrorResponse(err)
+	}
+
+	// Call use case
+	resp, err := deps.InvalidateRateUseCase.Execute(ctx, dto.InvalidateRateRequest{
+		Base:   base.String(),
+		Target: target.String(),
+	})
+	if err != nil {
+		log.LogError(ctx, err, "use case execution failed",
+			"duration_ms", time.Since(startTime).Milliseconds(),
+		)
+		return middleware.ErrorResponse(err)
+	}
+
+	// Log successful response
+	log.LogResponse(ctx, 200, time.Since(startTime).Milliseconds(),
+		"handler", "InvalidateRateHandler",
+		"base", base.String(),
+		"target", target.String(),
+	)
+
+	return middleware.SuccessResponse(ctx, 200, resp)
 }
 
 // HealthHandler handles GET /health requests.
@@ -240,7 +528,7 @@ func GetAllRatesHandler(ctx context.Context, event events.APIGatewayProxyRequest
 // - Formats and returns the response
 //
 // Returns:
-// - 200 OK if service is healthy
+// - 200 OK if service is healthy or degraded (provider failing, stale cache still served)
 // - 503 Service Unavailable if service is unhealthy
 func HealthHandler(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
 	startTime := time.Now()
@@ -293,5 +581,34 @@ func HealthHandler(ctx context.Context, event events.APIGatewayProxyRequest, dep
 	)
 
 	// Return response
-	return middleware.SuccessResponse(statusCode, resp)
+	return middleware.SuccessResponse(ctx, statusCode, resp)
+}
+
+// VersionHandler handles GET /version requests.
+//
+// This function:
+// - Extracts or generates request ID and adds to context
+// - Validates the HTTP method
+// - Returns the build information injected at link time (see package version)
+func VersionHandler(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	// Extract or generate request ID and add to context
+	ctx = middleware.WithRequestID(ctx, event)
+
+	// Get logger (use default if not provided)
+	log := deps.Logger
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log = log.WithContext(ctx)
+
+	log.LogRequest(ctx, event.HTTPMethod, event.Path,
+		"handler", "VersionHandler",
+	)
+
+	if err := middleware.ValidateMethod(event, http.MethodGet); err != nil {
+		log.LogError(ctx, err, "request validation failed")
+		return middleware.ErrorResponse(err)
+	}
+
+	return middleware.SuccessResponse(ctx, http.StatusOK, version.Get())
 }
@@ -1,14 +1,24 @@
 package lambda
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/middleware"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+	"github.com/misterfancybg/go-currenseen/pkg/version"
 )
 
 // mockGetRateUseCase is a mock implementation of GetExchangeRateUseCase for testing.
@@ -192,6 +202,435 @@ func TestGetAllRatesHandler_Success(t *testing.T) {
 	}
 }
 
+func TestGetRateHandler_TimestampPrecision(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 15, 4, 5, 678_000_000, time.UTC)
+	deps := &HandlerDependencies{
+		GetRateUseCase: &mockGetRateUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+				return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85, Timestamp: timestamp}, nil
+			},
+		},
+		Response: config.ResponseConfig{TimestampPrecision: time.Second},
+	}
+
+	resp := GetRateHandler(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:     "GET",
+		Path:           "/rates/USD/EUR",
+		PathParameters: map[string]string{"base": "USD", "target": "EUR"},
+	}, deps)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status code 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Timestamp != "2025-01-02T15:04:05Z" {
+		t.Errorf("timestamp = %q, want 2025-01-02T15:04:05Z", body.Timestamp)
+	}
+}
+
+func TestGetAllRatesHandler_NDJSON(t *testing.T) {
+	now := time.Now()
+	deps := &HandlerDependencies{
+		GetAllRatesUseCase: &mockGetAllRatesUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+				return dto.RatesResponse{
+					Base: req.Base,
+					Rates: map[string]dto.RateResponse{
+						"EUR": {Base: req.Base, Target: "EUR", Rate: 0.85, Timestamp: now},
+						"GBP": {Base: req.Base, Target: "GBP", Rate: 0.75, Timestamp: now},
+					},
+					Timestamp: now,
+				}, nil
+			},
+		},
+	}
+	request := func(format string) events.APIGatewayProxyRequest {
+		return events.APIGatewayProxyRequest{
+			HTTPMethod:            "GET",
+			Path:                  "/rates/USD",
+			PathParameters:        map[string]string{"base": "USD"},
+			QueryStringParameters: map[string]string{"format": format},
+		}
+	}
+
+	resp := GetAllRatesHandler(context.Background(), request("ndjson"), deps)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status code 200, got %d", resp.StatusCode)
+	}
+	if resp.Headers["Content-Type"] != "application/x-ndjson" {
+		t.Errorf("expected Content-Type application/x-ndjson, got %s", resp.Headers["Content-Type"])
+	}
+	lines := strings.Split(strings.TrimSuffix(resp.Body, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), resp.Body)
+	}
+	for _, line := range lines {
+		var rate dto.RateResponse
+		if err := json.Unmarshal([]byte(line), &rate); err != nil {
+			t.Fatalf("line %q is not a JSON rate: %v", line, err)
+		}
+		if rate.Base != "USD" || rate.Rate == 0 {
+			t.Errorf("unexpected rate %+v", rate)
+		}
+	}
+
+	if resp := GetAllRatesHandler(context.Background(), request("xml"), deps); resp.StatusCode != 400 {
+		t.Errorf("expected status code 400 for an unsupported format, got %d", resp.StatusCode)
+	}
+}
+
+func TestHandlers_LogCurrencyCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		params     map[string]string
+		handler    func(context.Context, events.APIGatewayProxyRequest, *HandlerDependencies) events.APIGatewayProxyResponse
+		wantBase   string
+		wantTarget string
+	}{
+		{"rate", "/rates/usd/eur", map[string]string{"base": "usd", "target": "eur"}, GetRateHandler, "USD", "EUR"},
+		{"all rates", "/rates/usd", map[string]string{"base": "usd"}, GetAllRatesHandler, "USD", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+			deps := &HandlerDependencies{
+				Logger: log,
+				GetRateUseCase: &mockGetRateUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+						log.WithContext(ctx).Info("use case executed")
+						return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85, Timestamp: time.Now()}, nil
+					},
+				},
+				GetAllRatesUseCase: &mockGetAllRatesUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+						log.WithContext(ctx).Info("use case executed")
+						return dto.RatesResponse{Base: req.Base, Rates: map[string]dto.RateResponse{}, Timestamp: time.Now()}, nil
+					},
+				},
+			}
+
+			event := events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: tt.path, PathParameters: tt.params}
+			if resp := tt.handler(context.Background(), event, deps); resp.StatusCode != 200 {
+				t.Fatalf("expected status code 200, got %d", resp.StatusCode)
+			}
+
+			logged := map[string]bool{}
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("failed to parse log line %q: %v", line, err)
+				}
+				msg, _ := entry["msg"].(string)
+				if msg != "use case executed" && msg != "request completed" {
+					continue
+				}
+				logged[msg] = true
+				if entry["base_currency"] != tt.wantBase {
+					t.Errorf("%q base_currency = %v, want %q", msg, entry["base_currency"], tt.wantBase)
+				}
+				if target, ok := entry["target_currency"]; tt.wantTarget != "" && target != tt.wantTarget || tt.wantTarget == "" && ok {
+					t.Errorf("%q target_currency = %v, want %q", msg, target, tt.wantTarget)
+				}
+			}
+			if !logged["use case executed"] || !logged["request completed"] {
+				t.Errorf("missing log lines, got %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestGetRateHandler_Fields(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    map[string]string
+		wantKeys []string
+	}{
+		{"all fields by default", nil, []string{"base", "target", "rate", "timestamp", "age_seconds"}},
+		{"sparse fieldset", map[string]string{"fields": "rate,timestamp"}, []string{"rate", "timestamp"}},
+		{"unknown fields ignored", map[string]string{"fields": "rate,unknown"}, []string{"rate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := events.APIGatewayProxyRequest{
+				HTTPMethod:            "GET",
+				Path:                  "/rates/USD/EUR",
+				PathParameters:        map[string]string{"base": "USD", "target": "EUR"},
+				QueryStringParameters: tt.query,
+			}
+			deps := &HandlerDependencies{
+				GetRateUseCase: &mockGetRateUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+						return dto.RateResponse{Base: "USD", Target: "EUR", Rate: 0.85, Timestamp: time.Now()}, nil
+					},
+				},
+			}
+
+			resp := GetRateHandler(context.Background(), event, deps)
+
+			if resp.StatusCode != 200 {
+				t.Fatalf("expected status code 200, got %d: %s", resp.StatusCode, resp.Body)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if len(body) != len(tt.wantKeys) {
+				t.Errorf("body = %v, want keys %v", body, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := body[key]; !ok {
+					t.Errorf("body = %v, missing %q", body, key)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRateHandler_LastModified(t *testing.T) {
+	timestamp := time.Date(2024, 3, 5, 13, 30, 15, 0, time.UTC)
+	deps := &HandlerDependencies{
+		GetRateUseCase: &mockGetRateUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+				return dto.RateResponse{Base: "USD", Target: "EUR", Rate: 0.85, Timestamp: timestamp}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		wantStatus      int
+	}{
+		{"no condition", "", 200},
+		{"modified since", "Tue, 05 Mar 2024 13:00:00 GMT", 200},
+		{"not modified", "Tue, 05 Mar 2024 13:30:15 GMT", 304},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := events.APIGatewayProxyRequest{
+				HTTPMethod:     "GET",
+				Path:           "/rates/USD/EUR",
+				PathParameters: map[string]string{"base": "USD", "target": "EUR"},
+			}
+			if tt.ifModifiedSince != "" {
+				event.Headers = map[string]string{"If-Modified-Since": tt.ifModifiedSince}
+			}
+
+			resp := GetRateHandler(context.Background(), event, deps)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected status code %d, got %d: %s", tt.wantStatus, resp.StatusCode, resp.Body)
+			}
+			if got := resp.Headers["Last-Modified"]; got != "Tue, 05 Mar 2024 13:30:15 GMT" {
+				t.Errorf("Last-Modified = %q, want %q", got, "Tue, 05 Mar 2024 13:30:15 GMT")
+			}
+			if tt.wantStatus == 304 && resp.Body != "" {
+				t.Errorf("304 body = %q, want empty", resp.Body)
+			}
+		})
+	}
+}
+
+func TestGetDefaultRatesHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultBase entity.CurrencyCode
+		wantBase    string
+	}{
+		{name: "unset uses USD", defaultBase: "", wantBase: "USD"},
+		{name: "configured default", defaultBase: "EUR", wantBase: "EUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := events.APIGatewayProxyRequest{
+				HTTPMethod: "GET",
+				Path:       "/rates",
+			}
+
+			var gotBase string
+			deps := &HandlerDependencies{
+				GetAllRatesUseCase: &mockGetAllRatesUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+						gotBase = req.Base
+						return dto.RatesResponse{Base: req.Base, Rates: map[string]dto.RateResponse{}, Timestamp: time.Now()}, nil
+					},
+				},
+				DefaultBaseCurrency: tt.defaultBase,
+			}
+
+			resp := GetDefaultRatesHandler(context.Background(), event, deps)
+
+			if resp.StatusCode != 200 {
+				t.Fatalf("expected status code 200, got %d: %s", resp.StatusCode, resp.Body)
+			}
+			if gotBase != tt.wantBase {
+				t.Errorf("use case base = %q, want %q", gotBase, tt.wantBase)
+			}
+			if event.PathParameters != nil {
+				t.Errorf("caller event path parameters modified: %v", event.PathParameters)
+			}
+		})
+	}
+}
+
+func TestGetMultiBaseRatesHandler_Success(t *testing.T) {
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/rates",
+		QueryStringParameters: map[string]string{"bases": "USD,eur,GBP"},
+	}
+
+	var mu sync.Mutex
+	var called []string
+	deps := &HandlerDependencies{
+		GetAllRatesUseCase: &mockGetAllRatesUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+				mu.Lock()
+				called = append(called, req.Base)
+				mu.Unlock()
+				return dto.RatesResponse{Base: req.Base, Rates: map[string]dto.RateResponse{}, Timestamp: time.Now()}, nil
+			},
+		},
+	}
+
+	resp := GetMultiBaseRatesHandler(context.Background(), event, deps)
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status code 200, got %d: %s", resp.StatusCode, resp.Body)
+	}
+	if len(called) != 3 {
+		t.Errorf("use case called for %v, want 3 bases", called)
+	}
+
+	var body dto.MultiBaseRatesResponse
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	for _, base := range []string{"USD", "EUR", "GBP"} {
+		result, ok := body.Results[base]
+		if !ok {
+			t.Errorf("missing result for %s", base)
+			continue
+		}
+		if result.Error != nil || result.Rates == nil || result.Rates.Base != base {
+			t.Errorf("result[%s] = %+v, want rates for %s", base, result, base)
+		}
+	}
+}
+
+func TestGetMultiBaseRatesHandler_CurrencyAliases(t *testing.T) {
+	if err := middleware.SetCurrencyAliases(map[string]string{"XBT": "BTC"}); err != nil {
+		t.Fatalf("SetCurrencyAliases() error = %v", err)
+	}
+	t.Cleanup(func() { _ = middleware.SetCurrencyAliases(nil) })
+
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/rates",
+		QueryStringParameters: map[string]string{"bases": "XBT,BTC"},
+	}
+
+	var mu sync.Mutex
+	var called []string
+	deps := &HandlerDependencies{
+		GetAllRatesUseCase: &mockGetAllRatesUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+				mu.Lock()
+				called = append(called, req.Base)
+				mu.Unlock()
+				return dto.RatesResponse{Base: req.Base, Rates: map[string]dto.RateResponse{}, Timestamp: time.Now()}, nil
+			},
+		},
+	}
+
+	resp := GetMultiBaseRatesHandler(context.Background(), event, deps)
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status code 200, got %d: %s", resp.StatusCode, resp.Body)
+	}
+	// The alias and its canonical code are fetched once
+	if len(called) != 1 || called[0] != "BTC" {
+		t.Errorf("use case called for %v, want [BTC]", called)
+	}
+
+	var body dto.MultiBaseRatesResponse
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(body.Results) != 1 || body.Results["BTC"].Rates == nil {
+		t.Errorf("results = %+v, want rates keyed by BTC only", body.Results)
+	}
+}
+
+func TestGetMultiBaseRatesHandler_PartialFailure(t *testing.T) {
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/rates",
+		QueryStringParameters: map[string]string{"bases": "USD,XX1,JPY"},
+	}
+
+	deps := &HandlerDependencies{
+		GetAllRatesUseCase: &mockGetAllRatesUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+				if req.Base == "JPY" {
+					return dto.RatesResponse{}, provider.ErrCurrencyUnsupported
+				}
+				return dto.RatesResponse{Base: req.Base, Rates: map[string]dto.RateResponse{}, Timestamp: time.Now()}, nil
+			},
+		},
+	}
+
+	resp := GetMultiBaseRatesHandler(context.Background(), event, deps)
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status code 200, got %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var body dto.MultiBaseRatesResponse
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if result := body.Results["USD"]; result.Rates == nil || result.Error != nil {
+		t.Errorf("result[USD] = %+v, want rates", result)
+	}
+	if result := body.Results["XX1"]; result.Error == nil || result.Error.Code != "INVALID_CURRENCY_CODE" {
+		t.Errorf("result[XX1] = %+v, want INVALID_CURRENCY_CODE error", result)
+	}
+	if result := body.Results["JPY"]; result.Error == nil || result.Error.Code != "CURRENCY_UNSUPPORTED" {
+		t.Errorf("result[JPY] = %+v, want CURRENCY_UNSUPPORTED error", result)
+	}
+}
+
+func TestGetMultiBaseRatesHandler_TooManyBases(t *testing.T) {
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/rates",
+		QueryStringParameters: map[string]string{"bases": "USD,EUR,GBP"},
+	}
+
+	deps := &HandlerDependencies{
+		GetAllRatesUseCase: &mockGetAllRatesUseCase{},
+		MaxBasesPerRequest: 2,
+	}
+
+	resp := GetMultiBaseRatesHandler(context.Background(), event, deps)
+
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status code 400, got %d", resp.StatusCode)
+	}
+}
+
 func TestGetAllRatesHandler_InvalidCurrencyCode(t *testing.T) {
 	ctx := context.Background()
 	event := events.APIGatewayProxyRequest{
@@ -241,6 +680,38 @@ func TestHealthHandler_Success(t *testing.T) {
 	}
 }
 
+func TestVersionHandler(t *testing.T) {
+	event := events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/version"}
+
+	resp := Route(context.Background(), event, &HandlerDependencies{})
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status code 200, got %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		t.Fatalf("failed to decode body %q: %v", resp.Body, err)
+	}
+	want := map[string]string{
+		"version":   "dev",
+		"gitCommit": "unknown",
+		"buildTime": "unknown",
+		"goVersion": version.Get().GoVersion,
+	}
+	if len(body) != len(want) {
+		t.Errorf("body = %v, want exactly the keys of %v", body, want)
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Errorf("%s = %q, want %q", key, body[key], value)
+		}
+	}
+	if want["goVersion"] == "" {
+		t.Error("goVersion is empty")
+	}
+}
+
 func TestHealthHandler_Unhealthy(t *testing.T) {
 	ctx := context.Background()
 	event := events.APIGatewayProxyRequest{
@@ -268,3 +739,268 @@ func TestHealthHandler_Unhealthy(t *testing.T) {
 		t.Errorf("expected status code 503, got %d", resp.StatusCode)
 	}
 }
+
+func TestHandlers_MixedCaseCurrencyCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   string
+		target string
+	}{
+		{"lowercase", "usd", "eur"},
+		{"uppercase", "USD", "EUR"},
+		{"mixed case", "Usd", "eUr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRate dto.GetRateRequest
+			var gotRates dto.GetRatesRequest
+			deps := &HandlerDependencies{
+				GetRateUseCase: &mockGetRateUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+						gotRate = req
+						return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85, Timestamp: time.Now()}, nil
+					},
+				},
+				GetAllRatesUseCase: &mockGetAllRatesUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+						gotRates = req
+						return dto.RatesResponse{Base: req.Base, Timestamp: time.Now()}, nil
+					},
+				},
+			}
+
+			rateResp := GetRateHandler(context.Background(), events.APIGatewayProxyRequest{
+				HTTPMethod:     "GET",
+				Path:           "/rates/" + tt.base + "/" + tt.target,
+				PathParameters: map[string]string{"base": tt.base, "target": tt.target},
+			}, deps)
+			if rateResp.StatusCode != 200 {
+				t.Fatalf("GetRateHandler status = %d, want 200", rateResp.StatusCode)
+			}
+			if gotRate.Base != "USD" || gotRate.Target != "EUR" {
+				t.Errorf("GetRate request = %s/%s, want USD/EUR", gotRate.Base, gotRate.Target)
+			}
+
+			ratesResp := GetAllRatesHandler(context.Background(), events.APIGatewayProxyRequest{
+				HTTPMethod:     "GET",
+				Path:           "/rates/" + tt.base,
+				PathParameters: map[string]string{"base": tt.base},
+			}, deps)
+			if ratesResp.StatusCode != 200 {
+				t.Fatalf("GetAllRatesHandler status = %d, want 200", ratesResp.StatusCode)
+			}
+			if gotRates.Base != "USD" {
+				t.Errorf("GetAllRates request base = %s, want USD", gotRates.Base)
+			}
+		})
+	}
+}
+
+func TestHandlers_RateSourceHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		sourceHeader bool
+		fetched      bool // whether the use case called the provider (cache miss)
+		want         string
+	}{
+		{"enabled, fetched from provider", true, true, "currency_api"},
+		{"enabled, served from cache", true, false, ""},
+		{"disabled", false, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Simulate the provider recording into the handler's FetchSource
+			record := func(ctx context.Context) {
+				if source := provider.FetchSourceFromContext(ctx); tt.fetched && source != nil {
+					source.Record("currency_api", "https://api.example.com/v1/currencies/usd.json")
+				}
+			}
+			deps := &HandlerDependencies{
+				GetRateUseCase: &mockGetRateUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+						record(ctx)
+						return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85, Timestamp: time.Now()}, nil
+					},
+				},
+				GetAllRatesUseCase: &mockGetAllRatesUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+						record(ctx)
+						return dto.RatesResponse{Base: req.Base, Timestamp: time.Now()}, nil
+					},
+				},
+				Response: config.ResponseConfig{SourceHeader: tt.sourceHeader},
+			}
+
+			responses := map[string]events.APIGatewayProxyResponse{
+				"GetRateHandler": GetRateHandler(context.Background(), events.APIGatewayProxyRequest{
+					HTTPMethod:     "GET",
+					Path:           "/rates/USD/EUR",
+					PathParameters: map[string]string{"base": "USD", "target": "EUR"},
+				}, deps),
+				"GetAllRatesHandler": GetAllRatesHandler(context.Background(), events.APIGatewayProxyRequest{
+					HTTPMethod:     "GET",
+					Path:           "/rates/USD",
+					PathParameters: map[string]string{"base": "USD"},
+				}, deps),
+			}
+			for handler, resp := range responses {
+				if resp.StatusCode != 200 {
+					t.Fatalf("%s status = %d, want 200", handler, resp.StatusCode)
+				}
+				got, ok := resp.Headers["X-Rate-Source"]
+				if got != tt.want || ok != (tt.want != "") {
+					t.Errorf("%s X-Rate-Source = %q (present %v), want %q", handler, got, ok, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlers_FreshnessSLAHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		stale   bool
+		want    string
+	}{
+		{"enabled", true, false, "5400"},
+		{"enabled, stale response", true, true, ""},
+		{"disabled", false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := &HandlerDependencies{
+				GetRateUseCase: &mockGetRateUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+						return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85, Timestamp: time.Now(), Stale: tt.stale}, nil
+					},
+				},
+				GetAllRatesUseCase: &mockGetAllRatesUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+						return dto.RatesResponse{Base: req.Base, Timestamp: time.Now(), Stale: tt.stale}, nil
+					},
+				},
+				Response: config.ResponseConfig{FreshnessSLA: tt.enabled},
+				CacheTTL: 90 * time.Minute,
+			}
+
+			responses := map[string]events.APIGatewayProxyResponse{
+				"GetRateHandler": GetRateHandler(context.Background(), events.APIGatewayProxyRequest{
+					HTTPMethod:     "GET",
+					Path:           "/rates/USD/EUR",
+					PathParameters: map[string]string{"base": "USD", "target": "EUR"},
+				}, deps),
+				"GetAllRatesHandler": GetAllRatesHandler(context.Background(), events.APIGatewayProxyRequest{
+					HTTPMethod:     "GET",
+					Path:           "/rates/USD",
+					PathParameters: map[string]string{"base": "USD"},
+				}, deps),
+			}
+			for handler, resp := range responses {
+				if resp.StatusCode != 200 {
+					t.Fatalf("%s status = %d, want 200", handler, resp.StatusCode)
+				}
+				got, ok := resp.Headers["X-Rate-Freshness-SLA"]
+				if got != tt.want || ok != (tt.want != "") {
+					t.Errorf("%s X-Rate-Freshness-SLA = %q (present %v), want %q", handler, got, ok, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlers_PairDenylist(t *testing.T) {
+	ctx := context.Background()
+	denylist, err := middleware.NewPairDenylist([]string{"USD/RUB", "IRR/*"})
+	if err != nil {
+		t.Fatalf("NewPairDenylist() error = %v", err)
+	}
+
+	useCaseCalled := false
+	deps := &HandlerDependencies{
+		GetRateUseCase: &mockGetRateUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+				useCaseCalled = true
+				return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85}, nil
+			},
+		},
+		GetAllRatesUseCase: &mockGetAllRatesUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRatesRequest) (dto.RatesResponse, error) {
+				useCaseCalled = true
+				return dto.RatesResponse{
+					Base: req.Base,
+					Rates: map[string]dto.RateResponse{
+						"EUR": {Base: req.Base, Target: "EUR", Rate: 0.85},
+						"RUB": {Base: req.Base, Target: "RUB", Rate: 90},
+					},
+				}, nil
+			},
+		},
+		PairDenylist: denylist,
+	}
+
+	t.Run("denied pair is rejected before the use case", func(t *testing.T) {
+		useCaseCalled = false
+		resp := GetRateHandler(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod:     "GET",
+			PathParameters: map[string]string{"base": "usd", "target": "rub"},
+		}, deps)
+
+		if resp.StatusCode != 403 {
+			t.Errorf("expected status code 403, got %d", resp.StatusCode)
+		}
+		if useCaseCalled {
+			t.Error("use case called for a denied pair")
+		}
+	})
+
+	t.Run("allowed pair is served", func(t *testing.T) {
+		resp := GetRateHandler(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod:     "GET",
+			PathParameters: map[string]string{"base": "USD", "target": "EUR"},
+		}, deps)
+
+		if resp.StatusCode != 200 {
+			t.Errorf("expected status code 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("denied base is rejected", func(t *testing.T) {
+		useCaseCalled = false
+		resp := GetAllRatesHandler(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod:     "GET",
+			PathParameters: map[string]string{"base": "IRR"},
+		}, deps)
+
+		if resp.StatusCode != 403 {
+			t.Errorf("expected status code 403, got %d", resp.StatusCode)
+		}
+		if useCaseCalled {
+			t.Error("use case called for a denied base")
+		}
+	})
+
+	t.Run("denied targets are removed from all rates", func(t *testing.T) {
+		resp := GetAllRatesHandler(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod:     "GET",
+			PathParameters: map[string]string{"base": "USD"},
+		}, deps)
+
+		if resp.StatusCode != 200 {
+			t.Fatalf("expected status code 200, got %d", resp.StatusCode)
+		}
+		var body dto.RatesResponse
+		if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if _, ok := body.Rates["RUB"]; ok {
+			t.Error("denied pair USD/RUB was served")
+		}
+		if _, ok := body.Rates["EUR"]; !ok {
+			t.Error("allowed pair USD/EUR was not served")
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package lambda
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// IdempotencyStore defines the interface for storing results of mutating requests.
+// This interface enables dependency injection and makes handlers testable.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) (payload []byte, found bool, err error)
+	Save(ctx context.Context, key string, payload []byte) error
+}
+
+// ExtractIdempotencyKey extracts the Idempotency-Key header from the request.
+// Returns an empty string if the header is not present.
+func ExtractIdempotencyKey(event events.APIGatewayProxyRequest) string {
+	if key := event.Headers["Idempotency-Key"]; key != "" {
+		return strings.TrimSpace(key)
+	}
+	return strings.TrimSpace(event.Headers["idempotency-key"])
+}
+
+// isMutatingMethod reports whether the HTTP method has side effects.
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// scopedIdempotencyKey returns the store key for a client's Idempotency-Key.
+//
+// The key is scoped as client|method|path|key, so two clients choosing the same
+// key (or one client reusing a key on another route) never replay each other's
+// results. It is hashed so client identities (e.g. API keys) are never stored.
+func scopedIdempotencyKey(client string, event events.APIGatewayProxyRequest, key string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{client, event.HTTPMethod, event.Path, key}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithIdempotency executes a mutating handler at most once per Idempotency-Key.
+//
+// This function:
+// - Executes fn directly if there is no store, key, or mutating method
+// - Scopes the key to the client, method, and path (see scopedIdempotencyKey)
+// - Returns the stored response on replay instead of re-executing fn
+// - Stores the response of the first execution (5xx responses are not stored)
+//
+// client identifies the caller, e.g. a verified middleware.ClientIdentity key.
+// Store errors never fail the request: the handler is executed as if no
+// key was provided, and the failure is logged.
+func WithIdempotency(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+	store IdempotencyStore,
+	client string,
+	log *logger.Logger,
+	fn func() events.APIGatewayProxyResponse,
+) events.APIGatewayProxyResponse {
+	key := ExtractIdempotencyKey(event)
+	if store == nil || key == "" || !isMutatingMethod(event.HTTPMethod) {
+		return fn()
+	}
+	key = scopedIdempotencyKey(client, event, key)
+
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log = log.WithContext(ctx)
+
+	// Replay stored result if present
+	payload, found, err := store.Get(ctx, key)
+	if err != nil {
+		log.Warn("failed to read idempotency record", "error", err.Error())
+	} else if found {
+		var stored events.APIGatewayProxyResponse
+		if err := json.Unmarshal(payload, &stored); err == nil {
+			log.Info("replaying stored response for idempotency key",
+				"status_code", stored.StatusCode,
+			)
+			return stored
+		}
+		log.Warn("failed to decode idempotency record, re-executing")
+	}
+
+	resp := fn()
+
+	// Don't store server errors - the client should be able to retry them
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp
+	}
+
+	payload, err = json.Marshal(resp)
+	if err != nil {
+		log.Warn("failed to encode idempotency record", "error", err.Error())
+		return resp
+	}
+	if err := store.Save(ctx, key, payload); err != nil {
+		log.Warn("failed to save idempotency record", "error", err.Error())
+	}
+
+	return resp
+}
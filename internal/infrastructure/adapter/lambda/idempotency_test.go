@@ -0,0 +1,191 @@
+package lambda
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// mockIdempotencyStore is an in-memory implementation of IdempotencyStore for testing.
+type mockIdempotencyStore struct {
+	records map[string][]byte
+}
+
+// testClient is the client identity idempotency keys are scoped to in tests.
+const testClient = "ip:203.0.113.7"
+
+func newMockIdempotencyStore() *mockIdempotencyStore {
+	return &mockIdempotencyStore{records: make(map[string][]byte)}
+}
+
+func (m *mockIdempotencyStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	payload, ok := m.records[key]
+	return payload, ok, nil
+}
+
+func (m *mockIdempotencyStore) Save(ctx context.Context, key string, payload []byte) error {
+	m.records[key] = payload
+	return nil
+}
+
+func TestWithIdempotency_FirstCallExecutes(t *testing.T) {
+	ctx := context.Background()
+	store := newMockIdempotencyStore()
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Headers:    map[string]string{"Idempotency-Key": "abc"},
+	}
+
+	calls := 0
+	resp := WithIdempotency(ctx, event, store, testClient, nil, func() events.APIGatewayProxyResponse {
+		calls++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: `{"invalidated":1}`}
+	})
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if _, ok := store.records[scopedIdempotencyKey(testClient, event, "abc")]; !ok {
+		t.Error("expected response to be stored under the scoped key")
+	}
+	if _, ok := store.records["abc"]; ok {
+		t.Error("expected the raw Idempotency-Key not to be used as the store key")
+	}
+}
+
+func TestWithIdempotency_ReplayReturnsStoredResult(t *testing.T) {
+	ctx := context.Background()
+	store := newMockIdempotencyStore()
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Headers:    map[string]string{"idempotency-key": "abc"},
+	}
+
+	calls := 0
+	handler := func() events.APIGatewayProxyResponse {
+		calls++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: `{"invalidated":1}`}
+	}
+
+	first := WithIdempotency(ctx, event, store, testClient, nil, handler)
+	second := WithIdempotency(ctx, event, store, testClient, nil, handler)
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1 (replay must not re-execute)", calls)
+	}
+	if second.StatusCode != first.StatusCode || second.Body != first.Body {
+		t.Errorf("replayed response = %+v, want %+v", second, first)
+	}
+}
+
+func TestWithIdempotency_ServerErrorNotStored(t *testing.T) {
+	ctx := context.Background()
+	store := newMockIdempotencyStore()
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Headers:    map[string]string{"Idempotency-Key": "abc"},
+	}
+
+	calls := 0
+	handler := func() events.APIGatewayProxyResponse {
+		calls++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusInternalServerError}
+	}
+
+	WithIdempotency(ctx, event, store, testClient, nil, handler)
+	WithIdempotency(ctx, event, store, testClient, nil, handler)
+
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2 (5xx must be retryable)", calls)
+	}
+}
+
+func TestWithIdempotency_Bypass(t *testing.T) {
+	ctx := context.Background()
+	store := newMockIdempotencyStore()
+
+	tests := []struct {
+		name  string
+		event events.APIGatewayProxyRequest
+		store IdempotencyStore
+	}{
+		{
+			name:  "no key",
+			event: events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost},
+			store: store,
+		},
+		{
+			name: "non-mutating method",
+			event: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodGet,
+				Headers:    map[string]string{"Idempotency-Key": "abc"},
+			},
+			store: store,
+		},
+		{
+			name: "nil store",
+			event: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Headers:    map[string]string{"Idempotency-Key": "abc"},
+			},
+			store: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := func() events.APIGatewayProxyResponse {
+				calls++
+				return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+			}
+			WithIdempotency(ctx, tt.event, tt.store, testClient, nil, handler)
+			WithIdempotency(ctx, tt.event, tt.store, testClient, nil, handler)
+			if calls != 2 {
+				t.Errorf("handler calls = %d, want 2", calls)
+			}
+		})
+	}
+
+	if len(store.records) != 0 {
+		t.Errorf("expected no stored records, got %d", len(store.records))
+	}
+}
+
+func TestWithIdempotency_KeyScopedToClientMethodAndPath(t *testing.T) {
+	ctx := context.Background()
+	store := newMockIdempotencyStore()
+	base := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodDelete,
+		Path:       "/rates/USD/EUR",
+		Headers:    map[string]string{"Idempotency-Key": "abc"},
+	}
+	otherPath := base
+	otherPath.Path = "/rates/USD/GBP"
+	otherMethod := base
+	otherMethod.HTTPMethod = http.MethodPost
+
+	calls := 0
+	handler := func() events.APIGatewayProxyResponse {
+		calls++
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+	}
+
+	WithIdempotency(ctx, base, store, testClient, nil, handler)
+	WithIdempotency(ctx, base, store, "ip:198.51.100.1", nil, handler)
+	WithIdempotency(ctx, otherPath, store, testClient, nil, handler)
+	WithIdempotency(ctx, otherMethod, store, testClient, nil, handler)
+	if calls != 4 {
+		t.Errorf("handler calls = %d, want 4 (same key from another client, path, or method must execute)", calls)
+	}
+
+	WithIdempotency(ctx, base, store, testClient, nil, handler)
+	if calls != 4 {
+		t.Errorf("handler calls = %d, want 4 (same client, method, path, and key must replay)", calls)
+	}
+}
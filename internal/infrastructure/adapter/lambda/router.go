@@ -0,0 +1,219 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/middleware"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// Handle serves an API Gateway request with the full middleware chain.
+//
+// This function:
+// - Recovers handler panics into a generic 500 with a logged stack trace
+// - Replays responses for duplicate deliveries (RequestDeduplicator)
+// - Rate limits every route by client identity
+// - Routes the request to the matching handler
+// - Sends buffered rate events before returning (EventFlusher)
+func Handle(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	response := middleware.WithRecovery(ctx, deps.Logger, func() events.APIGatewayProxyResponse {
+		return deps.RequestDeduplicator.Handle(ctx, event, func() events.APIGatewayProxyResponse {
+			return middleware.WithRateLimit(ctx, event, deps.RateLimiter, deps.ClientIdentifier, deps.APIKeyAuthenticator, deps.Logger, func() events.APIGatewayProxyResponse {
+				return Route(ctx, event, deps)
+			})
+		})
+	})
+
+	// Publishing is best effort: failures are logged and never fail the request
+	if deps.EventFlusher != nil {
+		if err := deps.EventFlusher.Flush(ctx); err != nil {
+			log := deps.Logger
+			if log == nil {
+				log = logger.NewFromEnv()
+			}
+			log.WithContext(ctx).Warn("failed to publish rate events", "error", err.Error())
+		}
+	}
+	return response
+}
+
+// Route routes API Gateway requests to the appropriate handler.
+//
+// This function:
+// - Extracts path and method from the event
+// - Normalizes the path (duplicate and trailing slashes, see normalizePath)
+// - Marks ?pretty=true requests for indented JSON (see middleware.WithPrettyJSON)
+// - Matches the path first (matchRoute), then the method (allowedMethods)
+// - Answers OPTIONS preflights with 204 and the route's Allow and CORS headers
+// - Authenticates the API key on every route except deps.AuthSkipPaths
+// - Applies Idempotency-Key replay to mutating admin routes
+// - Fills in path parameters from the path when the caller is not API Gateway
+// - Returns 404 for unknown paths
+// - Returns 405 with an Allow header for known paths with an unsupported method
+func Route(ctx context.Context, event events.APIGatewayProxyRequest, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	path := normalizePath(event.Path)
+	method := event.HTTPMethod
+	ctx = middleware.WithPrettyJSON(ctx, event)
+
+	r, params := matchRoute(path)
+	if r == routeUnknown {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       fmt.Sprintf(`{"error":"Route not found: %s %s"}`, method, path),
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		}
+	}
+
+	allowed := allowedMethods(r, deps)
+	if method == http.MethodOptions {
+		return preflightResponse(allowed)
+	}
+	if !slices.Contains(allowed, method) {
+		return methodNotAllowed(method, path, allowed)
+	}
+	if params != nil {
+		event = withPathParameters(event, params)
+	}
+
+	return middleware.WithAuthentication(ctx, event, path, deps.APIKeyAuthenticator, deps.AuthSkipPaths, deps.Logger, func() events.APIGatewayProxyResponse {
+		return dispatch(ctx, event, r, deps)
+	})
+}
+
+// dispatch runs the handler of a matched route for an allowed method.
+func dispatch(ctx context.Context, event events.APIGatewayProxyRequest, r route, deps *HandlerDependencies) events.APIGatewayProxyResponse {
+	switch r {
+	case routeHealth:
+		return HealthHandler(ctx, event, deps)
+
+	case routeVersion:
+		return VersionHandler(ctx, event, deps)
+
+	case routeRates:
+		// Several bases: /rates?bases=USD,EUR,GBP
+		if _, ok := event.QueryStringParameters["bases"]; ok {
+			return GetMultiBaseRatesHandler(ctx, event, deps)
+		}
+		// No base: all rates for the configured default base currency
+		return GetDefaultRatesHandler(ctx, event, deps)
+
+	case routeRatesBase:
+		return GetAllRatesHandler(ctx, event, deps)
+
+	default: // routeRatesPair
+		if event.HTTPMethod == http.MethodDelete {
+			// Admin cache invalidation; replays are scoped to the caller,
+			// and an unverified API key does not select the scope
+			client := deps.ClientIdentifier.VerifiedIdentifier(ctx, event, deps.APIKeyAuthenticator)
+			return WithIdempotency(ctx, event, deps.IdempotencyStore, client.Key(), deps.Logger, func() events.APIGatewayProxyResponse {
+				return InvalidateRateHandler(ctx, event, deps)
+			})
+		}
+		return GetRateHandler(ctx, event, deps)
+	}
+}
+
+// route identifies a known path pattern.
+type route int
+
+const (
+	routeUnknown   route = iota
+	routeHealth          // /health
+	routeVersion         // /version
+	routeRates           // /rates
+	routeRatesBase       // /rates/{base}
+	routeRatesPair       // /rates/{base}/{target}
+)
+
+// matchRoute returns the route for a normalized path and its path parameters.
+func matchRoute(path string) (route, map[string]string) {
+	switch {
+	case path == "/health":
+		return routeHealth, nil
+	case path == "/version":
+		return routeVersion, nil
+	case path == "/rates":
+		return routeRates, nil
+	case strings.HasPrefix(path, "/rates/"):
+		// Path format: /rates/{base} or /rates/{base}/{target}
+		pathParts := strings.Split(strings.TrimPrefix(path, "/rates/"), "/")
+		if len(pathParts) == 2 {
+			return routeRatesPair, map[string]string{"base": pathParts[0], "target": pathParts[1]}
+		} else if len(pathParts) == 1 && pathParts[0] != "" {
+			return routeRatesBase, map[string]string{"base": pathParts[0]}
+		}
+	}
+	return routeUnknown, nil
+}
+
+// allowedMethods returns the methods a route supports, used for dispatch,
+// the Allow header of 405 responses, and OPTIONS preflights.
+// DELETE /rates/{base}/{target} is only offered when cache invalidation is configured.
+func allowedMethods(r route, deps *HandlerDependencies) []string {
+	if r == routeRatesPair && deps.InvalidateRateUseCase != nil {
+		return []string{http.MethodGet, http.MethodDelete, http.MethodOptions}
+	}
+	return []string{http.MethodGet, http.MethodOptions}
+}
+
+// corsAllowHeaders are the request headers browsers may send, matching the
+// API Gateway CORS configuration.
+const corsAllowHeaders = "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token,Idempotency-Key"
+
+// preflightResponse answers an OPTIONS request with 204, listing the route's
+// methods in the Allow and CORS headers.
+func preflightResponse(allowed []string) events.APIGatewayProxyResponse {
+	methods := strings.Join(allowed, ", ")
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusNoContent,
+		Headers: map[string]string{
+			"Allow":                        methods,
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Methods": methods,
+			"Access-Control-Allow-Headers": corsAllowHeaders,
+			"Access-Control-Max-Age":       "300",
+		},
+	}
+}
+
+// methodNotAllowed returns a 405 response for a known path, listing the
+// supported methods in the Allow header.
+func methodNotAllowed(method, path string, allowed []string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusMethodNotAllowed,
+		Body:       fmt.Sprintf(`{"error":"Method not allowed: %s %s"}`, method, path),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"Allow":        strings.Join(allowed, ", "),
+		},
+	}
+}
+
+// normalizePath collapses duplicate slashes and trims a trailing slash, so
+// "/health/" and "//rates//USD/EUR/" match the same routes as their canonical form.
+// The root path "/" is kept as is.
+func normalizePath(path string) string {
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// withPathParameters returns event with params as its path parameters, unless
+// API Gateway already extracted them.
+func withPathParameters(event events.APIGatewayProxyRequest, params map[string]string) events.APIGatewayProxyRequest {
+	if len(event.PathParameters) == 0 {
+		event.PathParameters = params
+	}
+	return event
+}
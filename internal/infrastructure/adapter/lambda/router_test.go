@@ -0,0 +1,346 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/middleware"
+)
+
+func TestRoute_PathParametersFromPath(t *testing.T) {
+	var got dto.GetRateRequest
+	deps := &HandlerDependencies{
+		GetRateUseCase: &mockGetRateUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+				got = req
+				return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85}, nil
+			},
+		},
+	}
+
+	// No PathParameters, as when the request comes from the HTTP server
+	resp := Route(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/rates/USD/EUR"}, deps)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200 (body: %s)", resp.StatusCode, resp.Body)
+	}
+	if got.Base != "USD" || got.Target != "EUR" {
+		t.Errorf("request = %s/%s, want USD/EUR", got.Base, got.Target)
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "/"},
+		{"", ""},
+		{"/health", "/health"},
+		{"/health/", "/health"},
+		{"//rates//USD/EUR/", "/rates/USD/EUR"},
+		{"///", "/"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizePath(tt.path); got != tt.want {
+			t.Errorf("normalizePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRoute_NormalizesPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantHealth bool
+		wantPair   string
+	}{
+		{"health", "/health", true, ""},
+		{"health trailing slash", "/health/", true, ""},
+		{"pair", "/rates/USD/EUR", false, "USD/EUR"},
+		{"pair trailing slash", "/rates/USD/EUR/", false, "USD/EUR"},
+		{"pair double slashes", "//rates//USD//EUR", false, "USD/EUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthCalled := false
+			var pair string
+			deps := &HandlerDependencies{
+				HealthCheckUseCase: &mockHealthCheckUseCase{
+					executeFunc: func(ctx context.Context, req dto.HealthCheckRequest) (dto.HealthCheckResponse, error) {
+						healthCalled = true
+						return dto.HealthCheckResponse{Status: "healthy"}, nil
+					},
+				},
+				GetRateUseCase: &mockGetRateUseCase{
+					executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+						pair = req.Base + "/" + req.Target
+						return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85}, nil
+					},
+				},
+			}
+
+			resp := Route(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: tt.path}, deps)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("StatusCode = %d, want 200 (body: %s)", resp.StatusCode, resp.Body)
+			}
+			if healthCalled != tt.wantHealth || pair != tt.wantPair {
+				t.Errorf("health called = %v, pair = %q; want %v, %q", healthCalled, pair, tt.wantHealth, tt.wantPair)
+			}
+		})
+	}
+}
+
+func TestRoute_NotFound(t *testing.T) {
+	paths := []string{"/unknown", "/rates/USD/EUR/GBP", "/"}
+	for _, path := range paths {
+		resp := Route(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: path}, &HandlerDependencies{})
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s StatusCode = %d, want 404", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestRoute_MethodNotAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		deps      *HandlerDependencies
+		wantAllow string
+	}{
+		{"POST /health", http.MethodPost, "/health", &HandlerDependencies{}, "GET, OPTIONS"},
+		{"POST /rates", http.MethodPost, "/rates", &HandlerDependencies{}, "GET, OPTIONS"},
+		{"PUT /rates/{base}", http.MethodPut, "/rates/USD", &HandlerDependencies{}, "GET, OPTIONS"},
+		{"DELETE pair without invalidation", http.MethodDelete, "/rates/USD/EUR", &HandlerDependencies{}, "GET, OPTIONS"},
+		{"POST pair with invalidation", http.MethodPost, "/rates/USD/EUR", &HandlerDependencies{InvalidateRateUseCase: &mockInvalidateRateUseCase{}}, "GET, DELETE, OPTIONS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := Route(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: tt.method, Path: tt.path}, tt.deps)
+
+			if resp.StatusCode != http.StatusMethodNotAllowed {
+				t.Errorf("StatusCode = %d, want 405", resp.StatusCode)
+			}
+			if got := resp.Headers["Allow"]; got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestRoute_Options(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		deps      *HandlerDependencies
+		wantAllow string
+	}{
+		{"health", "/health", &HandlerDependencies{}, "GET, OPTIONS"},
+		{"pair", "/rates/USD/EUR", &HandlerDependencies{}, "GET, OPTIONS"},
+		{"pair with invalidation", "/rates/USD/EUR/", &HandlerDependencies{InvalidateRateUseCase: &mockInvalidateRateUseCase{}}, "GET, DELETE, OPTIONS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := Route(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: http.MethodOptions, Path: tt.path}, tt.deps)
+
+			if resp.StatusCode != http.StatusNoContent {
+				t.Fatalf("StatusCode = %d, want 204", resp.StatusCode)
+			}
+			if got := resp.Headers["Allow"]; got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if got := resp.Headers["Access-Control-Allow-Methods"]; got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.wantAllow)
+			}
+			if resp.Headers["Access-Control-Allow-Origin"] == "" || resp.Headers["Access-Control-Allow-Headers"] == "" {
+				t.Errorf("missing CORS headers: %v", resp.Headers)
+			}
+		})
+	}
+
+	resp := Route(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: http.MethodOptions, Path: "/unknown"}, &HandlerDependencies{})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("OPTIONS /unknown StatusCode = %d, want 404", resp.StatusCode)
+	}
+}
+
+// staticSecretsManager returns a fixed API key.
+type staticSecretsManager string
+
+func (s staticSecretsManager) GetAPIKey(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+func TestRoute_AuthSkipPaths(t *testing.T) {
+	cfg := &config.Config{SecretsManager: config.SecretsManagerConfig{Enabled: true}}
+	deps := &HandlerDependencies{
+		APIKeyAuthenticator: middleware.NewAPIKeyAuthenticator(staticSecretsManager("valid-key"), cfg, true),
+		AuthSkipPaths:       []string{"/health", "/version"},
+		HealthCheckUseCase: &mockHealthCheckUseCase{
+			executeFunc: func(ctx context.Context, req dto.HealthCheckRequest) (dto.HealthCheckResponse, error) {
+				return dto.HealthCheckResponse{Status: "healthy"}, nil
+			},
+		},
+		GetRateUseCase: &mockGetRateUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+				return dto.RateResponse{Base: req.Base, Target: req.Target, Rate: 0.85}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		apiKey     string
+		wantStatus int
+	}{
+		{"health without key", "/health", "", http.StatusOK},
+		{"health trailing slash without key", "/health/", "", http.StatusOK},
+		{"rates without key", "/rates/USD/EUR", "", http.StatusUnauthorized},
+		{"rates with invalid key", "/rates/USD/EUR", "wrong", http.StatusUnauthorized},
+		{"rates with key", "/rates/USD/EUR", "valid-key", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: tt.path}
+			if tt.apiKey != "" {
+				event.Headers = map[string]string{"X-API-Key": tt.apiKey}
+			}
+
+			resp := Route(context.Background(), event, deps)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d (body: %s)", resp.StatusCode, tt.wantStatus, resp.Body)
+			}
+		})
+	}
+}
+
+// mockInvalidateRateUseCase records invalidated pairs.
+type mockInvalidateRateUseCase struct {
+	requests []dto.InvalidateRateRequest
+}
+
+func (m *mockInvalidateRateUseCase) Execute(ctx context.Context, req dto.InvalidateRateRequest) (dto.InvalidateRateResponse, error) {
+	m.requests = append(m.requests, req)
+	return dto.InvalidateRateResponse{Base: req.Base, Target: req.Target, Invalidated: true}, nil
+}
+
+func TestRoute_InvalidateRateIdempotent(t *testing.T) {
+	invalidate := &mockInvalidateRateUseCase{}
+	deps := &HandlerDependencies{
+		InvalidateRateUseCase: invalidate,
+		IdempotencyStore:      newMockIdempotencyStore(),
+	}
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodDelete,
+		Path:       "/rates/USD/EUR",
+		Headers:    map[string]string{"Idempotency-Key": "retry-1"},
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Identity: events.APIGatewayRequestIdentity{SourceIP: "203.0.113.7"},
+		},
+	}
+
+	first := Route(context.Background(), event, deps)
+	second := Route(context.Background(), event, deps)
+
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200 (body: %s)", first.StatusCode, first.Body)
+	}
+	if second.StatusCode != first.StatusCode || second.Body != first.Body {
+		t.Errorf("replayed response = %d %s, want %d %s", second.StatusCode, second.Body, first.StatusCode, first.Body)
+	}
+	if len(invalidate.requests) != 1 {
+		t.Fatalf("invalidations = %d, want 1 (retry must replay)", len(invalidate.requests))
+	}
+	if got := invalidate.requests[0]; got.Base != "USD" || got.Target != "EUR" {
+		t.Errorf("invalidated %s/%s, want USD/EUR", got.Base, got.Target)
+	}
+}
+
+func TestRoute_InvalidateRateDisabled(t *testing.T) {
+	event := events.APIGatewayProxyRequest{HTTPMethod: http.MethodDelete, Path: "/rates/USD/EUR"}
+	resp := Route(context.Background(), event, &HandlerDependencies{})
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("StatusCode = %d, want 405 when no InvalidateRateUseCase is configured", resp.StatusCode)
+	}
+}
+
+func TestHandle_RecoversPanic(t *testing.T) {
+	flusher := &mockEventFlusher{}
+	deps := &HandlerDependencies{
+		GetRateUseCase: &mockGetRateUseCase{
+			executeFunc: func(ctx context.Context, req dto.GetRateRequest) (dto.RateResponse, error) {
+				panic("use case bug")
+			},
+		},
+		EventFlusher: flusher,
+	}
+
+	resp := Handle(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/rates/USD/EUR"}, deps)
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("StatusCode = %d, want 500", resp.StatusCode)
+	}
+	if flusher.calls != 1 {
+		t.Errorf("Flush calls = %d, want 1 after a panic", flusher.calls)
+	}
+}
+
+// mockEventFlusher counts Flush calls.
+type mockEventFlusher struct {
+	calls int
+	err   error
+}
+
+func (m *mockEventFlusher) Flush(ctx context.Context) error {
+	m.calls++
+	return m.err
+}
+
+func TestHandle_FlushesEvents(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"flush succeeds", nil},
+		{"flush error does not fail the request", errors.New("sns unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flusher := &mockEventFlusher{err: tt.err}
+			deps := &HandlerDependencies{
+				HealthCheckUseCase: &mockHealthCheckUseCase{
+					executeFunc: func(ctx context.Context, req dto.HealthCheckRequest) (dto.HealthCheckResponse, error) {
+						return dto.HealthCheckResponse{Status: "healthy"}, nil
+					},
+				},
+				EventFlusher: flusher,
+			}
+
+			resp := Handle(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/health"}, deps)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+			}
+			if flusher.calls != 1 {
+				t.Errorf("Flush calls = %d, want 1", flusher.calls)
+			}
+		})
+	}
+}
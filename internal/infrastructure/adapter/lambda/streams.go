@@ -0,0 +1,123 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// StreamOperation identifies the kind of change carried by a stream record.
+type StreamOperation string
+
+const (
+	// StreamOperationInsert indicates a newly cached rate.
+	StreamOperationInsert StreamOperation = "INSERT"
+
+	// StreamOperationModify indicates an updated cached rate.
+	StreamOperationModify StreamOperation = "MODIFY"
+
+	// StreamOperationRemove indicates a deleted or TTL-expired cached rate.
+	StreamOperationRemove StreamOperation = "REMOVE"
+)
+
+// StreamProcessor handles exchange rate changes from DynamoDB Streams,
+// e.g. to update a read replica or invalidate a CDN.
+//
+// For INSERT and MODIFY the rate is decoded from NewImage; for REMOVE it is
+// decoded from OldImage (the stream must include the required images).
+type StreamProcessor interface {
+	Process(ctx context.Context, op StreamOperation, rate *entity.ExchangeRate) error
+}
+
+// LoggingStreamProcessor logs each change. It is the default processor.
+type LoggingStreamProcessor struct {
+	Logger *logger.Logger
+}
+
+// Process implements StreamProcessor.
+func (p LoggingStreamProcessor) Process(ctx context.Context, op StreamOperation, rate *entity.ExchangeRate) error {
+	log := p.Logger
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log.WithContext(ctx).Info("exchange rate changed",
+		"operation", string(op),
+		"base", rate.Base.String(),
+		"target", rate.Target.String(),
+		"rate", rate.Rate,
+	)
+	return nil
+}
+
+// StreamHandler handles a DynamoDB Streams event.
+//
+// This function:
+// - Decodes each record's image into an ExchangeRate
+// - Dispatches INSERT, MODIFY, and REMOVE records to the processor
+// - Skips items that are not exchange rates (e.g. idempotency records)
+// - Reports failed records as batch item failures so only they are retried
+//
+// Records that cannot be decoded are logged and skipped - retrying them would
+// never succeed.
+func StreamHandler(ctx context.Context, event events.DynamoDBEvent, processor StreamProcessor, log *logger.Logger) events.DynamoDBEventResponse {
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log = log.WithContext(ctx)
+
+	response := events.DynamoDBEventResponse{BatchItemFailures: []events.DynamoDBBatchItemFailure{}}
+
+	for _, record := range event.Records {
+		op, rate, err := decodeStreamRecord(record)
+		if err != nil {
+			if !errors.Is(err, dynamodb.ErrNotRateItem) {
+				log.Warn("skipping undecodable stream record",
+					"event_id", record.EventID,
+					"event_name", record.EventName,
+					"error", err.Error(),
+				)
+			}
+			continue
+		}
+
+		if err := processor.Process(ctx, op, rate); err != nil {
+			log.Error("failed to process stream record",
+				"event_id", record.EventID,
+				"operation", string(op),
+				"error", err.Error(),
+			)
+			response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{
+				ItemIdentifier: record.Change.SequenceNumber,
+			})
+		}
+	}
+
+	return response
+}
+
+// decodeStreamRecord determines the operation of a stream record and decodes
+// the relevant image.
+func decodeStreamRecord(record events.DynamoDBEventRecord) (StreamOperation, *entity.ExchangeRate, error) {
+	op := StreamOperation(record.EventName)
+
+	var image map[string]events.DynamoDBAttributeValue
+	switch op {
+	case StreamOperationInsert, StreamOperationModify:
+		image = record.Change.NewImage
+	case StreamOperationRemove:
+		image = record.Change.OldImage
+	default:
+		return "", nil, fmt.Errorf("unknown stream event name %q", record.EventName)
+	}
+
+	rate, err := dynamodb.StreamImageToEntity(image)
+	if err != nil {
+		return "", nil, err
+	}
+	return op, rate, nil
+}
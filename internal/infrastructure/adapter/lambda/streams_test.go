@@ -0,0 +1,136 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// processedChange records a single StreamProcessor call.
+type processedChange struct {
+	op   StreamOperation
+	rate *entity.ExchangeRate
+}
+
+// mockStreamProcessor records processed changes and optionally fails.
+type mockStreamProcessor struct {
+	changes []processedChange
+	err     error
+}
+
+func (m *mockStreamProcessor) Process(ctx context.Context, op StreamOperation, rate *entity.ExchangeRate) error {
+	m.changes = append(m.changes, processedChange{op: op, rate: rate})
+	return m.err
+}
+
+// rateImage builds a stream image for an exchange rate item.
+func rateImage(base, target, rate string) map[string]events.DynamoDBAttributeValue {
+	return map[string]events.DynamoDBAttributeValue{
+		"PK":        events.NewStringAttribute("RATE#" + base + "#" + target),
+		"Base":      events.NewStringAttribute(base),
+		"Target":    events.NewStringAttribute(target),
+		"Rate":      events.NewNumberAttribute(rate),
+		"Timestamp": events.NewNumberAttribute("1700000000"),
+		"Stale":     events.NewBooleanAttribute(false),
+		"ttl":       events.NewNumberAttribute("1700003600"),
+	}
+}
+
+func streamRecord(eventName, sequence string, newImage, oldImage map[string]events.DynamoDBAttributeValue) events.DynamoDBEventRecord {
+	return events.DynamoDBEventRecord{
+		EventID:   "event-" + sequence,
+		EventName: eventName,
+		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: sequence,
+			NewImage:       newImage,
+			OldImage:       oldImage,
+		},
+	}
+}
+
+func TestStreamHandler_DispatchesOperations(t *testing.T) {
+	event := events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{
+			streamRecord("INSERT", "1", rateImage("USD", "EUR", "0.85"), nil),
+			streamRecord("MODIFY", "2", rateImage("USD", "EUR", "0.86"), rateImage("USD", "EUR", "0.85")),
+			streamRecord("REMOVE", "3", nil, rateImage("USD", "GBP", "0.73")),
+		},
+	}
+
+	processor := &mockStreamProcessor{}
+	resp := StreamHandler(context.Background(), event, processor, nil)
+
+	if len(resp.BatchItemFailures) != 0 {
+		t.Errorf("BatchItemFailures = %v, want none", resp.BatchItemFailures)
+	}
+	if len(processor.changes) != 3 {
+		t.Fatalf("processed %d changes, want 3", len(processor.changes))
+	}
+
+	tests := []struct {
+		op     StreamOperation
+		target string
+		rate   float64
+	}{
+		{op: StreamOperationInsert, target: "EUR", rate: 0.85},
+		{op: StreamOperationModify, target: "EUR", rate: 0.86},
+		{op: StreamOperationRemove, target: "GBP", rate: 0.73},
+	}
+	for i, tt := range tests {
+		got := processor.changes[i]
+		if got.op != tt.op {
+			t.Errorf("change %d op = %s, want %s", i, got.op, tt.op)
+		}
+		if got.rate.Base.String() != "USD" || got.rate.Target.String() != tt.target {
+			t.Errorf("change %d pair = %s/%s, want USD/%s", i, got.rate.Base, got.rate.Target, tt.target)
+		}
+		if got.rate.Rate != tt.rate {
+			t.Errorf("change %d rate = %v, want %v", i, got.rate.Rate, tt.rate)
+		}
+	}
+}
+
+func TestStreamHandler_SkipsNonRateAndInvalidRecords(t *testing.T) {
+	idempotencyImage := map[string]events.DynamoDBAttributeValue{
+		"PK":      events.NewStringAttribute("IDEMPOTENCY#abc"),
+		"Payload": events.NewBinaryAttribute([]byte("{}")),
+	}
+	invalidImage := rateImage("USD", "EUR", "0.85")
+	invalidImage["Base"] = events.NewStringAttribute("INVALID")
+
+	event := events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{
+			streamRecord("INSERT", "1", idempotencyImage, nil),
+			streamRecord("INSERT", "2", invalidImage, nil),
+			streamRecord("REMOVE", "3", nil, nil),
+		},
+	}
+
+	processor := &mockStreamProcessor{}
+	resp := StreamHandler(context.Background(), event, processor, nil)
+
+	if len(processor.changes) != 0 {
+		t.Errorf("processed %d changes, want 0", len(processor.changes))
+	}
+	if len(resp.BatchItemFailures) != 0 {
+		t.Errorf("BatchItemFailures = %v, want none (undecodable records are not retried)", resp.BatchItemFailures)
+	}
+}
+
+func TestStreamHandler_ReportsProcessorFailures(t *testing.T) {
+	event := events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{
+			streamRecord("INSERT", "42", rateImage("USD", "EUR", "0.85"), nil),
+		},
+	}
+
+	processor := &mockStreamProcessor{err: errors.New("replica unavailable")}
+	resp := StreamHandler(context.Background(), event, processor, nil)
+
+	if len(resp.BatchItemFailures) != 1 || resp.BatchItemFailures[0].ItemIdentifier != "42" {
+		t.Errorf("BatchItemFailures = %v, want [42]", resp.BatchItemFailures)
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// maxRequestBodyBytes bounds the request body read into the API Gateway event.
+const maxRequestBodyBytes = 1 << 20
+
+// APIGatewayFunc handles a request in API Gateway proxy form, like the Lambda handlers.
+type APIGatewayFunc func(ctx context.Context, event events.APIGatewayProxyRequest) events.APIGatewayProxyResponse
+
+// APIGatewayHandler adapts fn to an http.Handler.
+//
+// Each HTTP request is converted to an API Gateway proxy event (method, path,
+// headers, query parameters, body, and the peer IP as the source IP), and the
+// returned proxy response is written back. This lets the Lambda routes run
+// unchanged behind the HTTP server.
+func APIGatewayHandler(fn APIGatewayFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := toProxyRequest(r)
+		if err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		writeProxyResponse(w, fn(r.Context(), event))
+	})
+}
+
+// toProxyRequest converts an HTTP request to an API Gateway proxy event.
+// Header names are lowercased, as API Gateway HTTP APIs deliver them; single-value
+// maps hold the first value of multi-value headers and parameters.
+func toProxyRequest(r *http.Request) (events.APIGatewayProxyRequest, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodyBytes))
+	if err != nil {
+		return events.APIGatewayProxyRequest{}, err
+	}
+
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod:                      r.Method,
+		Path:                            r.URL.Path,
+		Headers:                         make(map[string]string, len(r.Header)),
+		MultiValueHeaders:               make(map[string][]string, len(r.Header)),
+		QueryStringParameters:           make(map[string]string),
+		MultiValueQueryStringParameters: make(map[string][]string),
+		Body:                            string(body),
+	}
+	for name, values := range r.Header {
+		name = strings.ToLower(name)
+		event.Headers[name] = values[0]
+		event.MultiValueHeaders[name] = values
+	}
+	for name, values := range r.URL.Query() {
+		event.QueryStringParameters[name] = values[0]
+		event.MultiValueQueryStringParameters[name] = values
+	}
+
+	sourceIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		sourceIP = host
+	}
+	event.RequestContext.Identity.SourceIP = sourceIP
+	event.RequestContext.HTTPMethod = r.Method
+	event.RequestContext.Path = r.URL.Path
+
+	return event, nil
+}
+
+// writeProxyResponse writes an API Gateway proxy response to w.
+func writeProxyResponse(w http.ResponseWriter, resp events.APIGatewayProxyResponse) {
+	for name, value := range resp.Headers {
+		w.Header().Set(name, value)
+	}
+	for name, values := range resp.MultiValueHeaders {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	body := []byte(resp.Body)
+	if resp.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(resp.Body)
+		if err != nil {
+			http.Error(w, "invalid response body", http.StatusInternalServerError)
+			return
+		}
+		body = decoded
+	}
+
+	statusCode := resp.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestAPIGatewayHandler(t *testing.T) {
+	var got events.APIGatewayProxyRequest
+	handler := APIGatewayHandler(func(ctx context.Context, event events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		got = event
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusTeapot,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       `{"ok":true}`,
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/rates/USD?bases=USD,EUR", strings.NewReader("payload"))
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("X-API-Key", "key-123")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if got.HTTPMethod != http.MethodGet || got.Path != "/rates/USD" {
+		t.Errorf("event = %s %s, want GET /rates/USD", got.HTTPMethod, got.Path)
+	}
+	if got.QueryStringParameters["bases"] != "USD,EUR" {
+		t.Errorf("bases = %q, want USD,EUR", got.QueryStringParameters["bases"])
+	}
+	if got.Headers["x-api-key"] != "key-123" {
+		t.Errorf("x-api-key header = %q, want key-123", got.Headers["x-api-key"])
+	}
+	if got.RequestContext.Identity.SourceIP != "203.0.113.7" {
+		t.Errorf("SourceIP = %q, want 203.0.113.7", got.RequestContext.Identity.SourceIP)
+	}
+	if got.Body != "payload" {
+		t.Errorf("Body = %q, want payload", got.Body)
+	}
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusTeapot)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", resp.Header.Get("Content-Type"))
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestAPIGatewayHandler_Base64Body(t *testing.T) {
+	handler := APIGatewayHandler(func(ctx context.Context, event events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return events.APIGatewayProxyResponse{Body: "aGVsbG8=", IsBase64Encoded: true}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", rec.Code)
+	}
+	if rec.Body.String() != "hello" {
+		t.Errorf("body = %q, want hello", rec.Body.String())
+	}
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// Config holds HTTP server configuration.
+type Config struct {
+	Addr              string        // Listen address, e.g. ":8080"
+	ShutdownTimeout   time.Duration // Maximum time to wait for in-flight requests on shutdown
+	ReadHeaderTimeout time.Duration // Maximum time to read request headers
+}
+
+// DefaultConfig returns a default server configuration.
+//
+// Default values:
+// - Addr: ":8080"
+// - ShutdownTimeout: 25 seconds (below the usual 30s SIGTERM grace period)
+// - ReadHeaderTimeout: 10 seconds
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		ShutdownTimeout:   25 * time.Second,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// Server is an HTTP server that drains in-flight requests on shutdown.
+// It is used to run the service outside of AWS Lambda.
+//
+// Shutdown sequence (when the Run context is cancelled, e.g. on SIGTERM):
+// - Stop accepting new connections
+// - Wait for in-flight requests, up to ShutdownTimeout
+// - Run OnShutdown hooks in registration order (e.g. RateLimiter.Stop)
+type Server struct {
+	httpServer *http.Server
+	config     Config
+	logger     *logger.Logger
+	inFlight   atomic.Int64
+
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// New creates a new Server serving handler.
+// Zero config values fall back to DefaultConfig; a nil logger uses the default logger.
+func New(handler http.Handler, config Config, log *logger.Logger) *Server {
+	defaults := DefaultConfig()
+	if config.Addr == "" {
+		config.Addr = defaults.Addr
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = defaults.ShutdownTimeout
+	}
+	if config.ReadHeaderTimeout <= 0 {
+		config.ReadHeaderTimeout = defaults.ReadHeaderTimeout
+	}
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+
+	s := &Server{
+		config: config,
+		logger: log,
+	}
+	s.httpServer = &http.Server{
+		Addr:              config.Addr,
+		Handler:           s.trackInFlight(handler),
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+	}
+	return s
+}
+
+// OnShutdown registers a function to run after in-flight requests have drained,
+// e.g. stopping the rate limiter's cleanup ticker or closing idle DynamoDB connections.
+func (s *Server) OnShutdown(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, fn)
+}
+
+// InFlight returns the number of requests currently being handled.
+func (s *Server) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// trackInFlight wraps handler to count in-flight requests.
+func (s *Server) trackInFlight(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Run listens on the configured address and serves until ctx is cancelled,
+// then shuts down gracefully.
+//
+// Use signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt) to tie ctx to signals.
+//
+// Returns nil after a clean shutdown, or an error if listening fails or
+// in-flight requests did not finish within ShutdownTimeout.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx is cancelled, then shuts down gracefully.
+// It behaves like Run but accepts an existing listener (useful for tests).
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		s.logger.Info("HTTP server listening", "addr", listener.Addr().String())
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// Server stopped on its own (e.g. listener failure)
+		s.runHooks()
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("shutting down HTTP server",
+		"in_flight", s.InFlight(),
+		"timeout", s.config.ShutdownTimeout.String(),
+	)
+
+	// ctx is already cancelled - use a fresh context for the drain deadline
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	shutdownErr := s.httpServer.Shutdown(shutdownCtx)
+	if shutdownErr != nil {
+		s.logger.Warn("HTTP server shutdown did not complete",
+			"in_flight", s.InFlight(),
+			"error", shutdownErr.Error(),
+		)
+	} else {
+		s.logger.Info("in-flight requests drained")
+	}
+
+	s.runHooks()
+	s.logger.Info("HTTP server stopped")
+
+	if shutdownErr != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", shutdownErr)
+	}
+	return nil
+}
+
+// runHooks runs the registered shutdown hooks in registration order.
+func (s *Server) runHooks() {
+	s.mu.Lock()
+	hooks := s.hooks
+	s.hooks = nil
+	s.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
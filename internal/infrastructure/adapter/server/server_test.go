@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServer_GracefulShutdownCompletesInFlightRequest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+
+	srv := New(handler, Config{ShutdownTimeout: 5 * time.Second}, nil)
+	var hookCalls atomic.Int32
+	srv.OnShutdown(func() { hookCalls.Add(1) })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	url := "http://" + listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Serve(ctx, listener) }()
+
+	// Issue a request that stays in flight until released
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	if got := srv.InFlight(); got != 1 {
+		t.Errorf("InFlight() = %d, want 1", got)
+	}
+
+	// Signal shutdown while the request is in flight
+	cancel()
+
+	select {
+	case err := <-runErr:
+		t.Fatalf("Serve() returned %v before the in-flight request completed", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if hookCalls.Load() != 0 {
+		t.Error("shutdown hook ran before in-flight requests drained")
+	}
+
+	close(release)
+
+	res := <-response
+	if res.err != nil {
+		t.Fatalf("in-flight request failed: %v", res.err)
+	}
+	if res.body != "done" {
+		t.Errorf("body = %q, want done", res.body)
+	}
+
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Serve() error = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after in-flight request completed")
+	}
+
+	if hookCalls.Load() != 1 {
+		t.Errorf("shutdown hook calls = %d, want 1", hookCalls.Load())
+	}
+	if got := srv.InFlight(); got != 0 {
+		t.Errorf("InFlight() after shutdown = %d, want 0", got)
+	}
+
+	// New requests are refused after shutdown
+	if resp, err := http.Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("expected request after shutdown to fail")
+	}
+}
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	srv := New(handler, Config{ShutdownTimeout: 50 * time.Millisecond}, nil)
+	var hookCalled atomic.Bool
+	srv.OnShutdown(func() { hookCalled.Store(true) })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Serve(ctx, listener) }()
+
+	go func() {
+		if resp, err := http.Get("http://" + listener.Addr().String()); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+
+	select {
+	case err := <-runErr:
+		if err == nil {
+			t.Error("Serve() error = nil, want shutdown timeout error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after shutdown timeout")
+	}
+
+	if !hookCalled.Load() {
+		t.Error("expected shutdown hooks to run even if draining timed out")
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	srv := New(http.NotFoundHandler(), Config{}, nil)
+
+	if srv.config != DefaultConfig() {
+		t.Errorf("config = %+v, want %+v", srv.config, DefaultConfig())
+	}
+	if srv.httpServer.ReadHeaderTimeout != DefaultConfig().ReadHeaderTimeout {
+		t.Errorf("ReadHeaderTimeout = %v, want %v", srv.httpServer.ReadHeaderTimeout, DefaultConfig().ReadHeaderTimeout)
+	}
+}
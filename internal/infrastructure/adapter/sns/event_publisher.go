@@ -0,0 +1,198 @@
+package sns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+)
+
+const (
+	// maxBatchSize is the maximum number of messages in one SNS PublishBatch request.
+	maxBatchSize = 10
+
+	// maxConcurrentBatches bounds the PublishBatch requests sent at once.
+	maxConcurrentBatches = 4
+
+	// publishTimeout bounds a single PublishBatch request.
+	publishTimeout = 5 * time.Second
+)
+
+// publishBatchAPI is the subset of the SNS client used by EventPublisher.
+type publishBatchAPI interface {
+	PublishBatch(ctx context.Context, params *sns.PublishBatchInput, optFns ...func(*sns.Options)) (*sns.PublishBatchOutput, error)
+}
+
+// EventPublisher publishes rate events to an SNS topic.
+//
+// This publisher:
+// - Sends events as JSON messages with the SNS PublishBatch action (10 per request)
+// - Buffers events; a full batch is sent in the background, so a base refresh
+// that saves hundreds of rates does not wait on one SNS call per rate
+// - Sends the remaining events and waits for background batches on Flush
+//
+// Flush must be called before the request ends (e.g. before a Lambda invocation
+// returns), because Lambda freezes the sandbox once the handler has returned.
+type EventPublisher struct {
+	client   publishBatchAPI
+	topicARN string
+	sem      chan struct{} // bounds concurrent background batches
+
+	mu      sync.Mutex
+	pending []repository.RateEvent
+	errs    []error // errors from background batches, reported by Flush
+	wg      sync.WaitGroup
+}
+
+// NewEventPublisher creates a new SNS EventPublisher.
+//
+// Parameters:
+//   - cfg: The AWS SDK configuration (credentials, HTTP client, default region)
+//   - topicARN: The ARN of the SNS topic to publish to
+//
+// The client uses the topic's region; the SDK resolves the endpoint for any partition.
+// Returns an error if the topic ARN is invalid.
+func NewEventPublisher(cfg aws.Config, topicARN string) (*EventPublisher, error) {
+	parsed, err := arn.Parse(topicARN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SNS topic ARN: %w", err)
+	}
+	if parsed.Service != "sns" {
+		return nil, fmt.Errorf("invalid SNS topic ARN: service is %q, want \"sns\"", parsed.Service)
+	}
+
+	client := sns.NewFromConfig(cfg, func(o *sns.Options) {
+		if parsed.Region != "" {
+			o.Region = parsed.Region
+		}
+	})
+	return newEventPublisher(client, topicARN), nil
+}
+
+// newEventPublisher creates an EventPublisher for the given client.
+func newEventPublisher(client publishBatchAPI, topicARN string) *EventPublisher {
+	return &EventPublisher{
+		client:   client,
+		topicARN: topicARN,
+		sem:      make(chan struct{}, maxConcurrentBatches),
+	}
+}
+
+// Publish implements repository.EventPublisher.
+//
+// The event is buffered; once a full batch is buffered it is sent in the
+// background. Errors from sending are reported by Flush.
+func (p *EventPublisher) Publish(ctx context.Context, event repository.RateEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	p.mu.Lock()
+	p.pending = append(p.pending, event)
+	var batch []repository.RateEvent
+	if len(p.pending) >= maxBatchSize {
+		batch = p.pending
+		p.pending = nil
+	}
+	p.mu.Unlock()
+
+	if batch != nil {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.sem <- struct{}{}
+			defer func() { <-p.sem }()
+
+			// Not tied to the caller's cancellation; Flush waits for this send
+			sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+			defer cancel()
+			if err := p.send(sendCtx, batch); err != nil {
+				p.mu.Lock()
+				p.errs = append(p.errs, err)
+				p.mu.Unlock()
+			}
+		}()
+	}
+	return nil
+}
+
+// Flush implements repository.EventFlusher.
+//
+// It sends all buffered events, waits for background batches, and returns
+// the errors of every batch sent since the previous Flush.
+func (p *EventPublisher) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	batch := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+
+	var flushErr error
+	if len(batch) > 0 {
+		flushErr = p.send(ctx, batch)
+	}
+	p.wg.Wait()
+
+	p.mu.Lock()
+	errs := append(p.errs, flushErr)
+	p.errs = nil
+	p.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// send publishes events in PublishBatch requests of at most maxBatchSize messages.
+func (p *EventPublisher) send(ctx context.Context, events []repository.RateEvent) error {
+	var errs []error
+	for start := 0; start < len(events); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(events))
+		if err := p.publishBatch(ctx, events[start:end]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// publishBatch sends one PublishBatch request.
+// Returns an error if the request fails or any entry is rejected.
+func (p *EventPublisher) publishBatch(ctx context.Context, events []repository.RateEvent) error {
+	entries := make([]types.PublishBatchRequestEntry, 0, len(events))
+	for i, event := range events {
+		message, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rate event: %w", err)
+		}
+		entries = append(entries, types.PublishBatchRequestEntry{
+			Id:      aws.String(strconv.Itoa(i)),
+			Message: aws.String(string(message)),
+		})
+	}
+
+	output, err := p.client.PublishBatch(ctx, &sns.PublishBatchInput{
+		TopicArn:                   aws.String(p.topicARN),
+		PublishBatchRequestEntries: entries,
+	})
+	if err != nil {
+		return fmt.Errorf("SNS publish batch failed: %w", err)
+	}
+	if len(output.Failed) > 0 {
+		first := output.Failed[0]
+		return fmt.Errorf("SNS rejected %d of %d events: %s: %s",
+			len(output.Failed), len(entries), aws.ToString(first.Code), aws.ToString(first.Message))
+	}
+	return nil
+}
+
+// Ensure EventPublisher implements the repository.EventPublisher and EventFlusher interfaces.
+var (
+	_ repository.EventPublisher = (*EventPublisher)(nil)
+	_ repository.EventFlusher   = (*EventPublisher)(nil)
+)
@@ -0,0 +1,163 @@
+package sns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/repository"
+)
+
+const testTopicARN = "arn:aws:sns:eu-west-1:123456789012:rate-updates"
+
+// mockSNSClient records PublishBatch requests.
+type mockSNSClient struct {
+	mu      sync.Mutex
+	batches []*sns.PublishBatchInput
+	err     error
+	failed  []types.BatchResultErrorEntry
+}
+
+func (m *mockSNSClient) PublishBatch(ctx context.Context, params *sns.PublishBatchInput, optFns ...func(*sns.Options)) (*sns.PublishBatchOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batches = append(m.batches, params)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &sns.PublishBatchOutput{Failed: m.failed}, nil
+}
+
+func (m *mockSNSClient) messages(t *testing.T) []repository.RateEvent {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []repository.RateEvent
+	for _, batch := range m.batches {
+		if aws.ToString(batch.TopicArn) != testTopicARN {
+			t.Errorf("TopicArn = %q, want %q", aws.ToString(batch.TopicArn), testTopicARN)
+		}
+		if len(batch.PublishBatchRequestEntries) > maxBatchSize {
+			t.Errorf("batch has %d entries, want at most %d", len(batch.PublishBatchRequestEntries), maxBatchSize)
+		}
+		for _, entry := range batch.PublishBatchRequestEntries {
+			var event repository.RateEvent
+			if err := json.Unmarshal([]byte(aws.ToString(entry.Message)), &event); err != nil {
+				t.Fatalf("failed to decode message: %v", err)
+			}
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestNewEventPublisher(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1"}
+	if _, err := NewEventPublisher(cfg, testTopicARN); err != nil {
+		t.Fatalf("NewEventPublisher() error = %v", err)
+	}
+
+	invalid := []string{"", "not-an-arn", "arn:aws:sqs:eu-west-1:123456789012:queue"}
+	for _, topicARN := range invalid {
+		if _, err := NewEventPublisher(cfg, topicARN); err == nil {
+			t.Errorf("NewEventPublisher(%q) expected error, got nil", topicARN)
+		}
+	}
+}
+
+func TestEventPublisher_PublishAndFlush(t *testing.T) {
+	client := &mockSNSClient{}
+	p := newEventPublisher(client, testTopicARN)
+
+	expiresAt := int64(1700003600)
+	event := repository.RateEvent{
+		Type:      repository.RateEventTypeUpdated,
+		Base:      "USD",
+		Target:    "EUR",
+		Rate:      0.85,
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+		ExpiresAt: &expiresAt,
+	}
+	if err := p.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got := len(client.messages(t)); got != 0 {
+		t.Errorf("sent %d events before Flush, want 0 (buffered)", got)
+	}
+
+	if err := p.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	published := client.messages(t)
+	if len(published) != 1 {
+		t.Fatalf("published %d events, want 1", len(published))
+	}
+	if published[0].Base != "USD" || published[0].Target != "EUR" || published[0].Rate != 0.85 {
+		t.Errorf("published event = %+v, want USD/EUR 0.85", published[0])
+	}
+	if published[0].ExpiresAt == nil || *published[0].ExpiresAt != expiresAt {
+		t.Errorf("published ExpiresAt = %v, want %d", published[0].ExpiresAt, expiresAt)
+	}
+}
+
+func TestEventPublisher_Batches(t *testing.T) {
+	client := &mockSNSClient{}
+	p := newEventPublisher(client, testTopicARN)
+
+	for i := 0; i < 25; i++ {
+		if err := p.Publish(context.Background(), repository.RateEvent{Base: "USD", Target: "EUR", Rate: float64(i)}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := p.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := len(client.messages(t)); got != 25 {
+		t.Errorf("published %d events, want 25", got)
+	}
+	if got := len(client.batches); got != 3 {
+		t.Errorf("PublishBatch calls = %d, want 3", got)
+	}
+}
+
+func TestEventPublisher_FlushReportsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *mockSNSClient
+		wantErr string
+	}{
+		{"request error", &mockSNSClient{err: errors.New("AuthorizationError")}, "AuthorizationError"},
+		{"rejected entries", &mockSNSClient{failed: []types.BatchResultErrorEntry{{Id: aws.String("0"), Code: aws.String("InternalError"), Message: aws.String("boom")}}}, "InternalError"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newEventPublisher(tt.client, testTopicARN)
+
+			// A full background batch plus a partial batch sent by Flush
+			for i := 0; i < maxBatchSize+1; i++ {
+				if err := p.Publish(context.Background(), repository.RateEvent{Base: "USD", Target: "EUR"}); err != nil {
+					t.Fatalf("Publish() error = %v", err)
+				}
+			}
+
+			err := p.Flush(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Flush() error = %v, want %q", err, tt.wantErr)
+			}
+			if err := p.Flush(context.Background()); err != nil {
+				t.Errorf("second Flush() error = %v, want nil", err)
+			}
+		})
+	}
+}
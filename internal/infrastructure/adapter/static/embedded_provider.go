@@ -0,0 +1,126 @@
+// Package static provides a bundled snapshot of major-currency exchange rates
+// for use as a last-resort fallback in development and demo deployments.
+package static
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+//go:embed rates.json
+var snapshotJSON []byte
+
+// snapshot is the format of rates.json: rates of major currencies against one base.
+type snapshot struct {
+	Date  string             `json:"date"`
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// EmbeddedProvider serves rates from a snapshot compiled into the binary.
+//
+// The snapshot is old and covers only major currencies: every rate is marked
+// stale and carries the snapshot date as its timestamp. Pairs without the
+// snapshot base are derived as cross rates through it.
+//
+// It exists so development and demo deployments keep answering during a total
+// outage. It must never be used where rate accuracy matters (e.g. trading).
+//
+// EmbeddedProvider is safe for concurrent use.
+type EmbeddedProvider struct {
+	base      entity.CurrencyCode
+	rates     map[entity.CurrencyCode]float64 // Rates against base, including base itself (1)
+	timestamp time.Time
+}
+
+// NewEmbeddedProvider creates an EmbeddedProvider from the bundled snapshot.
+//
+// Returns an error if the snapshot cannot be parsed (a build defect).
+func NewEmbeddedProvider() (*EmbeddedProvider, error) {
+	return newEmbeddedProvider(snapshotJSON)
+}
+
+// newEmbeddedProvider creates an EmbeddedProvider from a snapshot in the rates.json format.
+func newEmbeddedProvider(data []byte) (*EmbeddedProvider, error) {
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded rate snapshot: %w", err)
+	}
+	timestamp, err := time.Parse("2006-01-02", snap.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedded rate snapshot date %q: %w", snap.Date, err)
+	}
+	base, err := entity.NewCurrencyCode(snap.Base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedded rate snapshot base: %w", err)
+	}
+
+	rates := map[entity.CurrencyCode]float64{base: 1}
+	for code, rate := range snap.Rates {
+		currency, err := entity.NewCurrencyCode(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedded rate snapshot currency: %w", err)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid embedded rate snapshot rate for %s: %v", code, rate)
+		}
+		rates[currency] = rate
+	}
+
+	return &EmbeddedProvider{base: base, rates: rates, timestamp: timestamp}, nil
+}
+
+// FetchRate implements provider.ExchangeRateProvider.
+//
+// Returns provider.ErrCurrencyUnsupported if either currency is not in the snapshot.
+func (p *EmbeddedProvider) FetchRate(ctx context.Context, base, target entity.CurrencyCode) (*entity.ExchangeRate, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	baseRate, ok := p.rates[base]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not in the embedded snapshot", provider.ErrCurrencyUnsupported, base)
+	}
+	targetRate, ok := p.rates[target]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not in the embedded snapshot", provider.ErrCurrencyUnsupported, target)
+	}
+	return entity.NewExchangeRate(base, target, targetRate/baseRate, p.timestamp, true)
+}
+
+// FetchAllRates implements provider.ExchangeRateProvider.
+//
+// Returns provider.ErrCurrencyUnsupported if base is not in the snapshot.
+func (p *EmbeddedProvider) FetchAllRates(ctx context.Context, base entity.CurrencyCode) ([]*entity.ExchangeRate, error) {
+	if _, ok := p.rates[base]; !ok {
+		return nil, fmt.Errorf("%w: %s is not in the embedded snapshot", provider.ErrCurrencyUnsupported, base)
+	}
+
+	targets := make([]entity.CurrencyCode, 0, len(p.rates)-1)
+	for code := range p.rates {
+		if code != base {
+			targets = append(targets, code)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	rates := make([]*entity.ExchangeRate, 0, len(targets))
+	for _, target := range targets {
+		rate, err := p.FetchRate(ctx, base, target)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// Ensure EmbeddedProvider implements ExchangeRateProvider interface.
+var _ provider.ExchangeRateProvider = (*EmbeddedProvider)(nil)
@@ -0,0 +1,100 @@
+package static
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
+)
+
+func TestNewEmbeddedProvider(t *testing.T) {
+	p, err := NewEmbeddedProvider()
+	if err != nil {
+		t.Fatalf("NewEmbeddedProvider() error = %v", err)
+	}
+
+	rates, err := p.FetchAllRates(context.Background(), "USD")
+	if err != nil {
+		t.Fatalf("FetchAllRates() error = %v", err)
+	}
+	if len(rates) < 10 {
+		t.Errorf("snapshot has %d rates for USD, want the major currencies", len(rates))
+	}
+	for _, rate := range rates {
+		if !rate.Stale {
+			t.Errorf("%s rate Stale = false, want true", rate.Target)
+		}
+		if rate.Target == "USD" {
+			t.Error("FetchAllRates() returned the base currency itself")
+		}
+	}
+}
+
+func TestNewEmbeddedProvider_InvalidSnapshot(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"invalid JSON", `{`},
+		{"invalid date", `{"date": "yesterday", "base": "USD", "rates": {"EUR": 0.9}}`},
+		{"invalid base", `{"date": "2024-01-15", "base": "usd1", "rates": {"EUR": 0.9}}`},
+		{"invalid currency", `{"date": "2024-01-15", "base": "USD", "rates": {"EURO": 0.9}}`},
+		{"non-positive rate", `{"date": "2024-01-15", "base": "USD", "rates": {"EUR": 0}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newEmbeddedProvider([]byte(tt.data)); err == nil {
+				t.Error("newEmbeddedProvider() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedProvider_FetchRate(t *testing.T) {
+	p, err := newEmbeddedProvider([]byte(`{"date": "2024-01-15", "base": "USD", "rates": {"EUR": 0.8, "GBP": 0.5}}`))
+	if err != nil {
+		t.Fatalf("newEmbeddedProvider() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		base    entity.CurrencyCode
+		target  entity.CurrencyCode
+		want    float64
+		wantErr error
+	}{
+		{"direct", "USD", "EUR", 0.8, nil},
+		{"inverse", "EUR", "USD", 1.25, nil},
+		{"cross", "EUR", "GBP", 0.625, nil},
+		{"unknown target", "USD", "JPY", 0, provider.ErrCurrencyUnsupported},
+		{"unknown base", "JPY", "USD", 0, provider.ErrCurrencyUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := p.FetchRate(context.Background(), tt.base, tt.target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FetchRate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchRate() error = %v", err)
+			}
+			if math.Abs(rate.Rate-tt.want) > 1e-9 {
+				t.Errorf("Rate = %v, want %v", rate.Rate, tt.want)
+			}
+			if !rate.Stale {
+				t.Error("Stale = false, want true")
+			}
+			if got := rate.Timestamp.Format("2006-01-02"); got != "2024-01-15" {
+				t.Errorf("Timestamp = %s, want the snapshot date", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+// Package bootstrap wires the service's dependencies from configuration.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/usecase"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	domainprovider "github.com/misterfancybg/go-currenseen/internal/domain/provider"
+	domainrepository "github.com/misterfancybg/go-currenseen/internal/domain/repository"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/api"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+	lambdaadapter "github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/lambda"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/sns"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/static"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/middleware"
+	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// NewHandlerDependencies initializes all dependencies for the request handlers.
+//
+// This function:
+// - Loads unified configuration
+// - Creates DynamoDB client and repository
+// - Creates HTTP client and API provider
+// - Creates circuit breaker and wraps provider
+// - Creates use cases with all dependencies
+// - Optionally initializes Secrets Manager for API keys
+//
+// It is shared by the Lambda (cmd/lambda) and HTTP server (cmd/server) entrypoints.
+// Dependencies are initialized once at startup (Lambda cold start) and reused
+// across requests for better performance.
+func NewHandlerDependencies(ctx context.Context, log *logger.Logger) (*lambdaadapter.HandlerDependencies, error) {
+	log.Info("initializing handler dependencies")
+
+	// Load unified configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Error("failed to load configuration", "error", err.Error())
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// 1. Initialize DynamoDB repository
+	dynamoClient, err := config.NewDynamoDBClient(ctx)
+	if err != nil {
+		log.Error("failed to create DynamoDB client", "error", err.Error())
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	// Bootstrap the table for local/dev deployments; production tables come from the template
+	if cfg.DynamoDB.AutoCreateTable {
+		if err := dynamodb.EnsureTable(ctx, dynamoClient, cfg.DynamoDB.TableName); err != nil {
+			log.Error("failed to ensure DynamoDB table", "error", err.Error(), "table", cfg.DynamoDB.TableName)
+			return nil, fmt.Errorf("failed to ensure DynamoDB table: %w", err)
+		}
+		log.Info("DynamoDB table ensured", "table", cfg.DynamoDB.TableName)
+	} else if cfg.DynamoDB.EnsureTTL {
+		// Cached rates carry expiry timestamps, but DynamoDB only acts on them with TTL enabled
+		if err := dynamodb.EnsureTTL(ctx, dynamoClient, cfg.DynamoDB.TableName); err != nil {
+			log.Error("failed to ensure DynamoDB TTL", "error", err.Error(), "table", cfg.DynamoDB.TableName)
+			return nil, fmt.Errorf("failed to ensure DynamoDB TTL: %w", err)
+		}
+		log.Info("DynamoDB TTL ensured", "table", cfg.DynamoDB.TableName)
+	}
+
+	// Publish rate update events if a topic is configured
+	var eventPublisher domainrepository.EventPublisher
+	var eventFlusher domainrepository.EventFlusher
+	if cfg.Events.TopicARN != "" {
+		awsConfig, err := config.LoadAWSConfig(ctx)
+		if err != nil {
+			log.Error("failed to load AWS config for event publisher", "error", err.Error())
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		publisher, err := sns.NewEventPublisher(awsConfig, cfg.Events.TopicARN)
+		if err != nil {
+			log.Error("failed to create event publisher", "error", err.Error())
+			return nil, fmt.Errorf("failed to create event publisher: %w", err)
+		}
+		eventPublisher = publisher
+		eventFlusher = publisher
+		log.Info("rate event publishing enabled", "topic_arn", cfg.Events.TopicARN)
+	}
+
+	repositoryConfig := dynamodb.DefaultRepositoryConfig()
+	repositoryConfig.ConsistentRead = cfg.DynamoDB.ConsistentRead
+	repositoryConfig.StaleRetention = cfg.Cache.StaleRetention
+	repository := dynamodb.NewDynamoDBRepositoryWithConfig(dynamoClient, cfg.DynamoDB.TableName, repositoryConfig, eventPublisher, log)
+	idempotencyStore := dynamodb.NewIdempotencyStore(dynamoClient, cfg.DynamoDB.TableName, cfg.Idempotency.TTL)
+
+	// 2. Initialize API provider with circuit breaker
+	httpClient := api.NewHTTPClient()
+
+	// Create base provider with logger
+	providerConfig := api.DefaultCurrencyAPIProviderConfig()
+	providerConfig.MaxResponseBytes = cfg.API.MaxResponseBytes
+	providerConfig.SmartURLSelection = cfg.API.SmartURLSelection
+	providerConfig.HTTPCacheTTL = cfg.API.HTTPCacheTTL
+	providerConfig.FetchBudget = cfg.API.FetchBudget
+	providerConfig.MaxFallbackAttempts = cfg.API.MaxFallbackAttempts
+	providerConfig.InflightDedup = cfg.API.InflightDedup
+	providerConfig.MinRates = cfg.API.MinRates
+	providerConfig.MaxDateAge = cfg.API.MaxDateAge
+	providerConfig.StaleDateFallback = cfg.API.StaleDateFallback
+	providerConfig.RateBounds = cfg.RateBounds
+	log.Info("rate bounds configured", "min_rate", cfg.RateBounds.Min, "max_rate", cfg.RateBounds.Max)
+	if cfg.API.UserAgent != "" {
+		providerConfig.UserAgent = cfg.API.UserAgent
+	}
+	var baseProvider domainprovider.ExchangeRateProvider = api.NewCurrencyAPIProviderWithConfig(httpClient, cfg.API.BaseURL, "", providerConfig, log)
+	if len(cfg.API.ProviderURLs) > 0 {
+		orderedProvider, err := api.NewCurrencyAPIProviderWithURLs(httpClient, cfg.API.ProviderURLs, providerConfig, log)
+		if err != nil {
+			log.Error("invalid provider URLs", "error", err.Error())
+			return nil, fmt.Errorf("invalid provider URLs: %w", err)
+		}
+		baseProvider = orderedProvider
+		log.Info("provider URL order configured", "urls", cfg.API.ProviderURLs)
+	}
+
+	// Average across additional providers if configured; each one is also
+	// probed individually by the health check
+	var healthProbes []usecase.ProviderProbe
+	if len(cfg.API.AveragingURLs) > 0 {
+		providers := []api.WeightedProvider{{Provider: baseProvider, Weight: 1}}
+		healthProbes = append(healthProbes, usecase.ProviderProbe{Name: "primary", Provider: baseProvider})
+		for i, providerURL := range cfg.API.AveragingURLs {
+			averaged := api.NewCurrencyAPIProviderWithConfig(httpClient, providerURL, "", providerConfig, log)
+			providers = append(providers, api.WeightedProvider{Provider: averaged, Weight: 1})
+			healthProbes = append(healthProbes, usecase.ProviderProbe{Name: probeName(i, providerURL), Provider: averaged})
+		}
+		averagingConfig := api.DefaultAveragingConfig()
+		averagingConfig.Quorum = cfg.API.AveragingQuorum
+		averagingConfig.OutlierSigma = cfg.API.OutlierSigma
+		averagingProvider, err := api.NewAveragingProvider(providers, averagingConfig, log)
+		if err != nil {
+			log.Error("failed to create averaging provider", "error", err.Error())
+			return nil, fmt.Errorf("failed to create averaging provider: %w", err)
+		}
+		baseProvider = averagingProvider
+		log.Info("multi-provider averaging enabled", "providers", len(providers))
+	}
+
+	// Derive pairs the upstream only publishes in the other direction
+	if cfg.API.InverseFallback {
+		baseProvider = api.NewInverseProvider(baseProvider, log)
+		log.Info("inverse pair fallback enabled")
+	}
+
+	// Wrap provider with circuit breaker (global, or one per base currency; optionally one per pair)
+	var breakerProvider *api.CircuitBreakerProvider
+	if cfg.CircuitBreakerScope.PerBase {
+		breakers, err := circuitbreaker.NewMultiCircuitBreaker(cfg.CircuitBreaker, cfg.CircuitBreakerScope.IdleTTL)
+		if err != nil {
+			log.Error("failed to create circuit breakers", "error", err.Error())
+			return nil, fmt.Errorf("failed to create circuit breakers: %w", err)
+		}
+		breakerProvider = api.NewPerBaseCircuitBreakerProvider(baseProvider, breakers, nil)
+		log.Info("per-base circuit breakers enabled", "idle_ttl", cfg.CircuitBreakerScope.IdleTTL.String())
+	} else {
+		circuitBreaker, err := circuitbreaker.NewCircuitBreaker(cfg.CircuitBreaker)
+		if err != nil {
+			log.Error("failed to create circuit breaker", "error", err.Error())
+			return nil, fmt.Errorf("failed to create circuit breaker: %w", err)
+		}
+		breakerProvider = api.NewCircuitBreakerProvider(baseProvider, circuitBreaker)
+	}
+	if cfg.CircuitBreakerScope.PerPair {
+		pairBreakers, err := circuitbreaker.NewMultiCircuitBreaker(cfg.CircuitBreaker, cfg.CircuitBreakerScope.IdleTTL)
+		if err != nil {
+			log.Error("failed to create pair circuit breakers", "error", err.Error())
+			return nil, fmt.Errorf("failed to create pair circuit breakers: %w", err)
+		}
+		breakerProvider.WithPairBreakers(pairBreakers, nil)
+		log.Info("per-pair circuit breakers enabled", "idle_ttl", cfg.CircuitBreakerScope.IdleTTL.String())
+	}
+	if cfg.CircuitBreakerScope.EmptyRatesFailure {
+		breakerProvider.WithAllRatesSuccess(api.NonEmptyRatesSuccess)
+	}
+	var provider domainprovider.ExchangeRateProvider = breakerProvider
+
+	// Bound concurrent provider calls (outside the circuit breaker so rejections aren't failures)
+	throttleConfig := api.DefaultThrottleConfig()
+	throttleConfig.MaxConcurrent = cfg.API.MaxConcurrentCalls
+	throttleConfig.MaxWait = cfg.API.MaxCallWait
+	throttledProvider, err := api.NewThrottledProvider(provider, throttleConfig)
+	if err != nil {
+		log.Error("failed to create throttled provider", "error", err.Error())
+		return nil, fmt.Errorf("failed to create throttled provider: %w", err)
+	}
+	provider = throttledProvider
+	log.Info("provider concurrency limit enabled",
+		"max_concurrent", throttleConfig.MaxConcurrent,
+		"max_wait", throttleConfig.MaxWait.String(),
+	)
+
+	// 3. Initialize use cases with logger
+	fallbackStrategy, err := usecase.ParseFallbackStrategy(cfg.Cache.FallbackStrategy)
+	if err != nil {
+		log.Error("invalid fallback strategy", "error", err.Error())
+		return nil, fmt.Errorf("invalid fallback strategy: %w", err)
+	}
+	log.Info("fallback strategy configured", "fallback_strategy", fallbackStrategy.Name())
+	getRateConfig := usecase.DefaultGetExchangeRateConfig()
+	getRateConfig.MaxRateDelta = cfg.Anomaly.MaxRateDelta
+	getRateConfig.RejectAnomalousRates = cfg.Anomaly.Reject
+	getRateConfig.AnomalyConfirmations = cfg.Anomaly.Confirmations
+	getRateConfig.AnomalyMaxCacheAge = cfg.Anomaly.MaxCacheAge
+	getRateConfig.FallbackStrategy = fallbackStrategy
+	getRateConfig.FallbackMaxStale = cfg.Cache.FallbackMaxStale
+	getRateConfig.AbsoluteMaxAge = cfg.Cache.AbsoluteMaxAge
+	getRateConfig.ExpiredCleanupGrace = cfg.Cache.ExpiredCleanupGrace
+	getRateConfig.WarmCacheOnFetch = cfg.Cache.WarmOnFetch
+	getRateConfig.SaveConcurrency = cfg.Cache.SaveConcurrency
+	getRateConfig.RefreshAheadFraction = cfg.Cache.RefreshAhead
+	getRateConfig.RefreshAheadJitter = cfg.Cache.RefreshAheadJitter
+	getRateConfig.NoCachePairs, err = usecase.ParseNoCachePairs(cfg.Cache.NoCachePairs)
+	if err != nil {
+		log.Error("invalid no-cache pairs", "error", err.Error())
+		return nil, fmt.Errorf("invalid no-cache pairs: %w", err)
+	}
+	if len(getRateConfig.NoCachePairs) > 0 {
+		log.Info("cache skipped for volatile pairs", "no_cache_pairs", len(getRateConfig.NoCachePairs))
+	}
+	if cfg.Cache.EmbeddedFallback {
+		embedded, err := static.NewEmbeddedProvider()
+		if err != nil {
+			log.Error("failed to load embedded rate snapshot", "error", err.Error())
+			return nil, fmt.Errorf("failed to load embedded rate snapshot: %w", err)
+		}
+		getRateConfig.EmbeddedFallback = embedded
+		log.Warn("embedded static rate fallback enabled; approximate rates may be served (not for production)")
+	}
+	if cfg.Cache.FetchLock {
+		getRateConfig.FetchLock = dynamodb.NewFetchLock(dynamoClient, cfg.DynamoDB.TableName, cfg.Cache.FetchLockTTL)
+		getRateConfig.FetchLockWait = cfg.Cache.FetchLockWait
+		log.Info("distributed fetch lock enabled",
+			"lock_ttl", cfg.Cache.FetchLockTTL.String(),
+			"lock_wait", cfg.Cache.FetchLockWait.String(),
+		)
+	}
+	getRateUseCase := usecase.NewGetExchangeRateUseCaseWithConfig(repository, provider, cfg.Cache.TTL, getRateConfig, log)
+	getAllRatesConfig := usecase.DefaultGetAllRatesConfig()
+	getAllRatesConfig.ExpiredCleanupGrace = cfg.Cache.ExpiredCleanupGrace
+	getAllRatesConfig.FallbackStrategy = fallbackStrategy
+	getAllRatesConfig.FallbackMaxStale = cfg.Cache.FallbackMaxStale
+	getAllRatesConfig.AbsoluteMaxAge = cfg.Cache.AbsoluteMaxAge
+	getAllRatesConfig.MinRates = cfg.Rates.MinRates
+	getAllRatesConfig.SaveConcurrency = cfg.Cache.SaveConcurrency
+	getAllRatesConfig.EmbeddedFallback = getRateConfig.EmbeddedFallback
+	getAllRatesUseCase := usecase.NewGetAllRatesUseCaseWithConfig(repository, provider, cfg.Cache.TTL, getAllRatesConfig, log)
+	// Health checks probe the unwrapped providers and only read the breaker state,
+	// skipping the probes while the circuit is open
+	healthConfig := usecase.DefaultHealthCheckConfig()
+	healthConfig.RequireCache = cfg.Health.RequireCache
+	healthConfig.CacheBase = entity.CurrencyCode(cfg.Rates.DefaultBase).Normalize() // validated with the configuration
+	healthCheckUseCase := usecase.NewHealthCheckUseCaseWithConfig(repository, baseProvider, breakerProvider, healthConfig)
+	if len(healthProbes) > 0 {
+		for i := range healthProbes {
+			healthProbes[i].Breaker = breakerProvider
+		}
+		healthCheckUseCase = usecase.NewHealthCheckUseCaseWithProviders(repository, healthProbes, healthConfig)
+		log.Info("per-provider health probes enabled", "providers", len(healthProbes))
+	}
+	if healthConfig.RequireCache {
+		log.Info("health check requires a warm cache", "base", healthConfig.CacheBase.String())
+	}
+
+	// 4. Initialize security components
+	var apiKeyAuthenticator *middleware.APIKeyAuthenticator
+	var rateLimiter *middleware.RateLimiter
+
+	// Initialize Secrets Manager if enabled
+	var secretsManager config.SecretsManager
+	if cfg.SecretsManager.Enabled {
+		sm, err := config.NewAWSSecretsManager(ctx, cfg.SecretsManager.SecretName, cfg.SecretsManager.CacheTTL)
+		if err != nil {
+			log.Warn("failed to initialize Secrets Manager, authentication will be disabled", "error", err.Error())
+		} else {
+			secretsManager = sm.WithCallTimeout(cfg.SecretsManager.Timeout).
+				WithFailureBackoff(cfg.SecretsManager.FailureBackoff).
+				WithPlaintextSecret(cfg.SecretsManager.Plaintext).
+				WithKeyField(cfg.SecretsManager.KeyField)
+			log.Info("Secrets Manager initialized", "secret_name", cfg.SecretsManager.SecretName)
+		}
+	}
+
+	// Initialize API key authenticator (enabled by default if Secrets Manager is configured)
+	authEnabled := cfg.SecretsManager.Enabled && secretsManager != nil
+	if authEnabled {
+		apiKeyAuthenticator = middleware.NewAPIKeyAuthenticator(secretsManager, cfg, true)
+		log.Info("API key authentication enabled", "skip_paths", cfg.Auth.SkipPaths)
+	} else {
+		log.Info("API key authentication disabled (for development)")
+	}
+
+	// Initialize rate limiter (enabled by default)
+	rateLimitConfig := loadRateLimiterConfig(log)
+	rateLimiter = middleware.NewRateLimiter(rateLimitConfig)
+	if rateLimitConfig.Enabled {
+		log.Info("Rate limiting enabled",
+			"requests_per_minute", rateLimitConfig.RequestsPerMinute,
+			"burst_size", rateLimitConfig.BurstSize,
+			"anon_requests_per_minute", rateLimitConfig.AnonRequestsPerMinute,
+			"anon_burst_size", rateLimitConfig.AnonBurstSize,
+			"tiers", len(rateLimitConfig.Tiers),
+			"exempt_keys", len(rateLimitConfig.ExemptKeys),
+		)
+	} else {
+		log.Info("Rate limiting disabled")
+	}
+
+	// Initialize client identification for rate limiting
+	clientIdentifier, err := middleware.NewClientIdentifierResolver(middleware.ClientIdentifierConfig{
+		TrustedProxies: cfg.ClientIdentity.TrustedProxies,
+		Header:         cfg.ClientIdentity.Header,
+	})
+	if err != nil {
+		log.Error("failed to create client identifier", "error", err.Error())
+		return nil, fmt.Errorf("failed to create client identifier: %w", err)
+	}
+
+	// Initialize request deduplication for duplicate API Gateway deliveries
+	dedupConfig := middleware.DefaultRequestDeduplicatorConfig()
+	dedupConfig.Window = cfg.RequestDedup.Window
+	dedupConfig.Enabled = cfg.RequestDedup.Window > 0
+	requestDeduplicator := middleware.NewRequestDeduplicator(dedupConfig)
+	if dedupConfig.Enabled {
+		log.Info("Request deduplication enabled", "window", dedupConfig.Window.String())
+	} else {
+		log.Info("Request deduplication disabled")
+	}
+
+	// Internal error details in error bodies, for debugging outside production
+	if cfg.Response.DebugErrors {
+		if middleware.SetDebugErrors(true) {
+			log.Warn("Debug error details enabled in error responses")
+		} else {
+			log.Warn("DEBUG_ERRORS ignored in production", "environment", logger.Environment())
+		}
+	}
+
+	// Available targets in errors for an unsupported target
+	middleware.SetAvailableTargetsDetail(cfg.Response.AvailableTargets)
+
+	// Data/meta envelope around success bodies (off by default for existing clients)
+	middleware.SetResponseEnvelope(cfg.Response.Envelope)
+
+	// Indented JSON for ?pretty=true, unless disabled for this deployment
+	middleware.SetPrettyJSONDisabled(cfg.Response.DisablePretty)
+
+	// 5. Create handler dependencies
+	// Default base currency for GET /rates (validated with the configuration)
+	defaultBase, err := entity.NewCurrencyCode(cfg.Rates.DefaultBase)
+	if err != nil {
+		log.Error("invalid default base currency", "error", err.Error())
+		return nil, fmt.Errorf("invalid default base currency: %w", err)
+	}
+
+	// Resolve legacy currency codes (e.g. XBT) to canonical codes during validation
+	if err := middleware.SetCurrencyAliases(cfg.Rates.CurrencyAliases); err != nil {
+		log.Error("invalid currency aliases", "error", err.Error())
+		return nil, fmt.Errorf("invalid currency aliases: %w", err)
+	}
+	if len(cfg.Rates.CurrencyAliases) > 0 {
+		log.Info("Currency aliases enabled", "aliases", len(cfg.Rates.CurrencyAliases))
+	}
+
+	// Currency pairs blocked from being served, parsed once here
+	pairDenylist, err := middleware.NewPairDenylist(cfg.Rates.DeniedPairs)
+	if err != nil {
+		log.Error("invalid denied pairs", "error", err.Error())
+		return nil, fmt.Errorf("invalid denied pairs: %w", err)
+	}
+	if pairDenylist.Len() > 0 {
+		log.Info("Currency pair denylist enabled", "denied_pairs", pairDenylist.Len())
+	}
+
+	// Admin routes mutate the cache, so they are only served to authenticated callers
+	var invalidateRateUseCase lambdaadapter.InvalidateRateUseCase
+	if apiKeyAuthenticator != nil {
+		invalidateRateUseCase = usecase.NewInvalidateRateUseCase(repository, log)
+	} else {
+		log.Info("admin routes disabled: API key authentication is not enabled")
+	}
+
+	deps := &lambdaadapter.HandlerDependencies{
+		GetRateUseCase:        getRateUseCase,
+		GetAllRatesUseCase:    getAllRatesUseCase,
+		HealthCheckUseCase:    healthCheckUseCase,
+		InvalidateRateUseCase: invalidateRateUseCase,
+		Logger:                log,
+		APIKeyAuthenticator:   apiKeyAuthenticator,
+		AuthSkipPaths:         cfg.Auth.SkipPaths,
+		RateLimiter:           rateLimiter,
+		ClientIdentifier:      clientIdentifier,
+		RequestDeduplicator:   requestDeduplicator,
+		Response:              cfg.Response,
+		CacheTTL:              cfg.Cache.TTL,
+		IdempotencyStore:      idempotencyStore,
+		EventFlusher:          eventFlusher,
+		DefaultBaseCurrency:   defaultBase,
+		MaxBasesPerRequest:    cfg.Rates.MaxBasesPerRequest,
+		PairDenylist:          pairDenylist,
+	}
+
+	log.Info("handler dependencies initialized successfully")
+	return deps, nil
+}
+
+// probeName returns the health check name of the averaging provider at index i:
+// its host (e.g. "latest.currency-api.pages.dev"), or "averaging_<i>" if the URL has none.
+func probeName(i int, providerURL string) string {
+	if parsed, err := url.Parse(providerURL); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return "averaging_" + strconv.Itoa(i+1)
+}
+
+// loadRateLimiterConfig loads the rate limiter configuration from the
+// RATE_LIMIT_* environment variables, on top of middleware.DefaultRateLimiterConfig.
+// Invalid values are ignored (RATE_LIMIT_TIERS with a warning).
+func loadRateLimiterConfig(log *logger.Logger) middleware.RateLimiterConfig {
+	rateLimitConfig := middleware.DefaultRateLimiterConfig()
+	// Allow configuration via environment variables
+	if envRateLimit := os.Getenv("RATE_LIMIT_REQUESTS_PER_MINUTE"); envRateLimit != "" {
+		if parsed, err := strconv.Atoi(envRateLimit); err == nil && parsed > 0 {
+			rateLimitConfig.RequestsPerMinute = parsed
+		}
+	}
+	if envBurst := os.Getenv("RATE_LIMIT_BURST_SIZE"); envBurst != "" {
+		if parsed, err := strconv.Atoi(envBurst); err == nil && parsed > 0 {
+			rateLimitConfig.BurstSize = parsed
+		}
+	}
+	if envAnonRateLimit := os.Getenv("RATE_LIMIT_ANON_REQUESTS_PER_MINUTE"); envAnonRateLimit != "" {
+		if parsed, err := strconv.Atoi(envAnonRateLimit); err == nil && parsed > 0 {
+			rateLimitConfig.AnonRequestsPerMinute = parsed
+		}
+	}
+	if envAnonBurst := os.Getenv("RATE_LIMIT_ANON_BURST_SIZE"); envAnonBurst != "" {
+		if parsed, err := strconv.Atoi(envAnonBurst); err == nil && parsed > 0 {
+			rateLimitConfig.AnonBurstSize = parsed
+		}
+	}
+	if tiers, err := middleware.ParseTierLimits(os.Getenv("RATE_LIMIT_TIERS")); err != nil {
+		log.Warn("ignoring invalid RATE_LIMIT_TIERS", "error", err.Error())
+	} else {
+		rateLimitConfig.Tiers = tiers
+	}
+	for _, key := range strings.Split(os.Getenv("RATE_LIMIT_EXEMPT_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			rateLimitConfig.ExemptKeys = append(rateLimitConfig.ExemptKeys, key)
+		}
+	}
+	if os.Getenv("RATE_LIMIT_ENABLED") == "false" {
+		rateLimitConfig.Enabled = false
+	}
+	return rateLimitConfig
+}
+
+// WatchConfig reloads the configuration on SIGHUP or CONFIG_FILE changes (see
+// config.WatchConfig) and applies it to the dependencies in current, until ctx
+// is done. It is meant for the long-running HTTP server, not Lambda.
+//
+// Only the cache TTL and the RATE_LIMIT_* settings are applied; other settings
+// need a restart. current is swapped for a copy of its dependencies with the
+// new TTL, so requests already holding the previous copy are unaffected.
+func WatchConfig(ctx context.Context, current *atomic.Pointer[lambdaadapter.HandlerDependencies], log *logger.Logger) {
+	config.WatchConfig(ctx, func(cfg *config.Config) {
+		current.Store(applyConfig(current.Load(), cfg, log))
+		log.Info("configuration reloaded", "cache_ttl", cfg.Cache.TTL.String())
+	}, func(err error) {
+		log.Warn("ignoring invalid configuration reload", "error", err.Error())
+	})
+}
+
+// cacheTTLSetter is implemented by use cases whose cache TTL can be changed at runtime.
+type cacheTTLSetter interface {
+	SetCacheTTL(ttl time.Duration)
+}
+
+// applyConfig applies the reloadable settings of cfg and returns a copy of deps
+// with them. The use cases and rate limiter are shared with deps and updated
+// in place, with atomic swaps.
+func applyConfig(deps *lambdaadapter.HandlerDependencies, cfg *config.Config, log *logger.Logger) *lambdaadapter.HandlerDependencies {
+	for _, uc := range []any{deps.GetRateUseCase, deps.GetAllRatesUseCase} {
+		if setter, ok := uc.(cacheTTLSetter); ok {
+			setter.SetCacheTTL(cfg.Cache.TTL)
+		}
+	}
+	if deps.RateLimiter != nil {
+		deps.RateLimiter.UpdateConfig(loadRateLimiterConfig(log))
+	}
+
+	updated := *deps
+	updated.CacheTTL = cfg.Cache.TTL
+	return &updated
+}
@@ -3,14 +3,41 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // APIConfig holds API configuration for external exchange rate providers.
 type APIConfig struct {
 	BaseURL       string        // Base URL for the exchange rate API
+	ProviderURLs  []string      // Provider base URLs in priority order (overrides BaseURL and the default fallback)
 	Timeout       time.Duration // HTTP client timeout
 	RetryAttempts int           // Maximum number of retry attempts
+
+	// Health-aware URL selection (disabled keeps ProviderURLs priority order)
+	SmartURLSelection bool // Prefer the healthiest provider URL based on recent results
+
+	// Multi-provider averaging (disabled when AveragingURLs is empty)
+	AveragingURLs   []string // Additional provider base URLs averaged with BaseURL
+	AveragingQuorum int      // Minimum providers that must return a rate (0 = majority)
+	OutlierSigma    float64  // Discard rates beyond N robust standard deviations from the median
+
+	MaxResponseBytes    int64         // Maximum provider response body size in bytes
+	UserAgent           string        // User-Agent header sent to providers (empty = provider default)
+	HTTPCacheTTL        time.Duration // How long provider response bodies are reused in memory (0 disables)
+	FetchBudget         time.Duration // Maximum total time of one provider fetch across all endpoints (0 = unbounded)
+	MaxFallbackAttempts int           // Maximum provider URLs tried by one fetch, primary included (0 = all)
+	InflightDedup       bool          // Share one download between concurrent fetches of the same provider URL
+	InverseFallback     bool          // Derive a pair from its inverse when the provider has no direct rate
+	MinRates            int           // Reject all-rates responses with fewer valid rates as invalid (0 = accept empty)
+
+	// Stale CDN edge detection (disabled when MaxDateAge is 0)
+	MaxDateAge        time.Duration // Warn when a provider response's date is older than this
+	StaleDateFallback bool          // Try the remaining provider URLs for a more recent date
+
+	// Concurrency limiting for provider calls
+	MaxConcurrentCalls int           // Maximum provider calls running at once
+	MaxCallWait        time.Duration // How long excess callers wait for a slot (0 = fail fast)
 }
 
 // LoadAPIConfig loads API configuration from environment variables.
@@ -19,6 +46,23 @@ type APIConfig struct {
 // - EXCHANGE_RATE_API_URL: Base URL for the API (default: "https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@latest/v1")
 // - EXCHANGE_RATE_API_TIMEOUT: HTTP client timeout in seconds (default: 10)
 // - EXCHANGE_RATE_API_RETRY_ATTEMPTS: Maximum retry attempts (default: 3)
+// - PROVIDER_URLS: Comma-separated provider base URLs in priority order (optional, overrides EXCHANGE_RATE_API_URL and the default fallback)
+// - SMART_URL_SELECTION: Prefer the healthiest provider URL based on recent success and latency (default: "false")
+// - AVERAGING_PROVIDER_URLS: Comma-separated additional base URLs to average with (optional)
+// - AVERAGING_QUORUM: Minimum providers that must return a rate (default: 0, majority)
+// - OUTLIER_SIGMA: Discard rates beyond N robust standard deviations (scaled MAD) from the median (default: 2.0, 0 disables)
+// - MAX_PROVIDER_RESPONSE_BYTES: Maximum provider response body size in bytes (default: 5242880)
+// - PROVIDER_USER_AGENT: User-Agent header sent to providers (default: "go-currenseen/<version>")
+// - PROVIDER_HTTP_CACHE_TTL: How long provider response bodies are reused in memory, as duration string (default: "0s", disabled)
+// - PROVIDER_FETCH_BUDGET: Maximum total time of one provider fetch across primary and fallback endpoints, as duration string (default: "0s", unbounded)
+// - MAX_FALLBACK_ATTEMPTS: Maximum provider URLs tried by one fetch, primary included, to bound latency with a long PROVIDER_URLS list (default: 0, all)
+// - MAX_PROVIDER_DATE_AGE: Warn when a provider response's date is older than this, as duration string (default: "0s", disabled)
+// - PROVIDER_STALE_DATE_FALLBACK: Try the remaining provider URLs when a response's date exceeds MAX_PROVIDER_DATE_AGE (default: "false")
+// - PROVIDER_INFLIGHT_DEDUP: Share one download between concurrent fetches of the same provider URL (default: "false")
+// - PROVIDER_INVERSE_FALLBACK: Derive a pair from its inverse when the provider has no direct rate (default: "false")
+// - PROVIDER_MIN_RATES: Reject all-rates responses with fewer valid rates, or without the base, as invalid (default: 0, disabled)
+// - MAX_CONCURRENT_PROVIDER_CALLS: Maximum provider calls running at once (default: 10)
+// - PROVIDER_CALL_MAX_WAIT: How long excess callers wait for a slot, as duration string (default: "5s", "0s" fails fast)
 //
 // Returns a configuration with defaults if environment variables are not set.
 //
@@ -53,9 +97,135 @@ func LoadAPIConfig() APIConfig {
 		}
 	}
 
+	// Load prioritized provider URLs from environment
+	var providerURLs []string
+	for _, url := range strings.Split(os.Getenv("PROVIDER_URLS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			providerURLs = append(providerURLs, url)
+		}
+	}
+
+	// Load smart URL selection flag from environment
+	smartURLSelection := os.Getenv("SMART_URL_SELECTION") == "true"
+
+	// Load averaging provider URLs from environment
+	var averagingURLs []string
+	for _, url := range strings.Split(os.Getenv("AVERAGING_PROVIDER_URLS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			averagingURLs = append(averagingURLs, url)
+		}
+	}
+
+	// Load averaging quorum from environment
+	averagingQuorum := 0 // default: majority
+	if quorumStr := os.Getenv("AVERAGING_QUORUM"); quorumStr != "" {
+		if parsed, err := strconv.Atoi(quorumStr); err == nil && parsed > 0 {
+			averagingQuorum = parsed
+		}
+	}
+
+	// Load outlier sigma from environment
+	outlierSigma := 2.0 // default
+	if sigmaStr := os.Getenv("OUTLIER_SIGMA"); sigmaStr != "" {
+		if parsed, err := strconv.ParseFloat(sigmaStr, 64); err == nil && parsed >= 0 {
+			outlierSigma = parsed
+		}
+	}
+
+	// Load maximum response body size from environment
+	maxResponseBytes := int64(5 << 20) // default: 5 MiB
+	if maxStr := os.Getenv("MAX_PROVIDER_RESPONSE_BYTES"); maxStr != "" {
+		if parsed, err := strconv.ParseInt(maxStr, 10, 64); err == nil && parsed > 0 {
+			maxResponseBytes = parsed
+		}
+	}
+
+	// Load provider User-Agent from environment (empty uses the provider default)
+	userAgent := strings.TrimSpace(os.Getenv("PROVIDER_USER_AGENT"))
+
+	// Load provider HTTP response cache TTL from environment
+	var httpCacheTTL time.Duration // default: disabled
+	if ttlStr := os.Getenv("PROVIDER_HTTP_CACHE_TTL"); ttlStr != "" {
+		if parsed, err := time.ParseDuration(ttlStr); err == nil && parsed >= 0 {
+			httpCacheTTL = parsed
+		}
+	}
+
+	// Load provider fetch budget from environment
+	var fetchBudget time.Duration // default: unbounded
+	if budgetStr := os.Getenv("PROVIDER_FETCH_BUDGET"); budgetStr != "" {
+		if parsed, err := time.ParseDuration(budgetStr); err == nil && parsed >= 0 {
+			fetchBudget = parsed
+		}
+	}
+
+	// Load maximum provider URLs tried per fetch from environment
+	maxFallbackAttempts := 0 // default: all
+	if attemptsStr := os.Getenv("MAX_FALLBACK_ATTEMPTS"); attemptsStr != "" {
+		if parsed, err := strconv.Atoi(attemptsStr); err == nil && parsed >= 0 {
+			maxFallbackAttempts = parsed
+		}
+	}
+
+	// Load stale provider date detection from environment
+	var maxDateAge time.Duration // default: disabled
+	if ageStr := os.Getenv("MAX_PROVIDER_DATE_AGE"); ageStr != "" {
+		if parsed, err := time.ParseDuration(ageStr); err == nil && parsed >= 0 {
+			maxDateAge = parsed
+		}
+	}
+	staleDateFallback := os.Getenv("PROVIDER_STALE_DATE_FALLBACK") == "true"
+
+	// Load in-flight download sharing flag from environment
+	inflightDedup := os.Getenv("PROVIDER_INFLIGHT_DEDUP") == "true"
+
+	// Load inverse pair fallback flag from environment
+	inverseFallback := os.Getenv("PROVIDER_INVERSE_FALLBACK") == "true"
+
+	// Load provider response validation from environment
+	minRates := 0 // default: disabled
+	if minStr := os.Getenv("PROVIDER_MIN_RATES"); minStr != "" {
+		if parsed, err := strconv.Atoi(minStr); err == nil && parsed >= 0 {
+			minRates = parsed
+		}
+	}
+
+	// Load provider concurrency limit from environment
+	maxConcurrentCalls := 10 // default
+	if maxStr := os.Getenv("MAX_CONCURRENT_PROVIDER_CALLS"); maxStr != "" {
+		if parsed, err := strconv.Atoi(maxStr); err == nil && parsed > 0 {
+			maxConcurrentCalls = parsed
+		}
+	}
+
+	// Load provider call wait from environment
+	maxCallWait := 5 * time.Second // default
+	if waitStr := os.Getenv("PROVIDER_CALL_MAX_WAIT"); waitStr != "" {
+		if parsed, err := time.ParseDuration(waitStr); err == nil && parsed >= 0 {
+			maxCallWait = parsed
+		}
+	}
+
 	return APIConfig{
-		BaseURL:       baseURL,
-		Timeout:       time.Duration(timeoutSeconds) * time.Second,
-		RetryAttempts: retryAttempts,
+		BaseURL:             baseURL,
+		ProviderURLs:        providerURLs,
+		SmartURLSelection:   smartURLSelection,
+		Timeout:             time.Duration(timeoutSeconds) * time.Second,
+		RetryAttempts:       retryAttempts,
+		AveragingURLs:       averagingURLs,
+		AveragingQuorum:     averagingQuorum,
+		OutlierSigma:        outlierSigma,
+		MaxResponseBytes:    maxResponseBytes,
+		UserAgent:           userAgent,
+		HTTPCacheTTL:        httpCacheTTL,
+		FetchBudget:         fetchBudget,
+		MaxFallbackAttempts: maxFallbackAttempts,
+		InflightDedup:       inflightDedup,
+		MaxDateAge:          maxDateAge,
+		StaleDateFallback:   staleDateFallback,
+		InverseFallback:     inverseFallback,
+		MinRates:            minRates,
+		MaxConcurrentCalls:  maxConcurrentCalls,
+		MaxCallWait:         maxCallWait,
 	}
 }
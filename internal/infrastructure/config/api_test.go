@@ -142,3 +142,268 @@ func TestLoadAPIConfig_AllCustom(t *testing.T) {
 		t.Errorf("RetryAttempts = %d, want 4", cfg.RetryAttempts)
 	}
 }
+
+func TestLoadAPIConfig_Averaging(t *testing.T) {
+	os.Setenv("AVERAGING_PROVIDER_URLS", "https://a.example.com/v1, https://b.example.com/v1,")
+	os.Setenv("AVERAGING_QUORUM", "2")
+	os.Setenv("OUTLIER_SIGMA", "1.5")
+	defer os.Unsetenv("AVERAGING_PROVIDER_URLS")
+	defer os.Unsetenv("AVERAGING_QUORUM")
+	defer os.Unsetenv("OUTLIER_SIGMA")
+
+	cfg := LoadAPIConfig()
+
+	if len(cfg.AveragingURLs) != 2 || cfg.AveragingURLs[1] != "https://b.example.com/v1" {
+		t.Errorf("AveragingURLs = %v, want 2 trimmed URLs", cfg.AveragingURLs)
+	}
+	if cfg.AveragingQuorum != 2 {
+		t.Errorf("AveragingQuorum = %d, want 2", cfg.AveragingQuorum)
+	}
+	if cfg.OutlierSigma != 1.5 {
+		t.Errorf("OutlierSigma = %v, want 1.5", cfg.OutlierSigma)
+	}
+}
+
+func TestLoadAPIConfig_MaxResponseBytes(t *testing.T) {
+	cfg := LoadAPIConfig()
+	if cfg.MaxResponseBytes != 5<<20 {
+		t.Errorf("MaxResponseBytes = %d, want %d (default)", cfg.MaxResponseBytes, 5<<20)
+	}
+
+	os.Setenv("MAX_PROVIDER_RESPONSE_BYTES", "1048576")
+	defer os.Unsetenv("MAX_PROVIDER_RESPONSE_BYTES")
+
+	cfg = LoadAPIConfig()
+	if cfg.MaxResponseBytes != 1048576 {
+		t.Errorf("MaxResponseBytes = %d, want 1048576", cfg.MaxResponseBytes)
+	}
+
+	os.Setenv("MAX_PROVIDER_RESPONSE_BYTES", "-1")
+	cfg = LoadAPIConfig()
+	if cfg.MaxResponseBytes != 5<<20 {
+		t.Errorf("MaxResponseBytes = %d, want default for invalid value", cfg.MaxResponseBytes)
+	}
+}
+
+func TestLoadAPIConfig_UserAgent(t *testing.T) {
+	if cfg := LoadAPIConfig(); cfg.UserAgent != "" {
+		t.Errorf("UserAgent = %q, want empty (provider default)", cfg.UserAgent)
+	}
+
+	os.Setenv("PROVIDER_USER_AGENT", "currenseen-prod/2.0")
+	defer os.Unsetenv("PROVIDER_USER_AGENT")
+
+	if cfg := LoadAPIConfig(); cfg.UserAgent != "currenseen-prod/2.0" {
+		t.Errorf("UserAgent = %q, want currenseen-prod/2.0", cfg.UserAgent)
+	}
+}
+
+func TestLoadAPIConfig_ProviderConcurrency(t *testing.T) {
+	cfg := LoadAPIConfig()
+	if cfg.MaxConcurrentCalls != 10 {
+		t.Errorf("MaxConcurrentCalls = %d, want 10 (default)", cfg.MaxConcurrentCalls)
+	}
+	if cfg.MaxCallWait != 5*time.Second {
+		t.Errorf("MaxCallWait = %v, want 5s (default)", cfg.MaxCallWait)
+	}
+
+	os.Setenv("MAX_CONCURRENT_PROVIDER_CALLS", "4")
+	os.Setenv("PROVIDER_CALL_MAX_WAIT", "0s")
+	defer os.Unsetenv("MAX_CONCURRENT_PROVIDER_CALLS")
+	defer os.Unsetenv("PROVIDER_CALL_MAX_WAIT")
+
+	cfg = LoadAPIConfig()
+	if cfg.MaxConcurrentCalls != 4 {
+		t.Errorf("MaxConcurrentCalls = %d, want 4", cfg.MaxConcurrentCalls)
+	}
+	if cfg.MaxCallWait != 0 {
+		t.Errorf("MaxCallWait = %v, want 0 (fail fast)", cfg.MaxCallWait)
+	}
+
+	os.Setenv("MAX_CONCURRENT_PROVIDER_CALLS", "0")
+	if cfg = LoadAPIConfig(); cfg.MaxConcurrentCalls != 10 {
+		t.Errorf("MaxConcurrentCalls = %d, want default for invalid value", cfg.MaxConcurrentCalls)
+	}
+}
+
+func TestLoadAPIConfig_HTTPCacheTTL(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"unset disables", "", 0},
+		{"custom TTL", "10s", 10 * time.Second},
+		{"invalid keeps default", "soon", 0},
+		{"negative keeps default", "-5s", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				os.Setenv("PROVIDER_HTTP_CACHE_TTL", tt.value)
+				defer os.Unsetenv("PROVIDER_HTTP_CACHE_TTL")
+			}
+
+			if cfg := LoadAPIConfig(); cfg.HTTPCacheTTL != tt.want {
+				t.Errorf("HTTPCacheTTL = %v, want %v", cfg.HTTPCacheTTL, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAPIConfig_FetchBudget(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"unset is unbounded", "", 0},
+		{"custom budget", "3s", 3 * time.Second},
+		{"invalid keeps default", "fast", 0},
+		{"negative keeps default", "-1s", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				os.Setenv("PROVIDER_FETCH_BUDGET", tt.value)
+				defer os.Unsetenv("PROVIDER_FETCH_BUDGET")
+			}
+
+			if cfg := LoadAPIConfig(); cfg.FetchBudget != tt.want {
+				t.Errorf("FetchBudget = %v, want %v", cfg.FetchBudget, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAPIConfig_MaxFallbackAttempts(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"unset tries all", "", 0},
+		{"custom limit", "2", 2},
+		{"invalid keeps default", "two", 0},
+		{"negative keeps default", "-1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				os.Setenv("MAX_FALLBACK_ATTEMPTS", tt.value)
+				defer os.Unsetenv("MAX_FALLBACK_ATTEMPTS")
+			}
+
+			if cfg := LoadAPIConfig(); cfg.MaxFallbackAttempts != tt.want {
+				t.Errorf("MaxFallbackAttempts = %v, want %v", cfg.MaxFallbackAttempts, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAPIConfig_SmartURLSelection(t *testing.T) {
+	if cfg := LoadAPIConfig(); cfg.SmartURLSelection {
+		t.Error("SmartURLSelection = true, want false by default")
+	}
+
+	os.Setenv("SMART_URL_SELECTION", "true")
+	defer os.Unsetenv("SMART_URL_SELECTION")
+
+	if cfg := LoadAPIConfig(); !cfg.SmartURLSelection {
+		t.Error("SmartURLSelection = false, want true")
+	}
+}
+
+func TestLoadAPIConfig_StaleDate(t *testing.T) {
+	if cfg := LoadAPIConfig(); cfg.MaxDateAge != 0 || cfg.StaleDateFallback {
+		t.Errorf("MaxDateAge = %v, StaleDateFallback = %v, want disabled by default", cfg.MaxDateAge, cfg.StaleDateFallback)
+	}
+
+	os.Setenv("MAX_PROVIDER_DATE_AGE", "48h")
+	os.Setenv("PROVIDER_STALE_DATE_FALLBACK", "true")
+	defer os.Unsetenv("MAX_PROVIDER_DATE_AGE")
+	defer os.Unsetenv("PROVIDER_STALE_DATE_FALLBACK")
+
+	cfg := LoadAPIConfig()
+	if cfg.MaxDateAge != 48*time.Hour {
+		t.Errorf("MaxDateAge = %v, want 48h", cfg.MaxDateAge)
+	}
+	if !cfg.StaleDateFallback {
+		t.Error("StaleDateFallback = false, want true")
+	}
+}
+
+func TestLoadAPIConfig_InflightDedup(t *testing.T) {
+	if cfg := LoadAPIConfig(); cfg.InflightDedup {
+		t.Error("InflightDedup = true, want false by default")
+	}
+
+	os.Setenv("PROVIDER_INFLIGHT_DEDUP", "true")
+	defer os.Unsetenv("PROVIDER_INFLIGHT_DEDUP")
+
+	if cfg := LoadAPIConfig(); !cfg.InflightDedup {
+		t.Error("InflightDedup = false, want true")
+	}
+}
+
+func TestLoadAPIConfig_MinRates(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"unset is disabled", "", 0},
+		{"custom minimum", "1", 1},
+		{"invalid keeps default", "some", 0},
+		{"negative keeps default", "-1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				os.Setenv("PROVIDER_MIN_RATES", tt.value)
+				defer os.Unsetenv("PROVIDER_MIN_RATES")
+			}
+
+			if cfg := LoadAPIConfig(); cfg.MinRates != tt.want {
+				t.Errorf("MinRates = %d, want %d", cfg.MinRates, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAPIConfig_InverseFallback(t *testing.T) {
+	if cfg := LoadAPIConfig(); cfg.InverseFallback {
+		t.Error("InverseFallback = true, want false by default")
+	}
+
+	os.Setenv("PROVIDER_INVERSE_FALLBACK", "true")
+	defer os.Unsetenv("PROVIDER_INVERSE_FALLBACK")
+
+	if cfg := LoadAPIConfig(); !cfg.InverseFallback {
+		t.Error("InverseFallback = false, want true")
+	}
+}
+
+func TestLoadAPIConfig_ProviderURLs(t *testing.T) {
+	os.Setenv("PROVIDER_URLS", "https://latest.currency-api.pages.dev/v1, https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@latest/v1,")
+	defer os.Unsetenv("PROVIDER_URLS")
+
+	cfg := LoadAPIConfig()
+
+	want := []string{
+		"https://latest.currency-api.pages.dev/v1",
+		"https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@latest/v1",
+	}
+	if len(cfg.ProviderURLs) != len(want) {
+		t.Fatalf("ProviderURLs = %v, want %v", cfg.ProviderURLs, want)
+	}
+	for i := range want {
+		if cfg.ProviderURLs[i] != want[i] {
+			t.Errorf("ProviderURLs[%d] = %q, want %q", i, cfg.ProviderURLs[i], want[i])
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 // - CIRCUIT_BREAKER_FAILURE_THRESHOLD: Number of failures before opening (default: 5)
 // - CIRCUIT_BREAKER_COOLDOWN_SECONDS: Cooldown duration in seconds (default: 30)
 // - CIRCUIT_BREAKER_SUCCESS_THRESHOLD: Successes needed in HalfOpen to close (default: 1)
+// - CIRCUIT_BREAKER_HALF_OPEN_MAX_CONCURRENT: Test requests allowed in flight in HalfOpen (default: 1)
 //
 // Returns a circuitbreaker.Config with defaults if environment variables are not set.
 //
@@ -46,9 +47,18 @@ func LoadCircuitBreakerConfig() circuitbreaker.Config {
 		}
 	}
 
+	// Load half-open concurrency limit from environment
+	halfOpenMaxConcurrent := 1 // default
+	if maxStr := os.Getenv("CIRCUIT_BREAKER_HALF_OPEN_MAX_CONCURRENT"); maxStr != "" {
+		if parsed, err := strconv.Atoi(maxStr); err == nil && parsed > 0 {
+			halfOpenMaxConcurrent = parsed
+		}
+	}
+
 	return circuitbreaker.Config{
-		FailureThreshold: failureThreshold,
-		CooldownDuration: time.Duration(cooldownSeconds) * time.Second,
-		SuccessThreshold: successThreshold,
+		FailureThreshold:      failureThreshold,
+		CooldownDuration:      time.Duration(cooldownSeconds) * time.Second,
+		SuccessThreshold:      successThreshold,
+		HalfOpenMaxConcurrent: halfOpenMaxConcurrent,
 	}
 }
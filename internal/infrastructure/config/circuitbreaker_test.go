@@ -25,6 +25,10 @@ func TestLoadCircuitBreakerConfig_Defaults(t *testing.T) {
 	if cfg.SuccessThreshold != 1 {
 		t.Errorf("SuccessThreshold = %d, want 1", cfg.SuccessThreshold)
 	}
+
+	if cfg.HalfOpenMaxConcurrent != 1 {
+		t.Errorf("HalfOpenMaxConcurrent = %d, want 1", cfg.HalfOpenMaxConcurrent)
+	}
 }
 
 func TestLoadCircuitBreakerConfig_CustomValues(t *testing.T) {
@@ -32,10 +36,12 @@ func TestLoadCircuitBreakerConfig_CustomValues(t *testing.T) {
 	os.Setenv("CIRCUIT_BREAKER_FAILURE_THRESHOLD", "10")
 	os.Setenv("CIRCUIT_BREAKER_COOLDOWN_SECONDS", "60")
 	os.Setenv("CIRCUIT_BREAKER_SUCCESS_THRESHOLD", "2")
+	os.Setenv("CIRCUIT_BREAKER_HALF_OPEN_MAX_CONCURRENT", "3")
 	defer func() {
 		os.Unsetenv("CIRCUIT_BREAKER_FAILURE_THRESHOLD")
 		os.Unsetenv("CIRCUIT_BREAKER_COOLDOWN_SECONDS")
 		os.Unsetenv("CIRCUIT_BREAKER_SUCCESS_THRESHOLD")
+		os.Unsetenv("CIRCUIT_BREAKER_HALF_OPEN_MAX_CONCURRENT")
 	}()
 
 	cfg := LoadCircuitBreakerConfig()
@@ -51,6 +57,10 @@ func TestLoadCircuitBreakerConfig_CustomValues(t *testing.T) {
 	if cfg.SuccessThreshold != 2 {
 		t.Errorf("SuccessThreshold = %d, want 2", cfg.SuccessThreshold)
 	}
+
+	if cfg.HalfOpenMaxConcurrent != 3 {
+		t.Errorf("HalfOpenMaxConcurrent = %d, want 3", cfg.HalfOpenMaxConcurrent)
+	}
 }
 
 func TestLoadCircuitBreakerConfig_InvalidValues(t *testing.T) {
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+)
+
+// LoadCleanupConfig loads expired rate cleanup configuration from environment variables.
+//
+// Environment variables:
+// - CLEANUP_MAX_AGE: Delete rates with a timestamp older than this (e.g. "48h") (default: 48h)
+// - CLEANUP_MAX_PAGES: Maximum scan pages per invocation (default: 10)
+// - CLEANUP_PAGE_SIZE: Items evaluated per scan page (default: 100)
+//
+// Returns a dynamodb.ExpiredCleanerConfig with defaults if environment variables are not set.
+//
+// Example usage:
+//
+//	cfg := LoadCleanupConfig()
+//	cleaner := dynamodb.NewExpiredCleaner(client, tableName, cfg)
+func LoadCleanupConfig() dynamodb.ExpiredCleanerConfig {
+	cfg := dynamodb.DefaultExpiredCleanerConfig()
+
+	// Load maximum rate age from environment
+	if maxAgeStr := os.Getenv("CLEANUP_MAX_AGE"); maxAgeStr != "" {
+		if parsed, err := time.ParseDuration(maxAgeStr); err == nil && parsed > 0 {
+			cfg.MaxAge = parsed
+		}
+	}
+
+	// Load page budget per invocation from environment
+	if maxPagesStr := os.Getenv("CLEANUP_MAX_PAGES"); maxPagesStr != "" {
+		if parsed, err := strconv.Atoi(maxPagesStr); err == nil && parsed > 0 {
+			cfg.MaxPages = parsed
+		}
+	}
+
+	// Load scan page size from environment
+	if pageSizeStr := os.Getenv("CLEANUP_PAGE_SIZE"); pageSizeStr != "" {
+		if parsed, err := strconv.ParseInt(pageSizeStr, 10, 32); err == nil && parsed > 0 {
+			cfg.PageSize = int32(parsed)
+		}
+	}
+
+	return cfg
+}
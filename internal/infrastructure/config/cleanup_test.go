@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoadCleanupConfig_Defaults(t *testing.T) {
+	// Clear environment variables
+	os.Unsetenv("CLEANUP_MAX_AGE")
+	os.Unsetenv("CLEANUP_MAX_PAGES")
+	os.Unsetenv("CLEANUP_PAGE_SIZE")
+
+	cfg := LoadCleanupConfig()
+
+	if cfg.MaxAge != 48*time.Hour {
+		t.Errorf("MaxAge = %v, want 48h", cfg.MaxAge)
+	}
+
+	if cfg.MaxPages != 10 {
+		t.Errorf("MaxPages = %d, want 10", cfg.MaxPages)
+	}
+
+	if cfg.PageSize != 100 {
+		t.Errorf("PageSize = %d, want 100", cfg.PageSize)
+	}
+}
+
+func TestLoadCleanupConfig_CustomValues(t *testing.T) {
+	// Set custom values
+	os.Setenv("CLEANUP_MAX_AGE", "72h")
+	os.Setenv("CLEANUP_MAX_PAGES", "5")
+	os.Setenv("CLEANUP_PAGE_SIZE", "250")
+	defer func() {
+		os.Unsetenv("CLEANUP_MAX_AGE")
+		os.Unsetenv("CLEANUP_MAX_PAGES")
+		os.Unsetenv("CLEANUP_PAGE_SIZE")
+	}()
+
+	cfg := LoadCleanupConfig()
+
+	if cfg.MaxAge != 72*time.Hour {
+		t.Errorf("MaxAge = %v, want 72h", cfg.MaxAge)
+	}
+
+	if cfg.MaxPages != 5 {
+		t.Errorf("MaxPages = %d, want 5", cfg.MaxPages)
+	}
+
+	if cfg.PageSize != 250 {
+		t.Errorf("PageSize = %d, want 250", cfg.PageSize)
+	}
+}
+
+func TestLoadCleanupConfig_InvalidValues(t *testing.T) {
+	// Invalid values fall back to defaults
+	os.Setenv("CLEANUP_MAX_AGE", "two days")
+	os.Setenv("CLEANUP_MAX_PAGES", "-1")
+	os.Setenv("CLEANUP_PAGE_SIZE", "0")
+	defer func() {
+		os.Unsetenv("CLEANUP_MAX_AGE")
+		os.Unsetenv("CLEANUP_MAX_PAGES")
+		os.Unsetenv("CLEANUP_PAGE_SIZE")
+	}()
+
+	cfg := LoadCleanupConfig()
+
+	if cfg.MaxAge != 48*time.Hour {
+		t.Errorf("MaxAge = %v, want 48h", cfg.MaxAge)
+	}
+
+	if cfg.MaxPages != 10 {
+		t.Errorf("MaxPages = %d, want 10", cfg.MaxPages)
+	}
+
+	if cfg.PageSize != 100 {
+		t.Errorf("PageSize = %d, want 100", cfg.PageSize)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
@@ -20,46 +24,234 @@ type Config struct {
 	// Circuit breaker configuration
 	CircuitBreaker circuitbreaker.Config
 
+	// Circuit breaker scoping (global or per base currency)
+	CircuitBreakerScope CircuitBreakerScopeConfig
+
 	// Cache configuration
 	Cache CacheConfig
 
 	// Secrets Manager configuration
 	SecretsManager SecretsManagerConfig
+
+	// Response formatting configuration
+	Response ResponseConfig
+
+	// Idempotency configuration for mutating endpoints
+	Idempotency IdempotencyConfig
+
+	// Accepted range of exchange rate values
+	RateBounds entity.RateBounds
+
+	// Anomaly detection against the last cached rate
+	Anomaly AnomalyConfig
+
+	// Rate update event publishing
+	Events EventsConfig
+
+	// Duplicate API Gateway delivery handling
+	RequestDedup RequestDedupConfig
+
+	// Client identification for rate limiting
+	ClientIdentity ClientIdentityConfig
+
+	// Rates endpoint defaults
+	Rates RatesConfig
+
+	// Health endpoint readiness checks
+	Health HealthConfig
+
+	// API key authentication
+	Auth AuthConfig
 }
 
 // DynamoDBConfig holds DynamoDB-specific configuration.
 type DynamoDBConfig struct {
-	TableName string // DynamoDB table name (required)
-	Region    string // AWS region (optional, uses default if not set)
+	TableName       string // DynamoDB table name (required)
+	Region          string // AWS region (optional, uses default if not set)
+	ConsistentRead  bool   // Use strongly consistent reads for Get (2x RCU cost, default: false)
+	AutoCreateTable bool   // Create the table on startup if it does not exist, for local/dev use (default: false)
+	EnsureTTL       bool   // Enable DynamoDB TTL on the ttl attribute on startup if disabled (default: false)
+
+	DisableRegionValidation bool // Accept any Region, e.g. a placeholder for DynamoDB Local (default: false)
+}
+
+// awsRegionPattern matches AWS region names such as "us-east-1", "ap-southeast-2",
+// or "us-gov-west-1".
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// CircuitBreakerScopeConfig holds circuit breaker scoping and outcome configuration.
+type CircuitBreakerScopeConfig struct {
+	PerBase bool          // Keep an independent circuit breaker per base currency (default: false)
+	PerPair bool          // Also guard single-pair fetches with a breaker per base/target pair (default: false)
+	IdleTTL time.Duration // How long an unused per-base or per-pair breaker is kept (default: 1 hour)
+
+	EmptyRatesFailure bool // Count an empty all-rates response as a breaker failure (default: false)
 }
 
 // CacheConfig holds cache-specific configuration.
 type CacheConfig struct {
-	TTL time.Duration // Cache TTL (default: 1 hour)
+	TTL                 time.Duration // Cache TTL (default: 1 hour)
+	MinTTL              time.Duration // Smallest TTL accepted by Validate (default: 10 seconds, 0 = no minimum)
+	MaxTTL              time.Duration // Largest TTL accepted by Validate (default: 24 hours, 0 = no maximum)
+	ExpiredCleanupGrace time.Duration // Delete cached rates expired for longer than this (default: 0, disabled)
+	StaleRetention      time.Duration // How long rates are kept past the TTL for stale fallbacks (default: 24 hours)
+	FallbackStrategy    string        // Order of cache, provider, and stale cache lookups (default: "cache-first")
+	FallbackMaxStale    time.Duration // How long past the TTL stale-ok serves rates before calling the provider (default: 6 hours)
+	AbsoluteMaxAge      time.Duration // Never serve a stale rate older than this as a fallback (default: 0, no cap)
+	WarmOnFetch         bool          // Also cache the other rates downloaded by a single-pair fetch (default: false)
+	SaveConcurrency     int           // Maximum concurrent cache saves when caching all rates for a base, also when warming the cache on a single-pair fetch (default: 10)
+	EmbeddedFallback    bool          // Serve bundled static rates when the cache and every provider fail; dev/demo only (default: false)
+	FetchLock           bool          // Limit provider fetches of a pair to one instance with a DynamoDB lock (default: false)
+	FetchLockTTL        time.Duration // Lease of a fetch lock (default: 10 seconds)
+	FetchLockWait       time.Duration // How long other instances poll the cache for the lock holder's rate (default: 2 seconds)
+	RefreshAhead        float64       // Refresh a cached rate while serving it once less than this fraction of the TTL remains (default: 0, disabled)
+	RefreshAheadJitter  float64       // Randomly shrink the refresh-ahead window by up to this fraction (default: 0.25)
+	NoCachePairs        []string      // Pairs always fetched from the provider, as BASE/TARGET; the cache only serves them as a stale fallback (optional)
 }
 
 // SecretsManagerConfig holds Secrets Manager configuration.
 type SecretsManagerConfig struct {
 	SecretName string        // Secret name or ARN (optional)
 	CacheTTL   time.Duration // Secret cache TTL (default: 5 minutes)
+	Timeout    time.Duration // Timeout of a Secrets Manager call (default: 3 seconds)
 	Enabled    bool          // Whether to use Secrets Manager (default: false)
+
+	FailureBackoff time.Duration // How long a failed secret fetch is returned again before retrying (default: 5 seconds, 0 = disabled)
+	Plaintext      bool          // Whether the secret is the bare API key rather than JSON (default: false)
+	KeyField       string        // JSON field of the primary API key in the secret (default: "api-key")
+}
+
+// ResponseConfig holds response formatting configuration.
+type ResponseConfig struct {
+	Envelope           bool          // Wrap response bodies in a {"data": ..., "meta": ...} envelope (default: false)
+	SourceHeader       bool          // Add an X-Rate-Source header naming the provider that served a fetch (default: false)
+	FreshnessSLA       bool          // Add an X-Rate-Freshness-SLA header with the cache TTL in seconds (default: false)
+	DebugErrors        bool          // Include internal error details in error bodies; ignored in production (default: false)
+	AvailableTargets   bool          // List the targets offered for a base in errors for an unsupported target (default: false)
+	DisablePretty      bool          // Ignore ?pretty=true so bodies are always compact JSON (default: false)
+	TimestampPrecision time.Duration // Truncate rate timestamps to a multiple of this (default: 0, unchanged)
+}
+
+// IdempotencyConfig holds idempotency-key configuration.
+type IdempotencyConfig struct {
+	TTL time.Duration // How long stored results are replayed (default: 1 hour)
+}
+
+// AnomalyConfig holds rate anomaly detection configuration.
+type AnomalyConfig struct {
+	MaxRateDelta float64 // Maximum relative change vs. the cached rate, 0.5 = 50% (default: 0.5, 0 disables)
+	Reject       bool    // Serve the cached rate instead of an anomalous fresh rate (default: false)
+
+	// Escape hatches so a rejected rate that is genuine is eventually saved
+	Confirmations int           // Accept a rejected rate once this many consecutive fetches agree on it (default: 3, 0 disables)
+	MaxCacheAge   time.Duration // Accept a rejected rate once the cached rate is older than this (default: 24h, 0 disables)
+}
+
+// EventsConfig holds rate update event publishing configuration.
+type EventsConfig struct {
+	TopicARN string // SNS topic ARN for rate update events (optional, disabled if empty)
+}
+
+// RequestDedupConfig holds request deduplication configuration.
+type RequestDedupConfig struct {
+	Window time.Duration // How long responses are replayed for duplicate request IDs (default: 0, disabled)
+}
+
+// ClientIdentityConfig holds client identification configuration.
+type ClientIdentityConfig struct {
+	TrustedProxies []string // IPs or CIDR ranges whose X-Forwarded-For entries are trusted (optional)
+	Header         string   // Header identifying clients without an API key or IP (optional)
+}
+
+// RatesConfig holds rates endpoint configuration.
+type RatesConfig struct {
+	DefaultBase        string            // Base currency for GET /rates without a base (default: "USD")
+	MaxBasesPerRequest int               // Maximum bases in GET /rates?bases= (default: 10)
+	MinRates           int               // All-rates responses with fewer rates are suspect and a richer cache is preferred; thinner stale fallbacks fail (default: 0, disabled)
+	DeniedPairs        []string          // Currency pairs never served, as BASE/TARGET with "*" wildcards (optional)
+	CurrencyAliases    map[string]string // Legacy or alternative codes mapped to canonical codes (optional)
+}
+
+// AuthConfig holds API key authentication configuration.
+type AuthConfig struct {
+	SkipPaths []string // Paths served without an API key, even when authentication is enabled (default: /health, /version)
+}
+
+// HealthConfig holds health endpoint configuration.
+type HealthConfig struct {
+	RequireCache bool // Report degraded until the default base currency has cached rates (default: false)
 }
 
 // LoadConfig loads all configuration from environment variables.
 //
 // Environment variables:
+// - CONFIG_FILE: JSON or YAML file with defaults for the variables below; set variables override it (optional, see LoadConfigFromFile)
 // - TABLE_NAME: DynamoDB table name (required)
 // - AWS_REGION: AWS region (optional)
+// - DISABLE_REGION_VALIDATION: Accept an AWS_REGION that does not look like an AWS region name (default: "false")
+// - DYNAMODB_CONSISTENT_READ: Use strongly consistent reads for Get, at twice the RCU cost (default: "false")
+// - AUTO_CREATE_TABLE: Create the table, GSI, and TTL on startup if missing; for local/dev only (default: "false")
+// - ENSURE_TTL: Enable DynamoDB TTL on the ttl attribute on startup if disabled (default: "false")
 // - CACHE_TTL: Cache TTL as duration string (default: "1h")
+// - CACHE_MIN_TTL: Smallest accepted CACHE_TTL, as duration string (default: "10s", "0s" = no minimum)
+// - CACHE_MAX_TTL: Largest accepted CACHE_TTL, as duration string (default: "24h", "0s" = no maximum)
+// - STALE_RETENTION: How long rates are kept in DynamoDB past CACHE_TTL for stale fallbacks, as duration string (default: "24h")
+// - EXPIRED_CLEANUP_GRACE: Delete cached rates expired for longer than this, as duration string (default: "0s", disabled)
+// - FALLBACK_STRATEGY: Resolution order, one of "cache-first", "stale-ok", "provider-first" (default: "cache-first")
+// - FALLBACK_MAX_STALE: How long past CACHE_TTL stale-ok serves rates before calling the provider, as duration string (default: "6h", "0s" = no bound)
+// - ABSOLUTE_MAX_AGE: Maximum age of a cached rate served as a stale fallback, as duration string (default: "0s" = no cap)
+// - CACHE_WARM_ON_FETCH: Also cache the other rates of the base file downloaded for a single pair (default: "false")
+// - EMBEDDED_FALLBACK_ENABLED: Serve bundled static rates, marked stale and approximate, when the cache and every provider fail; never for production (default: "false")
+// - SAVE_CONCURRENCY: Maximum concurrent cache saves when caching all rates for a base or warming the cache on a single-pair fetch, "1" saves sequentially (default: 10)
+// - FETCH_LOCK_ENABLED: Limit provider fetches of a pair to one instance with a DynamoDB lock (default: "false")
+// - FETCH_LOCK_TTL: Lease of a fetch lock, as duration string (default: "10s")
+// - FETCH_LOCK_WAIT: How long other instances poll the cache for the lock holder's rate, as duration string (default: "2s")
+// - CACHE_REFRESH_AHEAD: Refresh a cached rate while serving it once less than this fraction (0-1) of the TTL remains (default: 0, disabled)
+// - CACHE_REFRESH_AHEAD_JITTER: Randomly shrink the refresh-ahead window by up to this fraction (0-1) (default: 0.25)
+// - NO_CACHE_PAIRS: Comma-separated currency pairs always fetched from the provider, e.g. "BTC/USD,ETH/USD" (optional)
 // - EXCHANGE_RATE_API_URL: Base URL for the API (default: "https://cdn.jsdelivr.net/npm/@fawazahmed0/currency-api@latest/v1")
 // - EXCHANGE_RATE_API_TIMEOUT: HTTP client timeout in seconds (default: 10)
 // - EXCHANGE_RATE_API_RETRY_ATTEMPTS: Maximum retry attempts (default: 3)
 // - CIRCUIT_BREAKER_FAILURE_THRESHOLD: Number of failures before opening (default: 5)
 // - CIRCUIT_BREAKER_COOLDOWN_SECONDS: Cooldown duration in seconds (default: 30)
 // - CIRCUIT_BREAKER_SUCCESS_THRESHOLD: Successes needed in HalfOpen to close (default: 1)
+// - CIRCUIT_BREAKER_HALF_OPEN_MAX_CONCURRENT: Test requests allowed in flight in HalfOpen (default: 1)
+// - CIRCUIT_BREAKER_PER_BASE: Keep an independent circuit breaker per base currency (default: "false")
+// - CIRCUIT_BREAKER_PER_PAIR: Also keep a circuit breaker per base/target pair for pair-specific failures (default: "false")
+// - CIRCUIT_BREAKER_IDLE_TTL: How long an unused per-base or per-pair breaker is kept, as duration string (default: "1h")
+// - CIRCUIT_BREAKER_EMPTY_RATES_FAILURE: Count an empty all-rates response as a circuit breaker failure (default: "false")
 // - SECRETS_MANAGER_SECRET_NAME: Secret name or ARN (optional)
 // - SECRETS_MANAGER_CACHE_TTL: Secret cache TTL as duration string (default: "5m")
+// - SECRETS_MANAGER_TIMEOUT: Timeout of a Secrets Manager call, as duration string (default: "3s")
+// - SECRETS_MANAGER_FAILURE_BACKOFF: How long a failed secret fetch is returned again before retrying, as duration string (default: "5s", "0s" disables)
 // - SECRETS_MANAGER_ENABLED: Enable Secrets Manager (default: "false")
+// - SECRET_PLAINTEXT: Read the secret string as the bare API key instead of JSON (default: "false")
+// - SECRET_KEY_FIELD: JSON field of the primary API key in the secret (default: "api-key")
+// - RESPONSE_ENVELOPE: Wrap responses in a data/meta envelope (default: "false")
+// - RESPONSE_SOURCE_HEADER: Add an X-Rate-Source header naming the provider that served a fetch (default: "false")
+// - RESPONSE_FRESHNESS_SLA_HEADER: Add an X-Rate-Freshness-SLA header with the maximum age of a non-stale rate (default: "false")
+// - DEBUG_ERRORS: Include internal error details in a "debug" field of error bodies; never honored in production (default: "false")
+// - RESPONSE_AVAILABLE_TARGETS: List the targets offered for the base in 404 errors for an unsupported target (default: "false")
+// - DISABLE_PRETTY_JSON: Ignore ?pretty=true requests for indented JSON (default: "false")
+// - RESPONSE_TIMESTAMP_PRECISION: Truncate rate timestamps in responses to a multiple of this duration, e.g. "1s" (default: "0s" = unchanged)
+// - IDEMPOTENCY_TTL: How long idempotent results are replayed, as duration string (default: "1h")
+// - MIN_RATE: Smallest accepted exchange rate value (default: 1e-12)
+// - MAX_RATE: Largest accepted exchange rate value (default: 1e12)
+// - MAX_RATE_DELTA: Maximum relative change vs. the cached rate before a rate is anomalous (default: 0.5)
+// - REJECT_ANOMALOUS_RATES: Serve the cached rate instead of an anomalous one (default: "false")
+// - ANOMALY_CONFIRMATIONS: Accept a rejected rate once this many consecutive fetches agree on it (default: 3, 0 disables)
+// - ANOMALY_MAX_CACHE_AGE: Accept a rejected rate once the cached rate is older than this, as duration string (default: "24h", "0s" disables)
+// - RATE_EVENT_TOPIC_ARN: SNS topic ARN to publish rate update events to (optional)
+// - REQUEST_DEDUP_WINDOW: How long responses are replayed for duplicate request IDs, as duration string (default: "0s", disabled; e.g. "30s" enables)
+// - TRUSTED_PROXIES: Comma-separated IPs or CIDR ranges whose X-Forwarded-For entries are trusted (optional)
+// - CLIENT_ID_HEADER: Header identifying clients without an API key or source IP (optional)
+// - DEFAULT_BASE_CURRENCY: Base currency for GET /rates without a base (default: "USD")
+// - MAX_BASES_PER_REQUEST: Maximum bases in GET /rates?bases= (default: 10)
+// - MIN_RATES_THRESHOLD: Fresh all-rates responses with fewer rates are suspect; a richer cached set is served instead, and a stale fallback with fewer rates fails (default: 0, disabled)
+// - DENIED_PAIRS: Comma-separated currency pairs never served, e.g. "USD/RUB,*/KPW" (optional)
+// - CURRENCY_ALIASES: Comma-separated ALIAS:CODE pairs resolved before lookup, e.g. "XBT:BTC,DEM:EUR" (optional)
+// - READINESS_REQUIRE_CACHE: Report /health degraded until DEFAULT_BASE_CURRENCY has cached rates (default: "false")
+// - AUTH_SKIP_PATHS: Comma-separated paths served without an API key; set empty to authenticate every path (default: "/health,/version")
 //
 // Returns an error if required configuration is missing or invalid.
 //
@@ -70,11 +262,21 @@ type SecretsManagerConfig struct {
 //	    log.Fatalf("failed to load config: %v", err)
 //	}
 func LoadConfig() (*Config, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := applyConfigFile(path); err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
 	cfg := &Config{}
 
 	// Load DynamoDB configuration
 	cfg.DynamoDB.TableName = os.Getenv("TABLE_NAME")
 	cfg.DynamoDB.Region = os.Getenv("AWS_REGION")
+	cfg.DynamoDB.DisableRegionValidation = os.Getenv("DISABLE_REGION_VALIDATION") == "true"
+	cfg.DynamoDB.ConsistentRead = os.Getenv("DYNAMODB_CONSISTENT_READ") == "true"
+	cfg.DynamoDB.AutoCreateTable = os.Getenv("AUTO_CREATE_TABLE") == "true"
+	cfg.DynamoDB.EnsureTTL = os.Getenv("ENSURE_TTL") == "true"
 
 	// Load API configuration (reuse existing function)
 	cfg.API = LoadAPIConfig()
@@ -82,6 +284,18 @@ func LoadConfig() (*Config, error) {
 	// Load circuit breaker configuration (reuse existing function)
 	cfg.CircuitBreaker = LoadCircuitBreakerConfig()
 
+	// Load circuit breaker scope configuration
+	cfg.CircuitBreakerScope.PerBase = os.Getenv("CIRCUIT_BREAKER_PER_BASE") == "true"
+	cfg.CircuitBreakerScope.PerPair = os.Getenv("CIRCUIT_BREAKER_PER_PAIR") == "true"
+	breakerIdleTTL := circuitbreaker.DefaultIdleTTL // default
+	if ttlStr := os.Getenv("CIRCUIT_BREAKER_IDLE_TTL"); ttlStr != "" {
+		if parsed, err := time.ParseDuration(ttlStr); err == nil && parsed > 0 {
+			breakerIdleTTL = parsed
+		}
+	}
+	cfg.CircuitBreakerScope.IdleTTL = breakerIdleTTL
+	cfg.CircuitBreakerScope.EmptyRatesFailure = os.Getenv("CIRCUIT_BREAKER_EMPTY_RATES_FAILURE") == "true"
+
 	// Load cache configuration
 	cacheTTL := 1 * time.Hour // default
 	if ttlStr := os.Getenv("CACHE_TTL"); ttlStr != "" {
@@ -90,6 +304,81 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 	cfg.Cache.TTL = cacheTTL
+	cfg.Cache.MinTTL = 10 * time.Second // default
+	if minStr := os.Getenv("CACHE_MIN_TTL"); minStr != "" {
+		if parsed, err := time.ParseDuration(minStr); err == nil && parsed >= 0 {
+			cfg.Cache.MinTTL = parsed
+		}
+	}
+	cfg.Cache.MaxTTL = 24 * time.Hour // default
+	if maxStr := os.Getenv("CACHE_MAX_TTL"); maxStr != "" {
+		if parsed, err := time.ParseDuration(maxStr); err == nil && parsed >= 0 {
+			cfg.Cache.MaxTTL = parsed
+		}
+	}
+	cfg.Cache.StaleRetention = 24 * time.Hour // default
+	if retentionStr := os.Getenv("STALE_RETENTION"); retentionStr != "" {
+		if parsed, err := time.ParseDuration(retentionStr); err == nil && parsed >= 0 {
+			cfg.Cache.StaleRetention = parsed
+		}
+	}
+	if graceStr := os.Getenv("EXPIRED_CLEANUP_GRACE"); graceStr != "" {
+		if parsed, err := time.ParseDuration(graceStr); err == nil && parsed >= 0 {
+			cfg.Cache.ExpiredCleanupGrace = parsed
+		}
+	}
+	cfg.Cache.WarmOnFetch = os.Getenv("CACHE_WARM_ON_FETCH") == "true"
+	cfg.Cache.EmbeddedFallback = os.Getenv("EMBEDDED_FALLBACK_ENABLED") == "true"
+	cfg.Cache.SaveConcurrency = 10 // default
+	if concurrencyStr := os.Getenv("SAVE_CONCURRENCY"); concurrencyStr != "" {
+		if parsed, err := strconv.Atoi(concurrencyStr); err == nil && parsed > 0 {
+			cfg.Cache.SaveConcurrency = parsed
+		}
+	}
+	cfg.Cache.FetchLock = os.Getenv("FETCH_LOCK_ENABLED") == "true"
+	cfg.Cache.FetchLockTTL = 10 * time.Second // default
+	if ttlStr := os.Getenv("FETCH_LOCK_TTL"); ttlStr != "" {
+		if parsed, err := time.ParseDuration(ttlStr); err == nil && parsed > 0 {
+			cfg.Cache.FetchLockTTL = parsed
+		}
+	}
+	cfg.Cache.FetchLockWait = 2 * time.Second // default
+	if waitStr := os.Getenv("FETCH_LOCK_WAIT"); waitStr != "" {
+		if parsed, err := time.ParseDuration(waitStr); err == nil && parsed >= 0 {
+			cfg.Cache.FetchLockWait = parsed
+		}
+	}
+	if fractionStr := os.Getenv("CACHE_REFRESH_AHEAD"); fractionStr != "" {
+		if parsed, err := strconv.ParseFloat(fractionStr, 64); err == nil && parsed >= 0 && parsed < 1 {
+			cfg.Cache.RefreshAhead = parsed
+		}
+	}
+	cfg.Cache.RefreshAheadJitter = 0.25 // default
+	if jitterStr := os.Getenv("CACHE_REFRESH_AHEAD_JITTER"); jitterStr != "" {
+		if parsed, err := strconv.ParseFloat(jitterStr, 64); err == nil && parsed >= 0 && parsed <= 1 {
+			cfg.Cache.RefreshAheadJitter = parsed
+		}
+	}
+	for _, pair := range strings.Split(os.Getenv("NO_CACHE_PAIRS"), ",") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			cfg.Cache.NoCachePairs = append(cfg.Cache.NoCachePairs, pair)
+		}
+	}
+	cfg.Cache.FallbackStrategy = "cache-first" // default
+	if strategyStr := strings.TrimSpace(os.Getenv("FALLBACK_STRATEGY")); strategyStr != "" {
+		cfg.Cache.FallbackStrategy = strategyStr
+	}
+	cfg.Cache.FallbackMaxStale = 6 * time.Hour // default
+	if maxStaleStr := os.Getenv("FALLBACK_MAX_STALE"); maxStaleStr != "" {
+		if parsed, err := time.ParseDuration(maxStaleStr); err == nil && parsed >= 0 {
+			cfg.Cache.FallbackMaxStale = parsed
+		}
+	}
+	if maxAgeStr := os.Getenv("ABSOLUTE_MAX_AGE"); maxAgeStr != "" {
+		if parsed, err := time.ParseDuration(maxAgeStr); err == nil && parsed >= 0 {
+			cfg.Cache.AbsoluteMaxAge = parsed
+		}
+	}
 
 	// Load Secrets Manager configuration
 	cfg.SecretsManager.SecretName = os.Getenv("SECRETS_MANAGER_SECRET_NAME")
@@ -101,6 +390,155 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 	cfg.SecretsManager.CacheTTL = secretCacheTTL
+	cfg.SecretsManager.Timeout = DefaultSecretsCallTimeout
+	if timeoutStr := os.Getenv("SECRETS_MANAGER_TIMEOUT"); timeoutStr != "" {
+		if parsed, err := time.ParseDuration(timeoutStr); err == nil && parsed > 0 {
+			cfg.SecretsManager.Timeout = parsed
+		}
+	}
+	cfg.SecretsManager.FailureBackoff = DefaultSecretsFailureBackoff
+	if backoffStr := os.Getenv("SECRETS_MANAGER_FAILURE_BACKOFF"); backoffStr != "" {
+		if parsed, err := time.ParseDuration(backoffStr); err == nil && parsed >= 0 {
+			cfg.SecretsManager.FailureBackoff = parsed
+		}
+	}
+	cfg.SecretsManager.Plaintext = os.Getenv("SECRET_PLAINTEXT") == "true"
+	cfg.SecretsManager.KeyField = DefaultSecretKeyField
+	if keyField := os.Getenv("SECRET_KEY_FIELD"); keyField != "" {
+		cfg.SecretsManager.KeyField = keyField
+	}
+
+	// Load response configuration
+	cfg.Response.Envelope = os.Getenv("RESPONSE_ENVELOPE") == "true"
+	cfg.Response.SourceHeader = os.Getenv("RESPONSE_SOURCE_HEADER") == "true"
+	cfg.Response.FreshnessSLA = os.Getenv("RESPONSE_FRESHNESS_SLA_HEADER") == "true"
+	cfg.Response.DebugErrors = os.Getenv("DEBUG_ERRORS") == "true"
+	cfg.Response.AvailableTargets = os.Getenv("RESPONSE_AVAILABLE_TARGETS") == "true"
+	cfg.Response.DisablePretty = os.Getenv("DISABLE_PRETTY_JSON") == "true"
+	if precisionStr := os.Getenv("RESPONSE_TIMESTAMP_PRECISION"); precisionStr != "" {
+		if parsed, err := time.ParseDuration(precisionStr); err == nil && parsed >= 0 {
+			cfg.Response.TimestampPrecision = parsed
+		}
+	}
+
+	// Load idempotency configuration
+	idempotencyTTL := 1 * time.Hour // default
+	if ttlStr := os.Getenv("IDEMPOTENCY_TTL"); ttlStr != "" {
+		if parsed, err := time.ParseDuration(ttlStr); err == nil && parsed > 0 {
+			idempotencyTTL = parsed
+		}
+	}
+	cfg.Idempotency.TTL = idempotencyTTL
+
+	// Load rate bounds configuration
+	cfg.RateBounds = entity.DefaultRateBounds()
+	if minStr := os.Getenv("MIN_RATE"); minStr != "" {
+		if parsed, err := strconv.ParseFloat(minStr, 64); err == nil {
+			cfg.RateBounds.Min = parsed
+		}
+	}
+	if maxStr := os.Getenv("MAX_RATE"); maxStr != "" {
+		if parsed, err := strconv.ParseFloat(maxStr, 64); err == nil {
+			cfg.RateBounds.Max = parsed
+		}
+	}
+
+	// Load anomaly detection configuration
+	cfg.Anomaly.MaxRateDelta = 0.5 // default
+	if deltaStr := os.Getenv("MAX_RATE_DELTA"); deltaStr != "" {
+		if parsed, err := strconv.ParseFloat(deltaStr, 64); err == nil && parsed >= 0 {
+			cfg.Anomaly.MaxRateDelta = parsed
+		}
+	}
+	cfg.Anomaly.Reject = os.Getenv("REJECT_ANOMALOUS_RATES") == "true"
+	cfg.Anomaly.Confirmations = 3 // default
+	if confirmStr := os.Getenv("ANOMALY_CONFIRMATIONS"); confirmStr != "" {
+		if parsed, err := strconv.Atoi(confirmStr); err == nil && parsed >= 0 {
+			cfg.Anomaly.Confirmations = parsed
+		}
+	}
+	cfg.Anomaly.MaxCacheAge = 24 * time.Hour // default
+	if ageStr := os.Getenv("ANOMALY_MAX_CACHE_AGE"); ageStr != "" {
+		if parsed, err := time.ParseDuration(ageStr); err == nil && parsed >= 0 {
+			cfg.Anomaly.MaxCacheAge = parsed
+		}
+	}
+
+	// Load event publishing configuration
+	cfg.Events.TopicARN = os.Getenv("RATE_EVENT_TOPIC_ARN")
+
+	// Load request deduplication configuration
+	var dedupWindow time.Duration // default: disabled (opt-in)
+	if windowStr := os.Getenv("REQUEST_DEDUP_WINDOW"); windowStr != "" {
+		if parsed, err := time.ParseDuration(windowStr); err == nil && parsed >= 0 {
+			dedupWindow = parsed
+		}
+	}
+	cfg.RequestDedup.Window = dedupWindow
+
+	// Load client identification configuration
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			cfg.ClientIdentity.TrustedProxies = append(cfg.ClientIdentity.TrustedProxies, proxy)
+		}
+	}
+	cfg.ClientIdentity.Header = strings.TrimSpace(os.Getenv("CLIENT_ID_HEADER"))
+
+	// Load rates endpoint configuration
+	cfg.Rates.DefaultBase = "USD" // default
+	if baseStr := strings.TrimSpace(os.Getenv("DEFAULT_BASE_CURRENCY")); baseStr != "" {
+		cfg.Rates.DefaultBase = baseStr
+	}
+	cfg.Rates.MaxBasesPerRequest = 10 // default
+	if maxStr := os.Getenv("MAX_BASES_PER_REQUEST"); maxStr != "" {
+		if parsed, err := strconv.Atoi(maxStr); err == nil && parsed > 0 {
+			cfg.Rates.MaxBasesPerRequest = parsed
+		}
+	}
+	if minStr := os.Getenv("MIN_RATES_THRESHOLD"); minStr != "" {
+		if parsed, err := strconv.Atoi(minStr); err == nil && parsed >= 0 {
+			cfg.Rates.MinRates = parsed
+		}
+	}
+	for _, pair := range strings.Split(os.Getenv("DENIED_PAIRS"), ",") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			cfg.Rates.DeniedPairs = append(cfg.Rates.DeniedPairs, pair)
+		}
+	}
+	for _, entry := range strings.Split(os.Getenv("CURRENCY_ALIASES"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		alias, canonical, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid configuration: CURRENCY_ALIASES entry %q must be ALIAS:CODE", entry)
+		}
+		if cfg.Rates.CurrencyAliases == nil {
+			cfg.Rates.CurrencyAliases = make(map[string]string)
+		}
+		cfg.Rates.CurrencyAliases[strings.TrimSpace(alias)] = strings.TrimSpace(canonical)
+	}
+
+	// Load health endpoint configuration
+	cfg.Health.RequireCache = os.Getenv("READINESS_REQUIRE_CACHE") == "true"
+
+	// Load authentication configuration
+	skipPaths, ok := os.LookupEnv("AUTH_SKIP_PATHS")
+	if !ok {
+		skipPaths = "/health,/version" // default
+	}
+	for _, path := range strings.Split(skipPaths, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid configuration: AUTH_SKIP_PATHS entry %q must start with /", path)
+		}
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		cfg.Auth.SkipPaths = append(cfg.Auth.SkipPaths, path)
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -116,18 +554,49 @@ func LoadConfig() (*Config, error) {
 // - DynamoDB.TableName
 //
 // Optional validations:
-// - Cache TTL must be positive
+// - AWS region (if set) must look like an AWS region name, unless DISABLE_REGION_VALIDATION is set
+// - Cache TTL must be positive and within CACHE_MIN_TTL and CACHE_MAX_TTL (if set)
 // - Secrets Manager secret name must be set if enabled
+// - Rate bounds (if set) must be positive, finite, and ordered
+// - Default base currency (if set) must be a valid currency code
 func (c *Config) Validate() error {
 	// Validate required fields
 	if c.DynamoDB.TableName == "" {
 		return fmt.Errorf("TABLE_NAME is required")
 	}
 
+	// Validate AWS region format (empty means the SDK default is used)
+	if c.DynamoDB.Region != "" && !c.DynamoDB.DisableRegionValidation && !awsRegionPattern.MatchString(c.DynamoDB.Region) {
+		return fmt.Errorf("invalid AWS_REGION %q: must look like an AWS region name such as us-east-1", c.DynamoDB.Region)
+	}
+
 	// Validate cache TTL
 	if c.Cache.TTL <= 0 {
 		return fmt.Errorf("CACHE_TTL must be positive")
 	}
+	if c.Cache.MinTTL > 0 && c.Cache.MaxTTL > 0 && c.Cache.MinTTL > c.Cache.MaxTTL {
+		return fmt.Errorf("CACHE_MIN_TTL (%s) must not exceed CACHE_MAX_TTL (%s)", c.Cache.MinTTL, c.Cache.MaxTTL)
+	}
+	if c.Cache.MinTTL > 0 && c.Cache.TTL < c.Cache.MinTTL {
+		return fmt.Errorf("CACHE_TTL (%s) is below the minimum of %s (CACHE_MIN_TTL)", c.Cache.TTL, c.Cache.MinTTL)
+	}
+	if c.Cache.MaxTTL > 0 && c.Cache.TTL > c.Cache.MaxTTL {
+		return fmt.Errorf("CACHE_TTL (%s) exceeds the maximum of %s (CACHE_MAX_TTL)", c.Cache.TTL, c.Cache.MaxTTL)
+	}
+
+	// Validate rate bounds (zero value means provider defaults are used)
+	if c.RateBounds != (entity.RateBounds{}) {
+		if err := c.RateBounds.Validate(); err != nil {
+			return fmt.Errorf("invalid MIN_RATE/MAX_RATE: %w", err)
+		}
+	}
+
+	// Validate default base currency (empty means the handler default is used)
+	if c.Rates.DefaultBase != "" {
+		if _, err := entity.NewCurrencyCode(c.Rates.DefaultBase); err != nil {
+			return fmt.Errorf("invalid DEFAULT_BASE_CURRENCY: %w", err)
+		}
+	}
 
 	// Validate Secrets Manager configuration
 	if c.SecretsManager.Enabled {
@@ -170,3 +639,23 @@ func (c *Config) GetAPIKey(ctx context.Context, sm SecretsManager) (string, erro
 	// Fallback to environment variable
 	return os.Getenv("EXCHANGE_RATE_API_KEY"), nil
 }
+
+// GetAPIKeys retrieves every API key accepted by our service with its rate limit tier.
+//
+// If Secrets Manager is enabled and sm lists several keys (APIKeyLister), those
+// keys are returned. Otherwise the single key from GetAPIKey is returned without
+// a tier. Returns no keys if no key is configured.
+func (c *Config) GetAPIKeys(ctx context.Context, sm SecretsManager) ([]APIKeyEntry, error) {
+	if lister, ok := sm.(APIKeyLister); ok && c.SecretsManager.Enabled {
+		if keys, err := lister.GetAPIKeys(ctx); err == nil && len(keys) > 0 {
+			return keys, nil
+		}
+		// If Secrets Manager fails, fall through to the single key
+	}
+
+	apiKey, err := c.GetAPIKey(ctx, sm)
+	if err != nil || apiKey == "" {
+		return nil, err
+	}
+	return []APIKeyEntry{{Key: apiKey}}, nil
+}
@@ -12,18 +12,69 @@ func TestLoadConfig(t *testing.T) {
 	// Save original environment
 	originalEnv := make(map[string]string)
 	envVars := []string{
+		"CONFIG_FILE",
 		"TABLE_NAME",
 		"AWS_REGION",
+		"DISABLE_REGION_VALIDATION",
+		"DYNAMODB_CONSISTENT_READ",
+		"AUTO_CREATE_TABLE",
+		"ENSURE_TTL",
+		"RESPONSE_SOURCE_HEADER",
+		"RESPONSE_FRESHNESS_SLA_HEADER",
+		"DEBUG_ERRORS",
+		"RESPONSE_AVAILABLE_TARGETS",
+		"DISABLE_PRETTY_JSON",
+		"RESPONSE_TIMESTAMP_PRECISION",
 		"CACHE_TTL",
+		"CACHE_MIN_TTL",
+		"CACHE_MAX_TTL",
+		"EXPIRED_CLEANUP_GRACE",
+		"CACHE_WARM_ON_FETCH",
+		"SAVE_CONCURRENCY",
+		"EMBEDDED_FALLBACK_ENABLED",
+		"FETCH_LOCK_ENABLED",
+		"FETCH_LOCK_TTL",
+		"FETCH_LOCK_WAIT",
+		"CACHE_REFRESH_AHEAD",
+		"CACHE_REFRESH_AHEAD_JITTER",
+		"NO_CACHE_PAIRS",
+		"STALE_RETENTION",
+		"FALLBACK_STRATEGY",
+		"FALLBACK_MAX_STALE",
+		"ABSOLUTE_MAX_AGE",
 		"EXCHANGE_RATE_API_URL",
 		"EXCHANGE_RATE_API_TIMEOUT",
 		"EXCHANGE_RATE_API_RETRY_ATTEMPTS",
 		"CIRCUIT_BREAKER_FAILURE_THRESHOLD",
 		"CIRCUIT_BREAKER_COOLDOWN_SECONDS",
 		"CIRCUIT_BREAKER_SUCCESS_THRESHOLD",
+		"CIRCUIT_BREAKER_PER_BASE",
+		"CIRCUIT_BREAKER_PER_PAIR",
+		"CIRCUIT_BREAKER_IDLE_TTL",
+		"CIRCUIT_BREAKER_EMPTY_RATES_FAILURE",
 		"SECRETS_MANAGER_SECRET_NAME",
 		"SECRETS_MANAGER_CACHE_TTL",
+		"SECRETS_MANAGER_TIMEOUT",
+		"SECRETS_MANAGER_FAILURE_BACKOFF",
+		"SECRET_PLAINTEXT",
+		"SECRET_KEY_FIELD",
 		"SECRETS_MANAGER_ENABLED",
+		"MIN_RATE",
+		"MAX_RATE",
+		"MAX_RATE_DELTA",
+		"REJECT_ANOMALOUS_RATES",
+		"ANOMALY_CONFIRMATIONS",
+		"ANOMALY_MAX_CACHE_AGE",
+		"REQUEST_DEDUP_WINDOW",
+		"TRUSTED_PROXIES",
+		"CLIENT_ID_HEADER",
+		"DEFAULT_BASE_CURRENCY",
+		"MAX_BASES_PER_REQUEST",
+		"MIN_RATES_THRESHOLD",
+		"DENIED_PAIRS",
+		"CURRENCY_ALIASES",
+		"READINESS_REQUIRE_CACHE",
+		"AUTH_SKIP_PATHS",
 	}
 	for _, key := range envVars {
 		originalEnv[key] = os.Getenv(key)
@@ -59,9 +110,36 @@ func TestLoadConfig(t *testing.T) {
 				if cfg.Cache.TTL != 1*time.Hour {
 					t.Errorf("expected default Cache.TTL = 1h, got %v", cfg.Cache.TTL)
 				}
+				if cfg.Cache.StaleRetention != 24*time.Hour {
+					t.Errorf("expected default Cache.StaleRetention = 24h, got %v", cfg.Cache.StaleRetention)
+				}
+				if cfg.SecretsManager.Timeout != 3*time.Second {
+					t.Errorf("expected default SecretsManager.Timeout = 3s, got %v", cfg.SecretsManager.Timeout)
+				}
+				if cfg.SecretsManager.FailureBackoff != 5*time.Second {
+					t.Errorf("expected default SecretsManager.FailureBackoff = 5s, got %v", cfg.SecretsManager.FailureBackoff)
+				}
+				if cfg.SecretsManager.Plaintext {
+					t.Error("expected default SecretsManager.Plaintext = false")
+				}
+				if cfg.SecretsManager.KeyField != "api-key" {
+					t.Errorf("expected default SecretsManager.KeyField = api-key, got %q", cfg.SecretsManager.KeyField)
+				}
+				if cfg.Cache.MinTTL != 10*time.Second || cfg.Cache.MaxTTL != 24*time.Hour {
+					t.Errorf("expected default Cache.MinTTL/MaxTTL = 10s/24h, got %v/%v", cfg.Cache.MinTTL, cfg.Cache.MaxTTL)
+				}
 				if cfg.API.BaseURL == "" {
 					t.Error("expected default API.BaseURL to be set")
 				}
+				if cfg.RequestDedup.Window != 0 {
+					t.Errorf("expected default RequestDedup.Window = 0 (disabled), got %v", cfg.RequestDedup.Window)
+				}
+				if cfg.Rates.DefaultBase != "USD" {
+					t.Errorf("expected default Rates.DefaultBase = 'USD', got %q", cfg.Rates.DefaultBase)
+				}
+				if cfg.Health.RequireCache {
+					t.Error("expected default Health.RequireCache = false")
+				}
 			},
 		},
 		{
@@ -138,6 +216,29 @@ func TestLoadConfig(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "CACHE_TTL below default minimum",
+			envVars: map[string]string{
+				"TABLE_NAME": "TestTable",
+				"CACHE_TTL":  "1s",
+			},
+			wantErr: true,
+		},
+		{
+			name: "CACHE_TTL allowed by custom bounds",
+			envVars: map[string]string{
+				"TABLE_NAME":    "TestTable",
+				"CACHE_TTL":     "48h",
+				"CACHE_MIN_TTL": "1m",
+				"CACHE_MAX_TTL": "0s",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.MinTTL != time.Minute || cfg.Cache.MaxTTL != 0 {
+					t.Errorf("expected Cache.MinTTL/MaxTTL = 1m/0, got %v/%v", cfg.Cache.MinTTL, cfg.Cache.MaxTTL)
+				}
+			},
+		},
 		{
 			name: "Secrets Manager enabled without secret name",
 			envVars: map[string]string{
@@ -146,6 +247,582 @@ func TestLoadConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "custom rate bounds",
+			envVars: map[string]string{
+				"TABLE_NAME": "TestTable",
+				"MIN_RATE":   "0.0001",
+				"MAX_RATE":   "100000",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.RateBounds.Min != 0.0001 {
+					t.Errorf("expected RateBounds.Min = 0.0001, got %g", cfg.RateBounds.Min)
+				}
+				if cfg.RateBounds.Max != 100000 {
+					t.Errorf("expected RateBounds.Max = 100000, got %g", cfg.RateBounds.Max)
+				}
+			},
+		},
+		{
+			name: "anomaly detection configuration",
+			envVars: map[string]string{
+				"TABLE_NAME":             "TestTable",
+				"MAX_RATE_DELTA":         "0.25",
+				"REJECT_ANOMALOUS_RATES": "true",
+				"ANOMALY_CONFIRMATIONS":  "5",
+				"ANOMALY_MAX_CACHE_AGE":  "6h",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Anomaly.MaxRateDelta != 0.25 {
+					t.Errorf("expected Anomaly.MaxRateDelta = 0.25, got %g", cfg.Anomaly.MaxRateDelta)
+				}
+				if !cfg.Anomaly.Reject {
+					t.Error("expected Anomaly.Reject = true")
+				}
+				if cfg.Anomaly.Confirmations != 5 {
+					t.Errorf("expected Anomaly.Confirmations = 5, got %d", cfg.Anomaly.Confirmations)
+				}
+				if cfg.Anomaly.MaxCacheAge != 6*time.Hour {
+					t.Errorf("expected Anomaly.MaxCacheAge = 6h, got %v", cfg.Anomaly.MaxCacheAge)
+				}
+			},
+		},
+		{
+			name: "inverted rate bounds",
+			envVars: map[string]string{
+				"TABLE_NAME": "TestTable",
+				"MIN_RATE":   "10",
+				"MAX_RATE":   "1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "consistent reads",
+			envVars: map[string]string{
+				"TABLE_NAME":               "TestTable",
+				"DYNAMODB_CONSISTENT_READ": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.DynamoDB.ConsistentRead {
+					t.Error("expected DynamoDB.ConsistentRead = true")
+				}
+			},
+		},
+		{
+			name: "auto create table",
+			envVars: map[string]string{
+				"TABLE_NAME":        "test-table",
+				"AUTO_CREATE_TABLE": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.DynamoDB.AutoCreateTable {
+					t.Error("expected DynamoDB.AutoCreateTable = true")
+				}
+				if cfg.DynamoDB.EnsureTTL {
+					t.Error("expected DynamoDB.EnsureTTL = false by default")
+				}
+			},
+		},
+		{
+			name: "ensure TTL",
+			envVars: map[string]string{
+				"TABLE_NAME": "test-table",
+				"ENSURE_TTL": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.DynamoDB.EnsureTTL {
+					t.Error("expected DynamoDB.EnsureTTL = true")
+				}
+			},
+		},
+		{
+			name: "per-base circuit breakers",
+			envVars: map[string]string{
+				"TABLE_NAME":               "TestTable",
+				"CIRCUIT_BREAKER_PER_BASE": "true",
+				"CIRCUIT_BREAKER_IDLE_TTL": "15m",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.CircuitBreakerScope.PerBase {
+					t.Error("expected CircuitBreakerScope.PerBase = true")
+				}
+				if cfg.CircuitBreakerScope.IdleTTL != 15*time.Minute {
+					t.Errorf("expected CircuitBreakerScope.IdleTTL = 15m, got %v", cfg.CircuitBreakerScope.IdleTTL)
+				}
+			},
+		},
+		{
+			name: "per-pair circuit breakers",
+			envVars: map[string]string{
+				"TABLE_NAME":               "TestTable",
+				"CIRCUIT_BREAKER_PER_PAIR": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.CircuitBreakerScope.PerPair {
+					t.Error("expected CircuitBreakerScope.PerPair = true")
+				}
+				if cfg.CircuitBreakerScope.PerBase {
+					t.Error("expected CircuitBreakerScope.PerBase = false")
+				}
+			},
+		},
+		{
+			name: "empty rates count as breaker failure",
+			envVars: map[string]string{
+				"TABLE_NAME":                          "TestTable",
+				"CIRCUIT_BREAKER_EMPTY_RATES_FAILURE": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.CircuitBreakerScope.EmptyRatesFailure {
+					t.Error("expected CircuitBreakerScope.EmptyRatesFailure = true")
+				}
+			},
+		},
+		{
+			name: "expired cleanup grace",
+			envVars: map[string]string{
+				"TABLE_NAME":            "test-table",
+				"EXPIRED_CLEANUP_GRACE": "24h",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.ExpiredCleanupGrace != 24*time.Hour {
+					t.Errorf("expected Cache.ExpiredCleanupGrace = 24h, got %v", cfg.Cache.ExpiredCleanupGrace)
+				}
+			},
+		},
+		{
+			name: "cache warm on fetch",
+			envVars: map[string]string{
+				"TABLE_NAME":          "test-table",
+				"CACHE_WARM_ON_FETCH": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Cache.WarmOnFetch {
+					t.Error("expected Cache.WarmOnFetch = true")
+				}
+			},
+		},
+		{
+			name: "embedded fallback",
+			envVars: map[string]string{
+				"TABLE_NAME":                "test-table",
+				"EMBEDDED_FALLBACK_ENABLED": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Cache.EmbeddedFallback {
+					t.Error("expected Cache.EmbeddedFallback = true")
+				}
+			},
+		},
+		{
+			name: "save concurrency",
+			envVars: map[string]string{
+				"TABLE_NAME":       "test-table",
+				"SAVE_CONCURRENCY": "4",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.SaveConcurrency != 4 {
+					t.Errorf("expected Cache.SaveConcurrency = 4, got %d", cfg.Cache.SaveConcurrency)
+				}
+			},
+		},
+		{
+			name: "invalid save concurrency uses default",
+			envVars: map[string]string{
+				"TABLE_NAME":       "test-table",
+				"SAVE_CONCURRENCY": "0",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.SaveConcurrency != 10 {
+					t.Errorf("expected Cache.SaveConcurrency = 10, got %d", cfg.Cache.SaveConcurrency)
+				}
+			},
+		},
+		{
+			name: "fetch lock",
+			envVars: map[string]string{
+				"TABLE_NAME":         "test-table",
+				"FETCH_LOCK_ENABLED": "true",
+				"FETCH_LOCK_TTL":     "30s",
+				"FETCH_LOCK_WAIT":    "500ms",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Cache.FetchLock {
+					t.Error("expected Cache.FetchLock = true")
+				}
+				if cfg.Cache.FetchLockTTL != 30*time.Second {
+					t.Errorf("expected Cache.FetchLockTTL = 30s, got %v", cfg.Cache.FetchLockTTL)
+				}
+				if cfg.Cache.FetchLockWait != 500*time.Millisecond {
+					t.Errorf("expected Cache.FetchLockWait = 500ms, got %v", cfg.Cache.FetchLockWait)
+				}
+			},
+		},
+		{
+			name: "fetch lock defaults",
+			envVars: map[string]string{
+				"TABLE_NAME":     "test-table",
+				"FETCH_LOCK_TTL": "-1s",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.FetchLock {
+					t.Error("expected Cache.FetchLock = false")
+				}
+				if cfg.Cache.FetchLockTTL != 10*time.Second {
+					t.Errorf("expected Cache.FetchLockTTL = 10s, got %v", cfg.Cache.FetchLockTTL)
+				}
+				if cfg.Cache.FetchLockWait != 2*time.Second {
+					t.Errorf("expected Cache.FetchLockWait = 2s, got %v", cfg.Cache.FetchLockWait)
+				}
+			},
+		},
+		{
+			name: "refresh ahead",
+			envVars: map[string]string{
+				"TABLE_NAME":                 "test-table",
+				"CACHE_REFRESH_AHEAD":        "0.2",
+				"CACHE_REFRESH_AHEAD_JITTER": "0",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.RefreshAhead != 0.2 {
+					t.Errorf("expected Cache.RefreshAhead = 0.2, got %v", cfg.Cache.RefreshAhead)
+				}
+				if cfg.Cache.RefreshAheadJitter != 0 {
+					t.Errorf("expected Cache.RefreshAheadJitter = 0, got %v", cfg.Cache.RefreshAheadJitter)
+				}
+			},
+		},
+		{
+			name: "invalid refresh ahead uses defaults",
+			envVars: map[string]string{
+				"TABLE_NAME":                 "test-table",
+				"CACHE_REFRESH_AHEAD":        "1.5",
+				"CACHE_REFRESH_AHEAD_JITTER": "-0.1",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.RefreshAhead != 0 {
+					t.Errorf("expected Cache.RefreshAhead = 0, got %v", cfg.Cache.RefreshAhead)
+				}
+				if cfg.Cache.RefreshAheadJitter != 0.25 {
+					t.Errorf("expected Cache.RefreshAheadJitter = 0.25, got %v", cfg.Cache.RefreshAheadJitter)
+				}
+			},
+		},
+		{
+			name: "stale retention",
+			envVars: map[string]string{
+				"TABLE_NAME":      "test-table",
+				"STALE_RETENTION": "168h",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.StaleRetention != 168*time.Hour {
+					t.Errorf("expected Cache.StaleRetention = 168h, got %v", cfg.Cache.StaleRetention)
+				}
+			},
+		},
+		{
+			name: "fallback strategy",
+			envVars: map[string]string{
+				"TABLE_NAME":         "test-table",
+				"FALLBACK_STRATEGY":  "stale-ok",
+				"FALLBACK_MAX_STALE": "2h",
+				"ABSOLUTE_MAX_AGE":   "24h",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Cache.FallbackStrategy != "stale-ok" {
+					t.Errorf("expected Cache.FallbackStrategy = stale-ok, got %q", cfg.Cache.FallbackStrategy)
+				}
+				if cfg.Cache.FallbackMaxStale != 2*time.Hour {
+					t.Errorf("expected Cache.FallbackMaxStale = 2h, got %v", cfg.Cache.FallbackMaxStale)
+				}
+				if cfg.Cache.AbsoluteMaxAge != 24*time.Hour {
+					t.Errorf("expected Cache.AbsoluteMaxAge = 24h, got %v", cfg.Cache.AbsoluteMaxAge)
+				}
+			},
+		},
+		{
+			name: "default base currency",
+			envVars: map[string]string{
+				"TABLE_NAME":            "TestTable",
+				"DEFAULT_BASE_CURRENCY": "eur",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Rates.DefaultBase != "eur" {
+					t.Errorf("expected Rates.DefaultBase = 'eur', got %q", cfg.Rates.DefaultBase)
+				}
+			},
+		},
+		{
+			name: "max bases per request",
+			envVars: map[string]string{
+				"TABLE_NAME":            "TestTable",
+				"MAX_BASES_PER_REQUEST": "5",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Rates.MaxBasesPerRequest != 5 {
+					t.Errorf("expected Rates.MaxBasesPerRequest = 5, got %d", cfg.Rates.MaxBasesPerRequest)
+				}
+			},
+		},
+		{
+			name: "min rates threshold",
+			envVars: map[string]string{
+				"TABLE_NAME":          "TestTable",
+				"MIN_RATES_THRESHOLD": "100",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Rates.MinRates != 100 {
+					t.Errorf("expected Rates.MinRates = 100, got %d", cfg.Rates.MinRates)
+				}
+			},
+		},
+		{
+			name: "currency aliases",
+			envVars: map[string]string{
+				"TABLE_NAME":       "TestTable",
+				"CURRENCY_ALIASES": "XBT:BTC, DEM : EUR,",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if len(cfg.Rates.CurrencyAliases) != 2 {
+					t.Fatalf("expected 2 Rates.CurrencyAliases, got %v", cfg.Rates.CurrencyAliases)
+				}
+				if cfg.Rates.CurrencyAliases["XBT"] != "BTC" || cfg.Rates.CurrencyAliases["DEM"] != "EUR" {
+					t.Errorf("expected Rates.CurrencyAliases = map[DEM:EUR XBT:BTC], got %v", cfg.Rates.CurrencyAliases)
+				}
+			},
+		},
+		{
+			name: "malformed currency alias",
+			envVars: map[string]string{
+				"TABLE_NAME":       "TestTable",
+				"CURRENCY_ALIASES": "XBT=BTC",
+			},
+			wantErr: true,
+		},
+		{
+			name: "denied pairs",
+			envVars: map[string]string{
+				"TABLE_NAME":   "TestTable",
+				"DENIED_PAIRS": "USD/RUB, */KPW,,",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				want := []string{"USD/RUB", "*/KPW"}
+				if len(cfg.Rates.DeniedPairs) != len(want) {
+					t.Fatalf("expected Rates.DeniedPairs = %v, got %v", want, cfg.Rates.DeniedPairs)
+				}
+				for i := range want {
+					if cfg.Rates.DeniedPairs[i] != want[i] {
+						t.Errorf("expected Rates.DeniedPairs[%d] = %q, got %q", i, want[i], cfg.Rates.DeniedPairs[i])
+					}
+				}
+			},
+		},
+		{
+			name: "no-cache pairs",
+			envVars: map[string]string{
+				"TABLE_NAME":     "TestTable",
+				"NO_CACHE_PAIRS": "BTC/USD, eth/usd,,",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				want := []string{"BTC/USD", "eth/usd"}
+				if len(cfg.Cache.NoCachePairs) != len(want) {
+					t.Fatalf("expected Cache.NoCachePairs = %v, got %v", want, cfg.Cache.NoCachePairs)
+				}
+				for i := range want {
+					if cfg.Cache.NoCachePairs[i] != want[i] {
+						t.Errorf("expected Cache.NoCachePairs[%d] = %q, got %q", i, want[i], cfg.Cache.NoCachePairs[i])
+					}
+				}
+			},
+		},
+		{
+			name: "readiness requires cache",
+			envVars: map[string]string{
+				"TABLE_NAME":              "TestTable",
+				"READINESS_REQUIRE_CACHE": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Health.RequireCache {
+					t.Error("expected Health.RequireCache = true")
+				}
+			},
+		},
+		{
+			name: "invalid default base currency",
+			envVars: map[string]string{
+				"TABLE_NAME":            "TestTable",
+				"DEFAULT_BASE_CURRENCY": "DOLLARS",
+			},
+			wantErr: true,
+		},
+		{
+			name: "response source header",
+			envVars: map[string]string{
+				"TABLE_NAME":             "TestTable",
+				"RESPONSE_SOURCE_HEADER": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Response.SourceHeader {
+					t.Error("expected Response.SourceHeader = true")
+				}
+			},
+		},
+		{
+			name: "response freshness SLA header",
+			envVars: map[string]string{
+				"TABLE_NAME":                    "TestTable",
+				"RESPONSE_FRESHNESS_SLA_HEADER": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Response.FreshnessSLA {
+					t.Error("expected Response.FreshnessSLA = true")
+				}
+			},
+		},
+		{
+			name: "debug errors",
+			envVars: map[string]string{
+				"TABLE_NAME":   "TestTable",
+				"DEBUG_ERRORS": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Response.DebugErrors {
+					t.Error("expected Response.DebugErrors = true")
+				}
+			},
+		},
+		{
+			name: "available targets in errors",
+			envVars: map[string]string{
+				"TABLE_NAME":                 "TestTable",
+				"RESPONSE_AVAILABLE_TARGETS": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Response.AvailableTargets {
+					t.Error("expected Response.AvailableTargets = true")
+				}
+			},
+		},
+		{
+			name: "disable pretty JSON",
+			envVars: map[string]string{
+				"TABLE_NAME":          "TestTable",
+				"DISABLE_PRETTY_JSON": "true",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if !cfg.Response.DisablePretty {
+					t.Error("expected Response.DisablePretty = true")
+				}
+			},
+		},
+		{
+			name: "response timestamp precision",
+			envVars: map[string]string{
+				"TABLE_NAME":                   "TestTable",
+				"RESPONSE_TIMESTAMP_PRECISION": "1s",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Response.TimestampPrecision != time.Second {
+					t.Errorf("expected Response.TimestampPrecision = 1s, got %v", cfg.Response.TimestampPrecision)
+				}
+			},
+		},
+		{
+			name: "request dedup window",
+			envVars: map[string]string{
+				"TABLE_NAME":           "TestTable",
+				"REQUEST_DEDUP_WINDOW": "30s",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.RequestDedup.Window != 30*time.Second {
+					t.Errorf("expected RequestDedup.Window = 30s, got %v", cfg.RequestDedup.Window)
+				}
+			},
+		},
+		{
+			name: "client identity",
+			envVars: map[string]string{
+				"TABLE_NAME":       "TestTable",
+				"TRUSTED_PROXIES":  "10.0.0.0/8, 192.0.2.1,",
+				"CLIENT_ID_HEADER": "X-Client-ID",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				proxies := cfg.ClientIdentity.TrustedProxies
+				if len(proxies) != 2 || proxies[0] != "10.0.0.0/8" || proxies[1] != "192.0.2.1" {
+					t.Errorf("expected ClientIdentity.TrustedProxies = [10.0.0.0/8 192.0.2.1], got %v", proxies)
+				}
+				if cfg.ClientIdentity.Header != "X-Client-ID" {
+					t.Errorf("expected ClientIdentity.Header = 'X-Client-ID', got %q", cfg.ClientIdentity.Header)
+				}
+			},
+		},
+		{
+			name: "auth skip paths default",
+			envVars: map[string]string{
+				"TABLE_NAME": "TestTable",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if got := cfg.Auth.SkipPaths; len(got) != 2 || got[0] != "/health" || got[1] != "/version" {
+					t.Errorf("expected Auth.SkipPaths = [/health /version], got %v", got)
+				}
+			},
+		},
+		{
+			name: "auth skip paths",
+			envVars: map[string]string{
+				"TABLE_NAME":      "TestTable",
+				"AUTH_SKIP_PATHS": " /health/ ,,/status",
+			},
+			wantErr: false,
+			validateFn: func(t *testing.T, cfg *Config) {
+				if got := cfg.Auth.SkipPaths; len(got) != 2 || got[0] != "/health" || got[1] != "/status" {
+					t.Errorf("expected Auth.SkipPaths = [/health /status], got %v", got)
+				}
+			},
+		},
+		{
+			name: "auth skip paths relative",
+			envVars: map[string]string{
+				"TABLE_NAME":      "TestTable",
+				"AUTH_SKIP_PATHS": "health",
+			},
+			wantErr: true,
+		},
 		{
 			name: "Secrets Manager enabled with secret name",
 			envVars: map[string]string{
@@ -236,6 +913,128 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid AWS region",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+					Region:    "ap-southeast-2",
+				},
+				Cache: CacheConfig{
+					TTL: 1 * time.Hour,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid GovCloud AWS region",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+					Region:    "us-gov-west-1",
+				},
+				Cache: CacheConfig{
+					TTL: 1 * time.Hour,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AWS region missing dash",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+					Region:    "us-east1",
+				},
+				Cache: CacheConfig{
+					TTL: 1 * time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AWS region uppercase",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+					Region:    "US-EAST-1",
+				},
+				Cache: CacheConfig{
+					TTL: 1 * time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed AWS region with validation disabled",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName:               "TestTable",
+					Region:                  "localhost",
+					DisableRegionValidation: true,
+				},
+				Cache: CacheConfig{
+					TTL: 1 * time.Hour,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cache TTL below minimum",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+				},
+				Cache: CacheConfig{
+					TTL:    time.Second,
+					MinTTL: 10 * time.Second,
+					MaxTTL: 24 * time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cache TTL above maximum",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+				},
+				Cache: CacheConfig{
+					TTL:    48 * time.Hour,
+					MinTTL: 10 * time.Second,
+					MaxTTL: 24 * time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cache TTL within bounds",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+				},
+				Cache: CacheConfig{
+					TTL:    10 * time.Second,
+					MinTTL: 10 * time.Second,
+					MaxTTL: 24 * time.Hour,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cache TTL minimum above maximum",
+			config: &Config{
+				DynamoDB: DynamoDBConfig{
+					TableName: "TestTable",
+				},
+				Cache: CacheConfig{
+					TTL:    time.Hour,
+					MinTTL: 2 * time.Hour,
+					MaxTTL: time.Hour,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Secrets Manager enabled without secret name",
 			config: &Config{
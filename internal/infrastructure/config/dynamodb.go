@@ -3,7 +3,10 @@ package config
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
@@ -16,6 +19,11 @@ import (
 //   - AWS credentials file (~/.aws/credentials)
 //   - AWS config file (~/.aws/config)
 //
+// The endpoint can be overridden to run the same binary against DynamoDB Local
+// or LocalStack (see DynamoDBEndpoint):
+//   - DYNAMODB_ENDPOINT: DynamoDB endpoint URL, e.g. "http://localhost:8000"
+//   - AWS_ENDPOINT_URL: Endpoint URL of every AWS service, used if DYNAMODB_ENDPOINT is not set
+//
 // The client is safe for concurrent use by multiple goroutines.
 //
 // Example usage:
@@ -26,6 +34,39 @@ import (
 //	    log.Fatalf("failed to create DynamoDB client: %v", err)
 //	}
 func NewDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
+	cfg, err := LoadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create DynamoDB client from configuration
+	return dynamodb.NewFromConfig(cfg, withEndpoint(DynamoDBEndpoint())), nil
+}
+
+// DynamoDBEndpoint returns the DynamoDB endpoint override from DYNAMODB_ENDPOINT,
+// or AWS_ENDPOINT_URL if it is not set. Returns "" for the default AWS endpoint.
+func DynamoDBEndpoint() string {
+	if endpoint := strings.TrimSpace(os.Getenv("DYNAMODB_ENDPOINT")); endpoint != "" {
+		return endpoint
+	}
+	return strings.TrimSpace(os.Getenv("AWS_ENDPOINT_URL"))
+}
+
+// withEndpoint returns a client option that sends requests to endpoint.
+// An empty endpoint keeps the endpoint resolved from the AWS config.
+func withEndpoint(endpoint string) func(*dynamodb.Options) {
+	return func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	}
+}
+
+// LoadAWSConfig loads the shared AWS SDK configuration.
+//
+// This is the same configuration used for the DynamoDB client, so other AWS
+// integrations (e.g. SNS event publishing) use identical credentials and region.
+func LoadAWSConfig(ctx context.Context) (aws.Config, error) {
 	// Load default AWS configuration
 	// This automatically handles credentials from:
 	// 1. Environment variables
@@ -33,9 +74,7 @@ func NewDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
 	// 3. IAM role (when running on AWS)
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
 	}
-
-	// Create DynamoDB client from configuration
-	return dynamodb.NewFromConfig(cfg), nil
+	return cfg, nil
 }
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestNewDynamoDBClient_EndpointOverride(t *testing.T) {
+	tests := []struct {
+		name             string
+		dynamoDBEndpoint string
+		awsEndpointURL   string
+		want             string
+	}{
+		{"no override", "", "", ""},
+		{"DYNAMODB_ENDPOINT", "http://localhost:8000", "", "http://localhost:8000"},
+		{"AWS_ENDPOINT_URL", "", "http://localhost:4566", "http://localhost:4566"},
+		{"DYNAMODB_ENDPOINT takes precedence", "http://localhost:8000", "http://localhost:4566", "http://localhost:8000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", "us-east-1")
+			t.Setenv("DYNAMODB_ENDPOINT", tt.dynamoDBEndpoint)
+			t.Setenv("AWS_ENDPOINT_URL", tt.awsEndpointURL)
+
+			if got := DynamoDBEndpoint(); got != tt.want {
+				t.Errorf("DynamoDBEndpoint() = %q, want %q", got, tt.want)
+			}
+
+			client, err := NewDynamoDBClient(context.Background())
+			if err != nil {
+				t.Fatalf("NewDynamoDBClient() error = %v", err)
+			}
+			if got := aws.ToString(client.Options().BaseEndpoint); got != tt.want {
+				t.Errorf("client BaseEndpoint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
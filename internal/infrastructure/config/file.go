@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfigFromFile loads configuration from a JSON or YAML file, with
+// environment variables overriding file values.
+//
+// The file is a flat map of the environment variable names documented on
+// LoadConfig to scalar values, e.g. in YAML:
+//
+//	TABLE_NAME: ExchangeRates
+//	CACHE_TTL: 30m
+//	CIRCUIT_BREAKER_PER_BASE: true
+//
+// Files ending in .json are parsed as JSON; any other file as YAML (a superset
+// of JSON). Each value is applied as an environment variable unless that
+// variable is already set to a non-empty value, so every Load* function sees
+// the merged settings. The merged configuration is validated like LoadConfig's.
+//
+// Intended for local development; production keeps using environment variables.
+//
+// Returns an error if the file cannot be read or parsed, or the merged
+// configuration is invalid.
+func LoadConfigFromFile(path string) (*Config, error) {
+	if err := applyConfigFile(path); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return LoadConfig()
+}
+
+// configFileEnv holds the environment variables applyConfigFile set, with
+// their values, so a reload can replace them.
+var (
+	configFileMu  sync.Mutex
+	configFileEnv = make(map[string]string)
+)
+
+// applyConfigFile reads the config file at path and sets each of its values
+// as an environment variable that is not already set.
+//
+// Variables set by an earlier call are not treated as set: a reload replaces
+// their values, and unsets those no longer in the file.
+func applyConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	values, err := parseConfigFile(path, data)
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	configFileMu.Lock()
+	defer configFileMu.Unlock()
+	for key, value := range values {
+		current := os.Getenv(key)
+		if previous, ok := configFileEnv[key]; current != "" && (!ok || current != previous) {
+			continue // set in the environment, which overrides the file
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to apply %s from config file: %w", key, err)
+		}
+		configFileEnv[key] = value
+	}
+	for key, previous := range configFileEnv {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if os.Getenv(key) == previous {
+			os.Unsetenv(key)
+		}
+		delete(configFileEnv, key)
+	}
+	return nil
+}
+
+// parseConfigFile parses a config file into environment variable values.
+// Values must be scalars (strings, numbers, or booleans).
+func parseConfigFile(path string, data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber() // keep numbers as written, e.g. "0.1" rather than a float
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+	} else if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case bool, int, float64, json.Number:
+			values[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("%s: value must be a string, number, or boolean", key)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes content to a file named name in a temp directory and
+// registers env cleanup for the keys the file may set.
+func writeConfigFile(t *testing.T, name, content string, keys ...string) string {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFromFile_YAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+TABLE_NAME: FileTable
+CACHE_TTL: 30m
+CACHE_REFRESH_AHEAD: 0.2
+CIRCUIT_BREAKER_PER_BASE: true
+`, "TABLE_NAME", "CACHE_TTL", "CACHE_REFRESH_AHEAD", "CIRCUIT_BREAKER_PER_BASE")
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile() error = %v", err)
+	}
+	if cfg.DynamoDB.TableName != "FileTable" {
+		t.Errorf("TableName = %q, want FileTable", cfg.DynamoDB.TableName)
+	}
+	if cfg.Cache.TTL != 30*time.Minute {
+		t.Errorf("Cache.TTL = %v, want 30m", cfg.Cache.TTL)
+	}
+	if cfg.Cache.RefreshAhead != 0.2 {
+		t.Errorf("Cache.RefreshAhead = %g, want 0.2", cfg.Cache.RefreshAhead)
+	}
+	if !cfg.CircuitBreakerScope.PerBase {
+		t.Error("CircuitBreakerScope.PerBase = false, want true")
+	}
+}
+
+func TestLoadConfigFromFile_EnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"TABLE_NAME": "FileTable", "CACHE_TTL": "30m", "SAVE_CONCURRENCY": 4}`,
+		"SAVE_CONCURRENCY")
+	t.Setenv("TABLE_NAME", "EnvTable")
+	t.Setenv("CACHE_TTL", "")
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile() error = %v", err)
+	}
+	if cfg.DynamoDB.TableName != "EnvTable" {
+		t.Errorf("TableName = %q, want EnvTable from the environment", cfg.DynamoDB.TableName)
+	}
+	if cfg.Cache.TTL != 30*time.Minute {
+		t.Errorf("Cache.TTL = %v, want 30m from the file", cfg.Cache.TTL)
+	}
+	if cfg.Cache.SaveConcurrency != 4 {
+		t.Errorf("Cache.SaveConcurrency = %d, want 4 from the file", cfg.Cache.SaveConcurrency)
+	}
+}
+
+func TestLoadConfig_ConfigFileEnv(t *testing.T) {
+	path := writeConfigFile(t, "config.yml", "TABLE_NAME: FileTable\n", "TABLE_NAME")
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.DynamoDB.TableName != "FileTable" {
+		t.Errorf("TableName = %q, want FileTable", cfg.DynamoDB.TableName)
+	}
+}
+
+func TestLoadConfigFromFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"invalid JSON", "config.json", `{"TABLE_NAME": `},
+		{"invalid YAML", "config.yaml", "TABLE_NAME: [unclosed"},
+		{"non-scalar value", "config.yaml", "TABLE_NAME: FileTable\nDENIED_PAIRS:\n  - USD/RUB\n"},
+		{"merged config invalid", "config.yaml", "TABLE_NAME: FileTable\nCACHE_TTL: 1s\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content, "TABLE_NAME", "CACHE_TTL", "DENIED_PAIRS")
+			if _, err := LoadConfigFromFile(path); err == nil {
+				t.Error("LoadConfigFromFile() error = nil, want error")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("TABLE_NAME", "EnvTable")
+		if _, err := LoadConfigFromFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("LoadConfigFromFile() error = nil, want error")
+		}
+	})
+}
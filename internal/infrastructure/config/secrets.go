@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +21,81 @@ type SecretsManager interface {
 	GetAPIKey(ctx context.Context) (string, error)
 }
 
+// APIKeyEntry is an API key accepted by the service with its rate limit tier.
+type APIKeyEntry struct {
+	Key  string `json:"key"`
+	Tier string `json:"tier,omitempty"` // Rate limit tier, e.g. "free" or "pro" (empty uses the default limits)
+}
+
+// APIKeyLister is implemented by secrets managers whose secret can hold several API keys.
+type APIKeyLister interface {
+	// GetAPIKeys retrieves every API key in the secret with its tier.
+	// The primary key ("api-key") is first.
+	GetAPIKeys(ctx context.Context) ([]APIKeyEntry, error)
+}
+
+// DefaultSecretKeyField is the default JSON field of the primary API key in the secret.
+const DefaultSecretKeyField = "api-key"
+
+// apiKeySecret is the JSON shape of the API key secret, besides the primary
+// API key, which is read from the configured key field (see WithKeyField).
+type apiKeySecret struct {
+	Tier string        `json:"tier"` // Tier of the primary key (optional)
+	Keys []APIKeyEntry `json:"keys"` // Additional API keys (optional)
+}
+
+// parseAPIKeySecret parses the API key secret into its keys, primary key first.
+// The primary key is read from keyField (DefaultSecretKeyField if empty).
+// Additional entries with an empty key are skipped.
+//
+// With plaintext set, the whole secret (trimmed) is the API key. Otherwise a
+// secret that is not a JSON object and contains no whitespace is also taken
+// as a plaintext key, so a key stored without the JSON wrapper still works.
+func parseAPIKeySecret(secretString, keyField string, plaintext bool) ([]APIKeyEntry, error) {
+	trimmed := strings.TrimSpace(secretString)
+	if plaintext || isPlaintextSecret(trimmed) {
+		if trimmed == "" {
+			return nil, fmt.Errorf("plaintext secret is empty")
+		}
+		return []APIKeyEntry{{Key: trimmed}}, nil
+	}
+
+	if keyField == "" {
+		keyField = DefaultSecretKeyField
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(secretString), &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse secret JSON: %w", err)
+	}
+	var primary string
+	if raw, ok := fields[keyField]; ok {
+		if err := json.Unmarshal(raw, &primary); err != nil {
+			return nil, fmt.Errorf("secret field '%s' must be a string", keyField)
+		}
+	}
+	if primary == "" {
+		return nil, fmt.Errorf("secret does not contain a non-empty '%s' field", keyField)
+	}
+	var secret apiKeySecret
+	if err := json.Unmarshal([]byte(secretString), &secret); err != nil {
+		return nil, fmt.Errorf("failed to parse secret JSON: %w", err)
+	}
+
+	keys := []APIKeyEntry{{Key: primary, Tier: secret.Tier}}
+	for _, entry := range secret.Keys {
+		if entry.Key != "" {
+			keys = append(keys, entry)
+		}
+	}
+	return keys, nil
+}
+
+// isPlaintextSecret reports whether a trimmed secret looks like a bare API key
+// rather than (possibly malformed) JSON.
+func isPlaintextSecret(trimmed string) bool {
+	return trimmed != "" && !strings.HasPrefix(trimmed, "{") && !strings.ContainsAny(trimmed, " \t\r\n")
+}
+
 // cachedSecret holds a cached secret value with expiration time.
 type cachedSecret struct {
 	value     string
@@ -52,13 +128,34 @@ func (c *cachedSecret) set(value string, ttl time.Duration) {
 	c.expiresAt = time.Now().Add(ttl)
 }
 
+// DefaultSecretsCallTimeout is the default timeout of a Secrets Manager call.
+const DefaultSecretsCallTimeout = 3 * time.Second
+
+// DefaultSecretsFailureBackoff is how long a failed secret fetch is returned
+// again by default before Secrets Manager is called again.
+const DefaultSecretsFailureBackoff = 5 * time.Second
+
+// secretsClient is the subset of the Secrets Manager client used by AWSSecretsManager.
+// *secretsmanager.Client satisfies this interface; tests can provide a mock.
+type secretsClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
 // AWSSecretsManager implements SecretsManager using AWS Secrets Manager.
 type AWSSecretsManager struct {
-	client     *secretsmanager.Client
-	secretName string
-	cacheTTL   time.Duration
-	cache      *cachedSecret
-	mu         sync.RWMutex
+	client      secretsClient
+	secretName  string
+	cacheTTL    time.Duration
+	callTimeout time.Duration // Timeout of a Secrets Manager call (see WithCallTimeout)
+	keyField    string        // JSON field of the primary API key (see WithKeyField)
+	plaintext   bool          // Whether the secret is the bare API key (see WithPlaintextSecret)
+	cache       *cachedSecret
+	mu          sync.RWMutex
+
+	failureBackoff time.Duration // How long a failed fetch is returned again (see WithFailureBackoff)
+	failure        error         // Error of the last failed fetch, guarded by mu
+	retryAt        time.Time     // When Secrets Manager may be called again after failure, guarded by mu
+	now            func() time.Time
 }
 
 // NewAWSSecretsManager creates a new AWS Secrets Manager client.
@@ -96,12 +193,7 @@ func NewAWSSecretsManager(ctx context.Context, secretName string, cacheTTL time.
 
 	client := secretsmanager.NewFromConfig(cfg)
 
-	return &AWSSecretsManager{
-		client:     client,
-		secretName: secretName,
-		cacheTTL:   cacheTTL,
-		cache:      &cachedSecret{},
-	}, nil
+	return newAWSSecretsManager(client, secretName, cacheTTL), nil
 }
 
 // NewAWSSecretsManagerWithClient creates a new AWS Secrets Manager with a provided client.
@@ -121,64 +213,189 @@ func NewAWSSecretsManagerWithClient(client *secretsmanager.Client, secretName st
 		cacheTTL = 5 * time.Minute // default
 	}
 
+	return newAWSSecretsManager(client, secretName, cacheTTL), nil
+}
+
+// newAWSSecretsManager creates an AWSSecretsManager over any secretsClient,
+// with the default call timeout and failure backoff.
+func newAWSSecretsManager(client secretsClient, secretName string, cacheTTL time.Duration) *AWSSecretsManager {
 	return &AWSSecretsManager{
-		client:     client,
-		secretName: secretName,
-		cacheTTL:   cacheTTL,
-		cache:      &cachedSecret{},
-	}, nil
+		client:         client,
+		secretName:     secretName,
+		cacheTTL:       cacheTTL,
+		callTimeout:    DefaultSecretsCallTimeout,
+		keyField:       DefaultSecretKeyField,
+		cache:          &cachedSecret{},
+		failureBackoff: DefaultSecretsFailureBackoff,
+		now:            time.Now,
+	}
+}
+
+// WithCallTimeout sets the timeout of each Secrets Manager call, applied on
+// top of the caller's context so a hung call fails fast even if the context
+// has no deadline. A timeout <= 0 keeps DefaultSecretsCallTimeout.
+// It returns s for chaining and must be called before s is used.
+func (s *AWSSecretsManager) WithCallTimeout(timeout time.Duration) *AWSSecretsManager {
+	if timeout > 0 {
+		s.callTimeout = timeout
+	}
+	return s
+}
+
+// WithFailureBackoff sets how long a failed secret fetch is returned again
+// without calling Secrets Manager, so a failing or throttled secret is not
+// hit by every request. A backoff <= 0 disables this and every call retries.
+// It returns s for chaining and must be called before s is used.
+func (s *AWSSecretsManager) WithFailureBackoff(backoff time.Duration) *AWSSecretsManager {
+	s.failureBackoff = max(backoff, 0)
+	return s
+}
+
+// WithKeyField sets the JSON field of the primary API key in the secret, for
+// secrets that store it under a custom name, e.g. {"currency-api-key": "..."}.
+// An empty field keeps DefaultSecretKeyField.
+// It returns s for chaining and must be called before s is used.
+func (s *AWSSecretsManager) WithKeyField(field string) *AWSSecretsManager {
+	if field != "" {
+		s.keyField = field
+	}
+	return s
+}
+
+// WithPlaintextSecret sets whether the secret string is the API key itself
+// rather than a JSON object, e.g. for a secret created with
+// "aws secretsmanager create-secret --secret-string my-key". Without it, a
+// secret that is not JSON and contains no whitespace is still read as a
+// plaintext key (see GetAPIKeys).
+// It returns s for chaining and must be called before s is used.
+func (s *AWSSecretsManager) WithPlaintextSecret(enabled bool) *AWSSecretsManager {
+	s.plaintext = enabled
+	return s
 }
 
-// GetAPIKey retrieves the API key from AWS Secrets Manager.
+// GetAPIKey retrieves the primary API key from AWS Secrets Manager.
 //
-// The secret is expected to be a JSON object with an "api-key" field:
+// The secret is expected to be a JSON object with an "api-key" field (or the
+// field set with WithKeyField):
 // {"api-key": "your-api-key-here"}
-//
-// The secret is cached for the configured TTL to reduce API calls.
-// If the cache is expired or missing, the secret is fetched from Secrets Manager.
+// or the bare API key. See GetAPIKeys for the optional tier and additional keys.
 //
 // Security: This method never logs the API key value.
 func (s *AWSSecretsManager) GetAPIKey(ctx context.Context) (string, error) {
+	keys, err := s.GetAPIKeys(ctx)
+	if err != nil {
+		return "", err
+	}
+	return keys[0].Key, nil
+}
+
+// GetAPIKeys retrieves every API key from AWS Secrets Manager with its rate limit tier.
+//
+// Besides the required "api-key" (see WithKeyField), the secret may set the primary key's "tier"
+// and list additional keys:
+//
+//	{"api-key": "primary-key", "tier": "pro", "keys": [{"key": "other-key", "tier": "free"}]}
+//
+// A plaintext secret is a single key with the default tier. It is read as
+// plaintext when WithPlaintextSecret is set, or when it is not a JSON object
+// and contains no whitespace; anything else must be valid JSON. The secret is
+// read from SecretString, or from SecretBinary if it has no string value.
+//
+// The secret is cached for the configured TTL to reduce API calls.
+// If the cache is expired or missing, the secret is fetched from Secrets Manager,
+// bounded by the call timeout (see WithCallTimeout). A failed fetch is returned
+// again for the failure backoff before Secrets Manager is retried
+// (see WithFailureBackoff).
+//
+// Security: This method never logs API key values.
+func (s *AWSSecretsManager) GetAPIKeys(ctx context.Context) ([]APIKeyEntry, error) {
 	// Check cache first
 	if value, ok := s.cache.get(); ok {
-		return value, nil
+		return parseAPIKeySecret(value, s.keyField, s.plaintext)
+	}
+
+	// Return a recent failure instead of calling Secrets Manager again
+	if err := s.recentFailure(); err != nil {
+		return nil, err
 	}
 
 	// Fetch from Secrets Manager
+	keys, secretString, err := s.fetch(ctx)
+	if err != nil {
+		// A cancelled request says nothing about Secrets Manager
+		if ctx.Err() == nil {
+			s.recordFailure(err)
+		}
+		return nil, err
+	}
+
+	// Cache the secret
+	s.cache.set(secretString, s.cacheTTL)
+
+	return keys, nil
+}
+
+// fetch gets and parses the secret from Secrets Manager, bounded by the call timeout.
+func (s *AWSSecretsManager) fetch(ctx context.Context) ([]APIKeyEntry, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.callTimeout)
+	defer cancel()
 	result, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(s.secretName),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get secret from Secrets Manager: %w", err)
+		return nil, "", fmt.Errorf("failed to get secret from Secrets Manager: %w", err)
 	}
 
-	// Parse JSON secret
-	var secretData map[string]string
-	if err := json.Unmarshal([]byte(*result.SecretString), &secretData); err != nil {
-		return "", fmt.Errorf("failed to parse secret JSON: %w", err)
+	secretString, err := secretValue(result)
+	if err != nil {
+		return nil, "", err
+	}
+	keys, err := parseAPIKeySecret(secretString, s.keyField, s.plaintext)
+	if err != nil {
+		return nil, "", err
 	}
+	return keys, secretString, nil
+}
 
-	// Extract API key
-	apiKey, ok := secretData["api-key"]
-	if !ok {
-		return "", fmt.Errorf("secret does not contain 'api-key' field")
+// secretValue returns the secret's SecretString, or its SecretBinary (already
+// base64-decoded by the SDK) if the secret was stored as binary.
+func secretValue(result *secretsmanager.GetSecretValueOutput) (string, error) {
+	if result.SecretString != nil {
+		return *result.SecretString, nil
 	}
-	if apiKey == "" {
-		return "", fmt.Errorf("secret 'api-key' field is empty")
+	if len(result.SecretBinary) > 0 {
+		return string(result.SecretBinary), nil
 	}
+	return "", fmt.Errorf("secret has neither a string nor a binary value")
+}
 
-	// Cache the secret
-	s.cache.set(apiKey, s.cacheTTL)
+// recentFailure returns the error of the last failed fetch if it happened
+// within the failure backoff, or nil if Secrets Manager may be called.
+func (s *AWSSecretsManager) recentFailure() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.failure == nil || !s.now().Before(s.retryAt) {
+		return nil
+	}
+	return s.failure
+}
 
-	return apiKey, nil
+// recordFailure remembers a failed fetch for the failure backoff.
+func (s *AWSSecretsManager) recordFailure(err error) {
+	if s.failureBackoff <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failure = err
+	s.retryAt = s.now().Add(s.failureBackoff)
 }
 
-// InvalidateCache clears the cached secret, forcing a fresh fetch on next call.
-// This is useful when secrets are rotated.
+// InvalidateCache clears the cached secret and any recent failure, forcing a
+// fresh fetch on next call. This is useful when secrets are rotated.
 func (s *AWSSecretsManager) InvalidateCache() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.cache = &cachedSecret{}
+	s.failure = nil
 }
-
-
@@ -3,9 +3,12 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 )
@@ -107,6 +110,71 @@ func TestAWSSecretsManager_GetAPIKey_Logic(t *testing.T) {
 	})
 }
 
+func TestParseAPIKeySecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		plaintext bool
+		want      []APIKeyEntry
+		wantErr   bool
+	}{
+		{"primary key only", `{"api-key": "test-key"}`, false, []APIKeyEntry{{Key: "test-key"}}, false},
+		{
+			name:   "tiers and additional keys",
+			secret: `{"api-key": "pro-key", "tier": "pro", "keys": [{"key": "free-key", "tier": "free"}, {"key": ""}]}`,
+			want:   []APIKeyEntry{{Key: "pro-key", Tier: "pro"}, {Key: "free-key", Tier: "free"}},
+		},
+		{"missing api-key", `{"other-field": "value"}`, false, nil, true},
+		{"empty api-key", `{"api-key": ""}`, false, nil, true},
+		{"invalid JSON", `invalid json`, false, nil, true},
+		{"malformed JSON", `{"api-key": "test-key"`, false, nil, true},
+		{"plaintext fallback", "plain-key\n", false, []APIKeyEntry{{Key: "plain-key"}}, false},
+		{"plaintext flag", ` {"api-key": "test-key"} `, true, []APIKeyEntry{{Key: `{"api-key": "test-key"}`}}, false},
+		{"empty plaintext", "  ", true, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAPIKeySecret(tt.secret, "", tt.plaintext)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAPIKeySecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAPIKeySecret() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mockKeyLister lists several API keys.
+type mockKeyLister struct {
+	keys []APIKeyEntry
+}
+
+func (m *mockKeyLister) GetAPIKey(ctx context.Context) (string, error) {
+	return m.keys[0].Key, nil
+}
+
+func (m *mockKeyLister) GetAPIKeys(ctx context.Context) ([]APIKeyEntry, error) {
+	return m.keys, nil
+}
+
+func TestConfig_GetAPIKeys(t *testing.T) {
+	t.Setenv("EXCHANGE_RATE_API_KEY", "env-key")
+	lister := &mockKeyLister{keys: []APIKeyEntry{{Key: "pro-key", Tier: "pro"}, {Key: "free-key", Tier: "free"}}}
+
+	enabled := &Config{SecretsManager: SecretsManagerConfig{Enabled: true}}
+	if got, err := enabled.GetAPIKeys(context.Background(), lister); err != nil || !reflect.DeepEqual(got, lister.keys) {
+		t.Errorf("GetAPIKeys() = %v, %v; want %v", got, err, lister.keys)
+	}
+
+	disabled := &Config{}
+	want := []APIKeyEntry{{Key: "env-key"}}
+	if got, err := disabled.GetAPIKeys(context.Background(), lister); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAPIKeys() without Secrets Manager = %v, %v; want %v", got, err, want)
+	}
+}
+
 // Note: Caching and invalidation tests require AWS credentials or a more sophisticated mock.
 // These would be better suited for integration tests.
 // The caching logic is tested in TestCachedSecret below.
@@ -144,3 +212,185 @@ func createSecretJSON(apiKey string) string {
 	jsonBytes, _ := json.Marshal(secret)
 	return string(jsonBytes)
 }
+
+// blockingSecretsClient blocks every call until its context is done.
+type blockingSecretsClient struct{}
+
+func (blockingSecretsClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestAWSSecretsManager_CallTimeout(t *testing.T) {
+	t.Setenv("EXCHANGE_RATE_API_KEY", "env-key")
+	sm := newAWSSecretsManager(blockingSecretsClient{}, "my-secret", time.Minute).WithCallTimeout(50 * time.Millisecond)
+
+	// The caller's context has no deadline
+	started := time.Now()
+	if _, err := sm.GetAPIKey(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetAPIKey() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("GetAPIKey() took %v, want about the 50ms call timeout", elapsed)
+	}
+
+	cfg := &Config{SecretsManager: SecretsManagerConfig{Enabled: true}}
+	key, err := cfg.GetAPIKey(context.Background(), sm)
+	if err != nil {
+		t.Fatalf("Config.GetAPIKey() error = %v", err)
+	}
+	if key != "env-key" {
+		t.Errorf("Config.GetAPIKey() = %q, want the EXCHANGE_RATE_API_KEY fallback", key)
+	}
+}
+
+func TestAWSSecretsManager_WithCallTimeoutDefault(t *testing.T) {
+	sm := newAWSSecretsManager(blockingSecretsClient{}, "my-secret", time.Minute).WithCallTimeout(0)
+	if sm.callTimeout != DefaultSecretsCallTimeout {
+		t.Errorf("callTimeout = %v, want %v", sm.callTimeout, DefaultSecretsCallTimeout)
+	}
+}
+
+// countingSecretsClient counts calls and returns err, or secret (a valid JSON
+// secret if empty) if err is nil. With binary set, secret is returned as SecretBinary.
+type countingSecretsClient struct {
+	calls  int
+	err    error
+	secret string
+	binary bool
+}
+
+func (c *countingSecretsClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.binary {
+		return &secretsmanager.GetSecretValueOutput{SecretBinary: []byte(c.secret)}, nil
+	}
+	if c.secret != "" {
+		return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(c.secret)}, nil
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"api-key": "secret-key"}`)}, nil
+}
+
+func TestAWSSecretsManager_FailureBackoff(t *testing.T) {
+	client := &countingSecretsClient{err: errors.New("throttled")}
+	sm := newAWSSecretsManager(client, "my-secret", time.Minute).WithFailureBackoff(5 * time.Second)
+	now := time.Now()
+	sm.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := sm.GetAPIKey(ctx); err == nil {
+			t.Fatalf("GetAPIKey() call %d error = nil, want error", i+1)
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("client calls within the backoff = %d, want 1", client.calls)
+	}
+
+	// After the backoff the client is called again, and a success is cached
+	client.err = nil
+	now = now.Add(5 * time.Second)
+	key, err := sm.GetAPIKey(ctx)
+	if err != nil || key != "secret-key" {
+		t.Fatalf("GetAPIKey() after the backoff = %q, %v, want secret-key", key, err)
+	}
+	if _, err := sm.GetAPIKey(ctx); err != nil {
+		t.Fatalf("GetAPIKey() error = %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("client calls = %d, want 2", client.calls)
+	}
+}
+
+func TestAWSSecretsManager_FailureBackoffDisabled(t *testing.T) {
+	client := &countingSecretsClient{err: errors.New("throttled")}
+	sm := newAWSSecretsManager(client, "my-secret", time.Minute).WithFailureBackoff(0)
+
+	for i := 0; i < 3; i++ {
+		_, _ = sm.GetAPIKey(context.Background())
+	}
+	if client.calls != 3 {
+		t.Errorf("client calls = %d, want 3", client.calls)
+	}
+}
+
+func TestAWSSecretsManager_InvalidateCacheClearsFailure(t *testing.T) {
+	client := &countingSecretsClient{err: errors.New("throttled")}
+	sm := newAWSSecretsManager(client, "my-secret", time.Minute)
+
+	_, _ = sm.GetAPIKey(context.Background())
+	sm.InvalidateCache()
+	_, _ = sm.GetAPIKey(context.Background())
+	if client.calls != 2 {
+		t.Errorf("client calls = %d, want 2 after InvalidateCache", client.calls)
+	}
+}
+
+func TestAWSSecretsManager_PlaintextSecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		plaintext bool
+		want      string
+		wantErr   bool
+	}{
+		{"JSON-wrapped", `{"api-key": "json-key"}`, false, "json-key", false},
+		{"plaintext", "plain-key", false, "plain-key", false},
+		{"plaintext with flag", "plain key", true, "plain key", false},
+		{"malformed JSON", `{"api-key": `, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &countingSecretsClient{secret: tt.secret}
+			sm := newAWSSecretsManager(client, "my-secret", time.Minute).WithPlaintextSecret(tt.plaintext)
+
+			// The second call is served from the cache
+			for range 2 {
+				got, err := sm.GetAPIKey(context.Background())
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GetAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("GetAPIKey() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAWSSecretsManager_KeyFieldAndBinary(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		binary   bool
+		keyField string
+		want     string
+		wantErr  bool
+	}{
+		{"custom field", `{"currency-key": "custom-key", "api-key": "other"}`, false, "currency-key", "custom-key", false},
+		{"missing custom field", `{"api-key": "test-key"}`, false, "currency-key", "", true},
+		{"non-string custom field", `{"currency-key": 42}`, false, "currency-key", "", true},
+		{"binary JSON secret", `{"api-key": "binary-key"}`, true, "", "binary-key", false},
+		{"binary plaintext secret", "binary-key", true, "", "binary-key", false},
+		{"empty binary secret", "", true, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &countingSecretsClient{secret: tt.secret, binary: tt.binary}
+			sm := newAWSSecretsManager(client, "my-secret", time.Minute).WithKeyField(tt.keyField)
+
+			got, err := sm.GetAPIKey(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetAPIKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// configFilePollInterval is how often WatchConfig checks CONFIG_FILE for changes.
+const configFilePollInterval = 5 * time.Second
+
+// WatchConfig reloads the configuration for long-running (non-Lambda)
+// deployments and passes each reloaded configuration to onChange.
+//
+// This function:
+// - Reloads on SIGHUP
+// - When CONFIG_FILE is set, also reloads when the file changes (checked every 5 seconds)
+// - Loads and validates the configuration like LoadConfig
+// - Passes invalid configurations to onError (if not nil) instead of onChange,
+// so the previous configuration stays in effect
+//
+// Environment variables of a running process do not change, so a SIGHUP picks
+// up changes only through CONFIG_FILE. WatchConfig blocks until ctx is done;
+// onChange and onError are called from its goroutine.
+func WatchConfig(ctx context.Context, onChange func(*Config), onError func(error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	watchConfig(ctx, signals, configFilePollInterval, onChange, onError)
+}
+
+// watchConfig implements WatchConfig, reloading on every value received from
+// reload and, when CONFIG_FILE is set, on file changes seen every pollInterval.
+func watchConfig(ctx context.Context, reload <-chan os.Signal, pollInterval time.Duration, onChange func(*Config), onError func(error)) {
+	path := os.Getenv("CONFIG_FILE")
+	var poll <-chan time.Time
+	var version fileVersion
+	if path != "" {
+		version = statConfigFile(path)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reload:
+		case <-poll:
+			current := statConfigFile(path)
+			if current == version {
+				continue
+			}
+			version = current
+		}
+
+		cfg, err := LoadConfig()
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			continue
+		}
+		onChange(cfg)
+	}
+}
+
+// fileVersion identifies the content of a file by its modification time and size.
+type fileVersion struct {
+	modTime int64 // Unix nanoseconds
+	size    int64
+}
+
+// statConfigFile returns the version of the file at path, or the zero
+// version if it cannot be read.
+func statConfigFile(path string) fileVersion {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileVersion{}
+	}
+	return fileVersion{modTime: info.ModTime().UnixNano(), size: info.Size()}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// startWatch runs watchConfig until the test ends and returns the channels
+// its reloaded configurations and errors are sent to, and its reload trigger.
+func startWatch(t *testing.T, pollInterval time.Duration) (chan os.Signal, chan *Config, chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	reload := make(chan os.Signal, 1)
+	changes := make(chan *Config, 1)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchConfig(ctx, reload, pollInterval, func(cfg *Config) { changes <- cfg }, func(err error) { errs <- err })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return reload, changes, errs
+}
+
+func waitForConfig(t *testing.T, changes chan *Config) *Config {
+	t.Helper()
+	select {
+	case cfg := <-changes:
+		return cfg
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a config reload")
+		return nil
+	}
+}
+
+func TestWatchConfig_ReloadOnSignal(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "TABLE_NAME: FileTable\nCACHE_TTL: 30m\n", "TABLE_NAME", "CACHE_TTL")
+	t.Setenv("CONFIG_FILE", path)
+	if _, err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	// The file is not polled within the test, so only the signal reloads it
+	reload, changes, _ := startWatch(t, time.Hour)
+	if err := os.WriteFile(path, []byte("TABLE_NAME: FileTable\nCACHE_TTL: 2h\n"), 0o600); err != nil {
+		t.Fatalf("failed to update config file: %v", err)
+	}
+	reload <- os.Interrupt
+
+	if cfg := waitForConfig(t, changes); cfg.Cache.TTL != 2*time.Hour {
+		t.Errorf("reloaded Cache.TTL = %v, want 2h", cfg.Cache.TTL)
+	}
+}
+
+func TestWatchConfig_ReloadOnFileChange(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "TABLE_NAME: FileTable\nCACHE_TTL: 30m\n", "TABLE_NAME", "CACHE_TTL")
+	t.Setenv("CONFIG_FILE", path)
+	if _, err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	reload, changes, errs := startWatch(t, 10*time.Millisecond)
+	// Wait until the watcher runs, so it has seen the original file
+	reload <- os.Interrupt
+	waitForConfig(t, changes)
+
+	// An invalid file is reported and not applied
+	if err := os.WriteFile(path, []byte("TABLE_NAME: FileTable\nCACHE_TTL: 1s\n"), 0o600); err != nil {
+		t.Fatalf("failed to update config file: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("onError called with nil")
+		}
+	case cfg := <-changes:
+		t.Fatalf("onChange called with invalid Cache.TTL = %v", cfg.Cache.TTL)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the invalid reload")
+	}
+
+	if err := os.WriteFile(path, []byte("TABLE_NAME: FileTable\nCACHE_TTL: 45m\n"), 0o600); err != nil {
+		t.Fatalf("failed to update config file: %v", err)
+	}
+	if cfg := waitForConfig(t, changes); cfg.Cache.TTL != 45*time.Minute {
+		t.Errorf("reloaded Cache.TTL = %v, want 45m", cfg.Cache.TTL)
+	}
+}
+
+func TestWatchConfig_EnvOverridesReloadedFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "TABLE_NAME: FileTable\nCACHE_TTL: 30m\n", "TABLE_NAME")
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("CACHE_TTL", "20m")
+
+	reload, changes, _ := startWatch(t, time.Hour)
+	if err := os.WriteFile(path, []byte("TABLE_NAME: FileTable\nCACHE_TTL: 2h\n"), 0o600); err != nil {
+		t.Fatalf("failed to update config file: %v", err)
+	}
+	reload <- os.Interrupt
+
+	if cfg := waitForConfig(t, changes); cfg.Cache.TTL != 20*time.Minute {
+		t.Errorf("reloaded Cache.TTL = %v, want 20m from the environment", cfg.Cache.TTL)
+	}
+}
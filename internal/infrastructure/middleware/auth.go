@@ -2,13 +2,16 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/subtle"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
 )
 
 // ErrUnauthorized is returned when API key authentication fails.
@@ -53,33 +56,41 @@ func NewAPIKeyAuthenticator(secretsManager config.SecretsManager, cfg *config.Co
 // 1. X-API-Key header
 // 2. Authorization header (Bearer token format)
 //
+// Keys are trimmed of surrounding whitespace. A key that is empty after trimming
+// (e.g. an X-API-Key of spaces, or "Bearer " with no token) counts as absent,
+// so a request with no usable key gets ErrAPIKeyMissing rather than ErrUnauthorized.
+//
 // Returns the API key or an error if not found.
 func ExtractAPIKey(event events.APIGatewayProxyRequest) (string, error) {
 	// Try X-API-Key header first
-	if apiKey := event.Headers["X-API-Key"]; apiKey != "" {
-		return strings.TrimSpace(apiKey), nil
+	if apiKey := strings.TrimSpace(event.Headers["X-API-Key"]); apiKey != "" {
+		return apiKey, nil
 	}
-	if apiKey := event.Headers["x-api-key"]; apiKey != "" {
-		return strings.TrimSpace(apiKey), nil
+	if apiKey := strings.TrimSpace(event.Headers["x-api-key"]); apiKey != "" {
+		return apiKey, nil
 	}
 
 	// Try Authorization header (Bearer token)
-	if authHeader := event.Headers["Authorization"]; authHeader != "" {
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
-			return strings.TrimSpace(parts[1]), nil
-		}
+	if apiKey := bearerToken(event.Headers["Authorization"]); apiKey != "" {
+		return apiKey, nil
 	}
-	if authHeader := event.Headers["authorization"]; authHeader != "" {
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
-			return strings.TrimSpace(parts[1]), nil
-		}
+	if apiKey := bearerToken(event.Headers["authorization"]); apiKey != "" {
+		return apiKey, nil
 	}
 
 	return "", ErrAPIKeyMissing
 }
 
+// bearerToken returns the trimmed token of a "Bearer <token>" Authorization
+// header, or "" if the header is not a Bearer header or has no token.
+func bearerToken(authHeader string) string {
+	parts := strings.SplitN(strings.TrimSpace(authHeader), " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
+
 // ValidateAPIKey validates an API key against the stored secret.
 //
 // Security: Uses constant-time comparison to prevent timing attacks.
@@ -93,25 +104,103 @@ func (a *APIKeyAuthenticator) ValidateAPIKey(ctx context.Context, providedKey st
 		return ErrAPIKeyMissing
 	}
 
-	// Get valid API key from Secrets Manager or environment
-	validKey, err := a.config.GetAPIKey(ctx, a.secretsManager)
+	// Get valid API keys from Secrets Manager or environment
+	validKeys, err := a.config.GetAPIKeys(ctx, a.secretsManager)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve API key: %w", err)
 	}
 
-	if validKey == "" {
+	if len(validKeys) == 0 {
 		// No API key configured - allow request (for development)
 		return nil
 	}
 
-	// Constant-time comparison to prevent timing attacks
-	if subtle.ConstantTimeCompare([]byte(providedKey), []byte(validKey)) != 1 {
+	if _, ok := matchAPIKey(providedKey, validKeys); !ok {
 		return ErrUnauthorized
 	}
 
 	return nil
 }
 
+// matchAPIKey returns the entry of validKeys matching providedKey.
+//
+// Security: providedKey is compared against every key in constant time, on
+// SHA-256 digests so neither the matching entry nor key lengths leak through timing.
+func matchAPIKey(providedKey string, validKeys []config.APIKeyEntry) (config.APIKeyEntry, bool) {
+	provided := sha256.Sum256([]byte(providedKey))
+	var matched config.APIKeyEntry
+	found := 0
+	for _, entry := range validKeys {
+		valid := sha256.Sum256([]byte(entry.Key))
+		if subtle.ConstantTimeCompare(provided[:], valid[:]) == 1 && entry.Key != "" {
+			matched = entry
+			found = 1
+		}
+	}
+	return matched, found == 1
+}
+
+// VerifyAPIKey reports whether providedKey matches a configured API key.
+//
+// Unlike ValidateAPIKey, it only returns true for a real match: it returns false
+// when authentication is disabled, no key is configured, or the key cannot be
+// retrieved. Use it to grant privileges to a caller, e.g. a higher rate limit.
+// A nil authenticator verifies no key.
+func (a *APIKeyAuthenticator) VerifyAPIKey(ctx context.Context, providedKey string) bool {
+	_, ok := a.VerifiedTier(ctx, providedKey)
+	return ok
+}
+
+// VerifiedTier verifies providedKey like VerifyAPIKey and returns the rate limit
+// tier assigned to it in the secret ("" if the key has no tier).
+// Returns false if the key is not verified.
+func (a *APIKeyAuthenticator) VerifiedTier(ctx context.Context, providedKey string) (string, bool) {
+	if a == nil || !a.enabled || providedKey == "" {
+		return "", false
+	}
+
+	validKeys, err := a.config.GetAPIKeys(ctx, a.secretsManager)
+	if err != nil || len(validKeys) == 0 {
+		return "", false
+	}
+
+	entry, ok := matchAPIKey(providedKey, validKeys)
+	return entry.Tier, ok
+}
+
+// WithAuthentication authenticates a request with authenticator before running fn.
+//
+// This function:
+// - Runs fn without authentication if path is in skipPaths (e.g. /health)
+// - Returns a 401 response if the API key is missing or invalid
+// - Returns the error response of other failures (e.g. the key cannot be retrieved)
+//
+// path is the normalized request path the router matches on, so that a skip
+// entry cannot be bypassed with extra or trailing slashes.
+// If authenticator is nil (authentication disabled), fn is executed directly.
+func WithAuthentication(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+	path string,
+	authenticator *APIKeyAuthenticator,
+	skipPaths []string,
+	log *logger.Logger,
+	fn func() events.APIGatewayProxyResponse,
+) events.APIGatewayProxyResponse {
+	if authenticator == nil || slices.Contains(skipPaths, path) {
+		return fn()
+	}
+
+	if err := authenticator.AuthenticateRequest(ctx, event); err != nil {
+		if log == nil {
+			log = logger.NewFromEnv()
+		}
+		log.LogError(ctx, err, "authentication failed", "path", path)
+		return ErrorResponse(err)
+	}
+	return fn()
+}
+
 // AuthenticateRequest authenticates an API Gateway request using API key.
 //
 // This function:
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -108,6 +109,44 @@ func TestExtractAPIKey(t *testing.T) {
 			expectedKey: "test-key",
 			expectedErr: nil,
 		},
+		{
+			name: "whitespace-only X-API-Key",
+			event: events.APIGatewayProxyRequest{
+				Headers: map[string]string{
+					"X-API-Key": "   ",
+				},
+			},
+			expectedErr: ErrAPIKeyMissing,
+		},
+		{
+			name: "empty Bearer token",
+			event: events.APIGatewayProxyRequest{
+				Headers: map[string]string{
+					"Authorization": "Bearer ",
+				},
+			},
+			expectedErr: ErrAPIKeyMissing,
+		},
+		{
+			name: "whitespace-only Bearer token (lowercase)",
+			event: events.APIGatewayProxyRequest{
+				Headers: map[string]string{
+					"authorization": "Bearer \t ",
+				},
+			},
+			expectedErr: ErrAPIKeyMissing,
+		},
+		{
+			name: "whitespace-only X-API-Key falls back to Bearer",
+			event: events.APIGatewayProxyRequest{
+				Headers: map[string]string{
+					"X-API-Key":     " ",
+					"Authorization": "Bearer bearer-key",
+				},
+			},
+			expectedKey: "bearer-key",
+			expectedErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +273,38 @@ func TestAPIKeyAuthenticator_ValidateAPIKey(t *testing.T) {
 	}
 }
 
+func TestAPIKeyAuthenticator_VerifyAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		providedKey string
+		validKey    string
+		want        bool
+	}{
+		{"matching key", true, "valid-key", "valid-key", true},
+		{"wrong key", true, "other-key", "valid-key", false},
+		{"empty key", true, "", "valid-key", false},
+		{"authentication disabled", false, "valid-key", "valid-key", false},
+		{"no key configured", true, "any-key", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{SecretsManager: config.SecretsManagerConfig{Enabled: true}}
+			authenticator := NewAPIKeyAuthenticator(&mockSecretsManager{apiKey: tt.validKey}, cfg, tt.enabled)
+
+			if got := authenticator.VerifyAPIKey(context.Background(), tt.providedKey); got != tt.want {
+				t.Errorf("VerifyAPIKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var nilAuthenticator *APIKeyAuthenticator
+	if nilAuthenticator.VerifyAPIKey(context.Background(), "valid-key") {
+		t.Error("VerifyAPIKey() on nil authenticator = true, want false")
+	}
+}
+
 func TestAPIKeyAuthenticator_AuthenticateRequest(t *testing.T) {
 	sm := &mockSecretsManager{
 		apiKey: "valid-key",
@@ -277,6 +348,24 @@ func TestAPIKeyAuthenticator_AuthenticateRequest(t *testing.T) {
 			},
 			expectedErr: ErrAPIKeyMissing,
 		},
+		{
+			name: "whitespace-only API key",
+			event: events.APIGatewayProxyRequest{
+				Headers: map[string]string{
+					"X-API-Key": "  ",
+				},
+			},
+			expectedErr: ErrAPIKeyMissing,
+		},
+		{
+			name: "empty Bearer token",
+			event: events.APIGatewayProxyRequest{
+				Headers: map[string]string{
+					"Authorization": "Bearer ",
+				},
+			},
+			expectedErr: ErrAPIKeyMissing,
+		},
 	}
 
 	for _, tt := range tests {
@@ -311,3 +400,42 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestWithAuthentication(t *testing.T) {
+	authenticator := testAuthenticator("valid-key")
+	skipPaths := []string{"/health", "/version"}
+
+	tests := []struct {
+		name          string
+		path          string
+		headers       map[string]string
+		authenticator *APIKeyAuthenticator
+		wantStatus    int
+	}{
+		{"skipped path without key", "/health", nil, authenticator, http.StatusOK},
+		{"protected path without key", "/rates/USD", nil, authenticator, http.StatusUnauthorized},
+		{"protected path with invalid key", "/rates/USD", map[string]string{"X-API-Key": "wrong"}, authenticator, http.StatusUnauthorized},
+		{"protected path with valid key", "/rates/USD", map[string]string{"X-API-Key": "valid-key"}, authenticator, http.StatusOK},
+		{"skip entries match whole paths", "/health/extra", nil, authenticator, http.StatusUnauthorized},
+		{"authentication disabled", "/rates/USD", nil, nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			event := events.APIGatewayProxyRequest{Path: tt.path, Headers: tt.headers}
+
+			resp := WithAuthentication(context.Background(), event, tt.path, tt.authenticator, skipPaths, nil, func() events.APIGatewayProxyResponse {
+				called = true
+				return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+			})
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("handler called = %v, want %v", called, tt.wantStatus == http.StatusOK)
+			}
+		})
+	}
+}
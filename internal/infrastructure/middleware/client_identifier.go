@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ClientIdentitySource identifies which part of the request a client identity was resolved from.
+type ClientIdentitySource string
+
+const (
+	// ClientIdentitySourceAPIKey indicates the identity is the caller's API key.
+	ClientIdentitySourceAPIKey ClientIdentitySource = "api_key"
+
+	// ClientIdentitySourceIP indicates the identity is the caller's IP address.
+	ClientIdentitySourceIP ClientIdentitySource = "ip"
+
+	// ClientIdentitySourceHeader indicates the identity is the configured fallback header.
+	ClientIdentitySourceHeader ClientIdentitySource = "header"
+
+	// ClientIdentitySourceRequestID indicates no client identity was available and
+	// the request ID is used instead.
+	ClientIdentitySourceRequestID ClientIdentitySource = "request"
+)
+
+// ClientIdentity is a stable identifier for the caller of a request.
+type ClientIdentity struct {
+	Source ClientIdentitySource
+	Value  string
+	Tier   string // Rate limit tier of a verified API key (see VerifiedIdentifier)
+}
+
+// Key returns the identity as a rate limiter key, e.g. "ip:203.0.113.7".
+// The source prefix keeps API keys, IPs, and header values from colliding.
+// Returns an empty string if no identity was resolved.
+func (c ClientIdentity) Key() string {
+	if c.Value == "" {
+		return ""
+	}
+	return string(c.Source) + ":" + c.Value
+}
+
+// Authenticated reports whether the identity was resolved from an API key.
+func (c ClientIdentity) Authenticated() bool {
+	return c.Source == ClientIdentitySourceAPIKey && c.Value != ""
+}
+
+// ClientIdentifierConfig holds configuration for client identification.
+type ClientIdentifierConfig struct {
+	// TrustedProxies lists IPs or CIDR ranges of proxies (e.g. CloudFront) whose
+	// X-Forwarded-For entries are trusted. Empty trusts no proxy.
+	TrustedProxies []string
+	// Header is an optional header used when neither an API key nor an IP is available.
+	Header string
+}
+
+// ClientIdentifierResolver resolves a stable client identity from API Gateway events.
+type ClientIdentifierResolver struct {
+	trustedProxies []netip.Prefix
+	header         string
+}
+
+// NewClientIdentifierResolver creates a new ClientIdentifierResolver.
+//
+// Returns an error if a trusted proxy is not a valid IP address or CIDR range.
+func NewClientIdentifierResolver(config ClientIdentifierConfig) (*ClientIdentifierResolver, error) {
+	prefixes := make([]netip.Prefix, 0, len(config.TrustedProxies))
+	for _, proxy := range config.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return &ClientIdentifierResolver{
+		trustedProxies: prefixes,
+		header:         strings.TrimSpace(config.Header),
+	}, nil
+}
+
+// ClientIdentifier resolves the client identity of a request.
+//
+// Precedence:
+// 1. API key (X-API-Key or Bearer token)
+// 2. Client IP: requestContext.identity.sourceIp, or the X-Forwarded-For entry
+// left of the rightmost trusted proxy when sourceIp is a trusted proxy
+// 3. The configured fallback header
+//
+// Returns a zero ClientIdentity if none of these are present. A nil resolver
+// trusts no proxies and has no fallback header.
+func (r *ClientIdentifierResolver) ClientIdentifier(event events.APIGatewayProxyRequest) ClientIdentity {
+	if apiKey, err := ExtractAPIKey(event); err == nil && apiKey != "" {
+		return ClientIdentity{Source: ClientIdentitySourceAPIKey, Value: apiKey}
+	}
+	return r.AnonymousIdentifier(event)
+}
+
+// VerifiedIdentifier resolves the client identity of a request like ClientIdentifier,
+// but only identifies the caller by API key if authenticator verifies it; an
+// unverified key is ignored and the caller is resolved by AnonymousIdentifier.
+// A verified key's identity carries the key's rate limit tier.
+// A nil authenticator verifies no key.
+func (r *ClientIdentifierResolver) VerifiedIdentifier(ctx context.Context, event events.APIGatewayProxyRequest, authenticator *APIKeyAuthenticator) ClientIdentity {
+	identity := r.ClientIdentifier(event)
+	if identity.Authenticated() {
+		tier, ok := authenticator.VerifiedTier(ctx, identity.Value)
+		if !ok {
+			return r.AnonymousIdentifier(event)
+		}
+		identity.Tier = tier
+	}
+	return identity
+}
+
+// AnonymousIdentifier resolves the client identity of a request ignoring any API key,
+// i.e. steps 2 and 3 of ClientIdentifier. Use it when a presented API key has not
+// been verified and must not select the caller's identity.
+func (r *ClientIdentifierResolver) AnonymousIdentifier(event events.APIGatewayProxyRequest) ClientIdentity {
+	if ip := r.clientIP(event); ip.IsValid() {
+		return ClientIdentity{Source: ClientIdentitySourceIP, Value: ip.String()}
+	}
+
+	if r != nil && r.header != "" {
+		if value := strings.TrimSpace(headerValue(event.Headers, r.header)); value != "" {
+			return ClientIdentity{Source: ClientIdentitySourceHeader, Value: value}
+		}
+	}
+
+	return ClientIdentity{}
+}
+
+// clientIP returns the client IP, walking X-Forwarded-For only when the
+// immediate peer is a trusted proxy. Returns the zero Addr if unavailable.
+func (r *ClientIdentifierResolver) clientIP(event events.APIGatewayProxyRequest) netip.Addr {
+	sourceIP, err := netip.ParseAddr(strings.TrimSpace(event.RequestContext.Identity.SourceIP))
+	if err != nil {
+		return netip.Addr{}
+	}
+	sourceIP = sourceIP.Unmap()
+
+	if r == nil || !r.isTrusted(sourceIP) {
+		return sourceIP
+	}
+	return parseForwardedFor(headerValue(event.Headers, "X-Forwarded-For"), sourceIP, r.isTrusted)
+}
+
+// isTrusted reports whether addr belongs to a trusted proxy.
+func (r *ClientIdentifierResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseForwardedFor returns the client address from an X-Forwarded-For header.
+//
+// Entries are appended by each proxy, so only the right-hand side is trustworthy:
+// the header is walked from the right, skipping trusted proxies, and the first
+// untrusted entry is the client. Anything further left may be client-supplied.
+// If an entry is malformed, the walk stops at the last valid hop. If every entry
+// is trusted, the leftmost one is returned.
+func parseForwardedFor(header string, peer netip.Addr, trusted func(netip.Addr) bool) netip.Addr {
+	client := peer
+	if header == "" {
+		return client
+	}
+
+	entries := strings.Split(header, ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		addr, ok := parseForwardedAddr(entries[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !trusted(addr) {
+			break
+		}
+	}
+	return client
+}
+
+// parseForwardedAddr parses a single X-Forwarded-For entry, accepting an
+// optional port ("203.0.113.7:443", "[2001:db8::1]:443").
+func parseForwardedAddr(entry string) (netip.Addr, bool) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return netip.Addr{}, false
+	}
+	if addr, err := netip.ParseAddr(entry); err == nil {
+		return addr.Unmap(), true
+	}
+	if addrPort, err := netip.ParseAddrPort(entry); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	return netip.Addr{}, false
+}
+
+// headerValue returns a header value using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
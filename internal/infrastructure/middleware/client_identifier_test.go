@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func clientEvent(sourceIP string, headers map[string]string) events.APIGatewayProxyRequest {
+	return events.APIGatewayProxyRequest{
+		Headers: headers,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Identity: events.APIGatewayRequestIdentity{SourceIP: sourceIP},
+		},
+	}
+}
+
+func TestClientIdentifierResolver_Precedence(t *testing.T) {
+	resolver, err := NewClientIdentifierResolver(ClientIdentifierConfig{Header: "X-Client-ID"})
+	if err != nil {
+		t.Fatalf("NewClientIdentifierResolver() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		event events.APIGatewayProxyRequest
+		want  ClientIdentity
+	}{
+		{
+			name: "API key wins over IP and header",
+			event: clientEvent("203.0.113.7", map[string]string{
+				"X-API-Key":   "key-123",
+				"X-Client-ID": "client-1",
+			}),
+			want: ClientIdentity{Source: ClientIdentitySourceAPIKey, Value: "key-123"},
+		},
+		{
+			name:  "Bearer token is an API key",
+			event: clientEvent("203.0.113.7", map[string]string{"Authorization": "Bearer key-456"}),
+			want:  ClientIdentity{Source: ClientIdentitySourceAPIKey, Value: "key-456"},
+		},
+		{
+			name:  "source IP wins over header",
+			event: clientEvent("203.0.113.7", map[string]string{"X-Client-ID": "client-1"}),
+			want:  ClientIdentity{Source: ClientIdentitySourceIP, Value: "203.0.113.7"},
+		},
+		{
+			name:  "IPv4-mapped source IP is normalized",
+			event: clientEvent("::ffff:203.0.113.7", nil),
+			want:  ClientIdentity{Source: ClientIdentitySourceIP, Value: "203.0.113.7"},
+		},
+		{
+			name:  "header used without API key or IP",
+			event: clientEvent("", map[string]string{"x-client-id": " client-1 "}),
+			want:  ClientIdentity{Source: ClientIdentitySourceHeader, Value: "client-1"},
+		},
+		{
+			name:  "invalid source IP falls through to header",
+			event: clientEvent("not-an-ip", map[string]string{"X-Client-ID": "client-1"}),
+			want:  ClientIdentity{Source: ClientIdentitySourceHeader, Value: "client-1"},
+		},
+		{
+			name:  "nothing resolvable",
+			event: clientEvent("", nil),
+			want:  ClientIdentity{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.ClientIdentifier(tt.event); got != tt.want {
+				t.Errorf("ClientIdentifier() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIdentifierResolver_ForwardedFor(t *testing.T) {
+	resolver, err := NewClientIdentifierResolver(ClientIdentifierConfig{
+		TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1", " "},
+	})
+	if err != nil {
+		t.Fatalf("NewClientIdentifierResolver() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		sourceIP string
+		xff      string
+		want     string
+	}{
+		{"untrusted peer ignores XFF", "203.0.113.7", "198.51.100.1", "203.0.113.7"},
+		{"trusted peer uses XFF", "10.1.2.3", "198.51.100.1", "198.51.100.1"},
+		{"rightmost untrusted entry wins over spoofed leftmost", "10.1.2.3", "1.1.1.1, 198.51.100.1", "198.51.100.1"},
+		{"trusted hops are skipped", "10.1.2.3", "198.51.100.1, 192.0.2.1, 10.9.9.9", "198.51.100.1"},
+		{"all entries trusted returns leftmost", "10.1.2.3", "10.0.0.5, 192.0.2.1", "10.0.0.5"},
+		{"trusted peer without XFF", "10.1.2.3", "", "10.1.2.3"},
+		{"entries with ports", "10.1.2.3", "198.51.100.1:4711, [2001:db8::1]:443", "2001:db8::1"},
+		{"IPv6 entry", "10.1.2.3", "2001:db8::7", "2001:db8::7"},
+		{"malformed entry stops the walk", "10.1.2.3", "198.51.100.1, garbage, 10.0.0.5", "10.0.0.5"},
+		{"empty entry stops the walk", "10.1.2.3", "198.51.100.1,,", "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := clientEvent(tt.sourceIP, map[string]string{"x-forwarded-for": tt.xff})
+			got := resolver.ClientIdentifier(event)
+			if got.Source != ClientIdentitySourceIP || got.Value != tt.want {
+				t.Errorf("ClientIdentifier() = %+v, want ip %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseForwardedAddr(t *testing.T) {
+	tests := []struct {
+		entry  string
+		want   string
+		wantOK bool
+	}{
+		{"198.51.100.1", "198.51.100.1", true},
+		{" 198.51.100.1 ", "198.51.100.1", true},
+		{"198.51.100.1:8080", "198.51.100.1", true},
+		{"2001:db8::1", "2001:db8::1", true},
+		{"[2001:db8::1]:443", "2001:db8::1", true},
+		{"::ffff:198.51.100.1", "198.51.100.1", true},
+		{"", "", false},
+		{"unknown", "", false},
+		{"198.51.100", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, ok := parseForwardedAddr(tt.entry)
+			if ok != tt.wantOK {
+				t.Fatalf("parseForwardedAddr(%q) ok = %v, want %v", tt.entry, ok, tt.wantOK)
+			}
+			if ok && got != netip.MustParseAddr(tt.want) {
+				t.Errorf("parseForwardedAddr(%q) = %s, want %s", tt.entry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientIdentifierResolver_InvalidProxy(t *testing.T) {
+	for _, proxy := range []string{"not-an-ip", "10.0.0.0/33"} {
+		if _, err := NewClientIdentifierResolver(ClientIdentifierConfig{TrustedProxies: []string{proxy}}); err == nil {
+			t.Errorf("NewClientIdentifierResolver(%q) expected error, got nil", proxy)
+		}
+	}
+}
+
+func TestClientIdentity_Key(t *testing.T) {
+	var nilResolver *ClientIdentifierResolver
+	identity := nilResolver.ClientIdentifier(clientEvent("203.0.113.7", map[string]string{"X-Forwarded-For": "198.51.100.1"}))
+
+	if identity.Key() != "ip:203.0.113.7" {
+		t.Errorf("Key() = %q, want ip:203.0.113.7 (nil resolver trusts no proxy)", identity.Key())
+	}
+	if identity.Authenticated() {
+		t.Error("Authenticated() = true, want false for IP identity")
+	}
+	if (ClientIdentity{}).Key() != "" {
+		t.Errorf("zero Key() = %q, want empty", (ClientIdentity{}).Key())
+	}
+	if !(ClientIdentity{Source: ClientIdentitySourceAPIKey, Value: "k"}).Authenticated() {
+		t.Error("Authenticated() = false, want true for API key identity")
+	}
+}
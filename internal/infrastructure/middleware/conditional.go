@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// NotModified reports whether the request's If-Modified-Since header is at or
+// after lastModified, i.e. the client's copy is still current.
+//
+// HTTP dates have one-second precision, so lastModified is truncated to the
+// second before comparing. Returns false if lastModified is zero or the header
+// is absent or not a valid HTTP date.
+func NotModified(event events.APIGatewayProxyRequest, lastModified time.Time) bool {
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(headerValue(event.Headers, "If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// NotModifiedResponse creates a 304 Not Modified response without a body,
+// carrying Last-Modified and the security headers.
+func NotModifiedResponse(lastModified time.Time) events.APIGatewayProxyResponse {
+	resp := AddSecurityHeaders(events.APIGatewayProxyResponse{StatusCode: http.StatusNotModified})
+	return WithLastModified(resp, lastModified)
+}
+
+// WithLastModified sets the Last-Modified header of resp to lastModified in
+// RFC 1123 format (GMT), enabling If-Modified-Since conditional requests.
+// resp is returned unchanged if lastModified is zero.
+func WithLastModified(resp events.APIGatewayProxyResponse, lastModified time.Time) events.APIGatewayProxyResponse {
+	if lastModified.IsZero() {
+		return resp
+	}
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]string)
+	}
+	resp.Headers["Last-Modified"] = lastModified.UTC().Format(http.TimeFormat)
+	return resp
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestWithLastModified(t *testing.T) {
+	lastModified := time.Date(2024, 3, 5, 14, 30, 15, 500, time.FixedZone("CET", 3600))
+
+	resp := WithLastModified(events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, lastModified)
+
+	if got, want := resp.Headers["Last-Modified"], "Tue, 05 Mar 2024 13:30:15 GMT"; got != want {
+		t.Errorf("Last-Modified = %q, want %q", got, want)
+	}
+
+	if resp := WithLastModified(events.APIGatewayProxyResponse{}, time.Time{}); resp.Headers["Last-Modified"] != "" {
+		t.Errorf("Last-Modified = %q for a zero time, want none", resp.Headers["Last-Modified"])
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2024, 3, 5, 13, 30, 15, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		lastModified time.Time
+		want         bool
+	}{
+		{"no header", nil, lastModified, false},
+		{"same second", map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 13:30:15 GMT"}, lastModified, true},
+		{"after", map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 14:00:00 GMT"}, lastModified, true},
+		{"before", map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 13:30:14 GMT"}, lastModified, false},
+		{"lowercase header", map[string]string{"if-modified-since": "Tue, 05 Mar 2024 13:30:15 GMT"}, lastModified, true},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, lastModified, false},
+		{"zero timestamp", map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 13:30:15 GMT"}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := events.APIGatewayProxyRequest{Headers: tt.headers}
+			if got := NotModified(event, tt.lastModified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotModifiedResponse(t *testing.T) {
+	resp := NotModifiedResponse(time.Date(2024, 3, 5, 13, 30, 15, 0, time.UTC))
+
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("StatusCode = %d, want 304", resp.StatusCode)
+	}
+	if resp.Body != "" {
+		t.Errorf("Body = %q, want empty", resp.Body)
+	}
+	if resp.Headers["Last-Modified"] != "Tue, 05 Mar 2024 13:30:15 GMT" {
+		t.Errorf("Last-Modified = %q", resp.Headers["Last-Modified"])
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+var (
+	// currencyAliases maps legacy or alternative codes to canonical codes.
+	currencyAliases   map[entity.CurrencyCode]entity.CurrencyCode
+	currencyAliasesMu sync.RWMutex
+)
+
+// SetCurrencyAliases sets the alias table applied by ValidateCurrencyCode,
+// e.g. {"XBT": "BTC", "DEM": "EUR"}. It replaces any previous table; nil clears it.
+//
+// Aliases and canonical codes are normalized. Returns an error (and leaves the
+// current table unchanged) if a code is invalid or an alias maps to itself.
+// This function is thread-safe.
+func SetCurrencyAliases(aliases map[string]string) error {
+	table := make(map[entity.CurrencyCode]entity.CurrencyCode, len(aliases))
+	for alias, canonical := range aliases {
+		aliasCode, err := entity.NewCurrencyCode(alias)
+		if err != nil {
+			return fmt.Errorf("invalid currency alias %q: %w", alias, err)
+		}
+		canonicalCode, err := entity.NewCurrencyCode(canonical)
+		if err != nil {
+			return fmt.Errorf("invalid canonical code %q for alias %q: %w", canonical, alias, err)
+		}
+		if aliasCode.Equal(canonicalCode) {
+			return fmt.Errorf("currency alias %q maps to itself", alias)
+		}
+		table[aliasCode] = canonicalCode
+	}
+
+	currencyAliasesMu.Lock()
+	defer currencyAliasesMu.Unlock()
+	currencyAliases = table
+	return nil
+}
+
+// resolveCurrencyAlias returns the canonical code for an alias, or code unchanged.
+// Aliases are resolved once, not transitively.
+func resolveCurrencyAlias(code entity.CurrencyCode) entity.CurrencyCode {
+	currencyAliasesMu.RLock()
+	defer currencyAliasesMu.RUnlock()
+	if canonical, ok := currencyAliases[code]; ok {
+		return canonical
+	}
+	return code
+}
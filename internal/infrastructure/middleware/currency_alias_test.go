@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestValidateCurrencyCode_Aliases(t *testing.T) {
+	if err := SetCurrencyAliases(map[string]string{"XBT": "BTC", "dem": "eur"}); err != nil {
+		t.Fatalf("SetCurrencyAliases() error = %v", err)
+	}
+	t.Cleanup(func() { _ = SetCurrencyAliases(nil) })
+
+	tests := []struct {
+		name string
+		code string
+		want entity.CurrencyCode
+	}{
+		{"alias resolves", "XBT", "BTC"},
+		{"lowercase alias resolves", "xbt", "BTC"},
+		{"alias configured lowercase resolves", "DEM", "EUR"},
+		{"non-aliased code passes through", "USD", "USD"},
+		{"canonical code passes through", "BTC", "BTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateCurrencyCode(tt.code)
+			if err != nil {
+				t.Fatalf("ValidateCurrencyCode(%q) error = %v", tt.code, err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateCurrencyCode(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGetRateRequest_AliasesEchoCanonicalCodes(t *testing.T) {
+	if err := SetCurrencyAliases(map[string]string{"XBT": "BTC"}); err != nil {
+		t.Fatalf("SetCurrencyAliases() error = %v", err)
+	}
+	t.Cleanup(func() { _ = SetCurrencyAliases(nil) })
+
+	base, target, err := ValidateGetRateRequest(events.APIGatewayProxyRequest{
+		HTTPMethod:     "GET",
+		PathParameters: map[string]string{"base": "XBT", "target": "USD"},
+	})
+	if err != nil {
+		t.Fatalf("ValidateGetRateRequest() error = %v", err)
+	}
+	if base != "BTC" || target != "USD" {
+		t.Errorf("ValidateGetRateRequest() = %s/%s, want BTC/USD", base, target)
+	}
+
+	// An alias and its canonical code are the same currency
+	if _, _, err := ValidateGetRateRequest(events.APIGatewayProxyRequest{
+		HTTPMethod:     "GET",
+		PathParameters: map[string]string{"base": "XBT", "target": "BTC"},
+	}); err == nil {
+		t.Error("ValidateGetRateRequest(XBT/BTC) error = nil, want currency mismatch")
+	}
+}
+
+func TestSetCurrencyAliases_Invalid(t *testing.T) {
+	t.Cleanup(func() { _ = SetCurrencyAliases(nil) })
+	if err := SetCurrencyAliases(map[string]string{"XBT": "BTC"}); err != nil {
+		t.Fatalf("SetCurrencyAliases() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		aliases map[string]string
+	}{
+		{"invalid alias", map[string]string{"BITCOIN": "BTC"}},
+		{"invalid canonical code", map[string]string{"XBT": "B"}},
+		{"alias maps to itself", map[string]string{"btc": "BTC"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetCurrencyAliases(tt.aliases); err == nil {
+				t.Errorf("SetCurrencyAliases(%v) error = nil, want error", tt.aliases)
+			}
+		})
+	}
+
+	// A rejected table leaves the previous one in place
+	if got, _ := ValidateCurrencyCode("XBT"); got != "BTC" {
+		t.Errorf("ValidateCurrencyCode(XBT) = %q after rejected update, want BTC", got)
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// responseEnvelope controls whether SuccessResponse wraps bodies in a data/meta envelope.
+var responseEnvelope atomic.Bool
+
+// SetResponseEnvelope enables or disables the data/meta response envelope (RESPONSE_ENVELOPE).
+//
+// It is set once at startup; the envelope is off by default so existing clients
+// keep receiving the plain response body. This function is thread-safe.
+func SetResponseEnvelope(enabled bool) {
+	responseEnvelope.Store(enabled)
+}
+
+// ResponseMeta holds metadata about a response.
+// It is only included when the response envelope is enabled.
+type ResponseMeta struct {
+	RequestID    string    `json:"request_id,omitempty"`    // Request ID from context
+	Cached       bool      `json:"cached"`                  // Whether the data was served from cache
+	Source       string    `json:"source,omitempty"`        // Where the data came from (cache, provider, stale_cache)
+	Skipped      int       `json:"skipped,omitempty"`       // Provider entries dropped as invalid
+	CacheSkipped int       `json:"cache_skipped,omitempty"` // Cached entries that could not be read
+	Timestamp    time.Time `json:"timestamp"`               // When the response was built
+}
+
+// ResponseEnvelope wraps a response body with metadata.
+//
+// Format:
+//
+//	{
+//	  "data": {...},
+//	  "meta": {"cached": true, "source": "cache", "request_id": "...", "timestamp": "..."}
+//	}
+type ResponseEnvelope struct {
+	Data interface{}  `json:"data"`
+	Meta ResponseMeta `json:"meta"`
+}
+
+// cacheSourcer is implemented by response DTOs that know where their data came from.
+type cacheSourcer interface {
+	CacheSource() string
+}
+
+// skippedCounter is implemented by response DTOs that report dropped provider entries.
+type skippedCounter interface {
+	SkippedCount() int
+}
+
+// cacheSkippedCounter is implemented by response DTOs that report unreadable cache entries.
+type cacheSkippedCounter interface {
+	CacheSkippedCount() int
+}
+
+// buildResponseMeta builds response metadata from the request context and body.
+func buildResponseMeta(ctx context.Context, body interface{}) ResponseMeta {
+	// A sparse fieldset reports the metadata of the full response
+	if f, ok := body.(filteredBody); ok {
+		body = f.original
+	}
+
+	meta := ResponseMeta{
+		RequestID: logger.GetRequestID(ctx),
+		Timestamp: time.Now(),
+	}
+
+	if s, ok := body.(cacheSourcer); ok {
+		meta.Source = s.CacheSource()
+		meta.Cached = meta.Source == dto.SourceCache || meta.Source == dto.SourceStaleCache
+	}
+
+	if s, ok := body.(skippedCounter); ok {
+		meta.Skipped = s.SkippedCount()
+	}
+
+	if s, ok := body.(cacheSkippedCounter); ok {
+		meta.CacheSkipped = s.CacheSkippedCount()
+	}
+
+	return meta
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// enableEnvelope turns the response envelope on for the duration of a test.
+func enableEnvelope(t *testing.T) {
+	t.Helper()
+	SetResponseEnvelope(true)
+	t.Cleanup(func() { SetResponseEnvelope(false) })
+}
+
+func TestSuccessResponse_NoEnvelope(t *testing.T) {
+	ctx := logger.WithRequestID(context.Background(), "req-123")
+	body := dto.RateResponse{
+		Base:      "USD",
+		Target:    "EUR",
+		Rate:      0.85,
+		Timestamp: time.Now(),
+		Source:    dto.SourceCache,
+	}
+
+	resp := SuccessResponse(ctx, http.StatusOK, body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// Body should be the plain DTO
+	plain, _ := json.Marshal(body)
+	if resp.Body != string(plain) {
+		t.Errorf("Body = %s, want %s", resp.Body, plain)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(resp.Body), &raw); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if _, ok := raw["data"]; ok {
+		t.Error("expected no data field without envelope")
+	}
+	if _, ok := raw["meta"]; ok {
+		t.Error("expected no meta field without envelope")
+	}
+	if raw["base"] != "USD" {
+		t.Errorf("base = %v, want USD", raw["base"])
+	}
+}
+
+func TestSuccessResponse_Envelope(t *testing.T) {
+	enableEnvelope(t)
+	ctx := logger.WithRequestID(context.Background(), "req-123")
+	body := dto.RateResponse{
+		Base:      "USD",
+		Target:    "EUR",
+		Rate:      0.85,
+		Timestamp: time.Now(),
+		Source:    dto.SourceCache,
+	}
+
+	resp := SuccessResponse(ctx, http.StatusOK, body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if resp.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", resp.Headers["Content-Type"])
+	}
+
+	var envelope struct {
+		Data dto.RateResponse `json:"data"`
+		Meta ResponseMeta     `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(resp.Body), &envelope); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if envelope.Data.Base != "USD" || envelope.Data.Target != "EUR" {
+		t.Errorf("Data = %+v, want USD/EUR", envelope.Data)
+	}
+	if envelope.Data.Rate != 0.85 {
+		t.Errorf("Data.Rate = %f, want 0.85", envelope.Data.Rate)
+	}
+	if envelope.Meta.RequestID != "req-123" {
+		t.Errorf("Meta.RequestID = %q, want req-123", envelope.Meta.RequestID)
+	}
+	if !envelope.Meta.Cached {
+		t.Error("Meta.Cached = false, want true")
+	}
+	if envelope.Meta.Source != dto.SourceCache {
+		t.Errorf("Meta.Source = %q, want %q", envelope.Meta.Source, dto.SourceCache)
+	}
+	if envelope.Meta.Timestamp.IsZero() {
+		t.Error("Meta.Timestamp is zero")
+	}
+}
+
+func TestSuccessResponse_EnvelopeProviderSource(t *testing.T) {
+	enableEnvelope(t)
+	body := dto.RatesResponse{
+		Base:   "USD",
+		Rates:  map[string]dto.RateResponse{},
+		Source: dto.SourceProvider,
+	}
+
+	resp := SuccessResponse(context.Background(), http.StatusOK, body)
+
+	var envelope ResponseEnvelope
+	if err := json.Unmarshal([]byte(resp.Body), &envelope); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if envelope.Meta.Cached {
+		t.Error("Meta.Cached = true, want false for provider source")
+	}
+	if envelope.Meta.Source != dto.SourceProvider {
+		t.Errorf("Meta.Source = %q, want %q", envelope.Meta.Source, dto.SourceProvider)
+	}
+	if envelope.Meta.RequestID != "" {
+		t.Errorf("Meta.RequestID = %q, want empty", envelope.Meta.RequestID)
+	}
+}
+
+func TestSuccessResponse_EnvelopeCacheSkipped(t *testing.T) {
+	enableEnvelope(t)
+	body := dto.RatesResponse{
+		Base:         "USD",
+		Rates:        map[string]dto.RateResponse{},
+		Source:       dto.SourceCache,
+		CacheSkipped: 2,
+	}
+
+	resp := SuccessResponse(context.Background(), http.StatusOK, body)
+
+	var envelope ResponseEnvelope
+	if err := json.Unmarshal([]byte(resp.Body), &envelope); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if envelope.Meta.CacheSkipped != 2 {
+		t.Errorf("Meta.CacheSkipped = %d, want 2", envelope.Meta.CacheSkipped)
+	}
+}
@@ -7,14 +7,55 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
 )
 
+// debugErrors controls whether error bodies include internal error details.
+var debugErrors atomic.Bool
+
+// SetDebugErrors enables or disables internal error details in error bodies (DEBUG_ERRORS).
+//
+// Details are never exposed in production: enabling is refused (and false returned)
+// when logger.Environment() is a production environment (see isProduction).
+// Returns whether error details are enabled. This function is thread-safe.
+func SetDebugErrors(enabled bool) bool {
+	enabled = enabled && !isProduction(logger.Environment())
+	debugErrors.Store(enabled)
+	return enabled
+}
+
+// debugErrorsEnabled reports whether error details may be exposed.
+// The environment is re-checked so a production deployment never leaks details.
+func debugErrorsEnabled() bool {
+	return debugErrors.Load() && !isProduction(logger.Environment())
+}
+
+// isProduction reports whether env names a production environment: "prod"
+// (as in the SAM template's Environment parameter) or "production", in any case.
+func isProduction(env string) bool {
+	env = strings.TrimSpace(env)
+	return strings.EqualFold(env, "prod") || strings.EqualFold(env, "production")
+}
+
+// availableTargetsDetail controls whether error bodies list the targets
+// available for a base when the requested target is unsupported.
+var availableTargetsDetail atomic.Bool
+
+// SetAvailableTargetsDetail enables or disables listing the available targets
+// in error bodies for unsupported targets (RESPONSE_AVAILABLE_TARGETS).
+// This function is thread-safe.
+func SetAvailableTargetsDetail(enabled bool) {
+	availableTargetsDetail.Store(enabled)
+}
+
 // getStatusCode maps domain errors to HTTP status codes.
 //
 // This function:
@@ -44,9 +85,42 @@ func getStatusCode(err error) int {
 	if errors.Is(err, entity.ErrRateNotFound) {
 		return http.StatusNotFound
 	}
+	if errors.Is(err, entity.ErrPairDenied) {
+		return http.StatusForbidden
+	}
 	if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
 		return http.StatusServiceUnavailable
 	}
+	if errors.Is(err, provider.ErrUpstreamInvalidResponse) {
+		return http.StatusBadGateway
+	}
+	if errors.Is(err, provider.ErrProviderTransport) {
+		return http.StatusBadGateway
+	}
+	if errors.Is(err, provider.ErrProviderRead) {
+		return http.StatusBadGateway
+	}
+	if errors.Is(err, provider.ErrProviderRequestBuild) {
+		return http.StatusInternalServerError
+	}
+	if errors.Is(err, entity.ErrRateOutOfRange) {
+		return http.StatusBadGateway
+	}
+	if errors.Is(err, provider.ErrProviderBusy) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, provider.ErrProviderUnavailable) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, provider.ErrCurrencyUnsupported) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, ErrInvalidBases) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, ErrInvalidFormat) {
+		return http.StatusBadRequest
+	}
 
 	// Check for rate limit errors
 	if errors.Is(err, ErrRateLimitExceeded) {
@@ -90,9 +164,42 @@ func getErrorCode(err error) string {
 	if errors.Is(err, entity.ErrRateNotFound) {
 		return "RATE_NOT_FOUND"
 	}
+	if errors.Is(err, entity.ErrPairDenied) {
+		return "PAIR_DENIED"
+	}
 	if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
 		return "CIRCUIT_BREAKER_OPEN"
 	}
+	if errors.Is(err, provider.ErrUpstreamInvalidResponse) {
+		return "UPSTREAM_INVALID_RESPONSE"
+	}
+	if errors.Is(err, provider.ErrProviderTransport) {
+		return "PROVIDER_TRANSPORT_ERROR"
+	}
+	if errors.Is(err, provider.ErrProviderRead) {
+		return "PROVIDER_READ_ERROR"
+	}
+	if errors.Is(err, provider.ErrProviderRequestBuild) {
+		return "PROVIDER_REQUEST_BUILD_ERROR"
+	}
+	if errors.Is(err, entity.ErrRateOutOfRange) {
+		return "RATE_OUT_OF_RANGE"
+	}
+	if errors.Is(err, provider.ErrProviderBusy) {
+		return "PROVIDER_BUSY"
+	}
+	if errors.Is(err, provider.ErrProviderUnavailable) {
+		return "PROVIDER_UNAVAILABLE"
+	}
+	if errors.Is(err, provider.ErrCurrencyUnsupported) {
+		return "CURRENCY_UNSUPPORTED"
+	}
+	if errors.Is(err, ErrInvalidBases) {
+		return "INVALID_BASES"
+	}
+	if errors.Is(err, ErrInvalidFormat) {
+		return "INVALID_FORMAT"
+	}
 	if errors.Is(err, ErrRateLimitExceeded) {
 		return "RATE_LIMIT_EXCEEDED"
 	}
@@ -130,9 +237,39 @@ func getClientMessage(err error) string {
 	if errors.Is(err, entity.ErrRateNotFound) {
 		return "Exchange rate not found"
 	}
+	if errors.Is(err, entity.ErrPairDenied) {
+		return "Currency pair not available"
+	}
 	if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
 		return "Service temporarily unavailable"
 	}
+	if errors.Is(err, provider.ErrUpstreamInvalidResponse) {
+		return "Upstream service returned an invalid response"
+	}
+	if errors.Is(err, provider.ErrProviderTransport) {
+		return "Upstream service could not be reached"
+	}
+	if errors.Is(err, provider.ErrProviderRead) {
+		return "Upstream service returned an incomplete response"
+	}
+	if errors.Is(err, entity.ErrRateOutOfRange) {
+		return "Upstream service returned an implausible exchange rate"
+	}
+	if errors.Is(err, provider.ErrProviderBusy) {
+		return "Service temporarily unavailable"
+	}
+	if errors.Is(err, provider.ErrProviderUnavailable) {
+		return "Service temporarily unavailable"
+	}
+	if errors.Is(err, provider.ErrCurrencyUnsupported) {
+		return "Currency not supported"
+	}
+	if errors.Is(err, ErrInvalidBases) {
+		return "Invalid bases parameter"
+	}
+	if errors.Is(err, ErrInvalidFormat) {
+		return "Invalid format parameter"
+	}
 	if errors.Is(err, ErrRateLimitExceeded) {
 		return "Rate limit exceeded"
 	}
@@ -158,14 +295,8 @@ func getClientMessage(err error) string {
 // Security: Never exposes internal error details to clients.
 func ErrorResponse(err error) events.APIGatewayProxyResponse {
 	statusCode := getStatusCode(err)
-	errorCode := getErrorCode(err)
-	clientMessage := getClientMessage(err)
-
-	errorResp := dto.ErrorResponse{
-		Error:     clientMessage,
-		Code:      errorCode,
-		Timestamp: time.Now(),
-	}
+	errorResp := ErrorBody(err)
+	clientMessage := errorResp.Error
 
 	body, marshalErr := json.Marshal(errorResp)
 	if marshalErr != nil {
@@ -194,14 +325,52 @@ func ErrorResponse(err error) events.APIGatewayProxyResponse {
 	}
 }
 
+// ErrorBody returns the client-safe error body for err, as used by ErrorResponse.
+// It is useful for reporting errors inside an otherwise successful response.
+//
+// If SetDebugErrors enabled it (outside production), the body also carries the
+// internal error string in Debug. If SetAvailableTargetsDetail enabled it, an
+// unsupported target (provider.UnsupportedTargetError) lists the targets the
+// provider offers for the base in Details.
+func ErrorBody(err error) dto.ErrorResponse {
+	body := dto.ErrorResponse{
+		Error:     getClientMessage(err),
+		Code:      getErrorCode(err),
+		Timestamp: time.Now(),
+	}
+	if err != nil && debugErrorsEnabled() {
+		body.Debug = err.Error()
+	}
+	var unsupported *provider.UnsupportedTargetError
+	if availableTargetsDetail.Load() && errors.As(err, &unsupported) && len(unsupported.Available) > 0 {
+		body.Details = &dto.ErrorDetails{AvailableTargets: unsupported.Available}
+	}
+	return body
+}
+
 // SuccessResponse creates a success response for API Gateway.
 //
 // This function:
+// - Wraps the body in a data/meta envelope when enabled with SetResponseEnvelope,
+// with the request ID from ctx, the cache source, and a timestamp
 // - Marshals the response body to JSON
 // - Sets proper headers
 // - Returns 200 status code
-func SuccessResponse(statusCode int, body interface{}) events.APIGatewayProxyResponse {
-	jsonBody, err := json.Marshal(body)
+func SuccessResponse(ctx context.Context, statusCode int, body interface{}) events.APIGatewayProxyResponse {
+	if responseEnvelope.Load() {
+		body = ResponseEnvelope{
+			Data: body,
+			Meta: buildResponseMeta(ctx, body),
+		}
+	}
+
+	var jsonBody []byte
+	var err error
+	if prettyJSONRequested(ctx) {
+		jsonBody, err = json.MarshalIndent(body, "", "  ")
+	} else {
+		jsonBody, err = json.Marshal(body)
+	}
 	if err != nil {
 		// If marshaling fails, return error response
 		return ErrorResponse(fmt.Errorf("failed to marshal response: %w", err))
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/misterfancybg/go-currenseen/internal/application/dto"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	"github.com/misterfancybg/go-currenseen/internal/domain/provider"
 	"github.com/misterfancybg/go-currenseen/pkg/circuitbreaker"
 )
 
@@ -26,6 +28,20 @@ func TestGetStatusCode(t *testing.T) {
 		{"currency code mismatch", entity.ErrCurrencyCodeMismatch, http.StatusBadRequest},
 		{"rate not found", entity.ErrRateNotFound, http.StatusNotFound},
 		{"circuit open", circuitbreaker.ErrCircuitOpen, http.StatusServiceUnavailable},
+		{"upstream invalid response", provider.ErrUpstreamInvalidResponse, http.StatusBadGateway},
+		{"upstream missing base", fmt.Errorf("%w: base currency USD not found in response", provider.ErrUpstreamInvalidResponse), http.StatusBadGateway},
+		{"upstream non-positive rate", fmt.Errorf("%w: invalid rate: 0.000000 (must be positive)", provider.ErrUpstreamInvalidResponse), http.StatusBadGateway},
+		{"provider transport", fmt.Errorf("%w: connection refused", provider.ErrProviderTransport), http.StatusBadGateway},
+		{"provider read", fmt.Errorf("%w: unexpected EOF", provider.ErrProviderRead), http.StatusBadGateway},
+		{"provider request build", provider.ErrProviderRequestBuild, http.StatusInternalServerError},
+		{"provider transport timeout", fmt.Errorf("%w: %w", provider.ErrProviderTransport, context.DeadlineExceeded), http.StatusRequestTimeout},
+		{"rate out of range", fmt.Errorf("USD/EUR: %w", entity.ErrRateOutOfRange), http.StatusBadGateway},
+		{"provider busy", provider.ErrProviderBusy, http.StatusServiceUnavailable},
+		{"provider unavailable", provider.ErrProviderUnavailable, http.StatusServiceUnavailable},
+		{"currency unsupported", provider.ErrCurrencyUnsupported, http.StatusNotFound},
+		{"invalid bases", fmt.Errorf("%w: no base currencies given", ErrInvalidBases), http.StatusBadRequest},
+		{"invalid format", fmt.Errorf("%w: xml", ErrInvalidFormat), http.StatusBadRequest},
+		{"pair denied", fmt.Errorf("%w: USD/RUB", entity.ErrPairDenied), http.StatusForbidden},
 		{"path parameter error", errors.New("path parameter base not found"), http.StatusBadRequest},
 		{"method error", errors.New("method POST not allowed"), http.StatusBadRequest},
 		{"unknown error", errors.New("unknown error"), http.StatusInternalServerError},
@@ -52,6 +68,19 @@ func TestGetErrorCode(t *testing.T) {
 		{"currency code mismatch", entity.ErrCurrencyCodeMismatch, "CURRENCY_CODE_MISMATCH"},
 		{"rate not found", entity.ErrRateNotFound, "RATE_NOT_FOUND"},
 		{"circuit open", circuitbreaker.ErrCircuitOpen, "CIRCUIT_BREAKER_OPEN"},
+		{"upstream invalid response", provider.ErrUpstreamInvalidResponse, "UPSTREAM_INVALID_RESPONSE"},
+		{"upstream missing base", fmt.Errorf("%w: base currency USD not found in response", provider.ErrUpstreamInvalidResponse), "UPSTREAM_INVALID_RESPONSE"},
+		{"upstream non-positive rate", fmt.Errorf("%w: invalid rate: 0.000000 (must be positive)", provider.ErrUpstreamInvalidResponse), "UPSTREAM_INVALID_RESPONSE"},
+		{"provider transport", provider.ErrProviderTransport, "PROVIDER_TRANSPORT_ERROR"},
+		{"provider read", provider.ErrProviderRead, "PROVIDER_READ_ERROR"},
+		{"provider request build", provider.ErrProviderRequestBuild, "PROVIDER_REQUEST_BUILD_ERROR"},
+		{"rate out of range", entity.ErrRateOutOfRange, "RATE_OUT_OF_RANGE"},
+		{"provider busy", provider.ErrProviderBusy, "PROVIDER_BUSY"},
+		{"provider unavailable", provider.ErrProviderUnavailable, "PROVIDER_UNAVAILABLE"},
+		{"currency unsupported", provider.ErrCurrencyUnsupported, "CURRENCY_UNSUPPORTED"},
+		{"invalid bases", ErrInvalidBases, "INVALID_BASES"},
+		{"invalid format", ErrInvalidFormat, "INVALID_FORMAT"},
+		{"pair denied", entity.ErrPairDenied, "PAIR_DENIED"},
 		{"unknown error", errors.New("unknown"), "INTERNAL_ERROR"},
 	}
 
@@ -77,6 +106,17 @@ func TestGetClientMessage(t *testing.T) {
 		{"currency code mismatch", entity.ErrCurrencyCodeMismatch, "Base and target currencies cannot be the same"},
 		{"rate not found", entity.ErrRateNotFound, "Exchange rate not found"},
 		{"circuit open", circuitbreaker.ErrCircuitOpen, "Service temporarily unavailable"},
+		{"upstream invalid response", provider.ErrUpstreamInvalidResponse, "Upstream service returned an invalid response"},
+		{"provider transport", provider.ErrProviderTransport, "Upstream service could not be reached"},
+		{"provider read", provider.ErrProviderRead, "Upstream service returned an incomplete response"},
+		{"provider request build", provider.ErrProviderRequestBuild, "An error occurred processing your request"},
+		{"rate out of range", entity.ErrRateOutOfRange, "Upstream service returned an implausible exchange rate"},
+		{"provider busy", provider.ErrProviderBusy, "Service temporarily unavailable"},
+		{"provider unavailable", provider.ErrProviderUnavailable, "Service temporarily unavailable"},
+		{"currency unsupported", provider.ErrCurrencyUnsupported, "Currency not supported"},
+		{"invalid bases", ErrInvalidBases, "Invalid bases parameter"},
+		{"invalid format", ErrInvalidFormat, "Invalid format parameter"},
+		{"pair denied", entity.ErrPairDenied, "Currency pair not available"},
 		{"unknown error", errors.New("internal error"), "An error occurred processing your request"},
 	}
 
@@ -121,6 +161,121 @@ func TestErrorResponse(t *testing.T) {
 	}
 }
 
+func TestErrorBody(t *testing.T) {
+	body := ErrorBody(fmt.Errorf("base EUR: %w", provider.ErrCurrencyUnsupported))
+
+	if body.Code != "CURRENCY_UNSUPPORTED" {
+		t.Errorf("Code = %q, want CURRENCY_UNSUPPORTED", body.Code)
+	}
+	if body.Error != "Currency not supported" {
+		t.Errorf("Error = %q, want %q", body.Error, "Currency not supported")
+	}
+	if body.Timestamp.IsZero() {
+		t.Error("Timestamp is zero")
+	}
+	if body.Debug != "" {
+		t.Errorf("Debug = %q, want empty by default", body.Debug)
+	}
+}
+
+func TestErrorBody_DebugErrors(t *testing.T) {
+	err := fmt.Errorf("dynamodb: %w", errors.New("connection reset"))
+
+	tests := []struct {
+		name        string
+		environment string
+		wantEnabled bool
+		wantDebug   string
+	}{
+		{"development includes details", "development", true, err.Error()},
+		{"staging includes details", "staging", true, err.Error()},
+		{"production strips details", "production", false, ""},
+		{"prod strips details", "prod", false, ""},
+		{"prod in any case strips details", "PROD", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENVIRONMENT", tt.environment)
+			t.Cleanup(func() { SetDebugErrors(false) })
+
+			if got := SetDebugErrors(true); got != tt.wantEnabled {
+				t.Errorf("SetDebugErrors(true) = %v, want %v", got, tt.wantEnabled)
+			}
+
+			body := ErrorBody(err)
+			if body.Debug != tt.wantDebug {
+				t.Errorf("Debug = %q, want %q", body.Debug, tt.wantDebug)
+			}
+			if body.Error != "An error occurred processing your request" {
+				t.Errorf("Error = %q, want generic message", body.Error)
+			}
+		})
+	}
+}
+
+func TestErrorBody_DebugErrorsProductionAfterEnable(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "development")
+	t.Cleanup(func() { SetDebugErrors(false) })
+	SetDebugErrors(true)
+
+	// Switching to production after enabling must still strip details.
+	t.Setenv("ENVIRONMENT", "production")
+	body := ErrorBody(errors.New("secret internal detail"))
+	if body.Debug != "" {
+		t.Errorf("Debug = %q, want empty in production", body.Debug)
+	}
+}
+
+func TestErrorBody_AvailableTargets(t *testing.T) {
+	err := fmt.Errorf("fetch rate: %w", &provider.UnsupportedTargetError{
+		Base:      "USD",
+		Target:    "XYZ",
+		Available: []string{"EUR", "GBP"},
+	})
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		wantDetails []string
+	}{
+		{"disabled omits details", false, nil},
+		{"enabled lists targets", true, []string{"EUR", "GBP"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { SetAvailableTargetsDetail(false) })
+			SetAvailableTargetsDetail(tt.enabled)
+
+			resp := ErrorResponse(err)
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusNotFound)
+			}
+
+			var body dto.ErrorResponse
+			if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if body.Code != "CURRENCY_UNSUPPORTED" {
+				t.Errorf("Code = %q, want CURRENCY_UNSUPPORTED", body.Code)
+			}
+			if tt.wantDetails == nil {
+				if body.Details != nil {
+					t.Errorf("Details = %+v, want nil", body.Details)
+				}
+				return
+			}
+			if body.Details == nil {
+				t.Fatal("Details = nil, want available targets")
+			}
+			if fmt.Sprint(body.Details.AvailableTargets) != fmt.Sprint(tt.wantDetails) {
+				t.Errorf("AvailableTargets = %v, want %v", body.Details.AvailableTargets, tt.wantDetails)
+			}
+		})
+	}
+}
+
 func TestSuccessResponse(t *testing.T) {
 	body := dto.RateResponse{
 		Base:      "USD",
@@ -130,7 +285,7 @@ func TestSuccessResponse(t *testing.T) {
 		Stale:     false,
 	}
 
-	resp := SuccessResponse(http.StatusOK, body)
+	resp := SuccessResponse(context.Background(), http.StatusOK, body)
 
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
@@ -158,7 +313,7 @@ func TestSuccessResponse(t *testing.T) {
 func TestSuccessResponse_DefaultStatusCode(t *testing.T) {
 	body := map[string]string{"message": "success"}
 
-	resp := SuccessResponse(0, body)
+	resp := SuccessResponse(context.Background(), 0, body)
 
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
@@ -169,7 +324,7 @@ func TestSuccessResponse_MarshalError(t *testing.T) {
 	// Create a body that cannot be marshaled
 	body := make(chan int)
 
-	resp := SuccessResponse(http.StatusOK, body)
+	resp := SuccessResponse(context.Background(), http.StatusOK, body)
 
 	// Should return error response
 	if resp.StatusCode == http.StatusOK {
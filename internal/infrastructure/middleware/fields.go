@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+)
+
+// rateFields are the JSON fields of dto.RateResponse that ?fields= may select.
+var rateFields = map[string]bool{
+	"base":        true,
+	"target":      true,
+	"rate":        true,
+	"bid":         true,
+	"ask":         true,
+	"timestamp":   true,
+	"age_seconds": true,
+	"stale":       true,
+	"derived":     true,
+	"approximate": true,
+}
+
+// ParseRateFields returns the sparse fieldset requested with ?fields=rate,timestamp.
+//
+// Field names are trimmed and lowercased; unknown names are ignored.
+// Returns nil (all fields) when the parameter is absent or names no known field.
+func ParseRateFields(event events.APIGatewayProxyRequest) []string {
+	raw, ok := event.QueryStringParameters["fields"]
+	if !ok {
+		return nil
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if rateFields[name] && !seen[name] {
+			seen[name] = true
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// filteredBody is a response body reduced to a sparse fieldset.
+// It marshals as data; response metadata is still built from original.
+type filteredBody struct {
+	data     interface{}
+	original interface{}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b filteredBody) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.data)
+}
+
+// FilterRateFields reduces the rates in body to fields, for SuccessResponse.
+//
+// This function:
+// - Keeps only fields of a dto.RateResponse
+// - Keeps only fields of each rate in a dto.RatesResponse; the other fields
+// of the response (base, timestamp, ...) are kept
+// - Returns body unchanged when fields is empty or body is another type
+//
+// Filtering marshals each rate to a map and drops the unrequested keys, so
+// the result follows the JSON tags of dto.RateResponse (omitted fields stay omitted).
+func FilterRateFields(body interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return body, nil
+	}
+
+	switch resp := body.(type) {
+	case dto.RateResponse:
+		data, err := selectFields(resp, fields)
+		if err != nil {
+			return nil, err
+		}
+		return filteredBody{data: data, original: body}, nil
+
+	case dto.RatesResponse:
+		data, err := selectFields(resp, nil)
+		if err != nil {
+			return nil, err
+		}
+		rates := make(map[string]map[string]json.RawMessage, len(resp.Rates))
+		for target, rate := range resp.Rates {
+			if rates[target], err = selectFields(rate, fields); err != nil {
+				return nil, err
+			}
+		}
+		if data["rates"], err = json.Marshal(rates); err != nil {
+			return nil, fmt.Errorf("failed to marshal filtered rates: %w", err)
+		}
+		return filteredBody{data: data, original: body}, nil
+	}
+	return body, nil
+}
+
+// selectFields marshals v to a map of its JSON fields and keeps only fields.
+// A nil fields keeps every field.
+func selectFields(v interface{}, fields []string) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, fmt.Errorf("failed to decode response fields: %w", err)
+	}
+	if fields == nil {
+		return all, nil
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+)
+
+func TestParseRateFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   []string
+	}{
+		{"no parameter", nil, nil},
+		{"empty", map[string]string{"fields": ""}, nil},
+		{"sparse fieldset", map[string]string{"fields": "rate,timestamp"}, []string{"rate", "timestamp"}},
+		{"trimmed and lowercased", map[string]string{"fields": " Rate , TIMESTAMP"}, []string{"rate", "timestamp"}},
+		{"unknown ignored", map[string]string{"fields": "rate,source,foo"}, []string{"rate"}},
+		{"only unknown", map[string]string{"fields": "source"}, nil},
+		{"duplicates", map[string]string{"fields": "rate,rate"}, []string{"rate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseRateFields(events.APIGatewayProxyRequest{QueryStringParameters: tt.params})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRateFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// decodeKeys returns the top-level JSON object of body.
+func decodeKeys(t *testing.T, body string) map[string]json.RawMessage {
+	t.Helper()
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &keys); err != nil {
+		t.Fatalf("failed to decode %s: %v", body, err)
+	}
+	return keys
+}
+
+func TestFilterRateFields(t *testing.T) {
+	rate := dto.RateResponse{Base: "USD", Target: "EUR", Rate: 0.85, Timestamp: time.Now(), Source: dto.SourceCache}
+
+	t.Run("rate", func(t *testing.T) {
+		body, err := FilterRateFields(rate, []string{"rate", "timestamp"})
+		if err != nil {
+			t.Fatalf("FilterRateFields() error = %v", err)
+		}
+		keys := decodeKeys(t, SuccessResponse(context.Background(), http.StatusOK, body).Body)
+		if len(keys) != 2 || keys["rate"] == nil || keys["timestamp"] == nil {
+			t.Errorf("fields = %v, want rate and timestamp", keys)
+		}
+	})
+
+	t.Run("rates", func(t *testing.T) {
+		rates := dto.RatesResponse{Base: "USD", Rates: map[string]dto.RateResponse{"EUR": rate}, Timestamp: time.Now()}
+		body, err := FilterRateFields(rates, []string{"rate"})
+		if err != nil {
+			t.Fatalf("FilterRateFields() error = %v", err)
+		}
+		keys := decodeKeys(t, SuccessResponse(context.Background(), http.StatusOK, body).Body)
+		if string(keys["base"]) != `"USD"` {
+			t.Errorf("base = %s, want \"USD\"", keys["base"])
+		}
+		if got := decodeKeys(t, string(keys["rates"]))["EUR"]; string(got) != `{"rate":0.85}` {
+			t.Errorf("rates.EUR = %s, want {\"rate\":0.85}", got)
+		}
+	})
+
+	t.Run("no fields", func(t *testing.T) {
+		body, err := FilterRateFields(rate, nil)
+		if err != nil {
+			t.Fatalf("FilterRateFields() error = %v", err)
+		}
+		if _, ok := body.(dto.RateResponse); !ok {
+			t.Errorf("body = %T, want the unchanged dto.RateResponse", body)
+		}
+	})
+
+	t.Run("envelope keeps metadata", func(t *testing.T) {
+		enableEnvelope(t)
+		body, err := FilterRateFields(rate, []string{"rate"})
+		if err != nil {
+			t.Fatalf("FilterRateFields() error = %v", err)
+		}
+		var envelope struct {
+			Data map[string]json.RawMessage `json:"data"`
+			Meta ResponseMeta               `json:"meta"`
+		}
+		if err := json.Unmarshal([]byte(SuccessResponse(context.Background(), http.StatusOK, body).Body), &envelope); err != nil {
+			t.Fatalf("failed to decode envelope: %v", err)
+		}
+		if len(envelope.Data) != 1 || envelope.Meta.Source != dto.SourceCache {
+			t.Errorf("envelope = %+v, want one data field and source %q", envelope, dto.SourceCache)
+		}
+	})
+}
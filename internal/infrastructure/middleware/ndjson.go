@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+)
+
+// Response formats accepted by ?format=.
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// ParseResponseFormat returns the response format requested with ?format=.
+//
+// The value is trimmed and lowercased. Returns FormatJSON when the parameter
+// is absent or empty, and ErrInvalidFormat for any other unknown format.
+func ParseResponseFormat(event events.APIGatewayProxyRequest) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(event.QueryStringParameters["format"]))
+	switch format {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	}
+	return "", fmt.Errorf("%w: %q (supported: %s, %s)", ErrInvalidFormat, format, FormatJSON, FormatNDJSON)
+}
+
+// NDJSONResponse creates a newline-delimited JSON response for API Gateway.
+//
+// This function:
+// - Writes one JSON rate object per line, ordered by target currency
+// - Keeps only fields of each rate, if given (see ParseRateFields)
+// - Sets Content-Type to application/x-ndjson and adds security headers
+//
+// The response envelope and ?pretty=true do not apply: each line must be a
+// complete JSON value, and the rates already carry their base and timestamp.
+func NDJSONResponse(resp dto.RatesResponse, fields []string) events.APIGatewayProxyResponse {
+	targets := make([]string, 0, len(resp.Rates))
+	for target := range resp.Rates {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var body strings.Builder
+	for _, target := range targets {
+		var line []byte
+		var err error
+		if len(fields) == 0 {
+			line, err = json.Marshal(resp.Rates[target])
+		} else {
+			var selected map[string]json.RawMessage
+			if selected, err = selectFields(resp.Rates[target], fields); err == nil {
+				line, err = json.Marshal(selected)
+			}
+		}
+		if err != nil {
+			return ErrorResponse(fmt.Errorf("failed to marshal response: %w", err))
+		}
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/x-ndjson",
+	}
+	for key, value := range SecurityHeaders() {
+		headers[key] = value
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Body:       body.String(),
+		Headers:    headers,
+	}
+}
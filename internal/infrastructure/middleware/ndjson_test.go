@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+)
+
+func TestParseResponseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"no parameter", nil, FormatJSON, false},
+		{"empty", map[string]string{"format": ""}, FormatJSON, false},
+		{"json", map[string]string{"format": "json"}, FormatJSON, false},
+		{"ndjson", map[string]string{"format": "ndjson"}, FormatNDJSON, false},
+		{"trimmed and lowercased", map[string]string{"format": " NDJSON "}, FormatNDJSON, false},
+		{"unknown", map[string]string{"format": "xml"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResponseFormat(events.APIGatewayProxyRequest{QueryStringParameters: tt.params})
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFormat) {
+					t.Errorf("ParseResponseFormat() error = %v, want ErrInvalidFormat", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseResponseFormat() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNDJSONResponse(t *testing.T) {
+	now := time.Now()
+	rates := dto.RatesResponse{
+		Base: "USD",
+		Rates: map[string]dto.RateResponse{
+			"GBP": {Base: "USD", Target: "GBP", Rate: 0.75, Timestamp: now},
+			"EUR": {Base: "USD", Target: "EUR", Rate: 0.85, Timestamp: now},
+			"JPY": {Base: "USD", Target: "JPY", Rate: 150, Timestamp: now},
+		},
+		Timestamp: now,
+	}
+
+	t.Run("one rate per line", func(t *testing.T) {
+		resp := NDJSONResponse(rates, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+		if got := resp.Headers["Content-Type"]; got != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(resp.Body, "\n"), "\n")
+		if len(lines) != len(rates.Rates) {
+			t.Fatalf("got %d lines, want %d: %q", len(lines), len(rates.Rates), resp.Body)
+		}
+		for i, want := range []string{"EUR", "GBP", "JPY"} {
+			keys := decodeKeys(t, lines[i])
+			if string(keys["target"]) != `"`+want+`"` || string(keys["base"]) != `"USD"` || keys["rate"] == nil {
+				t.Errorf("line %d = %s, want the USD/%s rate", i, lines[i], want)
+			}
+		}
+	})
+
+	t.Run("sparse fieldset", func(t *testing.T) {
+		resp := NDJSONResponse(rates, []string{"target", "rate"})
+		lines := strings.Split(strings.TrimSuffix(resp.Body, "\n"), "\n")
+		if len(lines) != len(rates.Rates) {
+			t.Fatalf("got %d lines, want %d", len(lines), len(rates.Rates))
+		}
+		if lines[0] != `{"rate":0.85,"target":"EUR"}` {
+			t.Errorf("line 0 = %s, want {\"rate\":0.85,\"target\":\"EUR\"}", lines[0])
+		}
+	})
+
+	t.Run("no rates", func(t *testing.T) {
+		if resp := NDJSONResponse(dto.RatesResponse{Base: "USD"}, nil); resp.Body != "" {
+			t.Errorf("body = %q, want empty", resp.Body)
+		}
+	})
+}
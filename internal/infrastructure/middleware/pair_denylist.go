@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+// pairWildcard matches any currency code on one side of a denied pair.
+const pairWildcard = "*"
+
+// deniedPair is a parsed denylist entry. Either side may be pairWildcard.
+type deniedPair struct {
+	base   string
+	target string
+}
+
+// matches reports whether the entry denies base/target.
+func (p deniedPair) matches(base, target entity.CurrencyCode) bool {
+	return (p.base == pairWildcard || p.base == base.Normalize().String()) &&
+		(p.target == pairWildcard || p.target == target.Normalize().String())
+}
+
+// PairDenylist blocks currency pairs from being served (e.g. sanctioned currencies).
+//
+// Entries have the form "BASE/TARGET", where either side may be "*":
+// - "USD/RUB" denies exactly USD to RUB
+// - "*/RUB" denies every pair with RUB as target
+// - "RUB/*" denies every pair with RUB as base, including GET /rates/RUB
+//
+// A nil *PairDenylist allows every pair.
+type PairDenylist struct {
+	pairs []deniedPair
+}
+
+// NewPairDenylist parses denylist entries once, at startup.
+//
+// Entries are trimmed and uppercased; empty entries are ignored.
+// Returns an error if an entry is not "BASE/TARGET" with valid currency codes or "*".
+func NewPairDenylist(entries []string) (*PairDenylist, error) {
+	denylist := &PairDenylist{}
+	for _, entry := range entries {
+		entry = strings.ToUpper(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		base, target, ok := strings.Cut(entry, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid denied pair %q: want BASE/TARGET", entry)
+		}
+		pair := deniedPair{base: strings.TrimSpace(base), target: strings.TrimSpace(target)}
+		for _, code := range []string{pair.base, pair.target} {
+			if code == pairWildcard {
+				continue
+			}
+			if _, err := entity.NewCurrencyCode(code); err != nil {
+				return nil, fmt.Errorf("invalid denied pair %q: %w", entry, err)
+			}
+		}
+		denylist.pairs = append(denylist.pairs, pair)
+	}
+	return denylist, nil
+}
+
+// Len returns the number of denylist entries.
+func (d *PairDenylist) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.pairs)
+}
+
+// CheckPair returns entity.ErrPairDenied if base/target is denied.
+func (d *PairDenylist) CheckPair(base, target entity.CurrencyCode) error {
+	if d == nil {
+		return nil
+	}
+	for _, pair := range d.pairs {
+		if pair.matches(base, target) {
+			return fmt.Errorf("%w: %s/%s", entity.ErrPairDenied, base, target)
+		}
+	}
+	return nil
+}
+
+// CheckBase returns entity.ErrPairDenied if every pair with this base is denied
+// (an entry of the form "BASE/*" or "*/*").
+func (d *PairDenylist) CheckBase(base entity.CurrencyCode) error {
+	if d == nil {
+		return nil
+	}
+	for _, pair := range d.pairs {
+		if pair.target == pairWildcard && (pair.base == pairWildcard || pair.base == base.Normalize().String()) {
+			return fmt.Errorf("%w: %s/*", entity.ErrPairDenied, base)
+		}
+	}
+	return nil
+}
+
+// FilterRates removes denied pairs from a rates response.
+// The response is returned unchanged if nothing is denied.
+func (d *PairDenylist) FilterRates(resp dto.RatesResponse) dto.RatesResponse {
+	if d.Len() == 0 {
+		return resp
+	}
+
+	base := entity.CurrencyCode(resp.Base)
+	rates := make(map[string]dto.RateResponse, len(resp.Rates))
+	for target, rate := range resp.Rates {
+		if d.CheckPair(base, entity.CurrencyCode(target)) == nil {
+			rates[target] = rate
+		}
+	}
+	resp.Rates = rates
+	return resp
+}
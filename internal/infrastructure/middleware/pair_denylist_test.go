@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+)
+
+func TestPairDenylist_CheckPair(t *testing.T) {
+	denylist, err := NewPairDenylist([]string{"USD/RUB", "*/KPW", "irr/*"})
+	if err != nil {
+		t.Fatalf("NewPairDenylist() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		base       entity.CurrencyCode
+		target     entity.CurrencyCode
+		wantDenied bool
+	}{
+		{"exact match", "USD", "RUB", true},
+		{"exact match reversed is allowed", "RUB", "USD", false},
+		{"wildcard base", "EUR", "KPW", true},
+		{"wildcard target", "IRR", "EUR", true},
+		{"unnormalized code", "usd", "rub", true},
+		{"allowed pair", "USD", "EUR", false},
+		{"allowed pair sharing base", "USD", "GBP", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := denylist.CheckPair(tt.base, tt.target)
+			if denied := errors.Is(err, entity.ErrPairDenied); denied != tt.wantDenied {
+				t.Errorf("CheckPair(%s, %s) error = %v, want denied = %v", tt.base, tt.target, err, tt.wantDenied)
+			}
+		})
+	}
+}
+
+func TestPairDenylist_CheckBase(t *testing.T) {
+	denylist, err := NewPairDenylist([]string{"USD/RUB", "*/KPW", "IRR/*"})
+	if err != nil {
+		t.Fatalf("NewPairDenylist() error = %v", err)
+	}
+
+	if err := denylist.CheckBase("IRR"); !errors.Is(err, entity.ErrPairDenied) {
+		t.Errorf("CheckBase(IRR) error = %v, want ErrPairDenied", err)
+	}
+	// Bases with only some denied targets are served, with those targets filtered
+	for _, base := range []entity.CurrencyCode{"USD", "KPW", "EUR"} {
+		if err := denylist.CheckBase(base); err != nil {
+			t.Errorf("CheckBase(%s) error = %v, want nil", base, err)
+		}
+	}
+}
+
+func TestPairDenylist_FilterRates(t *testing.T) {
+	denylist, err := NewPairDenylist([]string{"USD/RUB", "*/KPW"})
+	if err != nil {
+		t.Fatalf("NewPairDenylist() error = %v", err)
+	}
+
+	resp := denylist.FilterRates(dto.RatesResponse{
+		Base: "USD",
+		Rates: map[string]dto.RateResponse{
+			"EUR": {Base: "USD", Target: "EUR", Rate: 0.85},
+			"RUB": {Base: "USD", Target: "RUB", Rate: 90},
+			"KPW": {Base: "USD", Target: "KPW", Rate: 900},
+		},
+	})
+
+	if len(resp.Rates) != 1 {
+		t.Fatalf("FilterRates() kept %d rates, want 1: %v", len(resp.Rates), resp.Rates)
+	}
+	if _, ok := resp.Rates["EUR"]; !ok {
+		t.Errorf("FilterRates() dropped allowed pair USD/EUR")
+	}
+}
+
+func TestPairDenylist_Nil(t *testing.T) {
+	var denylist *PairDenylist
+
+	if err := denylist.CheckPair("USD", "RUB"); err != nil {
+		t.Errorf("nil CheckPair() error = %v, want nil", err)
+	}
+	if err := denylist.CheckBase("USD"); err != nil {
+		t.Errorf("nil CheckBase() error = %v, want nil", err)
+	}
+	resp := denylist.FilterRates(dto.RatesResponse{Rates: map[string]dto.RateResponse{"RUB": {}}})
+	if len(resp.Rates) != 1 {
+		t.Errorf("nil FilterRates() kept %d rates, want 1", len(resp.Rates))
+	}
+}
+
+func TestNewPairDenylist_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+	}{
+		{"missing separator", []string{"USDRUB"}},
+		{"invalid base", []string{"DOLLAR/RUB"}},
+		{"invalid target", []string{"USD/R"}},
+		{"empty side", []string{"USD/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPairDenylist(tt.entries); err == nil {
+				t.Errorf("NewPairDenylist(%v) error = nil, want error", tt.entries)
+			}
+		})
+	}
+}
+
+func TestNewPairDenylist_IgnoresEmptyEntries(t *testing.T) {
+	denylist, err := NewPairDenylist([]string{"", "  ", "USD/RUB"})
+	if err != nil {
+		t.Fatalf("NewPairDenylist() error = %v", err)
+	}
+	if denylist.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", denylist.Len())
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// prettyJSONDisabled makes WithPrettyJSON ignore ?pretty=true.
+var prettyJSONDisabled atomic.Bool
+
+// SetPrettyJSONDisabled disables or re-enables indented JSON bodies (DISABLE_PRETTY_JSON).
+//
+// Pretty-printing only changes formatting; deployments that do not want clients
+// to request larger bodies can turn it off. This function is thread-safe.
+func SetPrettyJSONDisabled(disabled bool) {
+	prettyJSONDisabled.Store(disabled)
+}
+
+// prettyJSONKey is the context key marking requests that asked for indented JSON.
+type prettyJSONKey struct{}
+
+// WithPrettyJSON returns ctx marked for indented JSON when the request has
+// ?pretty=true (any value accepted by strconv.ParseBool) and pretty-printing
+// is not disabled. SuccessResponse then indents the body.
+func WithPrettyJSON(ctx context.Context, event events.APIGatewayProxyRequest) context.Context {
+	if prettyJSONDisabled.Load() {
+		return ctx
+	}
+	pretty, err := strconv.ParseBool(event.QueryStringParameters["pretty"])
+	if err != nil || !pretty {
+		return ctx
+	}
+	return context.WithValue(ctx, prettyJSONKey{}, true)
+}
+
+// prettyJSONRequested reports whether ctx was marked by WithPrettyJSON.
+func prettyJSONRequested(ctx context.Context) bool {
+	pretty, _ := ctx.Value(prettyJSONKey{}).(bool)
+	return pretty
+}
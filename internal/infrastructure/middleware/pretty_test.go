@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/application/dto"
+)
+
+func TestSuccessResponse_PrettyJSON(t *testing.T) {
+	body := dto.RateResponse{Base: "USD", Target: "EUR", Rate: 0.85}
+
+	tests := []struct {
+		name       string
+		query      map[string]string
+		disabled   bool
+		wantPretty bool
+	}{
+		{"compact by default", nil, false, false},
+		{"pretty requested", map[string]string{"pretty": "true"}, false, true},
+		{"pretty=1", map[string]string{"pretty": "1"}, false, true},
+		{"pretty=false", map[string]string{"pretty": "false"}, false, false},
+		{"invalid value", map[string]string{"pretty": "yes please"}, false, false},
+		{"disabled", map[string]string{"pretty": "true"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPrettyJSONDisabled(tt.disabled)
+			t.Cleanup(func() { SetPrettyJSONDisabled(false) })
+
+			ctx := WithPrettyJSON(context.Background(), events.APIGatewayProxyRequest{QueryStringParameters: tt.query})
+			resp := SuccessResponse(ctx, http.StatusOK, body)
+
+			pretty := strings.Contains(resp.Body, "\n  \"base\": \"USD\"")
+			if pretty != tt.wantPretty {
+				t.Errorf("indented = %v, want %v (body: %s)", pretty, tt.wantPretty, resp.Body)
+			}
+			if !tt.wantPretty && strings.ContainsAny(resp.Body, "\n ") {
+				t.Errorf("body = %s, want compact JSON", resp.Body)
+			}
+		})
+	}
+}
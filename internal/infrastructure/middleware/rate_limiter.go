@@ -2,10 +2,19 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
 )
 
 // ErrRateLimitExceeded is returned when the rate limit is exceeded.
@@ -17,24 +26,90 @@ type RateLimiterConfig struct {
 	RequestsPerMinute int
 	// BurstSize is the maximum burst size (defaults to RequestsPerMinute if 0).
 	BurstSize int
+	// AnonRequestsPerMinute is the maximum number of requests allowed per minute for
+	// callers without an API key, keyed by client IP (defaults to RequestsPerMinute if 0).
+	AnonRequestsPerMinute int
+	// AnonBurstSize is the maximum burst size for anonymous callers
+	// (defaults to AnonRequestsPerMinute if 0, or BurstSize if both are 0).
+	AnonBurstSize int
+	// IdleTTL is how long a client's bucket is kept without requests before it is
+	// evicted (defaults to 10 minutes if 0). A bucket idle this long would have
+	// refilled anyway, so eviction does not change the limits a client sees.
+	IdleTTL time.Duration
+	// Tiers are the limits of named API key tiers (e.g. "free", "pro"), selected
+	// by the tier of a verified key in the API key secret (optional). Keys without
+	// a tier, or with a tier not listed here, use RequestsPerMinute/BurstSize.
+	Tiers map[string]TierLimits
+	// ExemptKeys are API keys that are never rate limited, e.g. for internal
+	// monitoring (optional). Keys are compared in constant time.
+	ExemptKeys []string
 	// Enabled controls whether rate limiting is active.
 	Enabled bool
 }
 
+// TierLimits holds the sustained rate and burst size of an API key tier.
+type TierLimits struct {
+	// RequestsPerMinute is the sustained rate of the tier.
+	RequestsPerMinute int
+	// BurstSize is the maximum burst size (defaults to RequestsPerMinute if 0).
+	BurstSize int
+}
+
+// ParseTierLimits parses tier limits written as NAME:REQUESTS_PER_MINUTE[:BURST]
+// entries separated by commas, e.g. "free:60:10,pro:600:100".
+//
+// Returns an error if an entry is malformed, a limit is not a positive integer,
+// or a tier is listed twice. An empty spec returns no tiers.
+func ParseTierLimits(spec string) (map[string]TierLimits, error) {
+	tiers := make(map[string]TierLimits)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		name := strings.TrimSpace(parts[0])
+		if len(parts) < 2 || len(parts) > 3 || name == "" {
+			return nil, fmt.Errorf("rate limit tier %q must be NAME:REQUESTS_PER_MINUTE[:BURST]", entry)
+		}
+		if _, exists := tiers[name]; exists {
+			return nil, fmt.Errorf("rate limit tier %q is listed twice", name)
+		}
+
+		var limits TierLimits
+		for i, target := range []*int{&limits.RequestsPerMinute, &limits.BurstSize}[:len(parts)-1] {
+			value, err := strconv.Atoi(strings.TrimSpace(parts[i+1]))
+			if err != nil || value <= 0 {
+				return nil, fmt.Errorf("rate limit tier %q: %q is not a positive integer", name, parts[i+1])
+			}
+			*target = value
+		}
+		tiers[name] = limits
+	}
+	return tiers, nil
+}
+
 // DefaultRateLimiterConfig returns a default rate limiter configuration.
 func DefaultRateLimiterConfig() RateLimiterConfig {
 	return RateLimiterConfig{
-		RequestsPerMinute: 100,
-		BurstSize:         10,
-		Enabled:           true,
+		RequestsPerMinute:     100,
+		BurstSize:             10,
+		AnonRequestsPerMinute: 30,
+		AnonBurstSize:         5,
+		IdleTTL:               defaultBucketIdleTTL,
+		Enabled:               true,
 	}
 }
 
+// defaultBucketIdleTTL is how long an unused bucket is kept when IdleTTL is not set.
+const defaultBucketIdleTTL = 10 * time.Minute
+
 // tokenBucket represents a token bucket for rate limiting.
 type tokenBucket struct {
 	capacity   int       // Maximum tokens
 	tokens     int       // Current tokens
 	lastRefill time.Time // Last time tokens were refilled
+	lastUsed   time.Time // Last time a token was requested
 	refillRate float64   // Tokens per second
 	mu         sync.Mutex
 }
@@ -45,6 +120,7 @@ func newTokenBucket(capacity int, refillRate float64) *tokenBucket {
 		capacity:   capacity,
 		tokens:     capacity, // Start with full bucket
 		lastRefill: time.Now(),
+		lastUsed:   time.Now(),
 		refillRate: refillRate,
 	}
 }
@@ -57,6 +133,7 @@ func (tb *tokenBucket) take() bool {
 
 	now := time.Now()
 	elapsed := now.Sub(tb.lastRefill).Seconds()
+	tb.lastUsed = now
 
 	// Refill tokens based on elapsed time
 	tokensToAdd := int(elapsed * tb.refillRate)
@@ -74,43 +151,152 @@ func (tb *tokenBucket) take() bool {
 	return false
 }
 
+// idleSince reports whether the bucket has not been used since cutoff.
+func (tb *tokenBucket) idleSince(cutoff time.Time) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.lastUsed.Before(cutoff)
+}
+
+// retryAfter returns how long until the next token is available.
+// Returns 0 if a token is available now.
+func (tb *tokenBucket) retryAfter() time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	if tb.tokens > 0 || tb.refillRate <= 0 {
+		return 0
+	}
+	next := tb.lastRefill.Add(time.Duration(float64(time.Second) / tb.refillRate))
+	if wait := time.Until(next); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
 // RateLimiter implements rate limiting using token bucket algorithm.
 type RateLimiter struct {
-	buckets map[string]*tokenBucket
-	config  RateLimiterConfig
-	mu      sync.RWMutex
-	cleanup *time.Ticker
+	buckets  map[string]*tokenBucket
+	settings atomic.Pointer[rateLimiterSettings] // Swapped by UpdateConfig
+	mu       sync.RWMutex
+	cleanup  *time.Ticker
+
+	done     chan struct{} // closed by Stop to end the cleanup goroutine
+	stopOnce sync.Once
+}
+
+// rateLimiterSettings is a normalized RateLimiterConfig with its exempt key digests.
+type rateLimiterSettings struct {
+	config RateLimiterConfig
+	exempt [][sha256.Size]byte // SHA-256 digests of config.ExemptKeys
 }
 
 // NewRateLimiter creates a new rate limiter.
 func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
-	if config.BurstSize == 0 {
-		config.BurstSize = config.RequestsPerMinute
-	}
-
 	rl := &RateLimiter{
 		buckets: make(map[string]*tokenBucket),
-		config:  config,
+		done:    make(chan struct{}),
 	}
+	rl.settings.Store(newRateLimiterSettings(config))
 
-	// Start cleanup goroutine to remove old buckets (every 5 minutes)
+	// Start cleanup goroutine to remove idle buckets (every 5 minutes)
 	rl.cleanup = time.NewTicker(5 * time.Minute)
 	go rl.cleanupBuckets()
 
 	return rl
 }
 
-// cleanupBuckets periodically removes old buckets to prevent memory leaks.
+// UpdateConfig replaces the limiter's configuration, e.g. on a config reload.
+//
+// The new limits apply to the next request: existing buckets are dropped, so
+// every client starts again with a full bucket of its new burst size.
+// This method is thread-safe.
+func (rl *RateLimiter) UpdateConfig(config RateLimiterConfig) {
+	settings := newRateLimiterSettings(config)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.settings.Store(settings)
+	rl.buckets = make(map[string]*tokenBucket)
+}
+
+// current returns the limiter's current settings.
+func (rl *RateLimiter) current() *rateLimiterSettings {
+	return rl.settings.Load()
+}
+
+// newRateLimiterSettings applies the defaults of RateLimiterConfig to config.
+func newRateLimiterSettings(config RateLimiterConfig) *rateLimiterSettings {
+	if config.BurstSize == 0 {
+		config.BurstSize = config.RequestsPerMinute
+	}
+	if config.AnonRequestsPerMinute == 0 {
+		config.AnonRequestsPerMinute = config.RequestsPerMinute
+		if config.AnonBurstSize == 0 {
+			config.AnonBurstSize = config.BurstSize
+		}
+	}
+	if config.AnonBurstSize == 0 {
+		config.AnonBurstSize = config.AnonRequestsPerMinute
+	}
+	if config.IdleTTL <= 0 {
+		config.IdleTTL = defaultBucketIdleTTL
+	}
+	tiers := make(map[string]TierLimits, len(config.Tiers))
+	for name, tier := range config.Tiers {
+		if tier.BurstSize == 0 {
+			tier.BurstSize = tier.RequestsPerMinute
+		}
+		tiers[name] = tier
+	}
+	config.Tiers = tiers
+
+	settings := &rateLimiterSettings{config: config}
+	for _, key := range config.ExemptKeys {
+		if key != "" {
+			settings.exempt = append(settings.exempt, sha256.Sum256([]byte(key)))
+		}
+	}
+	return settings
+}
+
+// cleanupBuckets periodically removes idle buckets to prevent memory leaks.
 func (rl *RateLimiter) cleanupBuckets() {
-	for range rl.cleanup.C {
-		rl.mu.Lock()
-		// In a production system, you might want to track last access time
-		// and remove buckets that haven't been accessed in a while.
-		// For simplicity, we'll keep all buckets here.
-		rl.mu.Unlock()
+	for {
+		select {
+		case <-rl.cleanup.C:
+			rl.evictIdle(time.Now().Add(-rl.current().config.IdleTTL))
+		case <-rl.done:
+			return
+		}
 	}
 }
 
+// evictIdle removes buckets that have not been used since cutoff.
+// Returns the number of evicted buckets.
+func (rl *RateLimiter) evictIdle(cutoff time.Time) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	evicted := 0
+	for key, bucket := range rl.buckets {
+		if bucket.idleSince(cutoff) {
+			delete(rl.buckets, key)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// Stop stops the cleanup ticker and its goroutine.
+// It is safe to call Stop more than once; Allow keeps working after Stop.
+func (rl *RateLimiter) Stop() {
+	rl.stopOnce.Do(func() {
+		rl.cleanup.Stop()
+		close(rl.done)
+	})
+}
+
 // Allow checks if a request is allowed for the given key.
 //
 // Returns:
@@ -118,7 +304,59 @@ func (rl *RateLimiter) cleanupBuckets() {
 // - false if the rate limit is exceeded
 // - error if rate limiting is disabled or key is empty
 func (rl *RateLimiter) Allow(ctx context.Context, key string) (bool, error) {
-	if !rl.config.Enabled {
+	config := rl.current().config
+	return rl.allow(key, config.RequestsPerMinute, config.BurstSize)
+}
+
+// IsExempt reports whether apiKey is one of the configured ExemptKeys.
+//
+// Security: the key is compared against every exempt key in constant time,
+// on SHA-256 digests so neither the position of a match nor key lengths leak
+// through timing. A nil limiter or an empty key is never exempt.
+func (rl *RateLimiter) IsExempt(apiKey string) bool {
+	if rl == nil || apiKey == "" {
+		return false
+	}
+	settings := rl.current()
+	if len(settings.exempt) == 0 {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(apiKey))
+	match := 0
+	for _, exempt := range settings.exempt {
+		match |= subtle.ConstantTimeCompare(digest[:], exempt[:])
+	}
+	return match == 1
+}
+
+// AllowClient checks if a request is allowed for the given client identity.
+//
+// Authenticated (API key) callers are limited by the limits of their tier
+// (see RateLimiterConfig.Tiers) or RequestsPerMinute/BurstSize, anonymous
+// callers by AnonRequestsPerMinute/AnonBurstSize.
+//
+// Returns the same values as Allow.
+func (rl *RateLimiter) AllowClient(ctx context.Context, identity ClientIdentity) (bool, error) {
+	requestsPerMinute, burstSize := rl.limits(identity)
+	return rl.allow(identity.Key(), requestsPerMinute, burstSize)
+}
+
+// limits returns the per-minute rate and burst size for a client identity's tier.
+func (rl *RateLimiter) limits(identity ClientIdentity) (requestsPerMinute, burstSize int) {
+	config := rl.current().config
+	if identity.Authenticated() {
+		if tier, ok := config.Tiers[identity.Tier]; ok && identity.Tier != "" {
+			return tier.RequestsPerMinute, tier.BurstSize
+		}
+		return config.RequestsPerMinute, config.BurstSize
+	}
+	return config.AnonRequestsPerMinute, config.AnonBurstSize
+}
+
+// allow takes a token from the bucket for key, creating it with the given limits if needed.
+func (rl *RateLimiter) allow(key string, requestsPerMinute, burstSize int) (bool, error) {
+	if !rl.current().config.Enabled {
 		return true, nil
 	}
 
@@ -131,8 +369,8 @@ func (rl *RateLimiter) Allow(ctx context.Context, key string) (bool, error) {
 	bucket, exists := rl.buckets[key]
 	if !exists {
 		// Calculate refill rate (tokens per second)
-		refillRate := float64(rl.config.RequestsPerMinute) / 60.0
-		bucket = newTokenBucket(rl.config.BurstSize, refillRate)
+		refillRate := float64(requestsPerMinute) / 60.0
+		bucket = newTokenBucket(burstSize, refillRate)
 		rl.buckets[key] = bucket
 	}
 	rl.mu.Unlock()
@@ -148,7 +386,8 @@ func (rl *RateLimiter) Allow(ctx context.Context, key string) (bool, error) {
 // GetRemainingRequests returns the estimated number of remaining requests for a key.
 // This is approximate and may not be exact due to concurrent access.
 func (rl *RateLimiter) GetRemainingRequests(key string) int {
-	if !rl.config.Enabled || key == "" {
+	config := rl.current().config
+	if !config.Enabled || key == "" {
 		return -1 // Unknown
 	}
 
@@ -157,7 +396,7 @@ func (rl *RateLimiter) GetRemainingRequests(key string) int {
 	rl.mu.RUnlock()
 
 	if !exists {
-		return rl.config.BurstSize
+		return config.BurstSize
 	}
 
 	bucket.mu.Lock()
@@ -175,6 +414,94 @@ func (rl *RateLimiter) GetRemainingRequests(key string) int {
 	return bucket.tokens
 }
 
+// RetryAfter returns how long a client should wait before its next request is allowed.
+// Returns 0 if the key has no bucket or tokens are available.
+func (rl *RateLimiter) RetryAfter(key string) time.Duration {
+	rl.mu.RLock()
+	bucket, exists := rl.buckets[key]
+	rl.mu.RUnlock()
+
+	if !exists {
+		return 0
+	}
+	return bucket.retryAfter()
+}
+
+// WithRateLimit applies the rate limiter to a request before running fn.
+//
+// This function:
+// - Skips limiting for requests carrying one of the limiter's ExemptKeys
+// - Resolves the client identity (API key, client IP, or fallback header)
+// - Applies the authenticated limit only if authenticator verifies the API key;
+// an unverified key is ignored and the caller is limited by IP (or fallback header)
+// - Falls back to the request ID as key if no identity can be resolved (anonymous tier)
+// - Returns a 429 response with Retry-After and X-RateLimit-* headers on rejection
+//
+// Verifying here matters because the limiter runs before the route handlers
+// authenticate: otherwise a random key per request would buy a fresh bucket in
+// the higher tier. If authenticator is nil, every caller is anonymous.
+// If limiter is nil, fn is executed directly.
+func WithRateLimit(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+	limiter *RateLimiter,
+	resolver *ClientIdentifierResolver,
+	authenticator *APIKeyAuthenticator,
+	log *logger.Logger,
+	fn func() events.APIGatewayProxyResponse,
+) events.APIGatewayProxyResponse {
+	if limiter == nil {
+		return fn()
+	}
+	if apiKey, err := ExtractAPIKey(event); err == nil && limiter.IsExempt(apiKey) {
+		return fn()
+	}
+
+	identity := resolver.VerifiedIdentifier(ctx, event, authenticator)
+	if identity.Key() == "" {
+		identity = ClientIdentity{Source: ClientIdentitySourceRequestID, Value: ExtractOrGenerateRequestID(event)}
+	}
+
+	allowed, err := limiter.AllowClient(ctx, identity)
+	if err == nil && allowed {
+		return fn()
+	}
+	if err == nil {
+		err = ErrRateLimitExceeded
+	}
+
+	if log == nil {
+		log = logger.NewFromEnv()
+	}
+	log.LogError(ctx, err, "rate limit exceeded",
+		"rate_limit_key", logger.MaskAPIKey(identity.Key()),
+		"authenticated", identity.Authenticated(),
+	)
+
+	requestsPerMinute, _ := limiter.limits(identity)
+	return RateLimitResponse(requestsPerMinute, limiter.RetryAfter(identity.Key()))
+}
+
+// RateLimitResponse creates a 429 Too Many Requests response.
+//
+// Headers:
+// - Retry-After: Seconds until the next request is allowed (at least 1)
+// - X-RateLimit-Limit: Requests allowed per minute for the caller's tier
+// - X-RateLimit-Remaining: 0
+func RateLimitResponse(requestsPerMinute int, retryAfter time.Duration) events.APIGatewayProxyResponse {
+	resp := ErrorResponse(ErrRateLimitExceeded)
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	resp.Headers["Retry-After"] = strconv.Itoa(seconds)
+	resp.Headers["X-RateLimit-Limit"] = strconv.Itoa(requestsPerMinute)
+	resp.Headers["X-RateLimit-Remaining"] = "0"
+
+	return resp
+}
+
 // min returns the minimum of two integers.
 func min(a, b int) int {
 	if a < b {
@@ -182,5 +509,3 @@ func min(a, b int) int {
 	}
 	return b
 }
-
-
@@ -2,9 +2,16 @@ package middleware
 
 import (
 	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
 )
 
 func TestTokenBucket_Take(t *testing.T) {
@@ -200,7 +207,8 @@ func TestRateLimiter_GetRemainingRequests(t *testing.T) {
 	}
 
 	// Disabled limiter should return -1
-	limiter.config.Enabled = false
+	config.Enabled = false
+	limiter.UpdateConfig(config)
 	remaining = limiter.GetRemainingRequests(key)
 	if remaining != -1 {
 		t.Errorf("expected -1 for disabled limiter, got %d", remaining)
@@ -216,7 +224,451 @@ func TestDefaultRateLimiterConfig(t *testing.T) {
 	if config.BurstSize != 10 {
 		t.Errorf("expected BurstSize to be 10, got %d", config.BurstSize)
 	}
+	if config.AnonRequestsPerMinute != 30 {
+		t.Errorf("expected AnonRequestsPerMinute to be 30, got %d", config.AnonRequestsPerMinute)
+	}
+	if config.AnonBurstSize != 5 {
+		t.Errorf("expected AnonBurstSize to be 5, got %d", config.AnonBurstSize)
+	}
 	if !config.Enabled {
 		t.Error("expected Enabled to be true")
 	}
 }
+
+func TestRateLimiter_AllowClient_Tiers(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Enabled:               true,
+		RequestsPerMinute:     60,
+		BurstSize:             5,
+		AnonRequestsPerMinute: 6,
+		AnonBurstSize:         2,
+	})
+	defer limiter.cleanup.Stop()
+
+	tests := []struct {
+		name     string
+		identity ClientIdentity
+		allowed  int
+	}{
+		{"authenticated", ClientIdentity{Source: ClientIdentitySourceAPIKey, Value: "key-123"}, 5},
+		{"anonymous IP", ClientIdentity{Source: ClientIdentitySourceIP, Value: "203.0.113.7"}, 2},
+		{"anonymous header", ClientIdentity{Source: ClientIdentitySourceHeader, Value: "client-1"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := 0
+			for i := 0; i < 10; i++ {
+				if ok, _ := limiter.AllowClient(context.Background(), tt.identity); ok {
+					allowed++
+				}
+			}
+			if allowed != tt.allowed {
+				t.Errorf("allowed %d requests, want %d", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestNewRateLimiter_AnonDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        RateLimiterConfig
+		wantAnonRate  int
+		wantAnonBurst int
+	}{
+		{"inherits authenticated limits", RateLimiterConfig{RequestsPerMinute: 100, BurstSize: 10}, 100, 10},
+		{"anon burst defaults to anon rate", RateLimiterConfig{RequestsPerMinute: 100, BurstSize: 10, AnonRequestsPerMinute: 20}, 20, 20},
+		{"explicit anon limits", RateLimiterConfig{RequestsPerMinute: 100, AnonRequestsPerMinute: 20, AnonBurstSize: 3}, 20, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(tt.config)
+			defer limiter.cleanup.Stop()
+
+			if limiter.current().config.AnonRequestsPerMinute != tt.wantAnonRate {
+				t.Errorf("AnonRequestsPerMinute = %d, want %d", limiter.current().config.AnonRequestsPerMinute, tt.wantAnonRate)
+			}
+			if limiter.current().config.AnonBurstSize != tt.wantAnonBurst {
+				t.Errorf("AnonBurstSize = %d, want %d", limiter.current().config.AnonBurstSize, tt.wantAnonBurst)
+			}
+		})
+	}
+}
+
+// testAuthenticator returns an authenticator that accepts only validKey.
+func testAuthenticator(validKey string) *APIKeyAuthenticator {
+	cfg := &config.Config{SecretsManager: config.SecretsManagerConfig{Enabled: true}}
+	return NewAPIKeyAuthenticator(&mockSecretsManager{apiKey: validKey}, cfg, true)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	authenticator := testAuthenticator("key-123")
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Enabled:               true,
+		RequestsPerMinute:     60,
+		BurstSize:             3,
+		AnonRequestsPerMinute: 6,
+		AnonBurstSize:         1,
+	})
+	defer limiter.cleanup.Stop()
+
+	tests := []struct {
+		name      string
+		event     events.APIGatewayProxyRequest
+		allowed   int
+		wantLimit string
+	}{
+		{
+			name:      "authenticated tier",
+			event:     clientEvent("203.0.113.7", map[string]string{"X-API-Key": "key-123"}),
+			allowed:   3,
+			wantLimit: "60",
+		},
+		{
+			name:      "anonymous tier keyed by IP",
+			event:     clientEvent("198.51.100.1", nil),
+			allowed:   1,
+			wantLimit: "6",
+		},
+		{
+			name:      "unverified key uses anonymous tier",
+			event:     clientEvent("198.51.100.2", map[string]string{"X-API-Key": "forged-key"}),
+			allowed:   1,
+			wantLimit: "6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := func() events.APIGatewayProxyResponse {
+				calls++
+				return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+			}
+
+			var last events.APIGatewayProxyResponse
+			for i := 0; i <= tt.allowed; i++ {
+				last = WithRateLimit(context.Background(), tt.event, limiter, nil, authenticator, nil, handler)
+			}
+
+			if calls != tt.allowed {
+				t.Errorf("handler calls = %d, want %d", calls, tt.allowed)
+			}
+			if last.StatusCode != http.StatusTooManyRequests {
+				t.Fatalf("StatusCode = %d, want 429", last.StatusCode)
+			}
+			if last.Headers["X-RateLimit-Limit"] != tt.wantLimit {
+				t.Errorf("X-RateLimit-Limit = %q, want %q", last.Headers["X-RateLimit-Limit"], tt.wantLimit)
+			}
+			if last.Headers["X-RateLimit-Remaining"] != "0" {
+				t.Errorf("X-RateLimit-Remaining = %q, want 0", last.Headers["X-RateLimit-Remaining"])
+			}
+			if retryAfter, err := strconv.Atoi(last.Headers["Retry-After"]); err != nil || retryAfter < 1 || retryAfter > 10 {
+				t.Errorf("Retry-After = %q, want 1-10 seconds", last.Headers["Retry-After"])
+			}
+			if last.Headers["X-Content-Type-Options"] == "" {
+				t.Error("expected security headers on 429 response")
+			}
+		})
+	}
+}
+
+// mockKeyLister lists API keys with tiers.
+type mockKeyLister struct {
+	keys []config.APIKeyEntry
+}
+
+func (m *mockKeyLister) GetAPIKey(ctx context.Context) (string, error) {
+	return m.keys[0].Key, nil
+}
+
+func (m *mockKeyLister) GetAPIKeys(ctx context.Context) ([]config.APIKeyEntry, error) {
+	return m.keys, nil
+}
+
+func TestWithRateLimit_Tiers(t *testing.T) {
+	cfg := &config.Config{SecretsManager: config.SecretsManagerConfig{Enabled: true}}
+	authenticator := NewAPIKeyAuthenticator(&mockKeyLister{keys: []config.APIKeyEntry{
+		{Key: "pro-key", Tier: "pro"},
+		{Key: "free-key", Tier: "free"},
+		{Key: "plain-key"},
+	}}, cfg, true)
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Enabled:               true,
+		RequestsPerMinute:     60,
+		BurstSize:             3,
+		AnonRequestsPerMinute: 6,
+		AnonBurstSize:         1,
+		Tiers: map[string]TierLimits{
+			"free": {RequestsPerMinute: 30, BurstSize: 2},
+			"pro":  {RequestsPerMinute: 600, BurstSize: 8},
+		},
+	})
+	defer limiter.Stop()
+
+	tests := []struct {
+		name      string
+		apiKey    string
+		wantCalls int
+		wantLimit string
+	}{
+		{"pro key", "pro-key", 8, "600"},
+		{"free key", "free-key", 2, "30"},
+		{"key without tier", "plain-key", 3, "60"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := clientEvent("203.0.113."+strconv.Itoa(20+i), map[string]string{"X-API-Key": tt.apiKey})
+			calls := 0
+			var last events.APIGatewayProxyResponse
+			for j := 0; j < 10; j++ {
+				last = WithRateLimit(context.Background(), event, limiter, nil, authenticator, nil, func() events.APIGatewayProxyResponse {
+					calls++
+					return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+				})
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if last.Headers["X-RateLimit-Limit"] != tt.wantLimit {
+				t.Errorf("X-RateLimit-Limit = %q, want %q", last.Headers["X-RateLimit-Limit"], tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestParseTierLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]TierLimits
+		wantErr bool
+	}{
+		{"empty", "", map[string]TierLimits{}, false},
+		{"tiers", " free:60:10, pro:600 ", map[string]TierLimits{"free": {60, 10}, "pro": {600, 0}}, false},
+		{"missing rate", "free", nil, true},
+		{"too many parts", "free:1:2:3", nil, true},
+		{"not a number", "free:sixty", nil, true},
+		{"zero burst", "free:60:0", nil, true},
+		{"duplicate", "free:60,free:30", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTierLimits(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTierLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTierLimits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRateLimit_ExemptKeys(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Enabled:               true,
+		RequestsPerMinute:     60,
+		BurstSize:             2,
+		AnonRequestsPerMinute: 6,
+		AnonBurstSize:         1,
+		ExemptKeys:            []string{"monitor-key", "ops-key"},
+	})
+	defer limiter.Stop()
+	authenticator := testAuthenticator("key-123")
+
+	tests := []struct {
+		name      string
+		event     events.APIGatewayProxyRequest
+		wantCalls int
+	}{
+		{"exempt key is never rejected", clientEvent("203.0.113.7", map[string]string{"X-API-Key": "ops-key"}), 20},
+		{"exempt Bearer token", clientEvent("203.0.113.8", map[string]string{"Authorization": "Bearer monitor-key"}), 20},
+		{"normal key is still limited", clientEvent("203.0.113.9", map[string]string{"X-API-Key": "key-123"}), 2},
+		{"prefix of an exempt key is limited", clientEvent("203.0.113.10", map[string]string{"X-API-Key": "ops"}), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			for i := 0; i < 20; i++ {
+				WithRateLimit(context.Background(), tt.event, limiter, nil, authenticator, nil, func() events.APIGatewayProxyResponse {
+					calls++
+					return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+				})
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_IsExempt(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{Enabled: true, RequestsPerMinute: 60, ExemptKeys: []string{"monitor-key", ""}})
+	defer limiter.Stop()
+
+	if !limiter.IsExempt("monitor-key") {
+		t.Error("IsExempt(monitor-key) = false, want true")
+	}
+	for _, key := range []string{"", "monitor-key ", "other"} {
+		if limiter.IsExempt(key) {
+			t.Errorf("IsExempt(%q) = true, want false", key)
+		}
+	}
+	var nilLimiter *RateLimiter
+	if nilLimiter.IsExempt("monitor-key") {
+		t.Error("nil limiter IsExempt() = true, want false")
+	}
+}
+
+func TestWithRateLimit_NilLimiter(t *testing.T) {
+	calls := 0
+	for i := 0; i < 3; i++ {
+		WithRateLimit(context.Background(), clientEvent("198.51.100.1", nil), nil, nil, nil, nil, func() events.APIGatewayProxyResponse {
+			calls++
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+		})
+	}
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+}
+
+func TestWithRateLimit_RotatingKeysShareIPBucket(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Enabled:               true,
+		RequestsPerMinute:     60,
+		BurstSize:             10,
+		AnonRequestsPerMinute: 6,
+		AnonBurstSize:         2,
+	})
+	defer limiter.Stop()
+
+	tests := []struct {
+		name          string
+		authenticator *APIKeyAuthenticator
+	}{
+		{"invalid keys", testAuthenticator("key-123")},
+		{"no authenticator", nil},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := "198.51.100." + strconv.Itoa(10+i)
+			calls := 0
+			for j := 0; j < 5; j++ {
+				event := clientEvent(ip, map[string]string{"X-API-Key": "random-" + strconv.Itoa(j)})
+				WithRateLimit(context.Background(), event, limiter, nil, tt.authenticator, nil, func() events.APIGatewayProxyResponse {
+					calls++
+					return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}
+				})
+			}
+			if calls != 2 {
+				t.Errorf("handler calls = %d, want 2 (anonymous burst for the IP)", calls)
+			}
+		})
+	}
+
+	limiter.mu.RLock()
+	defer limiter.mu.RUnlock()
+	for key := range limiter.buckets {
+		if strings.HasPrefix(key, string(ClientIdentitySourceAPIKey)) {
+			t.Errorf("unexpected bucket for unverified key %q", key)
+		}
+	}
+}
+
+func TestRateLimiter_EvictIdle(t *testing.T) {
+	limiter := NewRateLimiter(DefaultRateLimiterConfig())
+	defer limiter.Stop()
+
+	limiter.Allow(context.Background(), "idle")
+	cutoff := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	limiter.Allow(context.Background(), "active")
+
+	if evicted := limiter.evictIdle(cutoff); evicted != 1 {
+		t.Errorf("evictIdle() = %d, want 1", evicted)
+	}
+
+	limiter.mu.RLock()
+	defer limiter.mu.RUnlock()
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("expected idle bucket to be evicted")
+	}
+	if _, ok := limiter.buckets["active"]; !ok {
+		t.Error("expected active bucket to be kept")
+	}
+}
+
+func TestNewRateLimiter_IdleTTLDefault(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{Enabled: true, RequestsPerMinute: 60})
+	defer limiter.Stop()
+
+	if limiter.current().config.IdleTTL != defaultBucketIdleTTL {
+		t.Errorf("IdleTTL = %v, want %v", limiter.current().config.IdleTTL, defaultBucketIdleTTL)
+	}
+}
+
+func TestRateLimiter_Stop(t *testing.T) {
+	limiter := NewRateLimiter(DefaultRateLimiterConfig())
+
+	limiter.Stop()
+	limiter.Stop() // idempotent
+
+	select {
+	case <-limiter.done:
+	default:
+		t.Error("expected done channel to be closed after Stop")
+	}
+
+	if allowed, err := limiter.Allow(context.Background(), "key"); err != nil || !allowed {
+		t.Errorf("Allow() after Stop = %v, %v, want true, nil", allowed, err)
+	}
+}
+
+func TestRateLimiter_UpdateConfig(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{Enabled: true, RequestsPerMinute: 1, BurstSize: 2})
+	defer limiter.Stop()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow(ctx, "key"); !ok {
+			t.Fatalf("request %d rejected within the burst", i+1)
+		}
+	}
+	if ok, _ := limiter.Allow(ctx, "key"); ok {
+		t.Fatal("request allowed beyond the burst")
+	}
+
+	limiter.UpdateConfig(RateLimiterConfig{Enabled: true, RequestsPerMinute: 1, BurstSize: 5})
+	for i := 0; i < 5; i++ {
+		if ok, _ := limiter.Allow(ctx, "key"); !ok {
+			t.Fatalf("request %d rejected within the new burst", i+1)
+		}
+	}
+	if ok, _ := limiter.Allow(ctx, "key"); ok {
+		t.Error("request allowed beyond the new burst")
+	}
+
+	// Concurrent updates and requests are safe (run with -race)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(burst int) {
+			defer wg.Done()
+			limiter.UpdateConfig(RateLimiterConfig{Enabled: true, RequestsPerMinute: 60, BurstSize: burst})
+		}(i + 1)
+		go func() {
+			defer wg.Done()
+			_, _ = limiter.AllowClient(ctx, ClientIdentity{Source: ClientIdentitySourceIP, Value: "192.0.2.1"})
+			_ = limiter.GetRemainingRequests("key")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+// errHandlerPanicked is reported (as a generic 500) for requests whose handler panicked.
+var errHandlerPanicked = errors.New("request handler panicked")
+
+// WithRecovery runs fn and turns a panic into a generic 500 response.
+//
+// This function:
+// - Recovers panics from fn (handlers, use cases, adapters) without re-panicking,
+// so one bad request cannot crash the Lambda instance or the HTTP server
+// - Logs the panic value and stack trace with the structured logger
+// - Returns ErrorResponse with a generic message; panic details are never sent to the client
+//
+// Context cancellations are not reported as panics: a panic carrying
+// context.Canceled or context.DeadlineExceeded is answered with the usual
+// timeout response and logged without a stack trace.
+// If log is nil, a logger is created from the environment.
+func WithRecovery(ctx context.Context, log *logger.Logger, fn func() events.APIGatewayProxyResponse) (resp events.APIGatewayProxyResponse) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if log == nil {
+			log = logger.NewFromEnv()
+		}
+
+		if err, ok := recovered.(error); ok && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			log.WithContext(ctx).Warn("request aborted by context cancellation", "error", err.Error())
+			resp = ErrorResponse(err)
+			return
+		}
+
+		log.WithContext(ctx).Error("recovered from panic in request handler",
+			"panic", fmt.Sprint(recovered),
+			"stack", string(debug.Stack()),
+		)
+		resp = ErrorResponse(errHandlerPanicked)
+	}()
+
+	return fn()
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/misterfancybg/go-currenseen/pkg/logger"
+)
+
+func TestWithRecovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         func() events.APIGatewayProxyResponse
+		wantStatus int
+		wantStack  bool
+	}{
+		{
+			name: "no panic",
+			fn: func() events.APIGatewayProxyResponse {
+				return SuccessResponse(context.Background(), http.StatusOK, map[string]string{})
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "nil map panic",
+			fn: func() events.APIGatewayProxyResponse {
+				var m map[string]int
+				m["boom"]++
+				return events.APIGatewayProxyResponse{}
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantStack:  true,
+		},
+		{
+			name: "context cancellation",
+			fn: func() events.APIGatewayProxyResponse {
+				panic(fmt.Errorf("fetch aborted: %w", context.Canceled))
+			},
+			wantStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := &logger.Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+			resp := WithRecovery(context.Background(), log, tt.fn)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if strings.Contains(resp.Body, "nil map") {
+				t.Errorf("Body = %s, must not leak panic details", resp.Body)
+			}
+
+			var entry struct {
+				Msg   string `json:"msg"`
+				Panic string `json:"panic"`
+				Stack string `json:"stack"`
+			}
+			if buf.Len() > 0 {
+				if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+					t.Fatalf("failed to decode log entry: %v", err)
+				}
+			}
+			if gotStack := strings.Contains(entry.Stack, "recovery_test.go"); gotStack != tt.wantStack {
+				t.Errorf("logged stack trace = %v, want %v (log: %s)", gotStack, tt.wantStack, buf.String())
+			}
+			if tt.wantStack && !strings.Contains(entry.Panic, "nil map") {
+				t.Errorf("logged panic = %q, want the panic value", entry.Panic)
+			}
+		})
+	}
+}
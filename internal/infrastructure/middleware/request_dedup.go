@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// RequestDeduplicatorConfig holds configuration for request deduplication.
+type RequestDeduplicatorConfig struct {
+	// Window is how long a response is replayed for duplicate deliveries of the same request.
+	Window time.Duration
+	// MaxEntries bounds the number of remembered requests (0 = unbounded).
+	MaxEntries int
+	// Enabled controls whether deduplication is active.
+	Enabled bool
+}
+
+// DefaultRequestDeduplicatorConfig returns a default request deduplication configuration.
+//
+// Default values:
+// - Window: 30 seconds (covers API Gateway retry delivery)
+// - MaxEntries: 10000
+// - Enabled: false (opt-in; a replayed response can hide a fresher rate)
+func DefaultRequestDeduplicatorConfig() RequestDeduplicatorConfig {
+	return RequestDeduplicatorConfig{
+		Window:     30 * time.Second,
+		MaxEntries: 10000,
+		Enabled:    false,
+	}
+}
+
+// dedupEntry is a remembered request. done is closed once resp is set.
+type dedupEntry struct {
+	resp      events.APIGatewayProxyResponse
+	expiresAt time.Time
+	done      chan struct{}
+}
+
+// RequestDeduplicator replays responses for duplicate deliveries of the same
+// API Gateway request.
+//
+// Requests are keyed on requestContext.requestId, which API Gateway keeps
+// stable across retried deliveries of one client request. For reads this saves
+// recomputing the response; for mutations it guarantees the handler runs at
+// most once per request within the window.
+//
+// The set is in-memory and therefore per Lambda execution environment.
+// Expired entries are swept at most once per window, so the set only holds
+// requests from roughly the last two windows.
+type RequestDeduplicator struct {
+	entries   map[string]*dedupEntry
+	config    RequestDeduplicatorConfig
+	now       func() time.Time
+	nextSweep time.Time // When expired entries are next removed
+	mu        sync.Mutex
+}
+
+// NewRequestDeduplicator creates a new request deduplicator using the system clock.
+func NewRequestDeduplicator(config RequestDeduplicatorConfig) *RequestDeduplicator {
+	return NewRequestDeduplicatorWithClock(config, time.Now)
+}
+
+// NewRequestDeduplicatorWithClock creates a new request deduplicator with a custom clock.
+// This is useful for testing window expiry. If now is nil, time.Now is used.
+func NewRequestDeduplicatorWithClock(config RequestDeduplicatorConfig, now func() time.Time) *RequestDeduplicator {
+	if now == nil {
+		now = time.Now
+	}
+	return &RequestDeduplicator{
+		entries: make(map[string]*dedupEntry),
+		config:  config,
+		now:     now,
+	}
+}
+
+// Handle executes fn once per API Gateway request ID within the window.
+//
+// This function:
+// - Executes fn directly if deduplication is disabled or the request has no request ID
+// - Returns the remembered response for a duplicate within the window
+// - Waits for the first delivery to finish if a duplicate arrives while it is in flight
+// - Does not remember 5xx responses so retries can recover from server errors
+// - Forgets the request if fn panics, so duplicates waiting on it get a 500 and retries run again
+//
+// Context cancellation: a duplicate waiting on an in-flight request returns
+// ErrorResponse(ctx.Err()) if ctx is cancelled first.
+func (d *RequestDeduplicator) Handle(ctx context.Context, event events.APIGatewayProxyRequest, fn func() events.APIGatewayProxyResponse) events.APIGatewayProxyResponse {
+	requestID := event.RequestContext.RequestID
+	if d == nil || !d.config.Enabled || d.config.Window <= 0 || requestID == "" {
+		return fn()
+	}
+
+	d.mu.Lock()
+	now := d.now()
+	if entry, exists := d.entries[requestID]; exists && (entry.expiresAt.IsZero() || now.Before(entry.expiresAt)) {
+		d.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.resp
+		case <-ctx.Done():
+			return ErrorResponse(ctx.Err())
+		}
+	}
+
+	if !now.Before(d.nextSweep) || (d.config.MaxEntries > 0 && len(d.entries) >= d.config.MaxEntries) {
+		d.evictExpired(now)
+		d.nextSweep = now.Add(d.config.Window)
+	}
+	if d.config.MaxEntries > 0 && len(d.entries) >= d.config.MaxEntries {
+		// Full of live entries - serve without deduplication rather than grow unbounded
+		d.mu.Unlock()
+		return fn()
+	}
+
+	// Zero expiresAt marks the entry as in flight
+	entry := &dedupEntry{done: make(chan struct{})}
+	d.entries[requestID] = entry
+	d.mu.Unlock()
+
+	// Deferred so a panicking fn still releases waiting duplicates
+	completed := false
+	var resp events.APIGatewayProxyResponse
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if !completed {
+			delete(d.entries, requestID)
+			entry.resp = ErrorResponse(errHandlerPanicked)
+		} else {
+			if resp.StatusCode >= http.StatusInternalServerError {
+				delete(d.entries, requestID)
+			} else {
+				entry.expiresAt = d.now().Add(d.config.Window)
+			}
+			entry.resp = resp
+		}
+		close(entry.done)
+	}()
+
+	resp = fn()
+	completed = true
+	return resp
+}
+
+// evictExpired removes expired entries. Must be called with d.mu held.
+func (d *RequestDeduplicator) evictExpired(now time.Time) {
+	for requestID, entry := range d.entries {
+		if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+			delete(d.entries, requestID)
+		}
+	}
+}
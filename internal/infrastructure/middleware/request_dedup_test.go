@@ -0,0 +1,287 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// fakeClock is a manually advanced clock for deduplication tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func dedupEvent(requestID string) events.APIGatewayProxyRequest {
+	return events.APIGatewayProxyRequest{
+		HTTPMethod:     http.MethodGet,
+		Path:           "/rates/USD/EUR",
+		RequestContext: events.APIGatewayProxyRequestContext{RequestID: requestID},
+	}
+}
+
+// enabledDedupConfig returns the default configuration with deduplication enabled.
+func enabledDedupConfig() RequestDeduplicatorConfig {
+	config := DefaultRequestDeduplicatorConfig()
+	config.Enabled = true
+	return config
+}
+
+// countingHandler returns a handler whose response body is the invocation count.
+func countingHandler(calls *int, statusCode int) func() events.APIGatewayProxyResponse {
+	return func() events.APIGatewayProxyResponse {
+		*calls++
+		return events.APIGatewayProxyResponse{StatusCode: statusCode, Body: fmt.Sprintf("call-%d", *calls)}
+	}
+}
+
+func TestRequestDeduplicator_DuplicateReturnsPreviousResponse(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	dedup := NewRequestDeduplicatorWithClock(enabledDedupConfig(), clock.Now)
+	ctx := context.Background()
+
+	calls := 0
+	handler := countingHandler(&calls, http.StatusOK)
+
+	first := dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	clock.Advance(10 * time.Second)
+	second := dedup.Handle(ctx, dedupEvent("req-1"), handler)
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if second.Body != first.Body {
+		t.Errorf("duplicate body = %q, want %q", second.Body, first.Body)
+	}
+
+	// A different request ID is not a duplicate
+	third := dedup.Handle(ctx, dedupEvent("req-2"), handler)
+	if calls != 2 || third.Body != "call-2" {
+		t.Errorf("new request: calls = %d, body = %q, want 2 and call-2", calls, third.Body)
+	}
+}
+
+func TestRequestDeduplicator_WindowExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	config := enabledDedupConfig()
+	config.Window = 30 * time.Second
+	dedup := NewRequestDeduplicatorWithClock(config, clock.Now)
+	ctx := context.Background()
+
+	calls := 0
+	handler := countingHandler(&calls, http.StatusOK)
+
+	dedup.Handle(ctx, dedupEvent("req-1"), handler)
+
+	clock.Advance(29 * time.Second)
+	dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	if calls != 1 {
+		t.Fatalf("handler calls within window = %d, want 1", calls)
+	}
+
+	clock.Advance(1 * time.Second)
+	resp := dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	if calls != 2 || resp.Body != "call-2" {
+		t.Errorf("after window: calls = %d, body = %q, want 2 and call-2", calls, resp.Body)
+	}
+}
+
+func TestRequestDeduplicator_ServerErrorNotRemembered(t *testing.T) {
+	dedup := NewRequestDeduplicator(enabledDedupConfig())
+	ctx := context.Background()
+
+	calls := 0
+	handler := countingHandler(&calls, http.StatusInternalServerError)
+
+	dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	dedup.Handle(ctx, dedupEvent("req-1"), handler)
+
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2 (5xx must not be replayed)", calls)
+	}
+}
+
+func TestRequestDeduplicator_Bypass(t *testing.T) {
+	disabled := DefaultRequestDeduplicatorConfig()
+
+	tests := []struct {
+		name   string
+		dedup  *RequestDeduplicator
+		event  events.APIGatewayProxyRequest
+		wanted int
+	}{
+		{"nil deduplicator", nil, dedupEvent("req-1"), 2},
+		{"disabled", NewRequestDeduplicator(disabled), dedupEvent("req-1"), 2},
+		{"missing request ID", NewRequestDeduplicator(enabledDedupConfig()), dedupEvent(""), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := countingHandler(&calls, http.StatusOK)
+
+			tt.dedup.Handle(context.Background(), tt.event, handler)
+			tt.dedup.Handle(context.Background(), tt.event, handler)
+
+			if calls != tt.wanted {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestRequestDeduplicator_ConcurrentDuplicateWaitsForFirst(t *testing.T) {
+	dedup := NewRequestDeduplicator(enabledDedupConfig())
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	handler := func() events.APIGatewayProxyResponse {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		close(started)
+		<-release
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusCreated, Body: "created"}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	}()
+	<-started
+
+	done := make(chan events.APIGatewayProxyResponse, 1)
+	go func() {
+		done <- dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	}()
+
+	close(release)
+	resp := <-done
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Body != "created" {
+		t.Errorf("duplicate response = %d %q, want 201 created", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestRequestDeduplicator_MaxEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	config := enabledDedupConfig()
+	config.MaxEntries = 1
+	dedup := NewRequestDeduplicatorWithClock(config, clock.Now)
+	ctx := context.Background()
+
+	calls := 0
+	handler := countingHandler(&calls, http.StatusOK)
+
+	dedup.Handle(ctx, dedupEvent("req-1"), handler)
+	// Set is full - req-2 is served but not remembered
+	dedup.Handle(ctx, dedupEvent("req-2"), handler)
+	dedup.Handle(ctx, dedupEvent("req-2"), handler)
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+
+	// Once req-1 expires its slot is reclaimed
+	clock.Advance(config.Window)
+	dedup.Handle(ctx, dedupEvent("req-3"), handler)
+	dedup.Handle(ctx, dedupEvent("req-3"), handler)
+	if calls != 4 {
+		t.Errorf("handler calls = %d, want 4", calls)
+	}
+}
+
+func TestRequestDeduplicator_EvictsExpiredEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	config := enabledDedupConfig()
+	config.Window = 30 * time.Second
+	dedup := NewRequestDeduplicatorWithClock(config, clock.Now)
+	ctx := context.Background()
+
+	calls := 0
+	handler := countingHandler(&calls, http.StatusOK)
+	for i := 0; i < 5; i++ {
+		dedup.Handle(ctx, dedupEvent(fmt.Sprintf("old-%d", i)), handler)
+	}
+
+	// Well below MaxEntries, expired entries are still removed
+	clock.Advance(2 * config.Window)
+	dedup.Handle(ctx, dedupEvent("new"), handler)
+
+	dedup.mu.Lock()
+	remaining := len(dedup.entries)
+	dedup.mu.Unlock()
+	if remaining != 1 {
+		t.Errorf("entries = %d, want 1 (expired entries evicted)", remaining)
+	}
+}
+
+func TestRequestDeduplicator_PanicReleasesEntry(t *testing.T) {
+	dedup := NewRequestDeduplicator(enabledDedupConfig())
+	ctx := context.Background()
+
+	// A retry after a panic runs the handler again
+	func() {
+		defer func() { _ = recover() }()
+		dedup.Handle(ctx, dedupEvent("req-1"), func() events.APIGatewayProxyResponse { panic("boom") })
+	}()
+	calls := 0
+	dedup.Handle(ctx, dedupEvent("req-1"), countingHandler(&calls, http.StatusOK))
+	if calls != 1 {
+		t.Errorf("handler calls after panic = %d, want 1", calls)
+	}
+
+	// A duplicate waiting on a panicking request is released
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		defer func() { _ = recover() }()
+		dedup.Handle(ctx, dedupEvent("req-2"), func() events.APIGatewayProxyResponse {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	waiter := make(chan events.APIGatewayProxyResponse, 1)
+	go func() {
+		waiter <- dedup.Handle(ctx, dedupEvent("req-2"), countingHandler(new(int), http.StatusOK))
+	}()
+	close(release)
+
+	select {
+	case resp := <-waiter:
+		// 500 if it waited on the panicking request, 200 if it ran after the entry was removed
+		if resp.StatusCode != http.StatusInternalServerError && resp.StatusCode != http.StatusOK {
+			t.Errorf("waiting duplicate StatusCode = %d, want 500 or 200", resp.StatusCode)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("duplicate still waiting after the handler panicked")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"unicode"
 
@@ -11,6 +12,12 @@ import (
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
 )
 
+// ErrInvalidBases indicates a missing, empty, or oversized bases query parameter.
+var ErrInvalidBases = errors.New("invalid bases parameter")
+
+// ErrInvalidFormat indicates an unsupported format query parameter.
+var ErrInvalidFormat = errors.New("invalid format parameter")
+
 // ValidateMethod validates that the HTTP method matches the expected method.
 //
 // Returns an error if the method doesn't match.
@@ -23,7 +30,9 @@ func ValidateMethod(event events.APIGatewayProxyRequest, expectedMethod string)
 
 // ExtractPathParameter extracts a path parameter from the API Gateway event.
 //
-// Returns an error if the parameter is missing or empty.
+// Percent-encoded values are decoded, so a client sending %55SD gets the same
+// result as one sending USD; callers still validate the decoded value.
+// Returns an error if the parameter is missing, empty, or malformed percent-encoding.
 func ExtractPathParameter(event events.APIGatewayProxyRequest, paramName string) (string, error) {
 	if event.PathParameters == nil {
 		return "", fmt.Errorf("path parameter %s not found", paramName)
@@ -34,13 +43,22 @@ func ExtractPathParameter(event events.APIGatewayProxyRequest, paramName string)
 		return "", fmt.Errorf("path parameter %s not found or empty", paramName)
 	}
 
-	return value, nil
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return "", fmt.Errorf("path parameter %s is not valid percent-encoding: %w", paramName, err)
+	}
+	if decoded == "" {
+		return "", fmt.Errorf("path parameter %s not found or empty", paramName)
+	}
+
+	return decoded, nil
 }
 
 // ValidateCurrencyCode validates a currency code string.
 //
 // This function:
 // - Validates the currency code format using domain validation
+// - Resolves aliases set with SetCurrencyAliases to their canonical code
 // - Returns a domain error if invalid
 //
 // Security: Validates input before processing to prevent injection attacks.
@@ -56,7 +74,7 @@ func ValidateCurrencyCode(code string) (entity.CurrencyCode, error) {
 		return zero, fmt.Errorf("invalid currency code %s: %w", code, err)
 	}
 
-	return currencyCode, nil
+	return resolveCurrencyAlias(currencyCode), nil
 }
 
 // ValidateGetRateRequest validates a GET /rates/{base}/{target} request.
@@ -69,10 +87,26 @@ func ValidateCurrencyCode(code string) (entity.CurrencyCode, error) {
 //
 // Returns domain errors for invalid input.
 func ValidateGetRateRequest(event events.APIGatewayProxyRequest) (base, target entity.CurrencyCode, err error) {
+	return validatePairRequest(event, http.MethodGet)
+}
+
+// ValidateInvalidateRateRequest validates a DELETE /rates/{base}/{target} request.
+//
+// This function:
+// - Validates HTTP method is DELETE
+// - Extracts and validates base and target currency codes, as ValidateGetRateRequest
+//
+// Returns domain errors for invalid input.
+func ValidateInvalidateRateRequest(event events.APIGatewayProxyRequest) (base, target entity.CurrencyCode, err error) {
+	return validatePairRequest(event, http.MethodDelete)
+}
+
+// validatePairRequest validates a /rates/{base}/{target} request with the given method.
+func validatePairRequest(event events.APIGatewayProxyRequest, method string) (base, target entity.CurrencyCode, err error) {
 	var zero entity.CurrencyCode
 
 	// Validate HTTP method
-	if err := ValidateMethod(event, http.MethodGet); err != nil {
+	if err := ValidateMethod(event, method); err != nil {
 		return zero, zero, err
 	}
 
@@ -135,6 +169,49 @@ func ValidateGetRatesRequest(event events.APIGatewayProxyRequest) (base entity.C
 	return base, nil
 }
 
+// ValidateMultiBaseRequest validates a GET /rates?bases=USD,EUR request.
+//
+// This function:
+// - Validates HTTP method is GET
+// - Splits the comma-separated bases query parameter, trimming and uppercasing entries
+// - Resolves currency aliases, so valid entries are returned as canonical codes
+// - Drops empty and duplicate entries (after alias resolution), keeping the first occurrence order
+// - Validates the number of bases is between 1 and maxBases
+//
+// Invalid codes are kept as given, so callers can report them per base
+// instead of failing the whole request.
+//
+// Returns ErrInvalidBases if the parameter is missing, empty, or has too many bases.
+func ValidateMultiBaseRequest(event events.APIGatewayProxyRequest, maxBases int) ([]string, error) {
+	// Validate HTTP method
+	if err := ValidateMethod(event, http.MethodGet); err != nil {
+		return nil, err
+	}
+
+	var bases []string
+	seen := make(map[string]bool)
+	for _, base := range strings.Split(event.QueryStringParameters["bases"], ",") {
+		base = strings.ToUpper(strings.TrimSpace(base))
+		if code, err := ValidateCurrencyCode(base); err == nil {
+			base = code.String()
+		}
+		if base == "" || seen[base] {
+			continue
+		}
+		seen[base] = true
+		bases = append(bases, base)
+	}
+
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("%w: no base currencies given", ErrInvalidBases)
+	}
+	if len(bases) > maxBases {
+		return nil, fmt.Errorf("%w: %d base currencies given, at most %d allowed", ErrInvalidBases, len(bases), maxBases)
+	}
+
+	return bases, nil
+}
+
 // ValidateHealthRequest validates a GET /health request.
 //
 // This function:
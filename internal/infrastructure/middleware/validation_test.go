@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -78,6 +80,23 @@ func TestExtractPathParameter(t *testing.T) {
 			paramName: "base",
 			wantErr:   true,
 		},
+		{
+			name: "percent-encoded parameter",
+			event: events.APIGatewayProxyRequest{
+				PathParameters: map[string]string{"base": "%55SD"},
+			},
+			paramName: "base",
+			want:      "USD",
+			wantErr:   false,
+		},
+		{
+			name: "malformed percent-encoding",
+			event: events.APIGatewayProxyRequest{
+				PathParameters: map[string]string{"base": "US%G1"},
+			},
+			paramName: "base",
+			wantErr:   true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,6 +136,36 @@ func TestValidateCurrencyCode(t *testing.T) {
 	}
 }
 
+func TestValidateGetRateRequest_PercentEncoded(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		target  string
+		wantErr bool
+	}{
+		{"plain", "USD", "EUR", false},
+		{"encoded", "%55SD", "%45%55%52", false},
+		{"encoded lowercase", "%75sd", "eur", false},
+		{"decodes to invalid code", "US%44D", "EUR", true},
+		{"decodes to path separator", "US%2FD", "EUR", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, target, err := ValidateGetRateRequest(events.APIGatewayProxyRequest{
+				HTTPMethod:     "GET",
+				PathParameters: map[string]string{"base": tt.base, "target": tt.target},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateGetRateRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (base != "USD" || target != "EUR") {
+				t.Errorf("ValidateGetRateRequest() = %s/%s, want USD/EUR", base, target)
+			}
+		})
+	}
+}
+
 func TestValidateGetRateRequest(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -245,6 +294,52 @@ func TestValidateGetRatesRequest(t *testing.T) {
 	}
 }
 
+func TestValidateMultiBaseRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		bases     string
+		want      []string
+		wantErr   bool
+		wantBases bool // error is ErrInvalidBases
+	}{
+		{name: "single base", method: "GET", bases: "USD", want: []string{"USD"}},
+		{name: "several bases keep order", method: "GET", bases: "usd, EUR ,gbp", want: []string{"USD", "EUR", "GBP"}},
+		{name: "duplicates and empty entries dropped", method: "GET", bases: "USD,,usd,EUR,", want: []string{"USD", "EUR"}},
+		{name: "invalid codes are kept for per-base errors", method: "GET", bases: "USD,XX1", want: []string{"USD", "XX1"}},
+		{name: "aliases resolved before deduplicating", method: "GET", bases: "xbt,BTC,dem", want: []string{"BTC", "EUR"}},
+		{name: "missing parameter", method: "GET", bases: "", wantErr: true, wantBases: true},
+		{name: "only separators", method: "GET", bases: " , ,", wantErr: true, wantBases: true},
+		{name: "too many bases", method: "GET", bases: "USD,EUR,GBP,JPY", wantErr: true, wantBases: true},
+		{name: "wrong method", method: "POST", bases: "USD", wantErr: true},
+	}
+
+	if err := SetCurrencyAliases(map[string]string{"XBT": "BTC", "DEM": "EUR"}); err != nil {
+		t.Fatalf("SetCurrencyAliases() error = %v", err)
+	}
+	t.Cleanup(func() { _ = SetCurrencyAliases(nil) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := events.APIGatewayProxyRequest{HTTPMethod: tt.method}
+			if tt.bases != "" {
+				event.QueryStringParameters = map[string]string{"bases": tt.bases}
+			}
+
+			got, err := ValidateMultiBaseRequest(event, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateMultiBaseRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantBases && !errors.Is(err, ErrInvalidBases) {
+				t.Errorf("ValidateMultiBaseRequest() error = %v, want ErrInvalidBases", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateMultiBaseRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestValidateHealthRequest(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -24,7 +24,7 @@ const (
 	StateOpen
 
 	// StateHalfOpen represents the testing state.
-	// Allows one test request to check if the service has recovered.
+	// Allows a limited number of concurrent test requests to check if the service has recovered.
 	// If test succeeds, transitions to Closed. If fails, transitions back to Open.
 	StateHalfOpen
 )
@@ -57,6 +57,11 @@ type Config struct {
 	// Typically 1 (single successful test call).
 	// Default: 1
 	SuccessThreshold int
+
+	// HalfOpenMaxConcurrent is the number of test requests allowed in flight in HalfOpen state.
+	// Further requests are rejected until a test request resolves.
+	// Default: 1 (zero is treated as the default)
+	HalfOpenMaxConcurrent int
 }
 
 // DefaultConfig returns a default circuit breaker configuration.
@@ -65,11 +70,13 @@ type Config struct {
 // - FailureThreshold: 5
 // - CooldownDuration: 30 seconds
 // - SuccessThreshold: 1
+// - HalfOpenMaxConcurrent: 1
 func DefaultConfig() Config {
 	return Config{
-		FailureThreshold: 5,
-		CooldownDuration: 30 * time.Second,
-		SuccessThreshold: 1,
+		FailureThreshold:      5,
+		CooldownDuration:      30 * time.Second,
+		SuccessThreshold:      1,
+		HalfOpenMaxConcurrent: 1,
 	}
 }
 
@@ -85,6 +92,9 @@ func (c Config) Validate() error {
 	if c.SuccessThreshold <= 0 {
 		return errors.New("success threshold must be greater than 0")
 	}
+	if c.HalfOpenMaxConcurrent < 0 {
+		return errors.New("half-open max concurrent cannot be negative")
+	}
 	return nil
 }
 
@@ -93,7 +103,7 @@ func (c Config) Validate() error {
 // The circuit breaker has three states:
 // - Closed: Normal operation, all requests pass through
 // - Open: Failing fast, all requests are rejected immediately
-// - HalfOpen: Testing recovery, allows one test request
+// - HalfOpen: Testing recovery, allows up to HalfOpenMaxConcurrent test requests at once
 //
 // State transitions:
 // - Closed → Open: When failure count reaches threshold
@@ -108,6 +118,7 @@ type CircuitBreaker struct {
 	config          Config
 	failureCount    int
 	successCount    int
+	probesInFlight  int // Test requests allowed in HalfOpen that have not resolved yet
 	lastFailureTime time.Time
 	lastStateChange time.Time
 }
@@ -124,6 +135,9 @@ func NewCircuitBreaker(config Config) (*CircuitBreaker, error) {
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
+	if config.HalfOpenMaxConcurrent == 0 {
+		config.HalfOpenMaxConcurrent = DefaultConfig().HalfOpenMaxConcurrent
+	}
 
 	now := time.Now()
 	return &CircuitBreaker{
@@ -148,7 +162,11 @@ func (cb *CircuitBreaker) State() State {
 //
 // Returns:
 //   - true if the request is allowed
-//   - false if the circuit is open (request should be rejected)
+//   - false if the circuit is open, or HalfOpen with HalfOpenMaxConcurrent
+//     test requests already in flight (request should be rejected)
+//
+// Every allowed request must be resolved with RecordSuccess, RecordFailure,
+// or Release so its HalfOpen test slot is freed.
 //
 // This method also handles automatic state transitions:
 // - Open → HalfOpen when cooldown expires
@@ -171,8 +189,12 @@ func (cb *CircuitBreaker) Allow() bool {
 		return false
 
 	case StateHalfOpen:
-		// Allow one test request in HalfOpen state
+		// Allow a limited number of concurrent test requests
 		// After this, the state will change based on success/failure
+		if cb.probesInFlight >= cb.config.HalfOpenMaxConcurrent {
+			return false
+		}
+		cb.probesInFlight++
 		return true
 
 	default:
@@ -199,6 +221,8 @@ func (cb *CircuitBreaker) RecordSuccess() {
 		cb.failureCount = 0
 
 	case StateHalfOpen:
+		cb.releaseProbe()
+
 		// Increment success count
 		cb.successCount++
 
@@ -242,6 +266,30 @@ func (cb *CircuitBreaker) RecordFailure() {
 	}
 }
 
+// Release frees a HalfOpen test slot without recording an outcome.
+//
+// Use it when an allowed request ends with a result that says nothing about
+// the service's health (e.g. a client error or cancellation), so that
+// another test request can be allowed.
+//
+// This method is thread-safe.
+func (cb *CircuitBreaker) Release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen {
+		cb.releaseProbe()
+	}
+}
+
+// releaseProbe decrements the in-flight HalfOpen test request count.
+// Must be called with lock held.
+func (cb *CircuitBreaker) releaseProbe() {
+	if cb.probesInFlight > 0 {
+		cb.probesInFlight--
+	}
+}
+
 // updateState handles automatic state transitions based on time.
 // Must be called with lock held.
 func (cb *CircuitBreaker) updateState() {
@@ -262,6 +310,7 @@ func (cb *CircuitBreaker) transitionToOpen(now time.Time) {
 	cb.lastStateChange = now
 	cb.failureCount = 0 // Reset for next cycle
 	cb.successCount = 0
+	cb.probesInFlight = 0
 }
 
 // transitionToHalfOpen transitions the circuit breaker to HalfOpen state.
@@ -271,6 +320,7 @@ func (cb *CircuitBreaker) transitionToHalfOpen() {
 	cb.lastStateChange = time.Now()
 	cb.failureCount = 0
 	cb.successCount = 0
+	cb.probesInFlight = 0
 }
 
 // transitionToClosed transitions the circuit breaker to Closed state.
@@ -280,4 +330,5 @@ func (cb *CircuitBreaker) transitionToClosed() {
 	cb.lastStateChange = time.Now()
 	cb.failureCount = 0
 	cb.successCount = 0
+	cb.probesInFlight = 0
 }
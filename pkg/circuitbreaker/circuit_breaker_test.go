@@ -1,7 +1,9 @@
 package circuitbreaker
 
 import (
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -20,6 +22,10 @@ func TestDefaultConfig(t *testing.T) {
 	if config.SuccessThreshold != 1 {
 		t.Errorf("SuccessThreshold = %d, want 1", config.SuccessThreshold)
 	}
+
+	if config.HalfOpenMaxConcurrent != 1 {
+		t.Errorf("HalfOpenMaxConcurrent = %d, want 1", config.HalfOpenMaxConcurrent)
+	}
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -64,6 +70,16 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative half-open max concurrent",
+			config: Config{
+				FailureThreshold:      5,
+				CooldownDuration:      30 * time.Second,
+				SuccessThreshold:      1,
+				HalfOpenMaxConcurrent: -1,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -389,3 +405,118 @@ func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
 		t.Errorf("State = %v, want Closed (success should have reset failure count)", cb.State())
 	}
 }
+
+// openAndCoolDown opens cb and waits for its cooldown to expire.
+func openAndCoolDown(t *testing.T, cb *CircuitBreaker) {
+	t.Helper()
+	for i := 0; i < cb.config.FailureThreshold; i++ {
+		cb.RecordFailure()
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("State = %v, want Open", cb.State())
+	}
+	time.Sleep(cb.config.CooldownDuration + 10*time.Millisecond)
+}
+
+func TestCircuitBreaker_HalfOpen_LimitsConcurrentProbes(t *testing.T) {
+	for _, maxConcurrent := range []int{1, 3} {
+		t.Run(fmt.Sprintf("max %d", maxConcurrent), func(t *testing.T) {
+			cb, _ := NewCircuitBreaker(Config{
+				FailureThreshold:      1,
+				CooldownDuration:      20 * time.Millisecond,
+				SuccessThreshold:      1,
+				HalfOpenMaxConcurrent: maxConcurrent,
+			})
+			openAndCoolDown(t, cb)
+
+			// A burst of callers arrives at once
+			const callers = 50
+			var allowed atomic.Int32
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for i := 0; i < callers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					if cb.Allow() {
+						allowed.Add(1)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if got := int(allowed.Load()); got != maxConcurrent {
+				t.Errorf("allowed probes = %d, want %d", got, maxConcurrent)
+			}
+			if cb.State() != StateHalfOpen {
+				t.Errorf("State = %v, want HalfOpen", cb.State())
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_HalfOpen_ProbeResolutionFreesSlot(t *testing.T) {
+	cb, _ := NewCircuitBreaker(Config{
+		FailureThreshold: 1,
+		CooldownDuration: 20 * time.Millisecond,
+		SuccessThreshold: 2,
+	})
+	openAndCoolDown(t, cb)
+
+	if !cb.Allow() {
+		t.Fatal("Allow() = false, want first probe allowed")
+	}
+	if cb.Allow() {
+		t.Fatal("Allow() = true, want second probe rejected (default limit 1)")
+	}
+
+	// Release frees the slot without counting towards closing
+	cb.Release()
+	if !cb.Allow() {
+		t.Fatal("Allow() = false after Release, want probe allowed")
+	}
+
+	// A success frees the slot; the circuit stays HalfOpen until SuccessThreshold
+	cb.RecordSuccess()
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("State = %v, want HalfOpen after 1 of 2 successes", cb.State())
+	}
+	if !cb.Allow() {
+		t.Fatal("Allow() = false after RecordSuccess, want probe allowed")
+	}
+	cb.RecordSuccess()
+	if cb.State() != StateClosed {
+		t.Fatalf("State = %v, want Closed", cb.State())
+	}
+
+	// Release outside HalfOpen is a no-op
+	cb.Release()
+	if !cb.Allow() {
+		t.Error("Allow() = false in Closed state")
+	}
+}
+
+func TestCircuitBreaker_HalfOpen_FailureReopensAndResetsProbes(t *testing.T) {
+	cb, _ := NewCircuitBreaker(Config{
+		FailureThreshold: 1,
+		CooldownDuration: 20 * time.Millisecond,
+		SuccessThreshold: 1,
+	})
+	openAndCoolDown(t, cb)
+
+	if !cb.Allow() {
+		t.Fatal("Allow() = false, want probe allowed")
+	}
+	cb.RecordFailure()
+	if cb.State() != StateOpen {
+		t.Fatalf("State = %v, want Open", cb.State())
+	}
+
+	// After the next cooldown a fresh probe is allowed
+	time.Sleep(30 * time.Millisecond)
+	if !cb.Allow() {
+		t.Error("Allow() = false, want probe allowed after second cooldown")
+	}
+}
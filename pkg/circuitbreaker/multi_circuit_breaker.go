@@ -0,0 +1,122 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultIdleTTL is how long an unused breaker is kept by MultiCircuitBreaker.
+const DefaultIdleTTL = 1 * time.Hour
+
+// MultiCircuitBreaker manages independent circuit breakers per key
+// (e.g. one per base currency), so an outage affecting one key does not
+// reject requests for the others.
+//
+// This type:
+// - Creates a CircuitBreaker for a key on first use, all sharing one Config
+// - Removes breakers that have been idle for longer than the idle TTL
+// - Never removes a breaker that is not Closed, so open circuits are not reset
+//
+// Idle breakers are swept during Get at most once per idle TTL, so no
+// background goroutine is needed.
+//
+// MultiCircuitBreaker is thread-safe and can be used concurrently.
+type MultiCircuitBreaker struct {
+	mu        sync.Mutex
+	config    Config
+	idleTTL   time.Duration
+	breakers  map[string]*keyedBreaker
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// keyedBreaker is a breaker with its last access time.
+type keyedBreaker struct {
+	breaker  *CircuitBreaker
+	lastUsed time.Time
+}
+
+// NewMultiCircuitBreaker creates a new MultiCircuitBreaker.
+//
+// Parameters:
+//   - config: Configuration for every per-key breaker (use DefaultConfig() for defaults)
+//   - idleTTL: How long an unused breaker is kept (0 uses DefaultIdleTTL)
+//
+// Returns an error if the configuration is invalid or idleTTL is negative.
+func NewMultiCircuitBreaker(config Config, idleTTL time.Duration) (*MultiCircuitBreaker, error) {
+	return newMultiCircuitBreaker(config, idleTTL, time.Now)
+}
+
+// newMultiCircuitBreaker creates a MultiCircuitBreaker with a custom clock.
+func newMultiCircuitBreaker(config Config, idleTTL time.Duration, now func() time.Time) (*MultiCircuitBreaker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if idleTTL < 0 {
+		return nil, errors.New("idle TTL cannot be negative")
+	}
+	if idleTTL == 0 {
+		idleTTL = DefaultIdleTTL
+	}
+
+	return &MultiCircuitBreaker{
+		config:    config,
+		idleTTL:   idleTTL,
+		breakers:  make(map[string]*keyedBreaker),
+		lastSweep: now(),
+		now:       now,
+	}, nil
+}
+
+// Get returns the circuit breaker for key, creating it if needed.
+func (m *MultiCircuitBreaker) Get(key string) *CircuitBreaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= m.idleTTL {
+		m.sweep(now)
+	}
+
+	entry, ok := m.breakers[key]
+	if !ok {
+		// Config was validated in the constructor, so this cannot fail
+		breaker, _ := NewCircuitBreaker(m.config)
+		entry = &keyedBreaker{breaker: breaker}
+		m.breakers[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.breaker
+}
+
+// State returns the state of the breaker for key.
+// Keys without a breaker report StateClosed.
+func (m *MultiCircuitBreaker) State(key string) State {
+	m.mu.Lock()
+	entry, ok := m.breakers[key]
+	m.mu.Unlock()
+
+	if !ok {
+		return StateClosed
+	}
+	return entry.breaker.State()
+}
+
+// Len returns the number of breakers currently tracked.
+func (m *MultiCircuitBreaker) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.breakers)
+}
+
+// sweep removes Closed breakers that have been idle for longer than the idle TTL.
+// Must be called with lock held.
+func (m *MultiCircuitBreaker) sweep(now time.Time) {
+	for key, entry := range m.breakers {
+		if now.Sub(entry.lastUsed) >= m.idleTTL && entry.breaker.State() == StateClosed {
+			delete(m.breakers, key)
+		}
+	}
+	m.lastSweep = now
+}
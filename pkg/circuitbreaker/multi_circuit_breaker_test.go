@@ -0,0 +1,162 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for MultiCircuitBreaker tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestNewMultiCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		idleTTL time.Duration
+		wantTTL time.Duration
+		wantErr bool
+	}{
+		{name: "default idle TTL", config: DefaultConfig(), idleTTL: 0, wantTTL: DefaultIdleTTL},
+		{name: "custom idle TTL", config: DefaultConfig(), idleTTL: 5 * time.Minute, wantTTL: 5 * time.Minute},
+		{name: "negative idle TTL", config: DefaultConfig(), idleTTL: -time.Second, wantErr: true},
+		{name: "invalid config", config: Config{}, idleTTL: time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMultiCircuitBreaker(tt.config, tt.idleTTL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMultiCircuitBreaker() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if m.idleTTL != tt.wantTTL {
+				t.Errorf("idleTTL = %v, want %v", m.idleTTL, tt.wantTTL)
+			}
+			if m.Len() != 0 {
+				t.Errorf("Len() = %d, want 0", m.Len())
+			}
+		})
+	}
+}
+
+func TestMultiCircuitBreaker_Get_LazyCreation(t *testing.T) {
+	m, _ := NewMultiCircuitBreaker(DefaultConfig(), time.Hour)
+
+	usd := m.Get("USD")
+	if m.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", m.Len())
+	}
+	if again := m.Get("USD"); again != usd {
+		t.Error("Get() returned a different breaker for the same key")
+	}
+	if eur := m.Get("EUR"); eur == usd {
+		t.Error("Get() returned the same breaker for different keys")
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+}
+
+func TestMultiCircuitBreaker_IndependentStates(t *testing.T) {
+	config := Config{
+		FailureThreshold: 2,
+		CooldownDuration: time.Minute,
+		SuccessThreshold: 1,
+	}
+	m, _ := NewMultiCircuitBreaker(config, time.Hour)
+
+	usd := m.Get("USD")
+	usd.RecordFailure()
+	usd.RecordFailure()
+
+	if m.State("USD") != StateOpen {
+		t.Fatalf("State(USD) = %v, want Open", m.State("USD"))
+	}
+	if m.Get("USD").Allow() {
+		t.Error("USD Allow() = true, want false")
+	}
+	if !m.Get("EUR").Allow() {
+		t.Error("EUR Allow() = false, want true")
+	}
+	if m.State("EUR") != StateClosed {
+		t.Errorf("State(EUR) = %v, want Closed", m.State("EUR"))
+	}
+	if m.State("GBP") != StateClosed {
+		t.Errorf("State(GBP) = %v, want Closed for unknown key", m.State("GBP"))
+	}
+}
+
+func TestMultiCircuitBreaker_IdleCleanup(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	config := Config{
+		FailureThreshold: 1,
+		CooldownDuration: 24 * time.Hour,
+		SuccessThreshold: 1,
+	}
+	m, _ := newMultiCircuitBreaker(config, time.Minute, clock.Now)
+
+	m.Get("USD")
+	m.Get("EUR").RecordFailure() // Open, must survive cleanup
+	m.Get("GBP")
+
+	clock.Advance(30 * time.Second)
+	m.Get("GBP") // Keeps GBP fresh
+
+	clock.Advance(45 * time.Second)
+	m.Get("JPY") // Triggers the sweep
+
+	if _, ok := m.breakers["USD"]; ok {
+		t.Error("idle closed breaker USD was not removed")
+	}
+	if _, ok := m.breakers["EUR"]; !ok {
+		t.Error("open breaker EUR was removed")
+	}
+	if _, ok := m.breakers["GBP"]; !ok {
+		t.Error("recently used breaker GBP was removed")
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+}
+
+func TestMultiCircuitBreaker_ThreadSafety(t *testing.T) {
+	m, _ := NewMultiCircuitBreaker(DefaultConfig(), time.Hour)
+	keys := []string{"USD", "EUR", "GBP", "JPY"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := keys[i%len(keys)]
+			cb := m.Get(key)
+			if cb.Allow() {
+				cb.RecordSuccess()
+			}
+			_ = m.State(key)
+		}(i)
+	}
+	wg.Wait()
+
+	if m.Len() != len(keys) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(keys))
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"log/slog"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Context keys for logger values
@@ -23,6 +25,7 @@ const (
 // Logger wraps slog.Logger with additional functionality
 type Logger struct {
 	*slog.Logger
+	bodyMaxBytes int // see Config.BodyMaxBytes
 }
 
 // Config holds logger configuration
@@ -31,6 +34,10 @@ type Config struct {
 	Format     string // json or text (default: json)
 	AddSource  bool   // Include source file/line in logs (default: false)
 	CloudWatch bool   // Optimize for CloudWatch (default: true)
+
+	// BodyMaxBytes is the maximum number of bytes of a request body logged by
+	// LogRequestBody; 0 disables body logging (default: 0)
+	BodyMaxBytes int
 }
 
 // New creates a new logger with the given configuration.
@@ -74,7 +81,7 @@ func New(config *Config) *Logger {
 		)
 	}
 
-	return &Logger{Logger: logger}
+	return &Logger{Logger: logger, bodyMaxBytes: config.BodyMaxBytes}
 }
 
 // NewFromEnv creates a logger from environment variables.
@@ -82,6 +89,7 @@ func New(config *Config) *Logger {
 // - LOG_LEVEL: DEBUG, INFO, WARN, ERROR (default: INFO)
 // - LOG_FORMAT: json or text (default: json)
 // - LOG_SOURCE: true/false to include source file/line (default: false)
+// - LOG_BODY_MAX_BYTES: max bytes of request bodies logged, 0 disables (default: 0)
 func NewFromEnv() *Logger {
 	config := &Config{
 		Level:      getEnvOrDefault("LOG_LEVEL", "INFO"),
@@ -89,13 +97,16 @@ func NewFromEnv() *Logger {
 		AddSource:  getEnvOrDefault("LOG_SOURCE", "false") == "true",
 		CloudWatch: true,
 	}
+	if maxBytes, err := strconv.Atoi(getEnvOrDefault("LOG_BODY_MAX_BYTES", "0")); err == nil && maxBytes > 0 {
+		config.BodyMaxBytes = maxBytes
+	}
 
 	return New(config)
 }
 
 // WithRequestID adds request ID to the logger context
 func (l *Logger) WithRequestID(requestID string) *Logger {
-	return &Logger{Logger: l.Logger.With("request_id", requestID)}
+	return &Logger{Logger: l.Logger.With("request_id", requestID), bodyMaxBytes: l.bodyMaxBytes}
 }
 
 // WithContext creates a logger with values from context
@@ -117,7 +128,7 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 		logger = logger.With("target_currency", target)
 	}
 
-	return &Logger{Logger: logger}
+	return &Logger{Logger: logger, bodyMaxBytes: l.bodyMaxBytes}
 }
 
 // Debug logs a debug message with optional key-value pairs
@@ -147,6 +158,18 @@ func (l *Logger) LogRequest(ctx context.Context, method, path string, args ...an
 	l.WithContext(ctx).Info("incoming request", attrs...)
 }
 
+// LogRequestBody logs a request body at debug level, sanitized and truncated
+// to Config.BodyMaxBytes. Nothing is logged if body logging is disabled or the body is empty.
+func (l *Logger) LogRequestBody(ctx context.Context, body string) {
+	if l.bodyMaxBytes <= 0 || body == "" {
+		return
+	}
+	l.WithContext(ctx).Debug("request body",
+		"body", TruncateAndSanitize(body, l.bodyMaxBytes),
+		"body_bytes", len(body),
+	)
+}
+
 // LogResponse logs an HTTP response
 func (l *Logger) LogResponse(ctx context.Context, statusCode int, durationMs int64, args ...any) {
 	attrs := []any{"status_code", statusCode, "duration_ms", durationMs}
@@ -185,6 +208,27 @@ func SanitizeValue(value string) string {
 	return sanitized
 }
 
+// truncatedSuffix marks a value cut by TruncateAndSanitize.
+const truncatedSuffix = "...[TRUNCATED]"
+
+// TruncateAndSanitize sanitizes body with SanitizeValue and truncates the result
+// to at most max bytes (plus truncatedSuffix), never splitting a UTF-8 character.
+//
+// Sanitizing runs first so a secret cut at the boundary is still recognized.
+// A max of 0 or less disables truncation.
+func TruncateAndSanitize(body string, max int) string {
+	sanitized := SanitizeValue(body)
+	if max <= 0 || len(sanitized) <= max {
+		return sanitized
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(sanitized[cut]) {
+		cut--
+	}
+	return sanitized[:cut] + truncatedSuffix
+}
+
 // MaskAPIKey masks an API key, showing only first 4 and last 4 characters
 func MaskAPIKey(key string) string {
 	if key == "" {
@@ -213,6 +257,12 @@ func parseLogLevel(level string) slog.Level {
 	}
 }
 
+// Environment returns the deployment environment from ENVIRONMENT or ENV
+// (default: "development").
+func Environment() string {
+	return getEnvironment()
+}
+
 func getEnvironment() string {
 	env := os.Getenv("ENVIRONMENT")
 	if env == "" {
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
 	"os"
@@ -350,3 +351,50 @@ func TestSanitizeValue_Comprehensive(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateAndSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		max  int
+		want string
+	}{
+		{"shorter than max", "hello", 10, "hello"},
+		{"exactly max", "0123456789", 10, "0123456789"},
+		{"one byte over max", "0123456789a", 10, "0123456789" + truncatedSuffix},
+		{"max disabled", "0123456789", 0, "0123456789"},
+		{"empty body", "", 10, ""},
+		{"multi-byte rune at boundary", "abc€", 4, "abc" + truncatedSuffix},
+		{"secret in body", "base=USD&api_key=sk-live-123&target=EUR", 100, "base=USD&[REDACTED]&target=EUR"},
+		{"secret across boundary", "password: hunter2 and more text", 12, "[REDACTED] a" + truncatedSuffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateAndSanitize(tt.body, tt.max); got != tt.want {
+				t.Errorf("TruncateAndSanitize(%q, %d) = %q, want %q", tt.body, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogRequestBody(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+
+	disabled := &Logger{Logger: slog.New(handler)}
+	disabled.LogRequestBody(context.Background(), "token=abc123")
+	if buf.Len() != 0 {
+		t.Errorf("logged %q with body logging disabled, want nothing", buf.String())
+	}
+
+	enabled := (&Logger{Logger: slog.New(handler), bodyMaxBytes: 16}).WithRequestID("req-1")
+	enabled.LogRequestBody(context.Background(), "Bearer abc123 "+strings.Repeat("x", 100))
+	out := buf.String()
+	if strings.Contains(out, "abc123") {
+		t.Errorf("logged body contains secret: %s", out)
+	}
+	if !strings.Contains(out, truncatedSuffix) {
+		t.Errorf("logged body not truncated: %s", out)
+	}
+}
@@ -0,0 +1,116 @@
+// Package metrics provides lightweight in-process counters for operational visibility.
+//
+// Counters are reported through structured logs rather than a metrics backend,
+// so they work unchanged in Lambda (CloudWatch Logs) and locally.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// CacheResult describes how a request was served relative to the cache.
+type CacheResult string
+
+const (
+	// CacheHit indicates a valid cache entry was served.
+	CacheHit CacheResult = "hit"
+
+	// CacheMiss indicates the cache had no valid entry and the provider was called.
+	CacheMiss CacheResult = "miss"
+
+	// CacheStale indicates an expired cache entry was served as a fallback.
+	CacheStale CacheResult = "stale"
+)
+
+// CacheCounter counts cache results. The zero value is ready to use.
+//
+// A nil *CacheCounter is valid and counts nothing.
+// CacheCounter is safe for concurrent use by multiple goroutines.
+type CacheCounter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+	stale  atomic.Int64
+
+	lastSummary atomic.Int64 // Unix nanoseconds of the last summary claimed with SummaryDue
+}
+
+// CacheStats is a point-in-time snapshot of a CacheCounter.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	Stale  int64
+}
+
+// defaultCacheCounter is shared by use cases created without their own counter.
+var defaultCacheCounter = &CacheCounter{}
+
+// DefaultCacheCounter returns the process-wide cache counter.
+//
+// In Lambda, it accumulates across invocations served by the same instance.
+func DefaultCacheCounter() *CacheCounter {
+	return defaultCacheCounter
+}
+
+// Record counts a cache result and returns the updated totals.
+// Unknown results are not counted.
+func (c *CacheCounter) Record(result CacheResult) CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	switch result {
+	case CacheHit:
+		c.hits.Add(1)
+	case CacheMiss:
+		c.misses.Add(1)
+	case CacheStale:
+		c.stale.Add(1)
+	}
+	return c.Stats()
+}
+
+// SummaryDue reports whether a summary of the totals should be reported at now,
+// at most once per interval. The first call starts the interval and returns false.
+//
+// Exactly one concurrent caller is told a summary is due.
+func (c *CacheCounter) SummaryDue(now time.Time, interval time.Duration) bool {
+	if c == nil {
+		return false
+	}
+	last := c.lastSummary.Load()
+	if last == 0 {
+		c.lastSummary.CompareAndSwap(0, now.UnixNano())
+		return false
+	}
+	if now.UnixNano()-last < int64(interval) {
+		return false
+	}
+	return c.lastSummary.CompareAndSwap(last, now.UnixNano())
+}
+
+// Stats returns the current totals.
+func (c *CacheCounter) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Stale:  c.stale.Load(),
+	}
+}
+
+// Total returns the number of recorded results.
+func (s CacheStats) Total() int64 {
+	return s.Hits + s.Misses + s.Stale
+}
+
+// HitRatio returns the fraction of results served from a valid cache entry,
+// or 0 if nothing was recorded.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
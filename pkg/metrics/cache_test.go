@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheCounter_Record(t *testing.T) {
+	var counter CacheCounter
+
+	counter.Record(CacheHit)
+	counter.Record(CacheHit)
+	counter.Record(CacheHit)
+	counter.Record(CacheMiss)
+	stats := counter.Record(CacheStale)
+
+	want := CacheStats{Hits: 3, Misses: 1, Stale: 1}
+	if stats != want {
+		t.Errorf("Record() = %+v, want %+v", stats, want)
+	}
+	if got := counter.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got := stats.HitRatio(); got != 0.6 {
+		t.Errorf("HitRatio() = %v, want 0.6", got)
+	}
+}
+
+func TestCacheCounter_UnknownResultIgnored(t *testing.T) {
+	var counter CacheCounter
+
+	if stats := counter.Record("bogus"); stats.Total() != 0 {
+		t.Errorf("Record(bogus) total = %d, want 0", stats.Total())
+	}
+}
+
+func TestCacheCounter_Nil(t *testing.T) {
+	var counter *CacheCounter
+
+	if stats := counter.Record(CacheHit); stats != (CacheStats{}) {
+		t.Errorf("nil Record() = %+v, want zero", stats)
+	}
+}
+
+func TestCacheCounter_SummaryDue(t *testing.T) {
+	var counter CacheCounter
+	start := time.Unix(1700000000, 0)
+
+	if counter.SummaryDue(start, time.Minute) {
+		t.Error("SummaryDue() at start = true, want false")
+	}
+	if counter.SummaryDue(start.Add(30*time.Second), time.Minute) {
+		t.Error("SummaryDue() within interval = true, want false")
+	}
+	if !counter.SummaryDue(start.Add(time.Minute), time.Minute) {
+		t.Error("SummaryDue() after interval = false, want true")
+	}
+	if counter.SummaryDue(start.Add(time.Minute), time.Minute) {
+		t.Error("SummaryDue() twice = true, want false (already claimed)")
+	}
+
+	var nilCounter *CacheCounter
+	if nilCounter.SummaryDue(start, 0) {
+		t.Error("nil SummaryDue() = true, want false")
+	}
+}
+
+func TestCacheStats_HitRatioEmpty(t *testing.T) {
+	if got := (CacheStats{}).HitRatio(); got != 0 {
+		t.Errorf("HitRatio() = %v, want 0", got)
+	}
+}
+
+func TestCacheCounter_Concurrent(t *testing.T) {
+	var counter CacheCounter
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counter.Record(CacheHit)
+		}()
+	}
+	wg.Wait()
+
+	if got := counter.Stats().Hits; got != 100 {
+		t.Errorf("Hits = %d, want 100", got)
+	}
+}
@@ -0,0 +1,39 @@
+// Package version holds build information injected at link time.
+//
+// The variables are set with -ldflags, for example:
+//
+//	go build -ldflags "-X github.com/misterfancybg/go-currenseen/pkg/version.Version=v1.2.0" ./cmd/lambda
+//
+// Builds without ldflags report the defaults below.
+package version
+
+import "runtime"
+
+var (
+	// Version is the release version (default: "dev").
+	Version = "dev"
+
+	// GitCommit is the commit the binary was built from (default: "unknown").
+	GitCommit = "unknown"
+
+	// BuildTime is when the binary was built, in RFC 3339 (default: "unknown").
+	BuildTime = "unknown"
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
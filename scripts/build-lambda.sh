@@ -10,6 +10,10 @@ echo "Building Lambda function..."
 BINARY_NAME="bootstrap"
 LAMBDA_DIR="cmd/lambda"
 BUILD_DIR=".aws-sam/build/ExchangeRateFunction"
+VERSION_PKG="github.com/misterfancybg/go-currenseen/pkg/version"
+VERSION="${VERSION:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}"
+GIT_COMMIT="${GIT_COMMIT:-$(git rev-parse --short HEAD 2>/dev/null || echo unknown)}"
+BUILD_TIME="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
 
 # Create build directory
 mkdir -p "$BUILD_DIR"
@@ -17,7 +21,7 @@ mkdir -p "$BUILD_DIR"
 # Build Go binary for Linux/AMD64 (Lambda runtime)
 echo "Compiling Go binary for Linux/AMD64..."
 GOOS=linux GOARCH=amd64 CGO_ENABLED=0 go build \
-    -ldflags="-s -w" \
+    -ldflags="-s -w -X $VERSION_PKG.Version=$VERSION -X $VERSION_PKG.GitCommit=$GIT_COMMIT -X $VERSION_PKG.BuildTime=$BUILD_TIME" \
     -o "$BUILD_DIR/$BINARY_NAME" \
     "./$LAMBDA_DIR"
 
@@ -0,0 +1,111 @@
+//go:build integration
+// +build integration
+
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	dynamodbadapter "github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+)
+
+const ensureTableName = "ExchangeRatesEnsureTest"
+
+func TestEnsureTable_Idempotent(t *testing.T) {
+	setupIntegrationTest(t)
+	defer teardownIntegrationTest(t)
+
+	// Start from a missing table
+	if _, err := testClient.DeleteTable(testCtx, &dynamodb.DeleteTableInput{
+		TableName: aws.String(ensureTableName),
+	}); err == nil {
+		waiter := dynamodb.NewTableNotExistsWaiter(testClient)
+		if err := waiter.Wait(testCtx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(ensureTableName),
+		}, 30*time.Second); err != nil {
+			t.Fatalf("Failed to delete existing table: %v", err)
+		}
+	}
+	defer func() {
+		_, _ = testClient.DeleteTable(testCtx, &dynamodb.DeleteTableInput{
+			TableName: aws.String(ensureTableName),
+		})
+	}()
+
+	// Creating twice must succeed
+	for i := 0; i < 2; i++ {
+		if err := dynamodbadapter.EnsureTable(testCtx, testClient, ensureTableName); err != nil {
+			t.Fatalf("EnsureTable() call %d error = %v", i+1, err)
+		}
+	}
+
+	described, err := testClient.DescribeTable(testCtx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(ensureTableName),
+	})
+	if err != nil {
+		t.Fatalf("DescribeTable() error = %v", err)
+	}
+	if described.Table.TableStatus != types.TableStatusActive {
+		t.Errorf("TableStatus = %s, want ACTIVE", described.Table.TableStatus)
+	}
+	if len(described.Table.GlobalSecondaryIndexes) != 1 || aws.ToString(described.Table.GlobalSecondaryIndexes[0].IndexName) != gsiName {
+		t.Errorf("GlobalSecondaryIndexes = %+v, want %s", described.Table.GlobalSecondaryIndexes, gsiName)
+	}
+
+	ttl, err := testClient.DescribeTimeToLive(testCtx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(ensureTableName),
+	})
+	if err != nil {
+		t.Fatalf("DescribeTimeToLive() error = %v", err)
+	}
+	if desc := ttl.TimeToLiveDescription; desc == nil || aws.ToString(desc.AttributeName) != "ttl" {
+		t.Errorf("TimeToLiveDescription = %+v, want ttl attribute", desc)
+	}
+
+	// The created table works with the repository, including the GSI
+	repo := dynamodbadapter.NewDynamoDBRepository(testClient, ensureTableName)
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+	rate, _ := entity.NewExchangeRate(base, target, 0.85, time.Now(), false)
+	if err := repo.Save(testCtx, rate, time.Hour); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	rates, err := repo.GetByBase(testCtx, base)
+	if err != nil {
+		t.Fatalf("GetByBase() error = %v", err)
+	}
+	if len(rates) != 1 {
+		t.Errorf("GetByBase() returned %d rates, want 1", len(rates))
+	}
+}
+
+func TestEnsureTTL_Idempotent(t *testing.T) {
+	setupIntegrationTest(t)
+	defer teardownIntegrationTest(t)
+
+	// setupTestTable creates the table without TTL
+	for i := 0; i < 2; i++ {
+		if err := dynamodbadapter.EnsureTTL(testCtx, testClient, testTableName); err != nil {
+			t.Fatalf("EnsureTTL() call %d error = %v", i+1, err)
+		}
+	}
+
+	ttl, err := testClient.DescribeTimeToLive(testCtx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(testTableName),
+	})
+	if err != nil {
+		t.Fatalf("DescribeTimeToLive() error = %v", err)
+	}
+	desc := ttl.TimeToLiveDescription
+	if desc == nil || aws.ToString(desc.AttributeName) != "ttl" {
+		t.Fatalf("TimeToLiveDescription = %+v, want ttl attribute", desc)
+	}
+	if desc.TimeToLiveStatus != types.TimeToLiveStatusEnabled && desc.TimeToLiveStatus != types.TimeToLiveStatusEnabling {
+		t.Errorf("TimeToLiveStatus = %s, want ENABLED or ENABLING", desc.TimeToLiveStatus)
+	}
+}
@@ -11,11 +11,11 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
 	dynamodbadapter "github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+	"github.com/misterfancybg/go-currenseen/internal/infrastructure/config"
 )
 
 const (
@@ -24,8 +24,9 @@ const (
 )
 
 var (
-	testRepo *dynamodbadapter.DynamoDBRepository
-	testCtx  context.Context
+	testRepo   *dynamodbadapter.DynamoDBRepository
+	testClient *dynamodb.Client
+	testCtx    context.Context
 )
 
 // setupTestTable creates the test DynamoDB table with the required schema.
@@ -110,27 +111,13 @@ func teardownTestTable(ctx context.Context, client *dynamodb.Client) error {
 func setupIntegrationTest(t *testing.T) {
 	ctx := context.Background()
 
-	// Load AWS config
-	// For DynamoDB Local, set AWS_ENDPOINT_URL=http://localhost:8000
-	cfg, err := config.LoadDefaultConfig(ctx)
+	// Create the client like production does
+	// For DynamoDB Local, set AWS_ENDPOINT_URL (or DYNAMODB_ENDPOINT)=http://localhost:8000
+	client, err := config.NewDynamoDBClient(ctx)
 	if err != nil {
-		t.Fatalf("Failed to load AWS config: %v", err)
+		t.Fatalf("Failed to create DynamoDB client: %v", err)
 	}
 
-	// Override endpoint for DynamoDB Local if specified
-	if endpoint := os.Getenv("AWS_ENDPOINT_URL"); endpoint != "" {
-		cfg.EndpointResolverWithOptions = aws.EndpointResolverWithOptionsFunc(
-			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-				return aws.Endpoint{
-					URL:           endpoint,
-					SigningRegion: region,
-				}, nil
-			},
-		)
-	}
-
-	client := dynamodb.NewFromConfig(cfg)
-
 	// Create test table
 	if err := setupTestTable(ctx, client); err != nil {
 		t.Fatalf("Failed to setup test table: %v", err)
@@ -138,6 +125,7 @@ func setupIntegrationTest(t *testing.T) {
 
 	// Create repository
 	testRepo = dynamodbadapter.NewDynamoDBRepository(client, testTableName)
+	testClient = client
 	testCtx = ctx
 }
 
@@ -203,6 +191,41 @@ func TestDynamoDBRepository_Get_Success(t *testing.T) {
 	}
 }
 
+func TestDynamoDBRepository_Get_ConsistentRead(t *testing.T) {
+	setupIntegrationTest(t)
+	defer teardownIntegrationTest(t)
+
+	repo := dynamodbadapter.NewDynamoDBRepositoryWithConfig(
+		testClient,
+		testTableName,
+		dynamodbadapter.RepositoryConfig{ConsistentRead: true},
+		nil,
+		nil,
+	)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("CHF")
+
+	// Overwrite the rate several times; each read must see the write just before it
+	for _, value := range []float64{0.88, 0.89, 0.90} {
+		rate, err := entity.NewExchangeRate(base, target, value, time.Now(), false)
+		if err != nil {
+			t.Fatalf("Failed to create test rate: %v", err)
+		}
+		if err := repo.Save(testCtx, rate, 1*time.Hour); err != nil {
+			t.Fatalf("Failed to save rate: %v", err)
+		}
+
+		got, err := repo.Get(testCtx, base, target)
+		if err != nil {
+			t.Fatalf("Failed to get rate immediately after save: %v", err)
+		}
+		if got.Rate != value {
+			t.Errorf("Rate = %v, want %v (consistent read returned a stale value)", got.Rate, value)
+		}
+	}
+}
+
 func TestDynamoDBRepository_Get_NotFound(t *testing.T) {
 	setupIntegrationTest(t)
 	defer teardownIntegrationTest(t)
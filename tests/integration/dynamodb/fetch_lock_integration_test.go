@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package dynamodb
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/misterfancybg/go-currenseen/internal/domain/entity"
+	dynamodbadapter "github.com/misterfancybg/go-currenseen/internal/infrastructure/adapter/dynamodb"
+)
+
+func TestFetchLock_ConcurrentAcquire(t *testing.T) {
+	setupIntegrationTest(t)
+	defer teardownIntegrationTest(t)
+
+	base, _ := entity.NewCurrencyCode("USD")
+	target, _ := entity.NewCurrencyCode("EUR")
+
+	// One FetchLock per simulated instance, racing for the same pair
+	const instances = 10
+	locks := make([]*dynamodbadapter.FetchLock, instances)
+	for i := range locks {
+		locks[i] = dynamodbadapter.NewFetchLock(testClient, testTableName, 5*time.Second)
+	}
+
+	var acquired atomic.Int32
+	var winner atomic.Int32
+	var wg sync.WaitGroup
+	for i, lock := range locks {
+		wg.Add(1)
+		go func(i int, lock *dynamodbadapter.FetchLock) {
+			defer wg.Done()
+			ok, err := lock.Acquire(testCtx, base, target)
+			if err != nil {
+				t.Errorf("Acquire() error = %v", err)
+				return
+			}
+			if ok {
+				acquired.Add(1)
+				winner.Store(int32(i))
+			}
+		}(i, lock)
+	}
+	wg.Wait()
+
+	if got := acquired.Load(); got != 1 {
+		t.Fatalf("acquired by %d instances, want 1", got)
+	}
+
+	// A non-owner cannot release the lock
+	other := locks[(int(winner.Load())+1)%instances]
+	if err := other.Release(testCtx, base, target); err != nil {
+		t.Fatalf("Release() by non-owner error = %v", err)
+	}
+	if ok, _ := other.Acquire(testCtx, base, target); ok {
+		t.Fatal("Acquire() = true after a non-owner Release, want false")
+	}
+
+	// The owner's Release frees the lock for the next instance
+	if err := locks[winner.Load()].Release(testCtx, base, target); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if ok, err := other.Acquire(testCtx, base, target); err != nil || !ok {
+		t.Errorf("Acquire() after Release = %v, %v, want true", ok, err)
+	}
+	_ = other.Release(testCtx, base, target)
+}
+
+func TestFetchLock_ExpiredLockIsTakenOver(t *testing.T) {
+	setupIntegrationTest(t)
+	defer teardownIntegrationTest(t)
+
+	base, _ := entity.NewCurrencyCode("GBP")
+	target, _ := entity.NewCurrencyCode("JPY")
+
+	first := dynamodbadapter.NewFetchLock(testClient, testTableName, time.Second)
+	second := dynamodbadapter.NewFetchLock(testClient, testTableName, time.Second)
+
+	if ok, err := first.Acquire(testCtx, base, target); err != nil || !ok {
+		t.Fatalf("first Acquire() = %v, %v, want true", ok, err)
+	}
+	if ok, _ := second.Acquire(testCtx, base, target); ok {
+		t.Fatal("second Acquire() = true while the lock is held, want false")
+	}
+
+	// Lock expiry is second-granular; wait past it (DynamoDB TTL has not deleted the item yet)
+	time.Sleep(2500 * time.Millisecond)
+	if ok, err := second.Acquire(testCtx, base, target); err != nil || !ok {
+		t.Fatalf("Acquire() after expiry = %v, %v, want true", ok, err)
+	}
+	_ = second.Release(testCtx, base, target)
+}